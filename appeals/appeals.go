@@ -0,0 +1,125 @@
+// Package appeals helps bots handle ban appeals sent to a subreddit's
+// moderators, by finding the bans an appeal is about in the moderation log.
+package appeals
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+const (
+	// BanAction and UnbanAction are the moderation log actions of bans and
+	// lifted bans.
+	BanAction   = "banuser"
+	UnbanAction = "unbanuser"
+	// maxPages is the most pages of each action read from the moderation
+	// log when looking for a user's bans.
+	maxPages = 10
+)
+
+var notModmailErr = fmt.Errorf("message was not sent to a subreddit")
+
+// Appeal is a message to a subreddit's moderators from a user, with the
+// user's ban history in that subreddit.
+type Appeal struct {
+	Message   *reddit.Message
+	Subreddit string
+	User      string
+	// History holds the user's bans and lifted bans, newest first.
+	History []*reddit.ModAction
+}
+
+// Banned reports whether the latest entry in the user's history is a ban.
+// Bans older than the moderation log reaches are not found.
+func (a *Appeal) Banned() bool {
+	return len(a.History) > 0 && a.History[0].Action == BanAction
+}
+
+// Ban returns the latest ban in the user's history, or nil if there is none.
+func (a *Appeal) Ban() *reddit.ModAction {
+	for _, action := range a.History {
+		if action.Action == BanAction {
+			return action
+		}
+	}
+	return nil
+}
+
+// Open links a message sent to a subreddit's moderators to the ban history of
+// its author.
+func Open(m reddit.Moderator, msg *reddit.Message) (*Appeal, error) {
+	if msg.Subreddit == "" {
+		return nil, notModmailErr
+	}
+
+	history, err := History(m, msg.Subreddit, msg.Author)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Appeal{
+		Message:   msg,
+		Subreddit: msg.Subreddit,
+		User:      msg.Author,
+		History:   history,
+	}, nil
+}
+
+// History searches the subreddit's moderation log for the bans and lifted bans
+// of a user, and returns them newest first. Each search consumes up to ten
+// intervals of the handle.
+func History(m reddit.Moderator, subreddit, user string) (
+	[]*reddit.ModAction,
+	error,
+) {
+	var history []*reddit.ModAction
+	for _, action := range []string{BanAction, UnbanAction} {
+		actions, err := search(m, subreddit, user, action)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, actions...)
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].CreatedUTC > history[j].CreatedUTC
+	})
+	return history, nil
+}
+
+func search(m reddit.Moderator, subreddit, user, action string) (
+	[]*reddit.ModAction,
+	error,
+) {
+	var found []*reddit.ModAction
+	params := map[string]string{"type": action, "limit": "500"}
+	for page := 0; page < maxPages; page++ {
+		p, err := m.ModLog(subreddit, params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range p.Actions {
+			if strings.EqualFold(a.TargetAuthor, user) {
+				found = append(found, a)
+			}
+		}
+
+		if p.After == "" {
+			break
+		}
+		params["after"] = p.After
+	}
+	return found, nil
+}
+
+// Grant lifts the appellant's ban and replies to the appeal with text.
+func Grant(bot reddit.Bot, a *Appeal, text string) error {
+	if err := bot.Unban(a.Subreddit, a.User); err != nil {
+		return err
+	}
+	return bot.Reply(a.Message.Name, text)
+}
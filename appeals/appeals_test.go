@@ -0,0 +1,110 @@
+package appeals
+
+import (
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// modLogBot serves a moderation log in pages of two.
+type modLogBot struct {
+	reddit.Bot
+	log     map[string][]*reddit.ModAction
+	unbans  []string
+	replies []string
+}
+
+func (m *modLogBot) ModLog(
+	subreddit string,
+	params map[string]string,
+) (reddit.ModLogPage, error) {
+	actions := m.log[params["type"]]
+	start := 0
+	if params["after"] != "" {
+		for i, a := range actions {
+			if a.ID == params["after"] {
+				start = i + 1
+			}
+		}
+	}
+
+	end := start + 2
+	if end >= len(actions) {
+		return reddit.ModLogPage{Actions: actions[start:]}, nil
+	}
+	return reddit.ModLogPage{
+		Actions: actions[start:end],
+		After:   actions[end-1].ID,
+	}, nil
+}
+
+func (m *modLogBot) Unban(subreddit, user string) error {
+	m.unbans = append(m.unbans, subreddit+"/"+user)
+	return nil
+}
+
+func (m *modLogBot) Reply(parentName, text string) error {
+	m.replies = append(m.replies, parentName)
+	return nil
+}
+
+func action(id, kind, target string, created uint64) *reddit.ModAction {
+	return &reddit.ModAction{
+		ID:           id,
+		Action:       kind,
+		TargetAuthor: target,
+		CreatedUTC:   created,
+	}
+}
+
+func TestOpen(t *testing.T) {
+	bot := &modLogBot{
+		log: map[string][]*reddit.ModAction{
+			BanAction: {
+				action("a", BanAction, "other", 50),
+				action("b", BanAction, "Appellant", 40),
+				action("c", BanAction, "other", 30),
+				action("d", BanAction, "appellant", 10),
+			},
+			UnbanAction: {
+				action("e", UnbanAction, "appellant", 20),
+			},
+		},
+	}
+
+	a, err := Open(bot, &reddit.Message{
+		Name:      "t4_a",
+		Author:    "appellant",
+		Subreddit: "sub",
+	})
+	if err != nil {
+		t.Fatalf("failed to open appeal: %v", err)
+	}
+
+	var ids []string
+	for _, h := range a.History {
+		ids = append(ids, h.ID)
+	}
+	if len(ids) != 3 || ids[0] != "b" || ids[1] != "e" || ids[2] != "d" {
+		t.Errorf("got history %v; wanted [b e d]", ids)
+	}
+
+	if !a.Banned() || a.Ban().ID != "b" {
+		t.Errorf("wanted appellant banned by b")
+	}
+
+	if err := Grant(bot, a, "unbanned"); err != nil {
+		t.Fatalf("failed to grant appeal: %v", err)
+	}
+
+	if len(bot.unbans) != 1 || bot.unbans[0] != "sub/appellant" ||
+		len(bot.replies) != 1 || bot.replies[0] != "t4_a" {
+		t.Errorf("got unbans %v and replies %v", bot.unbans, bot.replies)
+	}
+}
+
+func TestOpenPrivateMessage(t *testing.T) {
+	if _, err := Open(&modLogBot{}, &reddit.Message{Author: "user"}); err != notModmailErr {
+		t.Errorf("got %v; wanted notModmailErr", err)
+	}
+}
@@ -0,0 +1,101 @@
+// Package archive records graw events as JSON lines and reads them back, so
+// handlers can be run again over historical traffic with graw.Replay.
+package archive
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// Event kinds, named after the botfaces handler method each event is
+// dispatched to.
+const (
+	PostKind         = "post"
+	CommentKind      = "comment"
+	UserPostKind     = "user_post"
+	UserCommentKind  = "user_comment"
+	PostReplyKind    = "post_reply"
+	CommentReplyKind = "comment_reply"
+	MentionKind      = "mention"
+	MessageKind      = "message"
+)
+
+// Event is one archived event: a Reddit element and the handler it was
+// dispatched to. Exactly one of Post, Comment, and Message is set.
+type Event struct {
+	// Kind names the handler the event is dispatched to.
+	Kind string `json:"kind"`
+	// Time is when the event was received. If zero, the creation time
+	// of the element is used.
+	Time    time.Time       `json:"time"`
+	Post    *reddit.Post    `json:"post,omitempty"`
+	Comment *reddit.Comment `json:"comment,omitempty"`
+	Message *reddit.Message `json:"message,omitempty"`
+}
+
+// When returns the time the event happened.
+func (e Event) When() time.Time {
+	if !e.Time.IsZero() {
+		return e.Time
+	}
+
+	switch {
+	case e.Post != nil:
+		return time.Unix(int64(e.Post.CreatedUTC), 0)
+	case e.Comment != nil:
+		return time.Unix(int64(e.Comment.CreatedUTC), 0)
+	case e.Message != nil:
+		return time.Unix(int64(e.Message.CreatedUTC), 0)
+	}
+	return time.Time{}
+}
+
+// Writer is the archiver sink: it appends events to an archive.
+type Writer interface {
+	// Write appends an event to the archive. It is safe to call from
+	// multiple goroutines.
+	Write(e Event) error
+}
+
+// Reader reads events from an archive in the order they were written.
+type Reader interface {
+	// Read returns the next event in the archive, or io.EOF once the
+	// archive is exhausted.
+	Read() (Event, error)
+}
+
+type writer struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewWriter returns a Writer which writes events to w, one JSON object per
+// line.
+func NewWriter(w io.Writer) Writer {
+	return &writer{enc: json.NewEncoder(w)}
+}
+
+func (w *writer) Write(e Event) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.enc.Encode(e)
+}
+
+type reader struct {
+	dec *json.Decoder
+}
+
+// NewReader returns a Reader of the JSON lines archive in r.
+func NewReader(r io.Reader) Reader {
+	return &reader{dec: json.NewDecoder(r)}
+}
+
+func (r *reader) Read() (Event, error) {
+	var e Event
+	err := r.dec.Decode(&e)
+	return e, err
+}
@@ -0,0 +1,66 @@
+package archive
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+func TestRoundTrip(t *testing.T) {
+	events := []Event{
+		{
+			Kind: PostKind,
+			Time: time.Unix(100, 0).UTC(),
+			Post: &reddit.Post{Name: "t3_a", Title: "title"},
+		},
+		{
+			Kind:    MentionKind,
+			Message: &reddit.Message{Name: "t1_b", Body: "body"},
+		},
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	for _, e := range events {
+		if err := w.Write(e); err != nil {
+			t.Fatalf("error writing event: %v", err)
+		}
+	}
+
+	r := NewReader(&buf)
+	for i, want := range events {
+		got, err := r.Read()
+		if err != nil {
+			t.Fatalf("%d: error reading event: %v", i, err)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%d: got %+v; wanted %+v", i, got, want)
+		}
+	}
+
+	if _, err := r.Read(); err != io.EOF {
+		t.Errorf("wanted io.EOF at end of archive; got %v", err)
+	}
+}
+
+func TestWhen(t *testing.T) {
+	for i, test := range []struct {
+		e    Event
+		when time.Time
+	}{
+		{Event{Time: time.Unix(5, 0)}, time.Unix(5, 0)},
+		{Event{Post: &reddit.Post{CreatedUTC: 6}}, time.Unix(6, 0)},
+		{Event{Comment: &reddit.Comment{CreatedUTC: 7}}, time.Unix(7, 0)},
+		{Event{Message: &reddit.Message{CreatedUTC: 8}}, time.Unix(8, 0)},
+		{Event{}, time.Time{}},
+	} {
+		if when := test.e.When(); !when.Equal(test.when) {
+			t.Errorf("%d: got %v; wanted %v", i, when, test.when)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// Formats a thread archive can be written in.
+const (
+	// JSONFormat writes the thread as one ThreadArchive JSON object.
+	JSONFormat = "json"
+	// HTMLFormat writes the thread as a page which needs nothing else to
+	// be read in a browser.
+	HTMLFormat = "html"
+)
+
+var formatErr = fmt.Errorf("the thread archive format is neither json nor html")
+
+// threadLimit is the most comments asked for with the thread, which is as
+// many as Reddit gives in one response.
+const threadLimit = 500
+
+// ThreadConfig configures the archiving of a thread.
+type ThreadConfig struct {
+	// Format is JSONFormat or HTMLFormat. If empty, the thread is
+	// written as JSON.
+	Format string
+	// MaxRequests is the most "more comments" stubs expanded. If zero,
+	// every stub is expanded. Stubs left unexpanded are counted in the
+	// archive's Unexpanded.
+	MaxRequests int
+	// Progress, if set, is called after each stub is expanded, so slow
+	// archives of huge threads can be followed.
+	Progress func(ThreadProgress)
+}
+
+// ThreadProgress reports how far the archiving of a thread has come.
+type ThreadProgress struct {
+	// Requests counts the stubs expanded so far.
+	Requests int
+	// Comments counts the comments in the tree so far.
+	Comments int
+	// Stubs counts the stubs left in the tree to expand.
+	Stubs int
+}
+
+// ThreadArchive is the archive of a thread: its post, with the expanded
+// comment tree in the post's Replies, and the urls of the post's media.
+type ThreadArchive struct {
+	// ArchivedAt is when the thread was fetched.
+	ArchivedAt time.Time    `json:"archived_at"`
+	Permalink  string       `json:"permalink"`
+	Post       *reddit.Post `json:"post"`
+	// Comments counts the comments in the tree.
+	Comments int `json:"comments"`
+	// Unexpanded counts the comments of stubs left in the tree, which
+	// are missing from the archive.
+	Unexpanded int      `json:"unexpanded"`
+	Media      []string `json:"media"`
+}
+
+// Thread writes an archive of the thread at the permalink to w, for bots
+// which preserve threads before they are edited or removed. The comment tree
+// is fetched in the order it was written and every stub in it is expanded
+// before anything is written, so the archive holds one whole snapshot of the
+// thread rather than the parts of it which fit in a response.
+func Thread(
+	lurker reddit.Lurker,
+	permalink string,
+	w io.Writer,
+	c ThreadConfig,
+) error {
+	format := c.Format
+	if format == "" {
+		format = JSONFormat
+	}
+	if format != JSONFormat && format != HTMLFormat {
+		return formatErr
+	}
+
+	archivedAt := time.Now().UTC()
+	thread, err := lurker.ThreadWithOptions(
+		permalink,
+		reddit.ThreadOptions{Sort: reddit.SortOld, Limit: threadLimit},
+	)
+	if err != nil {
+		return err
+	}
+
+	// A stub which comes back from its expansion, as Reddit sometimes
+	// returns them, is not asked for again.
+	tried := make(map[*reddit.More]bool)
+	requests := 0
+	for c.MaxRequests <= 0 || requests < c.MaxRequests {
+		more := nextStub(thread, tried)
+		if more == nil {
+			break
+		}
+		tried[more] = true
+
+		if _, err := lurker.ExpandMore(thread, more); err != nil {
+			return err
+		}
+		requests++
+
+		if c.Progress != nil {
+			comments, stubs, _ := count(thread)
+			c.Progress(ThreadProgress{
+				Requests: requests,
+				Comments: comments,
+				Stubs:    stubs,
+			})
+		}
+	}
+
+	comments, _, unexpanded := count(thread)
+	a := ThreadArchive{
+		ArchivedAt: archivedAt,
+		Permalink:  permalink,
+		Post:       thread,
+		Comments:   comments,
+		Unexpanded: unexpanded,
+		Media:      thread.MediaURLs(),
+	}
+
+	if format == HTMLFormat {
+		return threadPage.Execute(w, a)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(a)
+}
+
+// nextStub returns the first stub in the thread's comment tree which has not
+// been tried, or nil if there are none.
+func nextStub(thread *reddit.Post, tried map[*reddit.More]bool) *reddit.More {
+	if thread.More != nil && !tried[thread.More] {
+		return thread.More
+	}
+
+	var walk func(comments []*reddit.Comment) *reddit.More
+	walk = func(comments []*reddit.Comment) *reddit.More {
+		for _, comment := range comments {
+			if comment.More != nil && !tried[comment.More] {
+				return comment.More
+			}
+			if more := walk(comment.Replies); more != nil {
+				return more
+			}
+		}
+		return nil
+	}
+	return walk(thread.Replies)
+}
+
+// count returns the number of comments and stubs in the thread's comment
+// tree, and the number of comments the stubs stand for.
+func count(thread *reddit.Post) (comments, stubs, missing int) {
+	stub := func(more *reddit.More) {
+		if more != nil {
+			stubs++
+			missing += len(more.Children)
+		}
+	}
+
+	var walk func(cs []*reddit.Comment)
+	walk = func(cs []*reddit.Comment) {
+		for _, comment := range cs {
+			comments++
+			stub(comment.More)
+			walk(comment.Replies)
+		}
+	}
+
+	stub(thread.More)
+	walk(thread.Replies)
+	return comments, stubs, missing
+}
+
+var threadPage = template.Must(template.New("thread").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Post.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 50em; margin: auto; }
+.body { white-space: pre-wrap; }
+.comment { border-left: 2px solid #ccc; margin: 0.5em 0; padding-left: 1em; }
+.meta { color: #666; font-size: small; }
+</style>
+</head>
+<body>
+<h1>{{.Post.Title}}</h1>
+<p class="meta">/r/{{.Post.Subreddit}} by {{.Post.Author}}, {{.Post.Score}} points,
+archived {{.ArchivedAt.Format "2006-01-02 15:04:05 MST"}} from {{.Permalink}}</p>
+{{if .Post.IsSelf}}<div class="body">{{.Post.SelfText}}</div>{{else}}<p><a href="{{.Post.URL}}">{{.Post.URL}}</a></p>{{end}}
+{{if .Media}}<ul class="media">{{range .Media}}<li><a href="{{.}}">{{.}}</a></li>{{end}}</ul>{{end}}
+<h2>{{.Comments}} comments{{if .Unexpanded}} ({{.Unexpanded}} not archived){{end}}</h2>
+{{template "comments" .Post.Replies}}
+</body>
+</html>
+{{define "comments"}}{{range .}}<div class="comment" id="{{.Name}}">
+<p class="meta">{{.Author}}, {{.Score}} points</p>
+<div class="body">{{.Body}}</div>
+{{template "comments" .Replies}}</div>
+{{end}}{{end}}`))
@@ -0,0 +1,104 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// stubLurker serves a thread whose stubs each expand into one comment.
+type stubLurker struct {
+	reddit.Lurker
+	opts     reddit.ThreadOptions
+	expanded []string
+}
+
+func (l *stubLurker) ThreadWithOptions(
+	permalink string,
+	opts reddit.ThreadOptions,
+) (*reddit.Post, error) {
+	l.opts = opts
+	return &reddit.Post{
+		Title:               "title <b>",
+		Permalink:           permalink,
+		URL:                 "https://i.redd.it/a.png",
+		IsRedditMediaDomain: true,
+		Replies: []*reddit.Comment{
+			{
+				Name: "t1_a",
+				Body: "first",
+				More: &reddit.More{Name: "t1_m1", ParentID: "t1_a", Children: []string{"b"}},
+			},
+		},
+		More: &reddit.More{Name: "t1_m2", ParentID: "t3_p", Children: []string{"c", "d"}},
+	}, nil
+}
+
+func (l *stubLurker) ExpandMore(thread *reddit.Post, more *reddit.More) ([]*reddit.Comment, error) {
+	l.expanded = append(l.expanded, more.Name)
+	c := &reddit.Comment{Name: "t1_" + more.Children[0], Body: "expanded"}
+	if thread.More == more {
+		thread.More = nil
+		thread.Replies = append(thread.Replies, c)
+	} else {
+		thread.Replies[0].More = nil
+		thread.Replies[0].Replies = append(thread.Replies[0].Replies, c)
+	}
+	return []*reddit.Comment{c}, nil
+}
+
+func TestThread(t *testing.T) {
+	l := &stubLurker{}
+	var progress []ThreadProgress
+	var buf bytes.Buffer
+	if err := Thread(l, "/r/a/comments/p", &buf, ThreadConfig{
+		Progress: func(p ThreadProgress) { progress = append(progress, p) },
+	}); err != nil {
+		t.Fatalf("failed to archive thread: %v", err)
+	}
+
+	if l.opts.Sort != reddit.SortOld {
+		t.Errorf("fetched the thread sorted by %q", l.opts.Sort)
+	}
+	if len(l.expanded) != 2 || l.expanded[0] != "t1_m2" {
+		t.Errorf("expanded %v", l.expanded)
+	}
+	if len(progress) != 2 || progress[1] != (ThreadProgress{Requests: 2, Comments: 3}) {
+		t.Errorf("reported progress %+v", progress)
+	}
+
+	var a ThreadArchive
+	if err := json.Unmarshal(buf.Bytes(), &a); err != nil {
+		t.Fatalf("bad archive: %v", err)
+	}
+	if a.Comments != 3 || a.Unexpanded != 0 || len(a.Post.Replies) != 2 {
+		t.Errorf("archived %d comments, %d unexpanded", a.Comments, a.Unexpanded)
+	}
+	if len(a.Media) != 1 || a.Media[0] != "https://i.redd.it/a.png" {
+		t.Errorf("archived media %v", a.Media)
+	}
+}
+
+func TestThreadHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Thread(&stubLurker{}, "/r/a/comments/p", &buf, ThreadConfig{
+		Format:      HTMLFormat,
+		MaxRequests: 1,
+	}); err != nil {
+		t.Fatalf("failed to archive thread: %v", err)
+	}
+
+	page := buf.String()
+	for _, want := range []string{
+		"title &lt;b&gt;",
+		`id="t1_c"`,
+		"(1 not archived)",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("archive page lacks %q:\n%s", want, page)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+// Package blocklist keeps an account's block list and subreddits' ban lists in
+// step with a maintained list of usernames, such as a list of ban evaders
+// shared between subreddits:
+//
+//	users := blocklist.Parse(page.Content)
+//	result, err := blocklist.Sync(bot, blocklist.Config{
+//		Name:       "evaders",
+//		Users:      users,
+//		Subreddits: []string{"golang", "rust"},
+//		Store:      s,
+//	})
+//
+// Each sync reads the current lists and applies only the differences.
+package blocklist
+
+import (
+	"strings"
+
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/store"
+)
+
+// Config configures a sync.
+type Config struct {
+	// Name names the list, so several lists can share a store.
+	Name string
+	// Users are the names on the list.
+	Users []string
+	// If Block is set, the users are blocked by the bot's account.
+	Block bool
+	// The users are banned from each subreddit named here, with Ban.
+	Subreddits []string
+	Ban        reddit.Ban
+	// If set, the blocks and bans each sync applies are recorded here, and
+	// later syncs lift those of users who left the list. Without a store,
+	// syncs only add. Blocks and bans not applied by a sync of the list
+	// are never lifted.
+	Store store.Store
+}
+
+// Changes are the users added to and removed from a block or ban list.
+type Changes struct {
+	Added   []string
+	Removed []string
+}
+
+// Result is what a sync changed.
+type Result struct {
+	Blocks Changes
+	// Bans are the changes to each subreddit's ban list.
+	Bans map[string]Changes
+}
+
+// applied is what syncs of a list applied, by the lowercased names of the
+// users.
+type applied struct {
+	Blocked map[string]bool            `json:"blocked"`
+	Banned  map[string]map[string]bool `json:"banned"`
+}
+
+// Parse reads a list of usernames, one or more to a line separated by spaces
+// or commas, as blocklists are kept on wiki pages. Lines starting with # are
+// comments, and /u/ and u/ prefixes are dropped.
+func Parse(text string) []string {
+	var users []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		for _, field := range strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		}) {
+			field = strings.TrimPrefix(field, "/")
+			field = strings.TrimPrefix(field, "u/")
+			if field != "" {
+				users = append(users, field)
+			}
+		}
+	}
+	return users
+}
+
+// Diff compares a current list of usernames with the wanted one, returning
+// the users to add and those to remove. Usernames are compared without case.
+func Diff(current, want []string) (add, remove []string) {
+	have := names(current)
+	wanted := names(want)
+
+	for _, user := range want {
+		if key := strings.ToLower(user); !have[key] {
+			add = append(add, user)
+			have[key] = true
+		}
+	}
+	for _, user := range current {
+		if key := strings.ToLower(user); !wanted[key] {
+			remove = append(remove, user)
+			wanted[key] = true
+		}
+	}
+	return add, remove
+}
+
+func names(users []string) map[string]bool {
+	set := make(map[string]bool, len(users))
+	for _, user := range users {
+		set[strings.ToLower(user)] = true
+	}
+	return set
+}
+
+// Sync applies the list to the bot's block list and the subreddits' ban
+// lists. Reading each list costs a request, and each change another. If a
+// change fails, Sync stops and returns what it changed before the error.
+func Sync(bot reddit.Bot, c Config) (Result, error) {
+	state := applied{
+		Blocked: make(map[string]bool),
+		Banned:  make(map[string]map[string]bool),
+	}
+	if c.Store != nil {
+		err := c.Store.Load(stateKey(c.Name), &state)
+		if err != nil && err != store.NotFoundErr {
+			return Result{}, err
+		}
+	}
+
+	result, err := sync(bot, c, &state)
+	if c.Store != nil {
+		if saveErr := c.Store.Save(stateKey(c.Name), state); err == nil {
+			err = saveErr
+		}
+	}
+	return result, err
+}
+
+func sync(bot reddit.Bot, c Config, state *applied) (Result, error) {
+	result := Result{Bans: make(map[string]Changes)}
+
+	if c.Block {
+		current, err := bot.BlockedUsers()
+		if err != nil {
+			return result, err
+		}
+
+		result.Blocks, err = apply(
+			current, c.Users, state.Blocked,
+			bot.Block, bot.Unblock,
+		)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	for _, sub := range c.Subreddits {
+		current, err := bot.BannedUsers(sub)
+		if err != nil {
+			return result, err
+		}
+
+		key := strings.ToLower(sub)
+		if state.Banned[key] == nil {
+			state.Banned[key] = make(map[string]bool)
+		}
+
+		changes, err := apply(
+			current, c.Users, state.Banned[key],
+			func(user string) error { return bot.BanUser(sub, user, c.Ban) },
+			func(user string) error { return bot.Unban(sub, user) },
+		)
+		result.Bans[sub] = changes
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// apply adds the wanted users missing from the current list, and removes the
+// users the list applied who are no longer wanted, keeping its record of them.
+func apply(
+	current, want []string,
+	ours map[string]bool,
+	add, remove func(user string) error,
+) (Changes, error) {
+	var changes Changes
+	additions, removals := Diff(current, want)
+
+	// Users whose block or ban was lifted some other way are no longer
+	// the list's to lift.
+	present := names(current)
+	for user := range ours {
+		if !present[user] {
+			delete(ours, user)
+		}
+	}
+
+	for _, user := range additions {
+		if err := add(user); err != nil {
+			return changes, err
+		}
+		ours[strings.ToLower(user)] = true
+		changes.Added = append(changes.Added, user)
+	}
+
+	for _, user := range removals {
+		key := strings.ToLower(user)
+		if !ours[key] {
+			continue
+		}
+
+		if err := remove(user); err != nil {
+			return changes, err
+		}
+		delete(ours, key)
+		changes.Removed = append(changes.Removed, user)
+	}
+
+	return changes, nil
+}
+
+// stateKey is the key what syncs of the named list applied is stored under.
+func stateKey(name string) string {
+	if name == "" {
+		return "blocklist"
+	}
+	return "blocklist-" + name
+}
@@ -0,0 +1,107 @@
+package blocklist
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/store"
+)
+
+type listBot struct {
+	reddit.Bot
+	blocked []string
+	banned  map[string][]string
+	calls   []string
+}
+
+func without(users []string, user string) []string {
+	var kept []string
+	for _, u := range users {
+		if u != user {
+			kept = append(kept, u)
+		}
+	}
+	return kept
+}
+
+func (l *listBot) BlockedUsers() ([]string, error) { return l.blocked, nil }
+
+func (l *listBot) Block(user string) error {
+	l.calls = append(l.calls, "block "+user)
+	l.blocked = append(l.blocked, user)
+	return nil
+}
+
+func (l *listBot) Unblock(user string) error {
+	l.calls = append(l.calls, "unblock "+user)
+	l.blocked = without(l.blocked, user)
+	return nil
+}
+
+func (l *listBot) BannedUsers(sub string) ([]string, error) { return l.banned[sub], nil }
+
+func (l *listBot) BanUser(sub, user string, ban reddit.Ban) error {
+	l.calls = append(l.calls, "ban "+sub+" "+user+" "+ban.Reason)
+	l.banned[sub] = append(l.banned[sub], user)
+	return nil
+}
+
+func (l *listBot) Unban(sub, user string) error {
+	l.calls = append(l.calls, "unban "+sub+" "+user)
+	l.banned[sub] = without(l.banned[sub], user)
+	return nil
+}
+
+func TestSync(t *testing.T) {
+	bot := &listBot{
+		blocked: []string{"spammer"},
+		banned:  map[string][]string{"golang": {"Evader", "troll"}},
+	}
+	c := Config{
+		Name:       "evaders",
+		Users:      []string{"evader", "sock"},
+		Block:      true,
+		Subreddits: []string{"golang"},
+		Ban:        reddit.Ban{Reason: "evasion"},
+		Store:      store.NewMemoryStore(),
+	}
+
+	result, err := Sync(bot, c)
+	if err != nil {
+		t.Fatalf("failed to sync: %v", err)
+	}
+
+	if want := []string{
+		"block evader",
+		"block sock",
+		"ban golang sock evasion",
+	}; !reflect.DeepEqual(bot.calls, want) {
+		t.Errorf("got calls %v; wanted %v", bot.calls, want)
+	}
+	if !reflect.DeepEqual(result.Bans["golang"].Added, []string{"sock"}) {
+		t.Errorf("got ban changes %+v", result.Bans)
+	}
+
+	// Only what the list applied is lifted when users leave it.
+	bot.calls = nil
+	c.Users = nil
+	if _, err := Sync(bot, c); err != nil {
+		t.Fatalf("failed to sync: %v", err)
+	}
+
+	if want := []string{
+		"unblock evader",
+		"unblock sock",
+		"unban golang sock",
+	}; !reflect.DeepEqual(bot.calls, want) {
+		t.Errorf("got calls %v; wanted %v", bot.calls, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	text := "# shared evaders\n/u/alice, u/bob\ncarol\n\n"
+	if got := Parse(text); !reflect.DeepEqual(got, []string{"alice", "bob", "carol"}) {
+		t.Errorf("got %v", got)
+	}
+}
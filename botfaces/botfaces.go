@@ -3,7 +3,10 @@
 package botfaces
 
 import (
-	"github.com/turnage/graw/reddit"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/reputation"
+	"github.com/aldarisbm/graw/schedule"
+	"github.com/aldarisbm/graw/streams"
 )
 
 // Loader defines methods for bots that use external resources or need to do
@@ -104,3 +107,144 @@ type UserHandler interface {
 	// subreddit the bot can view. [Called as goroutine.]
 	UserComment(comment *reddit.Comment) error
 }
+
+// AccountSnapshotHandler defines methods for bots that track how their own
+// account is received over time.
+type AccountSnapshotHandler interface {
+	// AccountSnapshot is called periodically with the bot account's karma,
+	// follower count, and recent removal rate. Bots can record these to
+	// their metrics or audit logs. [Called as goroutine.]
+	AccountSnapshot(snapshot *streams.AccountSnapshot) error
+}
+
+// CrossingHandler defines methods for bots that watch for posts and comments
+// growing popular.
+type CrossingHandler interface {
+	// Crossing is called when a tracked post or comment crosses one of the
+	// configured score or comment count thresholds. [Called as goroutine.]
+	Crossing(crossing *streams.Crossing) error
+}
+
+// TrendHandler defines methods for bots that alert on terms suddenly
+// appearing far more often than usual.
+type TrendHandler interface {
+	// Trend is called when a term spikes in the posts and comments of the
+	// monitored subreddits. [Called as goroutine.]
+	Trend(trend *streams.Trend) error
+}
+
+// VolumeHandler defines methods for bots that watch for brigades and spam
+// waves in the subreddits they monitor.
+type VolumeHandler interface {
+	// VolumeAnomaly is called when a monitored subreddit receives far more
+	// or far fewer posts than usual. [Called as goroutine.]
+	VolumeAnomaly(anomaly *streams.VolumeAnomaly) error
+}
+
+// WatchedUserHandler defines methods for bots that alert mods to the activity
+// of users on a watchlist.
+type WatchedUserHandler interface {
+	// WatchedUser is called when a watched user posts or comments where
+	// the bot can see it. [Called as goroutine.]
+	WatchedUser(activity *streams.WatchedActivity) error
+}
+
+// LinkPostHandler defines methods for bots that police the domains linked in
+// subreddits they monitor.
+type LinkPostHandler interface {
+	// LinkPost is called with each new link post in a monitored subreddit
+	// and the reputation of the domain it links to, counting the post.
+	// [Called as goroutine.]
+	LinkPost(post *reputation.LinkPost) error
+}
+
+// ReportHandler defines methods for bots that alert moderators to reported
+// posts and comments.
+type ReportHandler interface {
+	// Reported is called once when a post or comment in a moderated
+	// subreddit gathers enough reports, with all of their reasons and
+	// counts. [Called as goroutine.]
+	Reported(reported *streams.Reported) error
+}
+
+// ModqueueHandler defines methods for bots that review the moderation queue.
+type ModqueueHandler interface {
+	// Queued is called once when a post or comment enters the
+	// moderation queue of a moderated subreddit. [Called as goroutine.]
+	Queued(queued *streams.Queued) error
+}
+
+// SpamHandler defines methods for bots that review what the spam filter
+// removes.
+type SpamHandler interface {
+	// Spam is called once when a post or comment in a moderated
+	// subreddit is removed as spam. [Called as goroutine.]
+	Spam(queued *streams.Queued) error
+}
+
+// EditedHandler defines methods for bots that review edits, e.g. to catch
+// posts changed after they were approved.
+type EditedHandler interface {
+	// Edited is called when a post or comment in a moderated subreddit
+	// is edited. [Called as goroutine.]
+	Edited(queued *streams.Queued) error
+}
+
+// ModmailHandler defines methods for bots that answer the new modmail of the
+// subreddits they moderate.
+type ModmailHandler interface {
+	// Modmail is called when a user writes in a modmail conversation,
+	// with their newest message. [Called as goroutine.]
+	Modmail(conversation *reddit.ModmailConversation) error
+}
+
+// LiveThreadHandler defines methods for bots that follow Reddit live threads,
+// e.g. to relay a match's updates.
+type LiveThreadHandler interface {
+	// LiveEvent is called with each event of a followed live thread, such
+	// as a new update or a change of its viewer count. [Called as
+	// goroutine.]
+	LiveEvent(event *reddit.LiveEvent) error
+}
+
+// RisingHandler defines methods for bots that detect posts early, as they
+// start rising.
+type RisingHandler interface {
+	// Rising is called when a post first appears in the rising listing
+	// of a subreddit. [Called as goroutine.]
+	Rising(post *streams.RisingPost) error
+}
+
+// GildedHandler defines methods for bots that highlight awarded posts and
+// comments.
+type GildedHandler interface {
+	// Gilded is called when a post or comment first appears in the
+	// gilded listing of a subreddit. [Called as goroutine.]
+	Gilded(awarded *streams.Awarded) error
+}
+
+// SearchResultHandler defines methods for bots that monitor saved searches,
+// e.g. for mentions of a brand or keyword.
+type SearchResultHandler interface {
+	// SearchResult is called when a post first matches a saved search.
+	// [Called as goroutine.]
+	SearchResult(result *streams.SearchResult) error
+}
+
+// SearchDigestHandler defines methods for bots that take saved search results
+// in batches.
+type SearchDigestHandler interface {
+	// SearchDigest is called with the saved search results gathered over
+	// a digest interval. [Called as goroutine.]
+	SearchDigest(digest *streams.SearchDigest) error
+}
+
+// DeferredHandler defines methods for bots that defer events to handle them
+// later, e.g. reminder bots.
+type DeferredHandler interface {
+	// Deferred is called when an event deferred with the Config's
+	// Scheduler comes due. Bots which do not implement it have the event
+	// dispatched to its usual handler method instead. [Called as
+	// goroutine.]
+	Deferred(d *schedule.Deferred) error
+}
@@ -0,0 +1,215 @@
+// Package chaos injects the faults Reddit is known for, such as rate limits,
+// gateway errors, timeouts, and truncated responses, into a bot's requests, so
+// tests and staging runs can verify the bot survives Reddit's bad days.
+//
+// Wrap a bot's client with it,
+//
+//	reddit.BotConfig{..., WrapClient: chaos.Wrap(chaos.Config{ServerErrors: 0.1})}
+//
+// or wrap the transport of an http.Client pointed at a fake server with
+// Transport, to inject the faults as real HTTP responses.
+package chaos
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// defaultTimeout is how long a timed out request hangs if the config does not
+// say.
+const defaultTimeout = 30 * time.Second
+
+// Config configures the faults injected. Each rate is the fraction of requests,
+// from 0 to 1, which fail that way; at most one fault is injected per request.
+type Config struct {
+	// RateLimits is the rate of 429 Too Many Requests responses.
+	RateLimits float64
+	// ServerErrors is the rate of 502, 503, and 504 responses, in equal
+	// parts.
+	ServerErrors float64
+	// Timeouts is the rate of requests which hang for Timeout, 30 seconds
+	// if zero, and then fail with a timeout.
+	Timeouts float64
+	Timeout  time.Duration
+	// Malformed is the rate of responses whose body is cut off halfway,
+	// so it is not valid JSON.
+	Malformed float64
+	// Seed seeds the choice of faults, so a failing run can be repeated.
+	// If zero, a seed is picked from the time.
+	Seed int64
+	// Clock times the timeouts. If nil, the wall clock is used.
+	Clock clock.Clock
+}
+
+// fault is a fault injected into a request.
+type fault int
+
+const (
+	none fault = iota
+	rateLimit
+	badGateway
+	busy
+	gatewayTimeout
+	timeout
+	malformed
+)
+
+// statuses are the status codes of the faults which are responses.
+var statuses = map[fault]int{
+	rateLimit:      http.StatusTooManyRequests,
+	badGateway:     http.StatusBadGateway,
+	busy:           http.StatusServiceUnavailable,
+	gatewayTimeout: http.StatusGatewayTimeout,
+}
+
+// errs are the errors a reddit.Doer returns for the faults which are
+// responses.
+var errs = map[fault]error{
+	rateLimit:      reddit.RateLimitErr,
+	badGateway:     reddit.GatewayErr,
+	busy:           reddit.BusyErr,
+	gatewayTimeout: reddit.GatewayTimeoutErr,
+}
+
+// timeoutError is the error of a timed out request. Like the errors of the net
+// package, it reports itself as a timeout.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "chaos: request timed out" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// injector picks the faults of a config.
+type injector struct {
+	cfg Config
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func newInjector(c Config) *injector {
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+
+	if c.Clock == nil {
+		c.Clock = clock.Real()
+	}
+
+	if c.Seed == 0 {
+		c.Seed = time.Now().UnixNano()
+	}
+
+	return &injector{cfg: c, rng: rand.New(rand.NewSource(c.Seed))}
+}
+
+// next picks the fault to inject into the next request.
+func (i *injector) next() fault {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	roll := i.rng.Float64()
+	if roll -= i.cfg.RateLimits; roll < 0 {
+		return rateLimit
+	}
+	if roll -= i.cfg.ServerErrors; roll < 0 {
+		return []fault{badGateway, busy, gatewayTimeout}[i.rng.Intn(3)]
+	}
+	if roll -= i.cfg.Timeouts; roll < 0 {
+		return timeout
+	}
+	if roll -= i.cfg.Malformed; roll < 0 {
+		return malformed
+	}
+	return none
+}
+
+// Wrap returns a function which wraps a reddit.Doer with faults, for the
+// WrapClient field of reddit.BotConfig and reddit.ScriptConfig.
+func Wrap(c Config) func(reddit.Doer) reddit.Doer {
+	i := newInjector(c)
+	return func(d reddit.Doer) reddit.Doer {
+		return &doer{Doer: d, i: i}
+	}
+}
+
+type doer struct {
+	reddit.Doer
+	i *injector
+}
+
+func (d *doer) Do(req *http.Request) ([]byte, error) {
+	f := d.i.next()
+	switch f {
+	case none:
+		return d.Doer.Do(req)
+	case timeout:
+		d.i.cfg.Clock.Sleep(d.i.cfg.Timeout)
+		return nil, timeoutError{}
+	case malformed:
+		body, err := d.Doer.Do(req)
+		return body[:len(body)/2], err
+	}
+
+	return nil, errs[f]
+}
+
+// Transport returns an http.RoundTripper which injects faults into the
+// responses of rt, or of http.DefaultTransport if rt is nil.
+func Transport(c Config, rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	return &transport{rt: rt, i: newInjector(c)}
+}
+
+type transport struct {
+	rt http.RoundTripper
+	i  *injector
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f := t.i.next()
+	switch f {
+	case none:
+		return t.rt.RoundTrip(req)
+	case timeout:
+		t.i.cfg.Clock.Sleep(t.i.cfg.Timeout)
+		return nil, timeoutError{}
+	case malformed:
+		resp, err := t.rt.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		body = body[:len(body)/2]
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		resp.ContentLength = int64(len(body))
+		resp.Header.Del("Content-Length")
+		return resp, nil
+	}
+
+	return &http.Response{
+		Status:     http.StatusText(statuses[f]),
+		StatusCode: statuses[f],
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,108 @@
+package chaos
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+)
+
+type bodyDoer struct{}
+
+func (bodyDoer) Do(req *http.Request) ([]byte, error) {
+	return []byte(`{"kind": "Listing"}`), nil
+}
+
+func TestWrap(t *testing.T) {
+	sim := clock.NewSimulation(time.Unix(0, 0))
+	for _, test := range []struct {
+		cfg  Config
+		errs []error
+	}{
+		{Config{RateLimits: 1}, []error{reddit.RateLimitErr}},
+		{
+			Config{ServerErrors: 1},
+			[]error{reddit.GatewayErr, reddit.BusyErr, reddit.GatewayTimeoutErr},
+		},
+		{Config{Timeouts: 1, Clock: sim}, []error{timeoutError{}}},
+	} {
+		test.cfg.Seed = 1
+		d := Wrap(test.cfg)(bodyDoer{})
+		_, err := d.Do(&http.Request{})
+		found := false
+		for _, wanted := range test.errs {
+			found = found || err == wanted
+		}
+		if !found {
+			t.Errorf("%+v: got error %v; wanted one of %v", test.cfg, err, test.errs)
+		}
+	}
+
+	if now := sim.Now(); now != time.Unix(0, 0).Add(defaultTimeout) {
+		t.Errorf("the timeout ended at %v; wanted it to hang %v", now, defaultTimeout)
+	}
+
+	body, err := Wrap(Config{Malformed: 1})(bodyDoer{}).Do(&http.Request{})
+	if err != nil || string(body) != `{"kind": ` {
+		t.Errorf("got %q, %v; wanted half the body", body, err)
+	}
+
+	body, err = Wrap(Config{})(bodyDoer{}).Do(&http.Request{})
+	if err != nil || string(body) != `{"kind": "Listing"}` {
+		t.Errorf("got %q, %v; wanted the untouched body", body, err)
+	}
+}
+
+func TestRates(t *testing.T) {
+	i := newInjector(Config{RateLimits: 0.2, ServerErrors: 0.3, Seed: 7})
+	counts := make(map[fault]int)
+	for n := 0; n < 10000; n++ {
+		counts[i.next()]++
+	}
+
+	rateLimits := counts[rateLimit]
+	serverErrors := counts[badGateway] + counts[busy] + counts[gatewayTimeout]
+	if rateLimits < 1800 || rateLimits > 2200 {
+		t.Errorf("got %d rate limits in 10000; wanted about 2000", rateLimits)
+	}
+	if serverErrors < 2800 || serverErrors > 3200 {
+		t.Errorf("got %d server errors in 10000; wanted about 3000", serverErrors)
+	}
+	if counts[timeout] != 0 || counts[malformed] != 0 {
+		t.Errorf("got faults which were not configured: %v", counts)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"kind": "Listing"}`))
+		},
+	))
+	defer server.Close()
+
+	client := &http.Client{Transport: Transport(Config{RateLimits: 1}, nil)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("got status %d; wanted 429", resp.StatusCode)
+	}
+
+	client = &http.Client{Transport: Transport(Config{Malformed: 1}, nil)}
+	resp, err = client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil || string(body) != `{"kind": ` {
+		t.Errorf("got %q, %v; wanted half the body", body, err)
+	}
+}
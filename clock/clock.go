@@ -0,0 +1,184 @@
+// Package clock abstracts the passage of time, so that rate limits,
+// schedules, and cooldowns can run on a simulated clock when bots are tested
+// against replayed traffic.
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock tells the time and waits on it.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After returns a channel which receives the time once d has passed.
+	After(d time.Duration) <-chan time.Time
+	// Sleep blocks until d has passed.
+	Sleep(d time.Duration)
+	// NewTimer returns a timer which fires once d has passed, unless it is
+	// stopped first.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer sends the time once on its channel when it fires. Unlike a channel
+// from After, it can be stopped, and reset to be waited on again.
+type Timer interface {
+	// C returns the channel the timer sends the time on.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing, reporting whether it had yet.
+	Stop() bool
+	// Reset makes the timer fire once d has passed from now, whether or not
+	// it had fired or was stopped, discarding any time it sent which was
+	// not received.
+	Reset(d time.Duration)
+}
+
+// Simulation is a Clock whose time only moves when it is told to. Sleeping
+// on it moves it forward immediately, as if the sleep had happened, so rate
+// limits and cooldowns cost no real time.
+type Simulation interface {
+	Clock
+	// AdvanceTo moves the clock forward to t, firing every timer due by
+	// then in order. The clock never moves backward; earlier times are
+	// ignored.
+	AdvanceTo(t time.Time)
+}
+
+type realClock struct{}
+
+// Real returns the wall clock.
+func Real() Clock {
+	return realClock{}
+}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+type realTimer struct {
+	t *time.Timer
+}
+
+func (r realTimer) C() <-chan time.Time { return r.t.C }
+func (r realTimer) Stop() bool          { return r.t.Stop() }
+
+func (r realTimer) Reset(d time.Duration) {
+	if !r.t.Stop() {
+		select {
+		case <-r.t.C:
+		default:
+		}
+	}
+	r.t.Reset(d)
+}
+
+type timer struct {
+	s        *simulation
+	deadline time.Time
+	c        chan time.Time
+}
+
+type simulation struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*timer
+}
+
+// NewSimulation returns a simulated clock which reads start until it is
+// advanced.
+func NewSimulation(start time.Time) Simulation {
+	return &simulation{now: start}
+}
+
+func (s *simulation) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.now
+}
+
+func (s *simulation) After(d time.Duration) <-chan time.Time {
+	return s.NewTimer(d).C()
+}
+
+func (s *simulation) NewTimer(d time.Duration) Timer {
+	t := &timer{s: s, c: make(chan time.Time, 1)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.start(t, d)
+	return t
+}
+
+// start sets the timer to fire once d has passed. The caller holds the lock.
+func (s *simulation) start(t *timer, d time.Duration) {
+	if d <= 0 {
+		t.c <- s.now
+		return
+	}
+
+	t.deadline = s.now.Add(d)
+	s.timers = append(s.timers, t)
+	sort.SliceStable(s.timers, func(i, j int) bool {
+		return s.timers[i].deadline.Before(s.timers[j].deadline)
+	})
+}
+
+// stop removes the timer from those waiting to fire, reporting whether it
+// was. The caller holds the lock.
+func (s *simulation) stop(t *timer) bool {
+	for i, waiting := range s.timers {
+		if waiting == t {
+			s.timers = append(s.timers[:i], s.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (t *timer) C() <-chan time.Time { return t.c }
+
+func (t *timer) Stop() bool {
+	t.s.mu.Lock()
+	defer t.s.mu.Unlock()
+	return t.s.stop(t)
+}
+
+func (t *timer) Reset(d time.Duration) {
+	t.s.mu.Lock()
+	defer t.s.mu.Unlock()
+
+	t.s.stop(t)
+	select {
+	case <-t.c:
+	default:
+	}
+	t.s.start(t, d)
+}
+
+func (s *simulation) Sleep(d time.Duration) {
+	s.AdvanceTo(s.Now().Add(d))
+}
+
+func (s *simulation) AdvanceTo(t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for len(s.timers) > 0 && !s.timers[0].deadline.After(t) {
+		next := s.timers[0]
+		s.timers = s.timers[1:]
+		if next.deadline.After(s.now) {
+			s.now = next.deadline
+		}
+		next.c <- s.now
+	}
+
+	if t.After(s.now) {
+		s.now = t
+	}
+}
@@ -0,0 +1,82 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSimulationTimers(t *testing.T) {
+	start := time.Unix(0, 0)
+	s := NewSimulation(start)
+
+	late := s.After(2 * time.Hour)
+	early := s.After(time.Hour)
+
+	s.AdvanceTo(start.Add(90 * time.Minute))
+	select {
+	case at := <-early:
+		if !at.Equal(start.Add(time.Hour)) {
+			t.Errorf("early timer fired at %v; wanted %v", at, start.Add(time.Hour))
+		}
+	default:
+		t.Errorf("early timer did not fire")
+	}
+
+	select {
+	case <-late:
+		t.Errorf("late timer fired early")
+	default:
+	}
+
+	s.AdvanceTo(start)
+	if now := s.Now(); !now.Equal(start.Add(90 * time.Minute)) {
+		t.Errorf("clock moved backward to %v", now)
+	}
+
+	s.Sleep(time.Hour)
+	select {
+	case <-late:
+	default:
+		t.Errorf("late timer did not fire after sleep")
+	}
+
+	if now := s.Now(); !now.Equal(start.Add(150 * time.Minute)) {
+		t.Errorf("got %v after sleep; wanted %v", now, start.Add(150*time.Minute))
+	}
+}
+
+func TestSimulationAfterNow(t *testing.T) {
+	s := NewSimulation(time.Unix(0, 0))
+	select {
+	case <-s.After(0):
+	default:
+		t.Errorf("timer for no time did not fire immediately")
+	}
+}
+
+func TestSimulationTimerReset(t *testing.T) {
+	start := time.Unix(0, 0)
+	s := NewSimulation(start)
+
+	timer := s.NewTimer(time.Hour)
+	if !timer.Stop() {
+		t.Errorf("stopping a waiting timer reported it had fired")
+	}
+	s.AdvanceTo(start.Add(2 * time.Hour))
+	select {
+	case <-timer.C():
+		t.Errorf("stopped timer fired")
+	default:
+	}
+
+	timer.Reset(time.Hour)
+	s.Sleep(time.Hour)
+	select {
+	case at := <-timer.C():
+		if !at.Equal(start.Add(3 * time.Hour)) {
+			t.Errorf("reset timer fired at %v; wanted %v", at, start.Add(3*time.Hour))
+		}
+	default:
+		t.Errorf("reset timer did not fire")
+	}
+}
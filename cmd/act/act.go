@@ -6,7 +6,7 @@ import (
 	"log"
 	"os"
 
-	"github.com/turnage/graw/reddit"
+	"github.com/aldarisbm/graw/reddit"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
@@ -32,6 +32,8 @@ var (
 	sendTo   = send.Arg("recipient", "User to send a message to.").Required().String()
 	sendSubj = send.Arg("subject", "Subject of the message.").Required().String()
 	sendBody = send.Arg("body", "Body of the message.").Required().String()
+
+	revoke = app.Command("revoke", "Revoke the account's OAuth2 tokens, e.g. after credentials leak.")
 )
 
 func bot(agentfile string) reddit.Bot {
@@ -63,5 +65,8 @@ func main() {
 	case send.FullCommand():
 		b := bot(*agent)
 		maybeFail(b.SendMessage(*sendTo, *sendSubj, *sendBody))
+	case revoke.FullCommand():
+		b := bot(*agent)
+		maybeFail(b.RevokeAll())
 	}
 }
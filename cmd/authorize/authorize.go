@@ -0,0 +1,72 @@
+// Package authorize is a utility for obtaining a refresh token for a Reddit
+// account and storing it in an agent file, using the reddit package.
+//
+// Register the redirect uri passed with --redirect on your app's settings page
+// on Reddit, then run
+//
+//	authorize --agent mybot.agent
+//
+// and open the printed url in a browser logged in as the bot's account. The
+// agent file of an installed app leaves client_secret empty.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/store"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	app      = kingpin.New("authorize", "A cli tool for authorizing a Reddit app to act as an account.")
+	agent    = app.Flag("agent", "Filename of the agent file holding the app's id and secret; the refresh token will be written here.").Required().String()
+	redirect = app.Flag("redirect", "Redirect uri registered with the app.").Default("http://localhost:8080/authorize_callback").String()
+	scopes   = app.Flag("scope", "OAuth2 scope to request; repeat for each. Defaults to reddit.BotScopes, every scope a reddit.Bot uses.").Strings()
+	pkce     = app.Flag("pkce", "Use a PKCE code challenge. Installed apps, which have no secret, always use one.").Bool()
+	keyFile  = app.Flag("key-file", "If set, the refresh token is encrypted with the key in this file; load the agent file with reddit.LoadSealedAgentFile.").String()
+)
+
+func main() {
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	userAgent, redditApp, err := reddit.LoadAgentFile(*agent)
+	if err != nil {
+		log.Fatalf("Failed to read agent file: %v\n", err)
+	}
+
+	refreshToken, err := reddit.Authorize(
+		reddit.AuthorizeConfig{
+			Agent:       userAgent,
+			App:         redditApp,
+			RedirectURL: *redirect,
+			Scopes:      *scopes,
+			PKCE:        *pkce,
+		},
+	)
+	if err != nil {
+		log.Fatalf("Failed to authorize: %v\n", err)
+	}
+
+	if err := saveRefreshToken(refreshToken); err != nil {
+		log.Fatalf("Failed to save refresh token: %v\n", err)
+	}
+
+	fmt.Printf("Saved refresh token to %s.\n", *agent)
+}
+
+func saveRefreshToken(refreshToken string) error {
+	if *keyFile == "" {
+		return reddit.SaveRefreshToken(*agent, refreshToken)
+	}
+
+	key, err := ioutil.ReadFile(*keyFile)
+	if err != nil {
+		return err
+	}
+
+	return reddit.SaveSealedRefreshToken(*agent, refreshToken, store.StaticKey(key))
+}
@@ -7,8 +7,9 @@ import (
 	"log"
 	"os"
 
-	"github.com/turnage/graw"
-	"github.com/turnage/graw/reddit"
+	"github.com/aldarisbm/graw"
+	"github.com/aldarisbm/graw/logging"
+	"github.com/aldarisbm/graw/reddit"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
@@ -116,7 +117,7 @@ func main() {
 		CommentReplies:    *commentreplies,
 		Messages:          *messages,
 		Mentions:          *mentions,
-		Logger:            log.New(os.Stderr, "", log.LstdFlags),
+		Logger:            logging.Std(log.New(os.Stderr, "", log.LstdFlags), logging.Debug),
 	}
 
 	var err error
@@ -0,0 +1,335 @@
+// Package command dispatches the commands users give a bot in comments and
+// messages, such as "!points u/someone", to the handlers registered for them.
+// Commands can require the user giving them to hold a user flair in the
+// subreddit, or an account in some standing, such as one with a verified
+// email, which the router looks up and caches, for tools only some of a
+// community may use:
+//
+//	router, err := command.New(bot, command.Config{
+//		Commands: []command.Command{
+//			{
+//				Name:       "award",
+//				FlairClass: "helper",
+//				Run: func(c command.Call) error {
+//					return bot.Reply(c.Name, "Awarded!")
+//				},
+//			},
+//		},
+//	})
+//	ok, err := router.Comment(comment)
+package command
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+)
+
+const (
+	// DefaultPrefix begins every command if the Config gives no prefix.
+	DefaultPrefix = "!"
+	// defaultCacheFor is how long a user's flair is trusted if the Config
+	// does not say.
+	defaultCacheFor = 10 * time.Minute
+)
+
+var (
+	noNameErr = fmt.Errorf("every command needs a name")
+	noRunErr  = fmt.Errorf("every command needs a Run function")
+)
+
+// Command is a command a Router dispatches.
+type Command struct {
+	// Name is the command's name, given after the prefix, e.g. "award".
+	// It is matched regardless of case.
+	Name string
+	// Flair, if set, is the user flair text the caller must hold in the
+	// subreddit, regardless of case.
+	Flair string
+	// FlairClass, if set, is a user flair CSS class the caller must hold
+	// in the subreddit. Flair with several classes holds each of them.
+	FlairClass string
+	// Requires is the standing the caller's account must have.
+	Requires Requirements
+	// Run handles a call of the command.
+	Run func(Call) error
+}
+
+// Requirements are the standing a command requires of its callers'
+// accounts. Accounts are looked up only for the requirements set.
+type Requirements struct {
+	// VerifiedEmail requires an account with a verified email.
+	VerifiedEmail bool
+	// Premium requires an account with Reddit Premium.
+	Premium bool
+	// Employee requires a Reddit employee's account.
+	Employee bool
+	// Moderator requires a moderator of the subreddit.
+	Moderator bool
+}
+
+// account reports whether the requirements need the caller's account.
+func (r Requirements) account() bool {
+	return r.VerifiedEmail || r.Premium || r.Employee
+}
+
+// meets reports whether an account meets the requirements on accounts.
+func (r Requirements) meets(a *reddit.Redditor) bool {
+	return (!r.VerifiedEmail || a.HasVerifiedEmail) &&
+		(!r.Premium || a.IsGold) &&
+		(!r.Employee || a.IsEmployee)
+}
+
+// gated reports whether the command requires a flair of its callers.
+func (c Command) gated() bool {
+	return c.Flair != "" || c.FlairClass != ""
+}
+
+// permits reports whether a flair meets the command's requirements.
+func (c Command) permits(f reddit.Flair) bool {
+	if c.Flair != "" && !strings.EqualFold(strings.TrimSpace(f.Text), c.Flair) {
+		return false
+	}
+	if c.FlairClass == "" {
+		return true
+	}
+	for _, class := range strings.Fields(f.CSSClass) {
+		if strings.EqualFold(class, c.FlairClass) {
+			return true
+		}
+	}
+	return false
+}
+
+// Call is a call of a command.
+type Call struct {
+	// Command is the name of the command called.
+	Command string
+	// Args are the words after the command.
+	Args      []string
+	Author    string
+	Subreddit string
+	// Name is the fullname of the comment or message holding the call,
+	// to reply to.
+	Name string
+	// Exactly one of Comment and Message is set.
+	Comment *reddit.Comment
+	Message *reddit.Message
+}
+
+// Config configures a Router.
+type Config struct {
+	// Prefix begins every command. If empty, DefaultPrefix is used.
+	Prefix   string
+	Commands []Command
+	// CacheFor is how long a user's flair, account, and moderator
+	// status are trusted once looked up. If zero, it is ten minutes.
+	CacheFor time.Duration
+	// Denied, if set, is called with each call refused because its
+	// author lacks the flair or standing the command requires, e.g. to
+	// reply with why.
+	Denied func(Call) error
+	// Clock times the flair cache. If nil, the wall clock is used.
+	Clock clock.Clock
+}
+
+// Router dispatches the commands in comments and messages. It is safe to use
+// from multiple goroutines.
+type Router interface {
+	// Comment runs the command the comment begins with, if any, and
+	// reports whether it held a command.
+	Comment(c *reddit.Comment) (bool, error)
+	// Message runs the command the message begins with, if any, and
+	// reports whether it held a command. Private messages are from no
+	// subreddit, so they can't call commands which require a flair.
+	Message(m *reddit.Message) (bool, error)
+}
+
+// entry is something looked up about a user, and when it was.
+type entry struct {
+	value interface{}
+	at    time.Time
+}
+
+type router struct {
+	bot      reddit.Bot
+	cfg      Config
+	commands map[string]Command
+
+	mu sync.Mutex
+	// cache holds what was looked up about users, by the kind of lookup,
+	// the subreddit if it is about one, and the user.
+	cache map[string]entry
+	// swept is when entries older than CacheFor were last deleted from the
+	// cache.
+	swept time.Time
+}
+
+// New returns a Router of the config's commands, which looks up its callers
+// with the bot. Looking up a user's flair needs the bot to moderate the
+// subreddit, with permission to manage flair.
+func New(bot reddit.Bot, c Config) (Router, error) {
+	if c.Prefix == "" {
+		c.Prefix = DefaultPrefix
+	}
+	if c.CacheFor <= 0 {
+		c.CacheFor = defaultCacheFor
+	}
+	if c.Clock == nil {
+		c.Clock = clock.Real()
+	}
+
+	commands := make(map[string]Command, len(c.Commands))
+	for _, command := range c.Commands {
+		if command.Name == "" {
+			return nil, noNameErr
+		}
+		if command.Run == nil {
+			return nil, noRunErr
+		}
+		commands[strings.ToLower(command.Name)] = command
+	}
+
+	return &router{
+		bot:      bot,
+		cfg:      c,
+		commands: commands,
+		cache:    make(map[string]entry),
+	}, nil
+}
+
+func (r *router) Comment(c *reddit.Comment) (bool, error) {
+	return r.dispatch(c.Body, Call{
+		Author:    c.Author,
+		Subreddit: c.Subreddit,
+		Name:      c.Name,
+		Comment:   c,
+	})
+}
+
+func (r *router) Message(m *reddit.Message) (bool, error) {
+	return r.dispatch(m.Body, Call{
+		Author:    m.Author,
+		Subreddit: m.Subreddit,
+		Name:      m.Name,
+		Message:   m,
+	})
+}
+
+// dispatch runs the command the text begins with as the call.
+func (r *router) dispatch(text string, call Call) (bool, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], r.cfg.Prefix) {
+		return false, nil
+	}
+
+	name := strings.TrimPrefix(fields[0], r.cfg.Prefix)
+	command, ok := r.commands[strings.ToLower(name)]
+	if !ok {
+		return false, nil
+	}
+	call.Command = command.Name
+	call.Args = fields[1:]
+
+	if command.gated() || command.Requires != (Requirements{}) {
+		permitted, err := r.permitted(command, call)
+		if err != nil {
+			return true, err
+		}
+		if !permitted {
+			if r.cfg.Denied != nil {
+				return true, r.cfg.Denied(call)
+			}
+			return true, nil
+		}
+	}
+
+	return true, command.Run(call)
+}
+
+// permitted reports whether the caller has the flair and standing the
+// command requires. Lookups stop at the first requirement not met.
+func (r *router) permitted(command Command, call Call) (bool, error) {
+	if command.gated() || command.Requires.Moderator {
+		if call.Subreddit == "" {
+			return false, nil
+		}
+	}
+
+	if command.gated() {
+		flair, err := r.lookup("flair", call.Subreddit, call.Author, func() (interface{}, error) {
+			return r.bot.UserFlair(call.Subreddit, call.Author)
+		})
+		if err != nil || !command.permits(flair.(reddit.Flair)) {
+			return false, err
+		}
+	}
+
+	if command.Requires.account() {
+		account, err := r.lookup("account", "", call.Author, func() (interface{}, error) {
+			return r.bot.AboutUser(call.Author)
+		})
+		if err != nil || !command.Requires.meets(account.(*reddit.Redditor)) {
+			return false, err
+		}
+	}
+
+	if command.Requires.Moderator {
+		mod, err := r.lookup("moderator", call.Subreddit, call.Author, func() (interface{}, error) {
+			return r.bot.IsModerator(call.Subreddit, call.Author)
+		})
+		if err != nil || !mod.(bool) {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// lookup returns what get looks up about the user, from the cache if it was
+// looked up recently.
+func (r *router) lookup(
+	kind, subreddit, user string,
+	get func() (interface{}, error),
+) (interface{}, error) {
+	key := kind + "/" + strings.ToLower(subreddit) + "/" + strings.ToLower(user)
+	now := r.cfg.Clock.Now()
+
+	r.mu.Lock()
+	e, ok := r.cache[key]
+	r.mu.Unlock()
+	if ok && now.Sub(e.at) < r.cfg.CacheFor {
+		return e.value, nil
+	}
+
+	value, err := get()
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cache[key] = entry{value: value, at: now}
+	r.sweep(now)
+	r.mu.Unlock()
+	return value, nil
+}
+
+// sweep deletes the cache entries which have outlived CacheFor, at most once
+// in that time, so the cache holds only the users seen recently. The caller
+// holds the lock.
+func (r *router) sweep(now time.Time) {
+	if now.Sub(r.swept) < r.cfg.CacheFor {
+		return
+	}
+
+	for key, e := range r.cache {
+		if now.Sub(e.at) >= r.cfg.CacheFor {
+			delete(r.cache, key)
+		}
+	}
+	r.swept = now
+}
@@ -0,0 +1,171 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// flairBot serves user flair, accounts, and moderators, and counts the flair
+// lookups.
+type flairBot struct {
+	reddit.Bot
+	flairs   map[string]reddit.Flair
+	accounts map[string]*reddit.Redditor
+	mods     map[string]bool
+	lookups  int
+}
+
+func (f *flairBot) UserFlair(subreddit, user string) (reddit.Flair, error) {
+	f.lookups++
+	return f.flairs[user], nil
+}
+
+func (f *flairBot) AboutUser(user string) (*reddit.Redditor, error) {
+	if a, ok := f.accounts[user]; ok {
+		return a, nil
+	}
+	return &reddit.Redditor{Name: user}, nil
+}
+
+func (f *flairBot) IsModerator(subreddit, user string) (bool, error) {
+	return f.mods[user], nil
+}
+
+func TestRouter(t *testing.T) {
+	bot := &flairBot{flairs: map[string]reddit.Flair{
+		"helper": {Text: "Helper", CSSClass: "green helper"},
+		"newbie": {Text: "New"},
+	}}
+	sim := clock.NewSimulation(time.Unix(0, 0))
+
+	var calls, denials []Call
+	run := func(c Call) error {
+		calls = append(calls, c)
+		return nil
+	}
+	r, err := New(bot, Config{
+		Commands: []Command{
+			{Name: "award", FlairClass: "helper", Run: run},
+			{Name: "ping", Run: run},
+		},
+		Denied: func(c Call) error {
+			denials = append(denials, c)
+			return nil
+		},
+		Clock: sim,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		author, body string
+		command      bool
+	}{
+		{"helper", "!award u/someone", true},
+		{"newbie", "!AWARD u/someone", true},
+		{"newbie", "!ping", true},
+		{"newbie", "!unknown", false},
+		{"helper", "no command here", false},
+	} {
+		ok, err := r.Comment(&reddit.Comment{Author: test.author, Subreddit: "sub", Body: test.body})
+		if err != nil || ok != test.command {
+			t.Errorf("%q: got %v, %v; wanted %v", test.body, ok, err, test.command)
+		}
+	}
+
+	if len(calls) != 2 || calls[0].Args[0] != "u/someone" || calls[1].Command != "ping" {
+		t.Errorf("ran %+v", calls)
+	}
+	if len(denials) != 1 || denials[0].Author != "newbie" {
+		t.Errorf("denied %+v", denials)
+	}
+
+	r.Comment(&reddit.Comment{Author: "helper", Subreddit: "sub", Body: "!award"})
+	if bot.lookups != 2 {
+		t.Errorf("looked up flair %d times; wanted the cache used", bot.lookups)
+	}
+	sim.AdvanceTo(time.Unix(0, 0).Add(time.Hour))
+	r.Comment(&reddit.Comment{Author: "helper", Subreddit: "sub", Body: "!award"})
+	if bot.lookups != 3 {
+		t.Errorf("looked up flair %d times; wanted the cache expired", bot.lookups)
+	}
+
+	if ok, _ := r.Message(&reddit.Message{Author: "helper", Body: "!award"}); !ok || len(denials) != 2 {
+		t.Errorf("wanted a private message denied a gated command")
+	}
+}
+
+func TestRequirements(t *testing.T) {
+	bot := &flairBot{
+		accounts: map[string]*reddit.Redditor{
+			"verified": {HasVerifiedEmail: true},
+			"mod":      {HasVerifiedEmail: true},
+		},
+		mods: map[string]bool{"mod": true},
+	}
+
+	var ran []string
+	r, err := New(bot, Config{Commands: []Command{
+		{
+			Name:     "enter",
+			Requires: Requirements{VerifiedEmail: true},
+			Run:      func(c Call) error { ran = append(ran, "enter "+c.Author); return nil },
+		},
+		{
+			Name:     "draw",
+			Requires: Requirements{VerifiedEmail: true, Moderator: true},
+			Run:      func(c Call) error { ran = append(ran, "draw "+c.Author); return nil },
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, author := range []string{"verified", "unverified", "mod"} {
+		for _, body := range []string{"!enter", "!draw"} {
+			if _, err := r.Comment(&reddit.Comment{Author: author, Subreddit: "sub", Body: body}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	want := []string{"enter verified", "enter mod", "draw mod"}
+	if len(ran) != len(want) {
+		t.Fatalf("ran %v; wanted %v", ran, want)
+	}
+	for i := range want {
+		if ran[i] != want[i] {
+			t.Errorf("ran %v; wanted %v", ran, want)
+		}
+	}
+}
+
+func TestCacheSweep(t *testing.T) {
+	bot := &flairBot{}
+	start := time.Unix(0, 0)
+	sim := clock.NewSimulation(start)
+	r, err := New(bot, Config{
+		Commands: []Command{{Name: "award", FlairClass: "helper", Run: func(Call) error { return nil }}},
+		Clock:    sim,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, author := range []string{"a", "b"} {
+		r.Comment(&reddit.Comment{Author: author, Subreddit: "sub", Body: "!award"})
+	}
+
+	// Once the cached flair has expired, looking up anyone else deletes it
+	// rather than let every user ever seen pile up.
+	sim.AdvanceTo(start.Add(time.Hour))
+	r.Comment(&reddit.Comment{Author: "c", Subreddit: "sub", Body: "!award"})
+	cache := r.(*router).cache
+	if len(cache) != 1 {
+		t.Errorf("cache holds %d entries after expiry; wanted only c's", len(cache))
+	}
+}
@@ -0,0 +1,111 @@
+// Package compose renders bot replies from templates.
+//
+// Templates use the text/template syntax, with a few extra functions for
+// citing Reddit content:
+//
+//	link    turns a permalink into a full link, following the Composer's config.
+//	np      turns a permalink into a non participation (np.reddit.com) link.
+//	plural  picks the form of a word for a count, e.g. {{plural .N "cat" "cats"}},
+//	        following the plural rules of the template's language.
+//
+// A simple x-post bot might use:
+//
+//	c := compose.New(compose.Config{NonParticipation: true})
+//	c.Add("xpost", `Cross posted from {{link .Permalink}} by /u/{{.Author}}`)
+//	text, err := c.Compose("xpost", post)
+//
+// Templates can also be kept in a directory or a wiki page, and reloaded while
+// the bot runs as they change; see NewReloader.
+package compose
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"text/template"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// Config configures the links a Composer generates.
+type Config struct {
+	// NonParticipation, when true, makes the link function point at
+	// np.reddit.com. Many subreddits require this for links into other
+	// communities.
+	NonParticipation bool
+	// Context is the number of parent comments to display when the link
+	// function is given a comment permalink. Zero displays none.
+	Context int
+}
+
+// Composer renders named reply templates.
+type Composer interface {
+	// Add parses text as the template called name, replacing any template
+	// previously added under that name.
+	Add(name, text string) error
+	// Compose renders the template called name with the given data.
+	Compose(name string, data interface{}) (string, error)
+}
+
+type composer struct {
+	cfg Config
+	// lang is the language of the templates, which chooses their plural
+	// rules. It is empty for languages with English plural rules.
+	lang      string
+	templates map[string]*template.Template
+	mu        sync.RWMutex
+}
+
+// New returns a Composer with no templates.
+func New(c Config) Composer {
+	return &composer{
+		cfg:       c,
+		templates: make(map[string]*template.Template),
+	}
+}
+
+func (c *composer) Add(name, text string) error {
+	t, err := template.New(name).Funcs(c.funcs()).Parse(text)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.templates[name] = t
+	return nil
+}
+
+func (c *composer) Compose(name string, data interface{}) (string, error) {
+	c.mu.RLock()
+	t, ok := c.templates[name]
+	c.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("no template named %q", name)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func (c *composer) funcs() template.FuncMap {
+	return template.FuncMap{
+		"link": func(permalink string) string {
+			return reddit.Link(
+				permalink,
+				c.cfg.NonParticipation,
+				c.cfg.Context,
+			)
+		},
+		"np": func(permalink string) string {
+			return reddit.Link(permalink, true, 0)
+		},
+		"plural": func(n int, forms ...string) string {
+			return plural(c.lang, n, forms)
+		},
+	}
+}
@@ -0,0 +1,104 @@
+package compose
+
+import (
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+func TestCompose(t *testing.T) {
+	c := New(Config{NonParticipation: true, Context: 3})
+	if err := c.Add("cite", "{{.Author}}: {{link .Permalink}}"); err != nil {
+		t.Fatalf("failed to add template: %v", err)
+	}
+
+	text, err := c.Compose(
+		"cite",
+		&reddit.Comment{Author: "user", Permalink: "/r/a/comments/b/c/d/"},
+	)
+	if err != nil {
+		t.Fatalf("failed to compose: %v", err)
+	}
+
+	expected := "user: https://np.reddit.com/r/a/comments/b/c/d/?context=3"
+	if text != expected {
+		t.Errorf("got %q; wanted %q", text, expected)
+	}
+}
+
+func TestComposeMissing(t *testing.T) {
+	if _, err := New(Config{}).Compose("missing", nil); err == nil {
+		t.Errorf("wanted error composing missing template")
+	}
+}
+
+func TestPlural(t *testing.T) {
+	for _, test := range []struct {
+		lang     string
+		n        int
+		forms    []string
+		expected string
+	}{
+		{"", 1, []string{"cat", "cats"}, "cat"},
+		{"en", 0, []string{"cat", "cats"}, "cats"},
+		{"fr", 0, []string{"chat", "chats"}, "chat"},
+		{"ja", 5, []string{"neko"}, "neko"},
+		{"ru", 21, []string{"кот", "кота", "котов"}, "кот"},
+		{"ru", 3, []string{"кот", "кота", "котов"}, "кота"},
+		{"ru", 12, []string{"кот", "кота", "котов"}, "котов"},
+		{"pl-PL", 22, []string{"kot", "koty", "kotów"}, "koty"},
+		{"cs", 5, []string{"kočka", "kočky"}, "kočky"},
+	} {
+		if form := plural(test.lang, test.n, test.forms); form != test.expected {
+			t.Errorf("%s %d: got %q; wanted %q", test.lang, test.n, form, test.expected)
+		}
+	}
+}
+
+func TestLocalizer(t *testing.T) {
+	l := NewLocalizer(LocaleConfig{
+		Default:    "en",
+		Subreddits: map[string]string{"brasil": "pt-BR", "france": "fr"},
+		Fallbacks:  map[string][]string{"gl": {"es"}},
+	})
+
+	for _, tmpl := range [][3]string{
+		{"en", "count", `{{.}} {{plural . "reply" "replies"}}`},
+		{"en", "bye", "bye"},
+		{"fr", "count", `{{.}} {{plural . "réponse" "réponses"}}`},
+		{"pt", "count", `{{.}} {{plural . "resposta" "respostas"}}`},
+		{"es", "bye", "adiós"},
+	} {
+		if err := l.Add(tmpl[0], tmpl[1], tmpl[2]); err != nil {
+			t.Fatalf("failed to add template: %v", err)
+		}
+	}
+
+	for _, test := range []struct {
+		subreddit, lang, name string
+		expected              string
+	}{
+		{subreddit: "France", name: "count", expected: "0 réponse"},
+		{subreddit: "brasil", name: "count", expected: "0 resposta"},
+		{subreddit: "brasil", name: "bye", expected: "bye"},
+		{subreddit: "golang", name: "count", expected: "0 replies"},
+		{lang: "gl", name: "bye", expected: "adiós"},
+	} {
+		var text string
+		var err error
+		if test.lang != "" {
+			text, err = l.Compose(test.lang, test.name, 0)
+		} else {
+			text, err = l.ComposeFor(test.subreddit, test.name, 0)
+		}
+
+		if err != nil || text != test.expected {
+			t.Errorf("%s%s/%s: got %q, %v; wanted %q",
+				test.subreddit, test.lang, test.name, text, err, test.expected)
+		}
+	}
+
+	if _, err := l.Compose("de", "missing", nil); err == nil {
+		t.Errorf("wanted error composing missing template")
+	}
+}
@@ -0,0 +1,146 @@
+package compose
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// LocaleConfig configures a Localizer.
+type LocaleConfig struct {
+	// Config configures the links of every language's templates.
+	Config
+	// Default is the language templates fall back to when a language has
+	// no template of the name asked for, e.g. "en".
+	Default string
+	// Subreddits maps subreddit names to the language replies in them are
+	// written in. Subreddits not named here use Default.
+	Subreddits map[string]string
+	// Fallbacks maps languages to the languages tried, in order, when they
+	// have no template of the name asked for. Every language also falls
+	// back to its primary language, e.g. "pt-BR" to "pt", and then to
+	// Default.
+	Fallbacks map[string][]string
+}
+
+// Localizer renders reply templates in the language of the community the
+// reply is for.
+type Localizer interface {
+	// Add parses text as the template called name in the language lang,
+	// replacing any template previously added under that name and
+	// language.
+	Add(lang, name, text string) error
+	// Compose renders the template called name in the language lang, e.g.
+	// one detected from the message being replied to, or in the first
+	// language of its fallback chain which has the template.
+	Compose(lang, name string, data interface{}) (string, error)
+	// ComposeFor renders the template called name in the language of the
+	// subreddit.
+	ComposeFor(subreddit, name string, data interface{}) (string, error)
+	// Language returns the language of the subreddit.
+	Language(subreddit string) string
+}
+
+type localizer struct {
+	cfg        LocaleConfig
+	subreddits map[string]string
+	fallbacks  map[string][]string
+
+	mu        sync.RWMutex
+	languages map[string]*composer
+}
+
+// NewLocalizer returns a Localizer with no templates.
+func NewLocalizer(c LocaleConfig) Localizer {
+	subreddits := make(map[string]string, len(c.Subreddits))
+	for sub, lang := range c.Subreddits {
+		subreddits[strings.ToLower(sub)] = lang
+	}
+
+	fallbacks := make(map[string][]string, len(c.Fallbacks))
+	for lang, chain := range c.Fallbacks {
+		fallbacks[strings.ToLower(lang)] = chain
+	}
+
+	return &localizer{
+		cfg:        c,
+		subreddits: subreddits,
+		fallbacks:  fallbacks,
+		languages:  make(map[string]*composer),
+	}
+}
+
+func (l *localizer) Add(lang, name, text string) error {
+	key := strings.ToLower(lang)
+
+	l.mu.Lock()
+	c, ok := l.languages[key]
+	if !ok {
+		c = New(l.cfg.Config).(*composer)
+		c.lang = lang
+		l.languages[key] = c
+	}
+	l.mu.Unlock()
+
+	return c.Add(name, text)
+}
+
+func (l *localizer) Compose(
+	lang, name string,
+	data interface{},
+) (string, error) {
+	for _, candidate := range l.chain(lang) {
+		l.mu.RLock()
+		c, ok := l.languages[candidate]
+		l.mu.RUnlock()
+		if !ok {
+			continue
+		}
+
+		c.mu.RLock()
+		_, ok = c.templates[name]
+		c.mu.RUnlock()
+		if ok {
+			return c.Compose(name, data)
+		}
+	}
+
+	return "", fmt.Errorf("no template named %q in %s or its fallbacks", name, lang)
+}
+
+func (l *localizer) ComposeFor(
+	subreddit, name string,
+	data interface{},
+) (string, error) {
+	return l.Compose(l.Language(subreddit), name, data)
+}
+
+func (l *localizer) Language(subreddit string) string {
+	if lang, ok := l.subreddits[strings.ToLower(subreddit)]; ok {
+		return lang
+	}
+	return l.cfg.Default
+}
+
+// chain returns the languages tried for a template in lang, lowercased and
+// in order.
+func (l *localizer) chain(lang string) []string {
+	var chain []string
+	seen := make(map[string]bool)
+	add := func(lang string) {
+		lang = strings.ToLower(lang)
+		if lang != "" && !seen[lang] {
+			seen[lang] = true
+			chain = append(chain, lang)
+		}
+	}
+
+	add(lang)
+	for _, fallback := range l.fallbacks[strings.ToLower(lang)] {
+		add(fallback)
+	}
+	add(primary(lang))
+	add(l.cfg.Default)
+	add(primary(l.cfg.Default))
+	return chain
+}
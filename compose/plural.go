@@ -0,0 +1,108 @@
+package compose
+
+import "strings"
+
+// pluralRule returns the index of the plural form a language uses for n.
+type pluralRule func(n int) int
+
+// pluralRules are the plural rules of languages whose rules differ from
+// English. Languages are keyed by their primary subtag.
+var pluralRules = map[string]pluralRule{
+	// One form for every count.
+	"ja": none,
+	"ko": none,
+	"zh": none,
+	"vi": none,
+	"th": none,
+	"id": none,
+	// Zero is singular.
+	"fr": zeroSingular,
+	"pt": zeroSingular,
+	// One, few, and many.
+	"ru": slavic,
+	"uk": slavic,
+	"be": slavic,
+	"sr": slavic,
+	"hr": slavic,
+	"bs": slavic,
+	"pl": func(n int) int {
+		switch {
+		case n == 1:
+			return 0
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return 1
+		}
+		return 2
+	},
+	"cs": czech,
+	"sk": czech,
+}
+
+func none(int) int { return 0 }
+
+func zeroSingular(n int) int {
+	if n == 0 || n == 1 {
+		return 0
+	}
+	return 1
+}
+
+func english(n int) int {
+	if n == 1 {
+		return 0
+	}
+	return 1
+}
+
+func slavic(n int) int {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return 0
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return 1
+	}
+	return 2
+}
+
+func czech(n int) int {
+	switch {
+	case n == 1:
+		return 0
+	case n >= 2 && n <= 4:
+		return 1
+	}
+	return 2
+}
+
+// plural returns the form of a word the language uses for the count n. If
+// fewer forms are given than the language has, the last is used.
+func plural(lang string, n int, forms []string) string {
+	if len(forms) == 0 {
+		return ""
+	}
+
+	if n < 0 {
+		n = -n
+	}
+
+	rule, ok := pluralRules[primary(lang)]
+	if !ok {
+		rule = english
+	}
+
+	i := rule(n)
+	if i >= len(forms) {
+		i = len(forms) - 1
+	}
+	return forms[i]
+}
+
+// primary returns the primary subtag of a language tag, e.g. "pt" for
+// "pt-BR".
+func primary(lang string) string {
+	lang = strings.ToLower(lang)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		return lang[:i]
+	}
+	return lang
+}
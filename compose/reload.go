@@ -0,0 +1,202 @@
+package compose
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+	"gopkg.in/yaml.v3"
+)
+
+// Source is where a Reloader reads its templates from.
+type Source interface {
+	// Templates returns the text of every template, by name, and a
+	// version which changes whenever any of them does.
+	Templates() (templates map[string]string, version string, err error)
+}
+
+// TemplateError describes a template a Reloader could not compile.
+type TemplateError struct {
+	Template string
+	Err      error
+}
+
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("template %q: %v", e.Template, e.Err)
+}
+
+// Reloader is a Composer whose templates are read from a Source and read
+// again when it changes, so their wording can change while the bot runs.
+// Templates are swapped in all at once, and only if every one of them
+// compiles; until then, the previous templates stay in use. Templates given
+// to Add last until the source next changes.
+type Reloader interface {
+	Composer
+	// Reload reads the source again, swapping in its templates if they
+	// changed. If one of them does not compile, the previous templates
+	// are kept and a *TemplateError is returned.
+	Reload() error
+	// Watch reloads the templates once every interval until kill is
+	// closed, sending errors to errs, e.g. those of a graw run so they
+	// reach its error handling. It blocks.
+	Watch(interval time.Duration, kill <-chan bool, errs chan<- error)
+}
+
+type reloader struct {
+	*composer
+	src Source
+
+	// reloading serializes reloads.
+	reloading sync.Mutex
+	version   string
+}
+
+// NewReloader returns a Reloader of the source's templates, reading them
+// once so compile errors are returned before the bot starts.
+func NewReloader(c Config, src Source) (Reloader, error) {
+	r := &reloader{composer: New(c).(*composer), src: src}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *reloader) Reload() error {
+	r.reloading.Lock()
+	defer r.reloading.Unlock()
+
+	texts, version, err := r.src.Templates()
+	if err != nil {
+		return err
+	}
+	if version == r.version {
+		return nil
+	}
+
+	templates := make(map[string]*template.Template, len(texts))
+	for name, text := range texts {
+		t, err := template.New(name).Funcs(r.funcs()).Parse(text)
+		if err != nil {
+			return &TemplateError{Template: name, Err: err}
+		}
+		templates[name] = t
+	}
+
+	r.composer.mu.Lock()
+	r.templates = templates
+	r.composer.mu.Unlock()
+	r.version = version
+	return nil
+}
+
+func (r *reloader) Watch(
+	interval time.Duration,
+	kill <-chan bool,
+	errs chan<- error,
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-kill:
+			return
+		case <-ticker.C:
+			if err := r.Reload(); err != nil {
+				select {
+				case errs <- err:
+				case <-kill:
+					return
+				}
+			}
+		}
+	}
+}
+
+type dirSource struct {
+	dir string
+}
+
+// Dir returns a Source of the files in a directory, each a template named
+// after the file without its extension, e.g. "welcome" for "welcome.tmpl".
+// Subdirectories and hidden files are skipped.
+func Dir(dir string) Source {
+	return &dirSource{dir: dir}
+}
+
+func (d *dirSource) Templates() (map[string]string, string, error) {
+	files, err := ioutil.ReadDir(d.dir)
+	if err != nil {
+		return nil, "", err
+	}
+
+	texts := make(map[string]string)
+	for _, f := range files {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+
+		text, err := ioutil.ReadFile(filepath.Join(d.dir, f.Name()))
+		if err != nil {
+			return nil, "", err
+		}
+		name := strings.TrimSuffix(f.Name(), filepath.Ext(f.Name()))
+		texts[name] = string(text)
+	}
+
+	return texts, digest(texts), nil
+}
+
+// digest is a version of the templates derived from their names and text.
+func digest(texts map[string]string) string {
+	names := make([]string, 0, len(texts))
+	for name := range texts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha1.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%d:%s%d:%s", len(name), name, len(texts[name]), texts[name])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type wikiSource struct {
+	wiki      reddit.Wiki
+	subreddit string
+	page      string
+}
+
+// WikiPage returns a Source of the templates in a page of the subreddit's
+// wiki. The page is a YAML mapping of template names to their text, which
+// block scalars keep readable:
+//
+//	welcome: |
+//	  Welcome to the subreddit, /u/{{.Author}}!
+//	removed: Your post was removed.
+func WikiPage(wiki reddit.Wiki, subreddit, page string) Source {
+	return &wikiSource{wiki: wiki, subreddit: subreddit, page: page}
+}
+
+func (w *wikiSource) Templates() (map[string]string, string, error) {
+	page, err := w.wiki.WikiPage(w.subreddit, w.page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	texts := make(map[string]string)
+	if err := yaml.Unmarshal([]byte(page.Content), &texts); err != nil {
+		return nil, "", fmt.Errorf(
+			"templates in /r/%s/wiki/%s: %v", w.subreddit, w.page, err,
+		)
+	}
+	return texts, page.RevisionID, nil
+}
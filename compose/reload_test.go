@@ -0,0 +1,91 @@
+package compose
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+type stubWiki struct {
+	reddit.Wiki
+	page *reddit.WikiPage
+}
+
+func (s *stubWiki) WikiPage(_, _ string) (*reddit.WikiPage, error) {
+	return s.page, nil
+}
+
+func TestReloaderDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "compose")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name, text string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("hello.tmpl", "hi {{.}}")
+	r, err := NewReloader(Config{}, Dir(dir))
+	if err != nil {
+		t.Fatalf("failed to load templates: %v", err)
+	}
+
+	compose := func(expected string) {
+		text, err := r.Compose("hello", "you")
+		if err != nil {
+			t.Fatalf("failed to compose: %v", err)
+		}
+		if text != expected {
+			t.Errorf("got %q; wanted %q", text, expected)
+		}
+	}
+	compose("hi you")
+
+	write("hello.tmpl", "hello {{.}}")
+	if err := r.Reload(); err != nil {
+		t.Fatalf("failed to reload: %v", err)
+	}
+	compose("hello you")
+
+	// A broken template keeps every previous one in use.
+	write("hello.tmpl", "bye {{.}}")
+	write("broken.tmpl", "{{.")
+	err = r.Reload()
+	if _, ok := err.(*TemplateError); !ok {
+		t.Errorf("got error %v; wanted a *TemplateError", err)
+	}
+	compose("hello you")
+}
+
+func TestReloaderWiki(t *testing.T) {
+	wiki := &stubWiki{page: &reddit.WikiPage{
+		Content:    "welcome: |\n  Welcome, {{.}}!\n",
+		RevisionID: "a",
+	}}
+
+	r, err := NewReloader(Config{}, WikiPage(wiki, "sub", "templates"))
+	if err != nil {
+		t.Fatalf("failed to load templates: %v", err)
+	}
+
+	text, err := r.Compose("welcome", "you")
+	if err != nil {
+		t.Fatalf("failed to compose: %v", err)
+	}
+	if text != "Welcome, you!\n" {
+		t.Errorf("got %q", text)
+	}
+
+	if _, err := NewReloader(Config{}, WikiPage(&stubWiki{page: &reddit.WikiPage{
+		Content: "welcome: {{.\n",
+	}}, "sub", "templates")); err == nil {
+		t.Errorf("wanted error loading a page which is not YAML")
+	}
+}
@@ -1,7 +1,19 @@
 package graw
 
 import (
-	"log"
+	"time"
+
+	"github.com/aldarisbm/graw/archive"
+	"github.com/aldarisbm/graw/digest"
+	"github.com/aldarisbm/graw/logging"
+	"github.com/aldarisbm/graw/once"
+	"github.com/aldarisbm/graw/reputation"
+	"github.com/aldarisbm/graw/responder"
+	"github.com/aldarisbm/graw/schedule"
+	"github.com/aldarisbm/graw/stats"
+	"github.com/aldarisbm/graw/streams"
+	"github.com/aldarisbm/graw/summarize"
+	"github.com/aldarisbm/graw/translate"
 )
 
 // Config configures a graw run or scan by specifying event sources. Each event
@@ -9,7 +21,9 @@ import (
 // able to handle requested event types.
 type Config struct {
 	// New posts in all subreddits named here will be forwarded to the bot's
-	// PostHandler.
+	// PostHandler. Mod bots may name streams.Moderated alone here, and in
+	// SubredditComments and the moderation queues below, to follow every
+	// subreddit they moderate with one stream, routing by Subreddit.
 	Subreddits []string
 	// New posts in all users' custom feeds named here will be forwarded to the bot's
 	// PostHandler.
@@ -18,6 +32,15 @@ type Config struct {
 	// New comments in all subreddits named here will be forwarded to the
 	// bot's CommentHandler.
 	SubredditComments []string
+	// CommentFilter chooses which comments of the SubredditComments
+	// streams are forwarded, e.g. only top level comments, only the OP's
+	// comments in some threads, or only replies to the bot. Every comment
+	// still reaches the taps, such as Stats.
+	CommentFilter streams.CommentFilter
+	// PostFilter chooses which posts of the Subreddits and CustomFeeds
+	// streams are forwarded, e.g. only those moderators distinguished.
+	// Every post still reaches the taps.
+	PostFilter streams.PostFilter
 	// New posts and comments made by all users named here will be forwarded
 	// to the bot's UserHandler. Note that since a separate monitor must be
 	// construced for every user, unlike subreddits, subscribing to the
@@ -35,7 +58,174 @@ type Config struct {
 	// When true, messages sent to the bot's inbox will be forwarded to the
 	// bot's MessageHandler.
 	Messages bool
-	// If set, internal messages will be logged here. This is a spammy log
-	// used for debugging graw.
-	Logger *log.Logger
+	// If set, a snapshot of the bot account's karma, followers, and recent
+	// removal rate will be taken this often and forwarded to the bot's
+	// AccountSnapshotHandler.
+	AccountSnapshots time.Duration
+	// When true, the bot's access token will be revoked when the run is
+	// stopped, so no live token outlives the bot.
+	RevokeOnShutdown bool
+	// Posts, comments, and messages made by the bot's own account have
+	// their Own field set when running as a logged in bot. When true, they
+	// are not forwarded to the bot's handlers at all, though they still
+	// count toward thresholds, trends, and volumes.
+	SkipOwnContent bool
+	// If set, streams start from this point instead of now, and everything
+	// posted after it is handled before live events. It is either the
+	// fullname of an element in the streams' listings (e.g. "t3_abc123"),
+	// or a unix timestamp. Streams whose listing does not hold the
+	// fullname start from now.
+	ResumeFrom string
+	// ListingLimit, if set, is how many elements the Subreddits,
+	// CustomFeeds, SubredditComments, Users, and inbox streams ask for per
+	// poll, up to 100. If zero, each stream sizes its polls to the volume
+	// of its listing. See streams.Start.
+	ListingLimit int
+	// Posts first appearing in the rising listing of all subreddits named
+	// here will be forwarded to the bot's RisingHandler, marked as Handled
+	// if the Subreddits or CustomFeeds streams already forwarded them to
+	// the PostHandler.
+	Rising []string
+	// RisingInterval is how often the rising listings are read. If zero,
+	// they are read every minute.
+	RisingInterval time.Duration
+	// Posts and comments first appearing in the gilded listing of all
+	// subreddits named here will be forwarded to the bot's GildedHandler
+	// with their awards.
+	Gilded []string
+	// GildedInterval is how often the gilded listings are read. If zero,
+	// they are read every minute.
+	GildedInterval time.Duration
+	// If set, these saved searches are run every Interval, five minutes if
+	// zero, and posts newly matching them will be forwarded to the bot's
+	// SearchResultHandler.
+	Searches *streams.SearchConfig
+	// If set, saved search results are gathered into digests and
+	// forwarded to the bot's SearchDigestHandler on this schedule instead
+	// of one at a time, or delivered to DigestSink if there is one.
+	SearchDigest *streams.DigestConfig
+	DigestSink   digest.Sink
+	// Each roundup configured here gathers the top posts of its sources
+	// and posts them every interval.
+	Roundups []digest.RoundupConfig
+	// If set, posts and comments from the Subreddits, CustomFeeds, and
+	// SubredditComments streams are counted toward the rolling statistics
+	// of their subreddits in this aggregator. If StatsSink is also set,
+	// the statistics of every subreddit are published to it every
+	// StatsInterval, or every minute if that is zero.
+	Stats         stats.Aggregator
+	StatsSink     stats.Sink
+	StatsInterval time.Duration
+	// If set, posts and comments from the Subreddits, CustomFeeds, and
+	// SubredditComments streams, and the reports of the Reports streams,
+	// are counted toward their author's activity in this counter, which
+	// handlers can query. The counts are saved every minute.
+	Authors stats.Authors
+	// If set, the authors of posts and comments from the Subreddits,
+	// CustomFeeds, and SubredditComments streams are recorded in this
+	// graph of the overlap between subreddits. It is saved every minute.
+	Overlap stats.Overlap
+	// If set, posts and comments from the Subreddits, CustomFeeds, and
+	// SubredditComments streams are tracked, and forwarded to the bot's
+	// CrossingHandler when their score or comment count crosses one of
+	// these thresholds.
+	Thresholds []streams.Threshold
+	// ThresholdInterval is how often tracked posts and comments are
+	// fetched again to check them against Thresholds. If zero, they are
+	// checked every 5 minutes.
+	ThresholdInterval time.Duration
+	// If set, the titles and bodies of posts and comments from the
+	// Subreddits, CustomFeeds, and SubredditComments streams are watched
+	// for trending terms, which are forwarded to the bot's TrendHandler.
+	Trends *streams.TrendConfig
+	// If set, the posts from the Subreddits and CustomFeeds streams are
+	// counted per subreddit, and sharp changes in their volume, such as
+	// brigades or spam waves, are forwarded to the bot's VolumeHandler.
+	// Set a Store in the config to keep the learned baselines across
+	// restarts.
+	PostVolume *streams.VolumeConfig
+	// If set, posts and comments by users on the watchlist, from the
+	// Subreddits, CustomFeeds, SubredditComments, and Users streams, are
+	// forwarded to the bot's WatchedUserHandler along with the matching
+	// entry. Users watched Anywhere get user streams of their own.
+	Watchlist streams.Watchlist
+	// If set, link posts from the Subreddits and CustomFeeds streams are
+	// counted toward their domain's reputation in this ledger, and
+	// forwarded with it to the bot's LinkPostHandler.
+	Reputation reputation.Ledger
+	// Posts and comments reported in all subreddits named here, which the
+	// bot must moderate, are forwarded to the bot's ReportHandler once
+	// each, with every reason they were reported for, when they have
+	// gathered MinReports reports.
+	Reports    []string
+	MinReports int
+	// ReportInterval is how often the report queues are read. If zero,
+	// they are read every minute.
+	ReportInterval time.Duration
+	// Posts and comments entering the moderation queue, removed as spam,
+	// or edited in all subreddits named here, which the bot must moderate,
+	// are forwarded to the bot's ModqueueHandler, SpamHandler, and
+	// EditedHandler.
+	Modqueue []string
+	Spam     []string
+	Edited   []string
+	// QueueInterval is how often those queues are read. If zero, they are
+	// read every minute.
+	QueueInterval time.Duration
+	// The new modmail conversations of all subreddits named here, which
+	// the bot must moderate, are forwarded to the bot's ModmailHandler
+	// each time a user writes in them.
+	Modmail []string
+	// ModmailInterval is how often the modmail is read. If zero, it is
+	// read every minute.
+	ModmailInterval time.Duration
+	// The events of all live threads named here by ID, such as their new
+	// updates, are forwarded to the bot's LiveThreadHandler, from each
+	// thread's websocket, until the thread is closed.
+	LiveThreads []string
+	// LiveFallback, if set, is how often live threads are polled while
+	// their websockets can't be reached, so their updates still arrive.
+	// If zero, the run waits for the websockets, and fails if it can't
+	// reach one.
+	LiveFallback time.Duration
+	// If set, the bot answers summons, a mention in a thread or a message
+	// such as "summarize <link>", with this summarizer's summary of the
+	// fully expanded thread, posted as a reply. Summons are read from
+	// inbox streams of their own, alongside any Mentions or Messages.
+	Summarizer summarize.Summarizer
+	// Summarize configures the summons command and thread expansion.
+	Summarize summarize.Config
+	// If set, modmail sent to the subreddits the bot moderates is
+	// answered by this responder's rules, from an inbox stream of its own.
+	Responder responder.Responder
+	// If set, the writes graw makes on the bot's behalf, such as
+	// summaries and modmail replies, go through this ledger, so a summons
+	// handled again after a restart is not answered twice. Wrap the bot
+	// with the ledger's Bot method to guard the handlers' own writes too.
+	Ledger once.Ledger
+	// If set, posts, comments, and messages are translated into the bot's
+	// language by this pipeline before they are forwarded to the bot's
+	// handlers. Wrap the bot with the pipeline's Bot method to translate
+	// its replies back.
+	Translation translate.Pipeline
+	// Ordering is the order posts, comments, and messages are handled in.
+	// By default each stream's events are handled one at a time; set
+	// AuthorOrder or ThreadOrder to handle those of different authors or
+	// threads in parallel while keeping each author's or thread's in
+	// order.
+	Ordering Ordering
+	// If set, events deferred with this scheduler are dispatched again
+	// when they come due, to the bot's DeferredHandler if it has one.
+	Scheduler schedule.Scheduler
+	// If set, the posts, comments, and messages dispatched to the bot's
+	// PostHandler, CommentHandler, UserHandler, PostReplyHandler,
+	// CommentReplyHandler, MentionHandler, and MessageHandler are also
+	// written here, so the run can be replayed later with Replay. The
+	// events of the bot's other handlers, e.g. for modmail, mod queues,
+	// or live threads, are not archived.
+	Archive archive.Writer
+	// If set, internal messages will be logged here, such as the errors
+	// the run stays up through and the one it stops with. See
+	// logging.Std to log to a *log.Logger.
+	Logger logging.Logger
 }
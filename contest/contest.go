@@ -0,0 +1,277 @@
+// Package contest runs competitions in Reddit threads: each top level comment
+// of a contest thread is an entry, and entries are ranked by their scores.
+//
+// Contest mode hides scores from everyone but moderators while a contest is
+// open, so a Contest takes snapshots of the scores as it goes, for a final
+// tally when the contest closes and a record of how it went:
+//
+//	contest.Open(bot, post.Name)
+//	c, _ := contest.New(bot, contest.Config{Permalink: post.Permalink})
+//	c.Refresh() // now and then
+//	contest.Close(bot, post.Name)
+//	results, _ := c.Tally()
+package contest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/store"
+)
+
+const (
+	// infoBatch is the most fullnames Reddit's /api/info accepts in one
+	// request.
+	infoBatch = 100
+	// entryLimit is the most comments read from the thread when looking
+	// for entries.
+	entryLimit = 500
+)
+
+var noPermalinkErr = fmt.Errorf("a contest needs the permalink of its thread")
+
+// Open turns contest mode on for a post, so its entries are shown in random
+// order with their scores hidden.
+func Open(bot reddit.Bot, post string) error {
+	return bot.SetContestMode(post, true)
+}
+
+// Close turns contest mode off for a post, revealing the scores.
+func Close(bot reddit.Bot, post string) error {
+	return bot.SetContestMode(post, false)
+}
+
+// Config configures a Contest.
+type Config struct {
+	// Permalink is the permalink of the contest thread.
+	Permalink string
+	// If set, snapshots are saved here, and loaded again when a contest is
+	// made with the same store and thread, so they survive restarts.
+	Store store.Store
+	// Clock times the snapshots. If nil, the wall clock is used.
+	Clock clock.Clock
+}
+
+// Snapshot is the scores of a contest's entries at a point in time.
+type Snapshot struct {
+	Time time.Time `json:"time"`
+	// Scores are the entries' scores by their fullnames.
+	Scores map[string]int32 `json:"scores"`
+}
+
+// Result is an entry's place in a tally.
+type Result struct {
+	// Rank is the entry's place, from 1. Entries with equal scores share
+	// a rank.
+	Rank  int
+	Entry *reddit.Comment
+	Score int32
+}
+
+// Contest tracks the entries of a contest thread and their scores.
+type Contest interface {
+	// Entries re-reads the thread for its entries: its top level
+	// comments which were not removed.
+	Entries() ([]*reddit.Comment, error)
+	// Refresh takes a snapshot of the entries' scores, reading the
+	// thread for entries first if it was not read yet. Reading the
+	// scores costs a request per hundred entries.
+	Refresh() (Snapshot, error)
+	// Snapshots returns every snapshot taken, oldest first.
+	Snapshots() []Snapshot
+	// Tally re-reads the entries and takes a final snapshot, and returns
+	// the entries ranked by their scores in it, highest first.
+	Tally() ([]Result, error)
+}
+
+type contest struct {
+	bot   reddit.Bot
+	cfg   Config
+	key   string
+	mu    sync.Mutex
+	read  bool
+	shots []Snapshot
+	// entries are the entries by fullname, in the order they were found.
+	entries map[string]*reddit.Comment
+	order   []string
+}
+
+// New returns a Contest for the thread at the config's permalink, with the
+// snapshots saved in the config's store if it has one.
+func New(bot reddit.Bot, c Config) (Contest, error) {
+	if c.Permalink == "" {
+		return nil, noPermalinkErr
+	}
+
+	if c.Clock == nil {
+		c.Clock = clock.Real()
+	}
+
+	ct := &contest{
+		bot:     bot,
+		cfg:     c,
+		key:     stateKey(c.Permalink),
+		entries: make(map[string]*reddit.Comment),
+	}
+
+	if c.Store != nil {
+		err := c.Store.Load(ct.key, &ct.shots)
+		if err != nil && err != store.NotFoundErr {
+			return nil, err
+		}
+	}
+
+	return ct, nil
+}
+
+// stateKey is the key a thread's snapshots are stored under.
+func stateKey(permalink string) string {
+	id := permalink
+	parts := strings.Split(strings.Trim(permalink, "/"), "/")
+	for i, part := range parts {
+		if part == "comments" && i+1 < len(parts) {
+			id = parts[i+1]
+		}
+	}
+
+	return "contest-" + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, id)
+}
+
+func (c *contest) Entries() ([]*reddit.Comment, error) {
+	thread, err := c.bot.ThreadWithOptions(
+		c.cfg.Permalink,
+		reddit.ThreadOptions{Limit: entryLimit, Depth: 1},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.read = true
+	for _, comment := range thread.Replies {
+		if _, removed := comment.Removal(); removed {
+			continue
+		}
+
+		if _, ok := c.entries[comment.Name]; !ok {
+			c.order = append(c.order, comment.Name)
+		}
+		c.entries[comment.Name] = comment
+	}
+
+	return c.list(), nil
+}
+
+// list returns the entries in the order they were found.
+func (c *contest) list() []*reddit.Comment {
+	entries := make([]*reddit.Comment, 0, len(c.order))
+	for _, name := range c.order {
+		entries = append(entries, c.entries[name])
+	}
+	return entries
+}
+
+func (c *contest) Refresh() (Snapshot, error) {
+	c.mu.Lock()
+	read := c.read
+	c.mu.Unlock()
+
+	if !read {
+		if _, err := c.Entries(); err != nil {
+			return Snapshot{}, err
+		}
+	}
+
+	c.mu.Lock()
+	names := append([]string(nil), c.order...)
+	c.mu.Unlock()
+
+	shot := Snapshot{Time: c.cfg.Clock.Now(), Scores: make(map[string]int32)}
+	for i := 0; i < len(names); i += infoBatch {
+		end := i + infoBatch
+		if end > len(names) {
+			end = len(names)
+		}
+
+		h, err := c.bot.ListingWithParams(
+			"/api/info",
+			map[string]string{"id": strings.Join(names[i:end], ",")},
+		)
+		if err != nil {
+			return Snapshot{}, err
+		}
+
+		for _, comment := range h.Comments {
+			// Entries removed since they were found drop out.
+			if _, removed := comment.Removal(); !removed {
+				shot.Scores[comment.Name] = comment.Score
+			}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.shots = append(c.shots, shot)
+	if c.cfg.Store != nil {
+		if err := c.cfg.Store.Save(c.key, c.shots); err != nil {
+			return shot, err
+		}
+	}
+
+	return shot, nil
+}
+
+func (c *contest) Snapshots() []Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Snapshot(nil), c.shots...)
+}
+
+func (c *contest) Tally() ([]Result, error) {
+	if _, err := c.Entries(); err != nil {
+		return nil, err
+	}
+
+	shot, err := c.Refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var results []Result
+	for _, entry := range c.list() {
+		if score, ok := shot.Scores[entry.Name]; ok {
+			results = append(results, Result{Entry: entry, Score: score})
+		}
+	}
+
+	// Ties keep the order the entries were found in, which is the order
+	// Reddit listed them.
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	for i := range results {
+		results[i].Rank = i + 1
+		if i > 0 && results[i].Score == results[i-1].Score {
+			results[i].Rank = results[i-1].Rank
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,136 @@
+package contest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/store"
+)
+
+type contestBot struct {
+	reddit.Bot
+	entries []*reddit.Comment
+	scores  map[string]int32
+	modes   []bool
+}
+
+func (c *contestBot) SetContestMode(post string, on bool) error {
+	c.modes = append(c.modes, on)
+	return nil
+}
+
+func (c *contestBot) ThreadWithOptions(
+	permalink string,
+	opts reddit.ThreadOptions,
+) (*reddit.Post, error) {
+	return &reddit.Post{Replies: c.entries}, nil
+}
+
+func (c *contestBot) ListingWithParams(path string, params map[string]string) (reddit.Harvest, error) {
+	var h reddit.Harvest
+	for _, name := range strings.Split(params["id"], ",") {
+		h.Comments = append(h.Comments, &reddit.Comment{Name: name, Score: c.scores[name]})
+	}
+	return h, nil
+}
+
+func TestContest(t *testing.T) {
+	s := store.NewMemoryStore()
+	bot := &contestBot{
+		entries: []*reddit.Comment{
+			{Name: "t1_a"},
+			{Name: "t1_b"},
+			{Name: "t1_removed", Body: "[removed]"},
+			{Name: "t1_c"},
+		},
+		scores: map[string]int32{"t1_a": 1, "t1_b": 5, "t1_c": 1},
+	}
+
+	if err := Open(bot, "t3_contest"); err != nil {
+		t.Fatal(err)
+	}
+
+	sim := clock.NewSimulation(time.Unix(0, 0))
+	cfg := Config{Permalink: "/r/sub/comments/contest/title/", Store: s, Clock: sim}
+	c, err := New(bot, cfg)
+	if err != nil {
+		t.Fatalf("failed to make contest: %v", err)
+	}
+
+	shot, err := c.Refresh()
+	if err != nil {
+		t.Fatalf("failed to refresh scores: %v", err)
+	}
+
+	if want := map[string]int32{"t1_a": 1, "t1_b": 5, "t1_c": 1}; !reflect.DeepEqual(shot.Scores, want) {
+		t.Errorf("got scores %v; wanted %v", shot.Scores, want)
+	}
+
+	sim.AdvanceTo(time.Unix(60, 0))
+	bot.scores["t1_c"] = 9
+	if err := Close(bot, "t3_contest"); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := c.Tally()
+	if err != nil {
+		t.Fatalf("failed to tally: %v", err)
+	}
+
+	var got []string
+	for _, r := range results {
+		got = append(got, strings.Repeat("#", r.Rank)+r.Entry.Name)
+	}
+	if want := []string{"#t1_c", "##t1_b", "###t1_a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got ranking %v; wanted %v", got, want)
+	}
+
+	if !reflect.DeepEqual(bot.modes, []bool{true, false}) {
+		t.Errorf("set contest mode %v", bot.modes)
+	}
+
+	restarted, err := New(bot, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shots := restarted.Snapshots()
+	if len(shots) != 2 || !shots[1].Time.Equal(time.Unix(60, 0)) || shots[1].Scores["t1_c"] != 9 {
+		t.Errorf("lost snapshots across a restart: %+v", shots)
+	}
+}
+
+func TestTallyTies(t *testing.T) {
+	bot := &contestBot{
+		entries: []*reddit.Comment{{Name: "t1_a"}, {Name: "t1_b"}, {Name: "t1_c"}},
+		scores:  map[string]int32{"t1_a": 2, "t1_b": 3, "t1_c": 2},
+	}
+
+	c, err := New(bot, Config{Permalink: "/r/sub/comments/contest/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := c.Tally()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ranks []int
+	for _, r := range results {
+		ranks = append(ranks, r.Rank)
+	}
+	if !reflect.DeepEqual(ranks, []int{1, 2, 2}) {
+		t.Errorf("got ranks %v; wanted ties to share one", ranks)
+	}
+}
+
+func TestStateKey(t *testing.T) {
+	if key := stateKey("/r/sub/comments/abc12/title/"); key != "contest-abc12" {
+		t.Errorf("got key %q", key)
+	}
+}
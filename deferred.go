@@ -0,0 +1,35 @@
+package graw
+
+import (
+	"github.com/aldarisbm/graw/botfaces"
+	"github.com/aldarisbm/graw/schedule"
+)
+
+// connectScheduler dispatches the config's deferred events to the handler as
+// they come due, if the config has a scheduler.
+func connectScheduler(
+	handler interface{},
+	c Config,
+	kill <-chan bool,
+	errs chan<- error,
+) {
+	if c.Scheduler == nil {
+		return
+	}
+
+	due := c.Scheduler.Due(kill)
+	go func() {
+		for d := range due {
+			errs <- redispatch(handler, d)
+		}
+	}()
+}
+
+// redispatch dispatches a deferred event to the handler's DeferredHandler, or
+// to the handler method it was first dispatched to.
+func redispatch(handler interface{}, d schedule.Deferred) error {
+	if dh, ok := handler.(botfaces.DeferredHandler); ok {
+		return dh.Deferred(&d)
+	}
+	return dispatch(handler, d.Event)
+}
@@ -0,0 +1,44 @@
+package graw
+
+import (
+	"testing"
+
+	"github.com/aldarisbm/graw/archive"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/schedule"
+)
+
+type reminderBot struct {
+	replayBot
+	deferred []string
+}
+
+func (r *reminderBot) Deferred(d *schedule.Deferred) error {
+	r.deferred = append(r.deferred, d.Event.Message.Name)
+	return nil
+}
+
+func TestRedispatch(t *testing.T) {
+	d := schedule.Deferred{Event: archive.Event{
+		Kind:    archive.MentionKind,
+		Message: &reddit.Message{Name: "t1_a"},
+	}}
+
+	plain := &replayBot{}
+	if err := redispatch(plain, d); err != nil {
+		t.Fatalf("failed to redispatch: %v", err)
+	}
+
+	if len(plain.mentions) != 1 {
+		t.Errorf("wanted deferred mention dispatched as a mention")
+	}
+
+	reminder := &reminderBot{}
+	if err := redispatch(reminder, d); err != nil {
+		t.Fatalf("failed to redispatch: %v", err)
+	}
+
+	if len(reminder.deferred) != 1 || len(reminder.mentions) != 0 {
+		t.Errorf("wanted deferred mention dispatched to DeferredHandler")
+	}
+}
@@ -0,0 +1,123 @@
+// Package digest delivers digests of saved search results somewhere other
+// than the bot's handlers, such as a moderator's inbox or a webhook, and posts
+// scheduled roundups of subreddits' top posts.
+package digest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/streams"
+)
+
+// Sink receives digests.
+type Sink interface {
+	Deliver(d *streams.SearchDigest) error
+}
+
+// Format renders a digest as the subject and markdown body of a message.
+func Format(d *streams.SearchDigest) (string, string) {
+	subject := fmt.Sprintf("%d new search results", len(d.Results))
+	if len(d.Results) == 1 {
+		subject = "1 new search result"
+	}
+
+	var body strings.Builder
+	for _, r := range d.Results {
+		fmt.Fprintf(
+			&body,
+			"- [%s](https://www.reddit.com%s) by /u/%s in /r/%s, matching %q\n",
+			escape(r.Post.Title),
+			r.Post.Permalink,
+			r.Post.Author,
+			r.Post.Subreddit,
+			r.Search.Query,
+		)
+	}
+	return subject, body.String()
+}
+
+// escape keeps a title from breaking out of a markdown link.
+func escape(title string) string {
+	return strings.NewReplacer("[", `\[`, "]", `\]`).Replace(title)
+}
+
+type messageSink struct {
+	acc  reddit.Account
+	user string
+}
+
+// Message returns a sink which sends digests as private messages from the
+// account to a user, or to a subreddit's moderators if user is /r/name.
+func Message(acc reddit.Account, user string) Sink {
+	return &messageSink{acc: acc, user: user}
+}
+
+func (m *messageSink) Deliver(d *streams.SearchDigest) error {
+	subject, body := Format(d)
+	return m.acc.SendMessage(m.user, subject, body)
+}
+
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// Webhook returns a sink which posts digests as JSON to the url. If client is
+// nil, http.DefaultClient is used.
+func Webhook(url string, client *http.Client) Sink {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &webhookSink{url: url, client: client}
+}
+
+// payload is the JSON a webhook receives.
+type payload struct {
+	Start   int64    `json:"start"`
+	End     int64    `json:"end"`
+	Results []result `json:"results"`
+}
+
+type result struct {
+	Query     string `json:"query"`
+	Name      string `json:"name"`
+	Title     string `json:"title"`
+	Author    string `json:"author"`
+	Subreddit string `json:"subreddit"`
+	Permalink string `json:"permalink"`
+}
+
+func (w *webhookSink) Deliver(d *streams.SearchDigest) error {
+	p := payload{Start: d.Start.Unix(), End: d.End.Unix()}
+	for _, r := range d.Results {
+		p.Results = append(p.Results, result{
+			Query:     r.Search.Query,
+			Name:      r.Post.Name,
+			Title:     r.Post.Title,
+			Author:    r.Post.Author,
+			Subreddit: r.Post.Subreddit,
+			Permalink: "https://www.reddit.com" + r.Post.Permalink,
+		})
+	}
+
+	blob, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(blob))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered digest with %s", resp.Status)
+	}
+	return nil
+}
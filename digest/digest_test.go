@@ -0,0 +1,87 @@
+package digest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/streams"
+)
+
+func testDigest() *streams.SearchDigest {
+	return &streams.SearchDigest{
+		Results: []*streams.SearchResult{{
+			Search: streams.Search{Query: "graw"},
+			Post: &reddit.Post{
+				Name:      "t3_a",
+				Title:     "Is [graw] any good?",
+				Author:    "gopher",
+				Subreddit: "golang",
+				Permalink: "/r/golang/comments/a/",
+			},
+		}},
+		Start: time.Unix(0, 0),
+		End:   time.Unix(3600, 0),
+	}
+}
+
+type messageBot struct {
+	reddit.Account
+	to, subject, body string
+}
+
+func (m *messageBot) SendMessage(user, subject, text string) error {
+	m.to, m.subject, m.body = user, subject, text
+	return nil
+}
+
+func TestMessage(t *testing.T) {
+	b := &messageBot{}
+	if err := Message(b, "/r/golang").Deliver(testDigest()); err != nil {
+		t.Fatal(err)
+	}
+
+	if b.to != "/r/golang" || b.subject != "1 new search result" {
+		t.Errorf("got message to %s about %q", b.to, b.subject)
+	}
+
+	expected := `- [Is \[graw\] any good?](https://www.reddit.com/r/golang/comments/a/)` +
+		` by /u/gopher in /r/golang, matching "graw"` + "\n"
+	if b.body != expected {
+		t.Errorf("got body %q; wanted %q", b.body, expected)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	var got payload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	if err := Webhook(srv.URL, nil).Deliver(testDigest()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.End != 3600 || len(got.Results) != 1 || got.Results[0].Query != "graw" {
+		t.Errorf("got payload %+v", got)
+	}
+}
+
+func TestWebhookFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	err := Webhook(srv.URL, nil).Deliver(testDigest())
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("got %v; wanted the webhook's status", err)
+	}
+}
@@ -0,0 +1,274 @@
+package digest
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/compose"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/summarize"
+)
+
+const (
+	// defaultPeriod is the span of the top listing roundups gather from if
+	// the config does not say.
+	defaultPeriod = "week"
+	// defaultTop is how many posts of each subreddit a roundup gathers if
+	// the config does not say.
+	defaultTop = 10
+	// maxSelfPostLength is the longest text post Reddit accepts.
+	maxSelfPostLength = 40000
+	// maxCommentLength is the longest comment Reddit accepts.
+	maxCommentLength = 10000
+)
+
+var (
+	noSourcesErr  = fmt.Errorf("a roundup needs subreddits to gather posts from")
+	noDestErr     = fmt.Errorf("a roundup needs a subreddit to be posted to")
+	noIntervalErr = fmt.Errorf("a scheduled roundup needs an interval")
+	periodErr     = fmt.Errorf(`period must be "hour", "day", "week", "month", "year", or "all"`)
+)
+
+// periods are the spans of Reddit's top listings.
+var periods = map[string]bool{
+	"hour":  true,
+	"day":   true,
+	"week":  true,
+	"month": true,
+	"year":  true,
+	"all":   true,
+}
+
+const (
+	defaultTitle = `Top posts of the {{.Period}}`
+	defaultBody  = `{{range .Sections}}## /r/{{.Subreddit}}
+
+{{range .Entries}}{{.Rank}}. [{{.Title}}]({{link .Post.Permalink}}) by /u/{{.Post.Author}}, {{.Score}} {{plural .Score "point" "points"}}
+{{else}}No posts this {{$.Period}}.
+{{end}}
+{{end}}`
+)
+
+// RoundupConfig configures a roundup: a post, or wiki page, listing the top
+// posts of a period in some subreddits.
+type RoundupConfig struct {
+	// Sources are the subreddits whose top posts are gathered.
+	Sources []string
+	// Period is the span of Reddit's top listing the posts are gathered
+	// from: "hour", "day", "week", "month", "year", or "all". If empty, it
+	// is "week".
+	Period string
+	// Top is how many posts of each subreddit are gathered. If zero, it
+	// is 10.
+	Top int
+	// Subreddit is where the roundup is posted.
+	Subreddit string
+	// Title and Body are the templates of the roundup post, in the syntax
+	// of the compose package, executed with a Roundup. If empty, a title
+	// naming the period and a numbered list of links by subreddit are
+	// used. A body too long for one post is continued in comments on it.
+	Title string
+	Body  string
+	// Compose configures the links of the templates.
+	Compose compose.Config
+	// Flair, if set, is the roundup post's link flair.
+	Flair reddit.PostFlair
+	// WikiPage, if set, is the page of Subreddit's wiki whose content the
+	// roundup replaces, instead of being posted.
+	WikiPage string
+	// Interval is how often a scheduled roundup is made, e.g. weekly.
+	Interval time.Duration
+	// Clock times scheduled roundups. If nil, the wall clock is used.
+	Clock clock.Clock
+}
+
+// Roundup is the top posts of a period, which roundup templates are executed
+// with.
+type Roundup struct {
+	Period string
+	// Time is when the roundup was gathered.
+	Time     time.Time
+	Sections []Section
+}
+
+// Section is the top posts of one subreddit.
+type Section struct {
+	Subreddit string
+	Entries   []Entry
+}
+
+// Entry is a post in a roundup.
+type Entry struct {
+	// Rank is the post's place in its subreddit's top posts, from 1.
+	Rank int
+	// Title is the post's title, escaped for a markdown link.
+	Title string
+	Score int
+	Post  *reddit.Post
+}
+
+func (c RoundupConfig) withDefaults() (RoundupConfig, error) {
+	if c.Period == "" {
+		c.Period = defaultPeriod
+	}
+	if c.Top <= 0 {
+		c.Top = defaultTop
+	}
+	if c.Title == "" {
+		c.Title = defaultTitle
+	}
+	if c.Body == "" {
+		c.Body = defaultBody
+	}
+	if c.Clock == nil {
+		c.Clock = clock.Real()
+	}
+
+	switch {
+	case len(c.Sources) == 0:
+		return c, noSourcesErr
+	case c.Subreddit == "":
+		return c, noDestErr
+	case !periods[c.Period]:
+		return c, periodErr
+	}
+	return c, nil
+}
+
+// Gather returns the top posts of the config's period in its subreddits.
+func Gather(scanner reddit.Scanner, c RoundupConfig) (*Roundup, error) {
+	c, err := c.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Roundup{Period: c.Period, Time: c.Clock.Now()}
+	for _, sub := range c.Sources {
+		h, err := scanner.ListingWithParams(
+			"/r/"+sub+"/top",
+			map[string]string{"t": c.Period, "limit": strconv.Itoa(c.Top)},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		s := Section{Subreddit: sub}
+		for i, p := range h.Posts {
+			if i == c.Top {
+				break
+			}
+			s.Entries = append(s.Entries, Entry{
+				Rank:  i + 1,
+				Title: escape(p.Title),
+				Score: int(p.Score),
+				Post:  p,
+			})
+		}
+		r.Sections = append(r.Sections, s)
+	}
+
+	return r, nil
+}
+
+// Publish posts the roundup as the config says: as a flaired text post
+// continued in comments if it is too long for one, or as the content of a wiki
+// page.
+func Publish(bot reddit.Bot, c RoundupConfig, r *Roundup) error {
+	c, err := c.withDefaults()
+	if err != nil {
+		return err
+	}
+
+	comp := compose.New(c.Compose)
+	if err := comp.Add("title", c.Title); err != nil {
+		return err
+	}
+	if err := comp.Add("body", c.Body); err != nil {
+		return err
+	}
+
+	title, err := comp.Compose("title", r)
+	if err != nil {
+		return err
+	}
+
+	body, err := comp.Compose("body", r)
+	if err != nil {
+		return err
+	}
+
+	if c.WikiPage != "" {
+		return bot.EditWikiPage(c.Subreddit, c.WikiPage, reddit.WikiEdit{
+			Content: body,
+			Reason:  title,
+		})
+	}
+
+	parts := summarize.Split(body, maxSelfPostLength)
+	if len(parts) == 0 {
+		parts = []string{""}
+	}
+
+	post, err := bot.GetPostSelfWithFlair(c.Subreddit, title, parts[0], c.Flair)
+	if err != nil {
+		return err
+	}
+
+	for _, part := range parts[1:] {
+		for _, comment := range summarize.Split(part, maxCommentLength) {
+			if err := bot.Reply(post.Name, comment); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Post gathers and publishes a roundup.
+func Post(bot reddit.Bot, c RoundupConfig) error {
+	r, err := Gather(bot, c)
+	if err != nil {
+		return err
+	}
+	return Publish(bot, c, r)
+}
+
+// Schedule posts a roundup every interval of the config until kill is closed,
+// sending the errors of each to errs.
+func Schedule(
+	bot reddit.Bot,
+	kill <-chan bool,
+	errs chan<- error,
+	c RoundupConfig,
+) error {
+	c, err := c.withDefaults()
+	if err != nil {
+		return err
+	}
+
+	if c.Interval <= 0 {
+		return noIntervalErr
+	}
+
+	go func() {
+		for {
+			select {
+			case <-kill:
+				return
+			case <-c.Clock.After(c.Interval):
+			}
+
+			if err := Post(bot, c); err != nil {
+				select {
+				case errs <- err:
+				case <-kill:
+					return
+				}
+			}
+		}
+	}()
+
+	return nil
+}
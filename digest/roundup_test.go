@@ -0,0 +1,158 @@
+package digest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+type roundupBot struct {
+	reddit.Bot
+	listings map[string][]*reddit.Post
+	params   map[string]string
+	title    string
+	post     string
+	flair    reddit.PostFlair
+	replies  []string
+	wiki     string
+	edit     reddit.WikiEdit
+}
+
+func (r *roundupBot) ListingWithParams(path string, params map[string]string) (reddit.Harvest, error) {
+	r.params = params
+	posts, ok := r.listings[path]
+	if !ok {
+		return reddit.Harvest{}, fmt.Errorf("unexpected listing %s", path)
+	}
+	return reddit.Harvest{Posts: posts}, nil
+}
+
+func (r *roundupBot) GetPostSelfWithFlair(
+	subreddit, title, text string,
+	flair reddit.PostFlair,
+) (reddit.Submission, error) {
+	r.title, r.post, r.flair = title, text, flair
+	return reddit.Submission{Name: "t3_roundup"}, nil
+}
+
+func (r *roundupBot) Reply(parent, text string) error {
+	r.replies = append(r.replies, parent+": "+text)
+	return nil
+}
+
+func (r *roundupBot) EditWikiPage(subreddit, page string, edit reddit.WikiEdit) error {
+	r.wiki, r.edit = subreddit+"/"+page, edit
+	return nil
+}
+
+func testRoundupBot() *roundupBot {
+	return &roundupBot{listings: map[string][]*reddit.Post{
+		"/r/golang/top": {
+			{Title: "Go [2] is out", Author: "gopher", Score: 100, Permalink: "/r/golang/comments/a/"},
+			{Title: "graw", Author: "bot", Score: 1, Permalink: "/r/golang/comments/b/"},
+		},
+		"/r/rust/top": nil,
+	}}
+}
+
+func TestPostRoundup(t *testing.T) {
+	bot := testRoundupBot()
+	c := RoundupConfig{
+		Sources:   []string{"golang", "rust"},
+		Period:    "day",
+		Top:       5,
+		Subreddit: "roundups",
+		Flair:     reddit.PostFlair{TemplateID: "weekly"},
+	}
+	if err := Post(bot, c); err != nil {
+		t.Fatalf("failed to post roundup: %v", err)
+	}
+
+	if bot.params["t"] != "day" || bot.params["limit"] != "5" {
+		t.Errorf("gathered with params %v", bot.params)
+	}
+
+	if bot.title != "Top posts of the day" {
+		t.Errorf("got title %q", bot.title)
+	}
+
+	if bot.flair.TemplateID != "weekly" {
+		t.Errorf("posted with flair %+v", bot.flair)
+	}
+
+	for _, line := range []string{
+		"## /r/golang",
+		"1. [Go \\[2\\] is out](https://www.reddit.com/r/golang/comments/a/) by /u/gopher, 100 points",
+		"2. [graw](https://www.reddit.com/r/golang/comments/b/) by /u/bot, 1 point",
+		"## /r/rust",
+		"No posts this day.",
+	} {
+		if !strings.Contains(bot.post, line) {
+			t.Errorf("roundup lacks %q:\n%s", line, bot.post)
+		}
+	}
+
+	if len(bot.replies) != 0 {
+		t.Errorf("continued a short roundup in comments: %v", bot.replies)
+	}
+}
+
+func TestPostRoundupSplits(t *testing.T) {
+	bot := testRoundupBot()
+	c := RoundupConfig{
+		Sources:   []string{"golang"},
+		Subreddit: "roundups",
+		Body:      strings.Repeat("word ", 10000),
+	}
+	if err := Post(bot, c); err != nil {
+		t.Fatalf("failed to post roundup: %v", err)
+	}
+
+	if len(bot.post) > maxSelfPostLength {
+		t.Errorf("posted %d characters", len(bot.post))
+	}
+
+	if len(bot.replies) != 1 || !strings.HasPrefix(bot.replies[0], "t3_roundup: word") {
+		t.Errorf("continued roundup in %v", bot.replies)
+	}
+}
+
+func TestPostRoundupWiki(t *testing.T) {
+	bot := testRoundupBot()
+	c := RoundupConfig{
+		Sources:   []string{"golang"},
+		Subreddit: "roundups",
+		WikiPage:  "top",
+		Title:     "Roundup",
+		Body:      "{{range .Sections}}{{len .Entries}} posts{{end}}",
+	}
+	if err := Post(bot, c); err != nil {
+		t.Fatalf("failed to update roundup page: %v", err)
+	}
+
+	if bot.wiki != "roundups/top" || bot.edit.Content != "2 posts" || bot.edit.Reason != "Roundup" {
+		t.Errorf("edited %s with %+v", bot.wiki, bot.edit)
+	}
+
+	if bot.post != "" {
+		t.Errorf("posted a roundup meant for the wiki")
+	}
+}
+
+func TestRoundupConfigErrors(t *testing.T) {
+	for i, test := range []struct {
+		c   RoundupConfig
+		err error
+	}{
+		{RoundupConfig{Subreddit: "roundups"}, noSourcesErr},
+		{RoundupConfig{Sources: []string{"golang"}}, noDestErr},
+		{RoundupConfig{Sources: []string{"golang"}, Subreddit: "roundups", Period: "fortnight"}, periodErr},
+		{RoundupConfig{Sources: []string{"golang"}, Subreddit: "roundups"}, noIntervalErr},
+	} {
+		if err := Schedule(testRoundupBot(), nil, nil, test.c); err != test.err {
+			t.Errorf("%d: got error %v; wanted %v", i, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package graw
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// customFeedsEnv holds custom feeds as comma separated "user/feed" pairs.
+const customFeedsEnv = "GRAW_CUSTOM_FEEDS"
+
+var customFeedErr = fmt.Errorf(`%s must hold "user/feed" pairs`, customFeedsEnv)
+
+// envConfig maps environment variables to the Config fields they set. Lists
+// are comma separated, booleans are parsed by strconv.ParseBool, and durations
+// by time.ParseDuration.
+func envConfig(c *Config) map[string]interface{} {
+	return map[string]interface{}{
+		"GRAW_SUBREDDITS":         &c.Subreddits,
+		"GRAW_SUBREDDIT_COMMENTS": &c.SubredditComments,
+		"GRAW_USERS":              &c.Users,
+		"GRAW_RISING":             &c.Rising,
+		"GRAW_GILDED":             &c.Gilded,
+		"GRAW_REPORTS":            &c.Reports,
+		"GRAW_MODQUEUE":           &c.Modqueue,
+		"GRAW_SPAM":               &c.Spam,
+		"GRAW_EDITED":             &c.Edited,
+		"GRAW_MODMAIL":            &c.Modmail,
+		"GRAW_LIVE_THREADS":       &c.LiveThreads,
+		"GRAW_POST_REPLIES":       &c.PostReplies,
+		"GRAW_COMMENT_REPLIES":    &c.CommentReplies,
+		"GRAW_MENTIONS":           &c.Mentions,
+		"GRAW_MESSAGES":           &c.Messages,
+		"GRAW_SKIP_OWN_CONTENT":   &c.SkipOwnContent,
+		"GRAW_REVOKE_ON_SHUTDOWN": &c.RevokeOnShutdown,
+		"GRAW_RESUME_FROM":        &c.ResumeFrom,
+		"GRAW_LISTING_LIMIT":      &c.ListingLimit,
+		"GRAW_MIN_REPORTS":        &c.MinReports,
+		"GRAW_ACCOUNT_SNAPSHOTS":  &c.AccountSnapshots,
+		"GRAW_RISING_INTERVAL":    &c.RisingInterval,
+		"GRAW_GILDED_INTERVAL":    &c.GildedInterval,
+		"GRAW_REPORT_INTERVAL":    &c.ReportInterval,
+		"GRAW_QUEUE_INTERVAL":     &c.QueueInterval,
+		"GRAW_MODMAIL_INTERVAL":   &c.ModmailInterval,
+		"GRAW_LIVE_FALLBACK":      &c.LiveFallback,
+	}
+}
+
+// ConfigFromEnv returns a Config read from environment variables, so a
+// containerized bot needs no config file. The variables are
+//
+//	GRAW_SUBREDDITS, GRAW_SUBREDDIT_COMMENTS, GRAW_USERS, GRAW_RISING,
+//	GRAW_GILDED, GRAW_REPORTS, GRAW_MODQUEUE, GRAW_SPAM, GRAW_EDITED,
+//	GRAW_MODMAIL, GRAW_LIVE_THREADS: comma separated names, e.g. "golang,rust"
+//	GRAW_CUSTOM_FEEDS: comma separated "user/feed" pairs
+//	GRAW_POST_REPLIES, GRAW_COMMENT_REPLIES, GRAW_MENTIONS, GRAW_MESSAGES,
+//	GRAW_SKIP_OWN_CONTENT, GRAW_REVOKE_ON_SHUTDOWN: "true" or "false"
+//	GRAW_RESUME_FROM: a fullname or unix timestamp
+//	GRAW_LISTING_LIMIT, GRAW_MIN_REPORTS: integers
+//	GRAW_ACCOUNT_SNAPSHOTS, GRAW_RISING_INTERVAL, GRAW_GILDED_INTERVAL,
+//	GRAW_REPORT_INTERVAL, GRAW_QUEUE_INTERVAL, GRAW_MODMAIL_INTERVAL,
+//	GRAW_LIVE_FALLBACK: durations, e.g. "5m"
+//
+// each setting the Config field of the same name. Unset variables leave their
+// fields zero. Options which take Go values, such as handlers and stores, must
+// be set on the returned Config.
+func ConfigFromEnv() (Config, error) {
+	return configFromEnv(os.LookupEnv)
+}
+
+// FromEnv returns a bot configured by reddit.NewBotFromEnv and a Config
+// returned by ConfigFromEnv, ready for Run.
+func FromEnv() (reddit.Bot, Config, error) {
+	c, err := ConfigFromEnv()
+	if err != nil {
+		return nil, Config{}, err
+	}
+
+	bot, err := reddit.NewBotFromEnv()
+	if err != nil {
+		return nil, Config{}, err
+	}
+
+	return bot, c, nil
+}
+
+func configFromEnv(lookup func(string) (string, bool)) (Config, error) {
+	c := Config{}
+	for name, field := range envConfig(&c) {
+		value, ok := lookup(name)
+		if !ok || value == "" {
+			continue
+		}
+
+		if err := setFromEnv(field, value); err != nil {
+			return Config{}, fmt.Errorf("%s: %v", name, err)
+		}
+	}
+
+	if value, ok := lookup(customFeedsEnv); ok && value != "" {
+		c.CustomFeeds = make(map[string][]string)
+		for _, pair := range envList(value) {
+			parts := strings.Split(pair, "/")
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return Config{}, customFeedErr
+			}
+			c.CustomFeeds[parts[0]] = append(c.CustomFeeds[parts[0]], parts[1])
+		}
+	}
+
+	return c, nil
+}
+
+func setFromEnv(field interface{}, value string) error {
+	var err error
+	switch f := field.(type) {
+	case *[]string:
+		*f = envList(value)
+	case *string:
+		*f = value
+	case *bool:
+		*f, err = strconv.ParseBool(value)
+	case *int:
+		*f, err = strconv.Atoi(value)
+	case *time.Duration:
+		*f, err = time.ParseDuration(value)
+	}
+	return err
+}
+
+// envList splits a comma separated list, dropping empty items.
+func envList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
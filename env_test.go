@@ -0,0 +1,53 @@
+package graw
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	env := map[string]string{
+		"GRAW_SUBREDDITS":         "golang, rust,,",
+		"GRAW_CUSTOM_FEEDS":       "a/one,a/two,b/three",
+		"GRAW_MENTIONS":           "true",
+		"GRAW_LISTING_LIMIT":      "50",
+		"GRAW_RISING_INTERVAL":    "5m",
+		"GRAW_RESUME_FROM":        "t3_abc",
+		"GRAW_REVOKE_ON_SHUTDOWN": "",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	c, err := configFromEnv(lookup)
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+
+	wanted := Config{
+		Subreddits: []string{"golang", "rust"},
+		CustomFeeds: map[string][]string{
+			"a": {"one", "two"},
+			"b": {"three"},
+		},
+		Mentions:       true,
+		ListingLimit:   50,
+		RisingInterval: 5 * time.Minute,
+		ResumeFrom:     "t3_abc",
+	}
+	if !reflect.DeepEqual(c, wanted) {
+		t.Errorf("got %+v; wanted %+v", c, wanted)
+	}
+
+	for name, value := range map[string]string{
+		"GRAW_MENTIONS":     "sometimes",
+		"GRAW_CUSTOM_FEEDS": "feed",
+	} {
+		env = map[string]string{name: value}
+		if _, err := configFromEnv(lookup); err == nil {
+			t.Errorf("wanted an error for %s=%s", name, value)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Package fetch makes HTTP requests to sites other than Reddit, for handlers
+// which enrich events from the web, e.g. by resolving a link or checking an
+// external API.
+//
+// Requests are rate limited per host, responses are size capped, and every
+// request, including its wait for the rate limit, has a deadline, so a slow
+// or hostile site can neither be flooded by the bot nor stall its handlers.
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+)
+
+const (
+	defaultRate    = time.Second
+	defaultMaxSize = 1 << 20
+	defaultTimeout = 10 * time.Second
+)
+
+var (
+	// TooLargeErr is returned for responses larger than the size cap.
+	TooLargeErr = fmt.Errorf("response is larger than the fetch size cap")
+	// BusyErr is returned for requests which would wait past their
+	// deadline for the host's rate limit.
+	BusyErr = fmt.Errorf("host's rate limit would delay the request past its deadline")
+)
+
+// Config configures a Fetcher.
+type Config struct {
+	// Agent is the user agent sent with every request.
+	Agent string
+	// Rate is the minimum time between requests to the same host. If
+	// zero, it is one second.
+	Rate time.Duration
+	// MaxSize is the largest response body, in bytes, a request reads. If
+	// zero, it is one megabyte.
+	MaxSize int64
+	// Timeout is the deadline of each request, including its wait for the
+	// rate limit. If zero, it is ten seconds.
+	Timeout time.Duration
+	// Client makes the requests. If nil, http.DefaultClient is used.
+	Client *http.Client
+	// Clock times the rate limit. If nil, the wall clock is used.
+	Clock clock.Clock
+}
+
+// Fetcher makes rate limited HTTP requests. It is safe to use from multiple
+// goroutines.
+type Fetcher interface {
+	// Get fetches the body of the page at the url.
+	Get(url string) ([]byte, error)
+	// Do sends the request and returns the body of the response. Responses
+	// with a status other than 2xx are errors.
+	Do(req *http.Request) ([]byte, error)
+}
+
+type fetcher struct {
+	cfg Config
+
+	mu sync.Mutex
+	// next holds, per host, the earliest time the next request to it may
+	// be sent.
+	next map[string]time.Time
+}
+
+// New returns a Fetcher.
+func New(c Config) Fetcher {
+	if c.Rate == 0 {
+		c.Rate = defaultRate
+	}
+	if c.MaxSize == 0 {
+		c.MaxSize = defaultMaxSize
+	}
+	if c.Timeout == 0 {
+		c.Timeout = defaultTimeout
+	}
+	if c.Client == nil {
+		c.Client = http.DefaultClient
+	}
+	if c.Clock == nil {
+		c.Clock = clock.Real()
+	}
+
+	return &fetcher{cfg: c, next: make(map[string]time.Time)}
+}
+
+func (f *fetcher) Get(rawurl string) ([]byte, error) {
+	req, err := http.NewRequest("GET", rawurl, nil)
+	if err != nil {
+		return nil, err
+	}
+	return f.Do(req)
+}
+
+func (f *fetcher) Do(req *http.Request) ([]byte, error) {
+	waited, err := f.wait(req.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), f.cfg.Timeout-waited)
+	defer cancel()
+	req = req.WithContext(ctx)
+	if f.cfg.Agent != "" {
+		req.Header.Set("User-Agent", f.cfg.Agent)
+	}
+
+	resp, err := f.cfg.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("bad response code: %d", resp.StatusCode)
+	}
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(io.LimitReader(resp.Body, f.cfg.MaxSize+1)); err != nil {
+		return nil, err
+	}
+
+	if int64(buf.Len()) > f.cfg.MaxSize {
+		return nil, TooLargeErr
+	}
+
+	return buf.Bytes(), nil
+}
+
+// wait reserves the next slot in the host's rate limit, sleeps until it, and
+// returns how long it slept. The wait uses up part of the request's deadline;
+// if it would use all of it, the slot is not reserved and BusyErr is
+// returned.
+func (f *fetcher) wait(u *url.URL) (time.Duration, error) {
+	host := strings.ToLower(u.Hostname())
+	now := f.cfg.Clock.Now()
+
+	f.mu.Lock()
+	slot := f.next[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	wait := slot.Sub(now)
+	if wait >= f.cfg.Timeout {
+		f.mu.Unlock()
+		return 0, BusyErr
+	}
+	f.next[host] = slot.Add(f.cfg.Rate)
+	f.mu.Unlock()
+
+	if wait > 0 {
+		f.cfg.Clock.Sleep(wait)
+	}
+	return wait, nil
+}
@@ -0,0 +1,61 @@
+package fetch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+)
+
+func TestGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("User-Agent") != "test-agent" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(strings.TrimPrefix(r.URL.Path, "/")))
+		},
+	))
+	defer server.Close()
+
+	f := New(Config{Agent: "test-agent", MaxSize: 5, Rate: time.Millisecond})
+	if body, err := f.Get(server.URL + "/hello"); err != nil || string(body) != "hello" {
+		t.Errorf("got %q, %v; wanted hello", body, err)
+	}
+
+	if _, err := f.Get(server.URL + "/toolong"); err != TooLargeErr {
+		t.Errorf("got %v; wanted TooLargeErr", err)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	start := time.Unix(0, 0)
+	sim := clock.NewSimulation(start)
+	f := New(Config{Rate: time.Minute, Timeout: 90 * time.Second, Clock: sim}).(*fetcher)
+
+	for i, host := range []string{"a.com", "b.com", "a.com"} {
+		u, _ := http.NewRequest("GET", "https://"+host+"/", nil)
+		if _, err := f.wait(u.URL); err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+	}
+
+	if elapsed := sim.Now().Sub(start); elapsed != time.Minute {
+		t.Errorf("got %v of waiting; wanted one minute, for a.com's second request", elapsed)
+	}
+
+	u, _ := http.NewRequest("GET", "https://A.com/", nil)
+	if _, err := f.wait(u.URL); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// a.com's next slot is now two minutes away, beyond the deadline.
+	f.next["a.com"] = sim.Now().Add(2 * time.Minute)
+	if _, err := f.wait(u.URL); err != BusyErr {
+		t.Errorf("got %v; wanted BusyErr", err)
+	}
+}
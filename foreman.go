@@ -1,17 +1,21 @@
 package graw
 
 import (
-	"log"
-
-	"github.com/turnage/graw/botfaces"
-	"github.com/turnage/graw/reddit"
+	"github.com/aldarisbm/graw/botfaces"
+	"github.com/aldarisbm/graw/logging"
+	"github.com/aldarisbm/graw/reddit"
 )
 
+// launch sets up the handler and starts a foreman over the run, returning its
+// stop() and wait() functions. Once the foreman stops, the run's streams are
+// killed and cancel, if set, is called, e.g. to cancel their requests in
+// flight.
 func launch(
 	handler interface{},
 	kill chan bool,
 	errs <-chan error,
-	logger *log.Logger,
+	logger logging.Logger,
+	cancel func(),
 ) (
 	func(),
 	func() error,
@@ -19,6 +23,9 @@ func launch(
 ) {
 	if setup, ok := handler.(botfaces.Loader); ok {
 		if err := setup.SetUp(); err != nil {
+			if cancel != nil {
+				cancel()
+			}
 			return nil, nil, err
 		}
 	}
@@ -33,7 +40,11 @@ func launch(
 	foremanError := make(chan error)
 
 	go func() {
-		foremanError <- foreman(foremanKiller, kill, errs, logger)
+		err := foreman(foremanKiller, kill, errs, logger)
+		if cancel != nil {
+			cancel()
+		}
+		foremanError <- err
 	}()
 
 	stop := func() {
@@ -53,7 +64,7 @@ func foreman(
 	kill <-chan bool,
 	killChildren chan<- bool,
 	errs <-chan error,
-	logger *log.Logger,
+	logger logging.Logger,
 ) error {
 	defer close(killChildren)
 	for {
@@ -64,12 +75,13 @@ func foreman(
 			switch err {
 			case nil:
 			case reddit.BusyErr:
-				logger.Printf("Reddit was busy; staying up.")
+				logger.Log(logging.Warn, "Reddit was busy; staying up.")
 			case reddit.GatewayErr:
-				logger.Printf("Bad gateway error; staying up.")
+				logger.Log(logging.Warn, "Bad gateway error; staying up.")
 			case reddit.GatewayTimeoutErr:
-				logger.Printf("Gateway timeout; staying up.")
+				logger.Log(logging.Warn, "Gateway timeout; staying up.")
 			default:
+				logger.Log(logging.Error, "Stopping.", logging.F("err", err))
 				return err
 			}
 		}
@@ -1,13 +1,13 @@
 package graw
 
 import (
+	"context"
 	"fmt"
-	"io/ioutil"
-	"log"
 	"testing"
 	"time"
 
-	"github.com/turnage/graw/reddit"
+	"github.com/aldarisbm/graw/logging"
+	"github.com/aldarisbm/graw/reddit"
 )
 
 type mockBot struct {
@@ -41,6 +41,32 @@ func TestForemanControls(t *testing.T) {
 	}
 }
 
+func TestForemanCancel(t *testing.T) {
+	kill := make(chan bool)
+	ctx, cancel := context.WithCancel(context.Background())
+	stop, wait, err := launch(nil, kill, make(chan error), logging.Discard(), cancel)
+	if err != nil {
+		t.Fatalf("error launching the foreman: %v", err)
+	}
+
+	stop()
+	if err := wait(); err != nil {
+		t.Fatalf("error from foreman run: %v", err)
+	}
+
+	// Requests in flight are cancelled once the streams are killed.
+	select {
+	case <-kill:
+	default:
+		t.Errorf("streams were not killed")
+	}
+	select {
+	case <-ctx.Done():
+	default:
+		t.Errorf("requests in flight were not cancelled")
+	}
+}
+
 func TestForemanError(t *testing.T) {
 	errs := make(chan error)
 	result, _ := testForeman(nil, errs, t)
@@ -69,12 +95,12 @@ func testForeman(handler interface{}, errs chan error, t *testing.T) (
 ) {
 	kill := make(chan bool)
 	result := make(chan error)
-	logger := log.New(ioutil.Discard, "", 0)
+	logger := logging.Discard()
 	if errs == nil {
 		errs = make(chan error)
 	}
 
-	stop, wait, err := launch(handler, kill, errs, logger)
+	stop, wait, err := launch(handler, kill, errs, logger, nil)
 	if err != nil {
 		t.Fatalf("error launching the foreman: %v", err)
 	}
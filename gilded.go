@@ -0,0 +1,55 @@
+package graw
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aldarisbm/graw/botfaces"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/streams"
+)
+
+// defaultGildedInterval is how often gilded listings are read if the Config
+// gives no interval.
+const defaultGildedInterval = time.Minute
+
+var gildedHandlerErr = fmt.Errorf(
+	"You must implement GildedHandler to take gilded feeds.",
+)
+
+// connectGilded connects the gilded listings of the config's subreddits to
+// the handler, if it names any.
+func connectGilded(
+	handler interface{},
+	sc reddit.Scanner,
+	c Config,
+	kill <-chan bool,
+	errs chan<- error,
+) error {
+	if len(c.Gilded) == 0 {
+		return nil
+	}
+
+	gh, ok := handler.(botfaces.GildedHandler)
+	if !ok {
+		return gildedHandlerErr
+	}
+
+	cfg := streams.GildedConfig{Interval: c.GildedInterval}
+	if cfg.Interval == 0 {
+		cfg.Interval = defaultGildedInterval
+	}
+
+	gilded, err := streams.Gilded(sc, kill, errs, cfg, c.Gilded...)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for a := range gilded {
+			errs <- gh.Gilded(a)
+		}
+	}()
+
+	return nil
+}
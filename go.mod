@@ -9,9 +9,9 @@ require (
 	github.com/kylelemons/godebug v1.1.0
 	github.com/mitchellh/mapstructure v1.1.2
 	github.com/stretchr/testify v1.6.1 // indirect
-	github.com/turnage/graw v0.0.0-20200719190030-8ef4107c4a29
 	github.com/turnage/redditproto v0.0.0-20151223012412-afedf1b6eddb
 	golang.org/x/net v0.0.0-20191209160850-c0dbc17a3553
 	golang.org/x/oauth2 v0.0.0-20191202225959-858c2ad4c8b6
 	gopkg.in/alecthomas/kingpin.v2 v2.2.6
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
 )
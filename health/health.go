@@ -0,0 +1,241 @@
+// Package health scores how well each of Reddit's endpoints is serving a bot,
+// from exponentially weighted moving averages of their latency and error
+// rate, so a bot can tell when Reddit is degraded and hold back its less
+// important requests to keep its essential streams responsive.
+//
+// Wrap a bot's client with a Monitor,
+//
+//	m := health.New(health.Config{})
+//	reddit.BotConfig{..., WrapClient: m.Wrap}
+//
+// and mark the requests which can wait with NonCritical:
+//
+//	bot.WithContext(health.NonCritical(ctx)).UserTrophies(user)
+package health
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+)
+
+const (
+	// defaultAlpha weighs each request in the averages if the Config
+	// does not say.
+	defaultAlpha = 0.1
+	// defaultSlow is the average latency which degrades an endpoint if
+	// the Config does not say.
+	defaultSlow = 5 * time.Second
+	// defaultErrorRate is the average error rate which degrades an
+	// endpoint if the Config does not say.
+	defaultErrorRate = 0.25
+	// defaultMinRequests is how many requests an endpoint must have served
+	// before it is judged, if the Config does not say.
+	defaultMinRequests = 10
+	// defaultDelay is how long non critical requests wait while Reddit is
+	// degraded, if the Config does not say.
+	defaultDelay = 30 * time.Second
+)
+
+// Config configures a Monitor.
+type Config struct {
+	// Alpha is the weight, from 0 to 1, of each request in the moving
+	// averages; higher values follow changes faster. If zero, it is 0.1.
+	Alpha float64
+	// An endpoint is degraded while its average latency is at least Slow,
+	// five seconds if zero, or its average error rate is at least
+	// ErrorRate, a quarter if zero, once it has served MinRequests
+	// requests, ten if zero.
+	Slow        time.Duration
+	ErrorRate   float64
+	MinRequests int
+	// Delay is how long requests marked NonCritical wait before they are
+	// sent while any endpoint is degraded. If zero, they wait 30 seconds;
+	// if negative, they are not delayed.
+	Delay time.Duration
+	// Clock times the requests and delays. If nil, the wall clock is used.
+	Clock clock.Clock
+}
+
+// Endpoint is the health of one of Reddit's endpoints.
+type Endpoint struct {
+	// Endpoint is the method and path of the endpoint, with the names of
+	// subreddits, users, and things replaced by "*", e.g.
+	// "GET /r/*/comments".
+	Endpoint string `json:"endpoint"`
+	Requests uint64 `json:"requests"`
+	// Latency and ErrorRate are the moving averages of the endpoint's
+	// latency and of the fraction of its requests which failed.
+	Latency   time.Duration `json:"latency"`
+	ErrorRate float64       `json:"error_rate"`
+	Degraded  bool          `json:"degraded"`
+}
+
+// Monitor tracks the health of the endpoints of the requests sent through the
+// clients it wraps. It is safe to use from multiple goroutines.
+type Monitor interface {
+	// Wrap wraps a bot's client, for the WrapClient field of
+	// reddit.BotConfig and reddit.ScriptConfig.
+	Wrap(d reddit.Doer) reddit.Doer
+	// Endpoints returns the health of every endpoint requested, sorted by
+	// endpoint, e.g. to publish to a metrics system.
+	Endpoints() []Endpoint
+	// Degraded reports whether any endpoint is degraded.
+	Degraded() bool
+}
+
+// nonCriticalKey marks the contexts of requests which can wait.
+type nonCriticalKey struct{}
+
+// NonCritical returns a context which marks the requests made with it, e.g.
+// through a bot's WithContext, as able to wait while Reddit is degraded.
+func NonCritical(ctx context.Context) context.Context {
+	return context.WithValue(ctx, nonCriticalKey{}, true)
+}
+
+// average is the running health of an endpoint.
+type average struct {
+	requests  uint64
+	latency   float64
+	errorRate float64
+}
+
+type monitor struct {
+	cfg Config
+
+	mu        sync.Mutex
+	endpoints map[string]*average
+}
+
+// New returns a Monitor which has seen no requests.
+func New(c Config) Monitor {
+	if c.Alpha <= 0 || c.Alpha > 1 {
+		c.Alpha = defaultAlpha
+	}
+	if c.Slow <= 0 {
+		c.Slow = defaultSlow
+	}
+	if c.ErrorRate <= 0 {
+		c.ErrorRate = defaultErrorRate
+	}
+	if c.MinRequests <= 0 {
+		c.MinRequests = defaultMinRequests
+	}
+	if c.Delay == 0 {
+		c.Delay = defaultDelay
+	}
+	if c.Clock == nil {
+		c.Clock = clock.Real()
+	}
+	return &monitor{cfg: c, endpoints: make(map[string]*average)}
+}
+
+func (m *monitor) Wrap(d reddit.Doer) reddit.Doer {
+	return &doer{Doer: d, m: m}
+}
+
+func (m *monitor) Endpoints() []Endpoint {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	endpoints := make([]Endpoint, 0, len(m.endpoints))
+	for name, a := range m.endpoints {
+		endpoints = append(endpoints, Endpoint{
+			Endpoint:  name,
+			Requests:  a.requests,
+			Latency:   time.Duration(a.latency),
+			ErrorRate: a.errorRate,
+			Degraded:  m.degraded(a),
+		})
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].Endpoint < endpoints[j].Endpoint
+	})
+	return endpoints
+}
+
+func (m *monitor) Degraded() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, a := range m.endpoints {
+		if m.degraded(a) {
+			return true
+		}
+	}
+	return false
+}
+
+// degraded reports whether the endpoint is degraded. The caller must hold the
+// lock.
+func (m *monitor) degraded(a *average) bool {
+	if a.requests < uint64(m.cfg.MinRequests) {
+		return false
+	}
+	return a.latency >= float64(m.cfg.Slow) || a.errorRate >= m.cfg.ErrorRate
+}
+
+// observe records a request to the endpoint.
+func (m *monitor) observe(endpoint string, latency time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a, ok := m.endpoints[endpoint]
+	if !ok {
+		a = &average{}
+		m.endpoints[endpoint] = a
+	}
+
+	failure := 0.0
+	if failed {
+		failure = 1
+	}
+
+	// The first request seeds the averages, so one slow first request
+	// does not take many more to forget.
+	if a.requests == 0 {
+		a.latency = float64(latency)
+		a.errorRate = failure
+	} else {
+		alpha := m.cfg.Alpha
+		a.latency += alpha * (float64(latency) - a.latency)
+		a.errorRate += alpha * (failure - a.errorRate)
+	}
+	a.requests++
+}
+
+type doer struct {
+	reddit.Doer
+	m *monitor
+}
+
+func (d *doer) Do(req *http.Request) ([]byte, error) {
+	if err := d.wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	start := d.m.cfg.Clock.Now()
+	body, err := d.Doer.Do(req)
+	d.m.observe(reddit.Endpoint(req), d.m.cfg.Clock.Now().Sub(start), err != nil)
+	return body, err
+}
+
+// wait delays non critical requests while Reddit is degraded, unless their
+// context is done first.
+func (d *doer) wait(ctx context.Context) error {
+	if d.m.cfg.Delay < 0 || ctx.Value(nonCriticalKey{}) == nil || !d.m.Degraded() {
+		return nil
+	}
+
+	select {
+	case <-d.m.cfg.Clock.After(d.m.cfg.Delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,92 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// slowDoer takes latency to answer each request, failing with err.
+type slowDoer struct {
+	sim     clock.Simulation
+	latency time.Duration
+	err     error
+}
+
+func (s *slowDoer) Do(req *http.Request) ([]byte, error) {
+	s.sim.Sleep(s.latency)
+	return nil, s.err
+}
+
+func request(path string) *http.Request {
+	return &http.Request{Method: "GET", URL: &url.URL{Path: path}}
+}
+
+func TestDegraded(t *testing.T) {
+	sim := clock.NewSimulation(time.Unix(0, 0))
+	m := New(Config{MinRequests: 3, Slow: time.Second, Clock: sim})
+	slow := &slowDoer{sim: sim, latency: 2 * time.Second}
+	d := m.Wrap(slow)
+
+	for i := 0; i < 3; i++ {
+		if m.Degraded() {
+			t.Errorf("degraded after %d requests; wanted MinRequests first", i)
+		}
+		d.Do(request("/r/sub/new"))
+	}
+	if !m.Degraded() {
+		t.Fatalf("wanted degraded after slow requests")
+	}
+
+	endpoints := m.Endpoints()
+	if len(endpoints) != 1 || endpoints[0].Latency != 2*time.Second || !endpoints[0].Degraded {
+		t.Errorf("got endpoints %+v", endpoints)
+	}
+
+	// Non critical requests wait while degraded; others do not.
+	slow.latency = 0
+	start := sim.Now()
+	d.Do(request("/api/info"))
+	if waited := sim.Now().Sub(start); waited != 0 {
+		t.Errorf("critical request waited %v", waited)
+	}
+	done := make(chan bool)
+	go func() {
+		d.Do(request("/api/info").WithContext(NonCritical(context.Background())))
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Errorf("non critical request did not wait")
+	case <-time.After(10 * time.Millisecond):
+	}
+	sim.AdvanceTo(start.Add(defaultDelay))
+	<-done
+}
+
+func TestErrorRate(t *testing.T) {
+	sim := clock.NewSimulation(time.Unix(0, 0))
+	m := New(Config{MinRequests: 1, Alpha: 0.5, ErrorRate: 0.5, Clock: sim})
+	failing := &slowDoer{sim: sim, err: reddit.BusyErr}
+	d := m.Wrap(failing)
+
+	d.Do(request("/api/info"))
+	if !m.Degraded() {
+		t.Errorf("wanted degraded after a failure")
+	}
+
+	failing.err = nil
+	d.Do(request("/api/info"))
+	d.Do(request("/api/info"))
+	if rate := m.Endpoints()[0].ErrorRate; rate != 0.25 {
+		t.Errorf("got error rate %v; wanted 0.25", rate)
+	}
+	if m.Degraded() {
+		t.Errorf("wanted recovered after successes")
+	}
+}
@@ -0,0 +1,238 @@
+// Package killswitch halts every write a bot makes when someone throws its
+// kill switch, so moderators can stop a misbehaving bot without access to its
+// server. Reads continue, so the bot keeps up with its streams and can be
+// resumed where it stands.
+//
+// The switch is thrown while any of its sources says so: a file on disk, a
+// flag in a wiki page, or a message from a moderator or operator:
+//
+//	sw := killswitch.New(bot, killswitch.Config{
+//		File:      "/var/run/mybot/halt",
+//		Subreddit: "mysub",
+//		Page:      "mybot/killswitch",
+//	})
+//	go sw.Watch(kill, errs)
+//	bot = sw.Bot(bot)
+//
+// and, in the bot's MessageHandler, sw.Command(message).
+package killswitch
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+const (
+	// DefaultFlag throws the switch when it is the first line of the
+	// wiki page.
+	DefaultFlag = "halt"
+	// DefaultHalt and DefaultResume are the messages which throw and
+	// reset the switch.
+	DefaultHalt   = "halt"
+	DefaultResume = "resume"
+	// defaultInterval is how often the wiki page is read if the Config
+	// does not say.
+	defaultInterval = time.Minute
+)
+
+// HaltedErr is returned by the write methods of a bot whose switch is thrown.
+var HaltedErr = fmt.Errorf("the kill switch is thrown; writes are halted")
+
+// Config configures a Switch. Every source is optional.
+type Config struct {
+	// File, if set, throws the switch while a file exists at this path.
+	// It is checked before every write.
+	File string
+	// Subreddit and Page, if set, name a wiki page which throws the
+	// switch while its first line is Flag, regardless of case. Moderators
+	// of the subreddit may also throw the switch by message.
+	Subreddit string
+	Page      string
+	// Flag is the first line which throws the switch. If empty,
+	// DefaultFlag is used.
+	Flag string
+	// Interval is how often Watch reads the wiki page. If zero, it is read
+	// every minute.
+	Interval time.Duration
+	// Operators may throw the switch by message, along with the
+	// moderators of Subreddit.
+	Operators []string
+	// Halt and Resume are the messages which throw and reset the switch.
+	// If empty, DefaultHalt and DefaultResume are used.
+	Halt   string
+	Resume string
+}
+
+// Switch is a kill switch. It is safe to use from multiple goroutines.
+type Switch interface {
+	// Halted reports whether the switch is thrown, and by which source:
+	// "file", "wiki", or the name of whoever threw it by message.
+	Halted() (bool, string)
+	// Halt throws the switch by hand, naming who threw it, and Resume
+	// resets what Halt and messages threw. Resume does not reset the file
+	// or the wiki page, which keep the switch thrown until they change.
+	Halt(by string)
+	Resume()
+	// Bot returns a view of the bot whose writes fail with HaltedErr
+	// while the switch is thrown.
+	Bot(b reddit.Bot) reddit.Bot
+	// Command throws or resets the switch if the message is the halt or
+	// resume command from an operator or a moderator of the subreddit,
+	// and reports whether it was a command. Messages from anyone else are
+	// ignored.
+	Command(m *reddit.Message) (bool, error)
+	// Check reads the wiki page now.
+	Check() error
+	// Watch reads the wiki page once every interval until kill is closed,
+	// sending errors to errs. It blocks.
+	Watch(kill <-chan bool, errs chan<- error)
+}
+
+type killSwitch struct {
+	bot reddit.Bot
+	cfg Config
+
+	mu sync.Mutex
+	// by is who threw the switch by hand or message, if anyone did.
+	by string
+	// wiki is set while the wiki page throws the switch.
+	wiki bool
+}
+
+// New returns a Switch which reads its wiki page and looks up moderators with
+// the bot.
+func New(bot reddit.Bot, c Config) Switch {
+	if c.Flag == "" {
+		c.Flag = DefaultFlag
+	}
+	if c.Halt == "" {
+		c.Halt = DefaultHalt
+	}
+	if c.Resume == "" {
+		c.Resume = DefaultResume
+	}
+	if c.Interval <= 0 {
+		c.Interval = defaultInterval
+	}
+	return &killSwitch{bot: bot, cfg: c}
+}
+
+func (k *killSwitch) Halted() (bool, string) {
+	if k.cfg.File != "" {
+		if _, err := os.Stat(k.cfg.File); err == nil {
+			return true, "file"
+		}
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.wiki {
+		return true, "wiki"
+	}
+	return k.by != "", k.by
+}
+
+func (k *killSwitch) Halt(by string) {
+	if by == "" {
+		by = "unknown"
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.by = by
+}
+
+func (k *killSwitch) Resume() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.by = ""
+}
+
+func (k *killSwitch) Bot(b reddit.Bot) reddit.Bot {
+	return reddit.Gate(b, func() error {
+		if halted, _ := k.Halted(); halted {
+			return HaltedErr
+		}
+		return nil
+	})
+}
+
+func (k *killSwitch) Command(m *reddit.Message) (bool, error) {
+	body := strings.TrimSpace(m.Body)
+	halt := strings.EqualFold(body, k.cfg.Halt)
+	if !halt && !strings.EqualFold(body, k.cfg.Resume) {
+		return false, nil
+	}
+
+	permitted, err := k.permitted(m.Author)
+	if err != nil || !permitted {
+		return true, err
+	}
+
+	if halt {
+		k.Halt(m.Author)
+	} else {
+		k.Resume()
+	}
+	return true, nil
+}
+
+// permitted reports whether the user may throw the switch by message.
+func (k *killSwitch) permitted(user string) (bool, error) {
+	for _, operator := range k.cfg.Operators {
+		if strings.EqualFold(operator, user) {
+			return true, nil
+		}
+	}
+	if k.cfg.Subreddit == "" || user == "" {
+		return false, nil
+	}
+	return k.bot.IsModerator(k.cfg.Subreddit, user)
+}
+
+func (k *killSwitch) Check() error {
+	if k.cfg.Subreddit == "" || k.cfg.Page == "" {
+		return nil
+	}
+
+	page, err := k.bot.WikiPage(k.cfg.Subreddit, k.cfg.Page)
+	if err != nil {
+		return err
+	}
+
+	first := ""
+	if s := bufio.NewScanner(strings.NewReader(page.Content)); s.Scan() {
+		first = strings.TrimSpace(s.Text())
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.wiki = strings.EqualFold(first, k.cfg.Flag)
+	return nil
+}
+
+func (k *killSwitch) Watch(kill <-chan bool, errs chan<- error) {
+	ticker := time.NewTicker(k.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if err := k.Check(); err != nil {
+			select {
+			case errs <- err:
+			case <-kill:
+				return
+			}
+		}
+
+		select {
+		case <-kill:
+			return
+		case <-ticker.C:
+		}
+	}
+}
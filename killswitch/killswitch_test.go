@@ -0,0 +1,134 @@
+package killswitch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// switchBot serves one wiki page, moderated by "mod", and counts its replies.
+type switchBot struct {
+	reddit.Bot
+	content string
+	replies int
+	revoked bool
+}
+
+func (s *switchBot) WikiPage(_, _ string) (*reddit.WikiPage, error) {
+	return &reddit.WikiPage{Content: s.content}, nil
+}
+
+func (s *switchBot) IsModerator(_, user string) (bool, error) {
+	return user == "mod", nil
+}
+
+func (s *switchBot) Reply(_, _ string) error {
+	s.replies++
+	return nil
+}
+
+func TestFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "killswitch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "halt")
+	inner := &switchBot{}
+	bot := New(inner, Config{File: file}).Bot(inner)
+
+	if err := bot.Reply("t1_a", "hi"); err != nil {
+		t.Errorf("wanted write to pass; got %v", err)
+	}
+
+	if err := ioutil.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := bot.Reply("t1_a", "hi"); err != HaltedErr {
+		t.Errorf("wanted HaltedErr; got %v", err)
+	}
+	if inner.replies != 1 {
+		t.Errorf("got %d replies; wanted 1", inner.replies)
+	}
+}
+
+func (s *switchBot) RevokeAll() error {
+	s.revoked = true
+	return nil
+}
+
+func TestRevokeHalted(t *testing.T) {
+	inner := &switchBot{}
+	sw := New(inner, Config{})
+	bot := sw.Bot(inner)
+	sw.Halt("operator")
+
+	// Revoking the credentials of a halted bot is the next step of an
+	// incident, so it is not halted.
+	if err := bot.RevokeAll(); err != nil {
+		t.Errorf("wanted revoke to pass; got %v", err)
+	}
+	if !inner.revoked {
+		t.Errorf("tokens were not revoked")
+	}
+	if err := bot.Reply("t1_a", "hi"); err != HaltedErr {
+		t.Errorf("wanted HaltedErr; got %v", err)
+	}
+}
+
+func TestWiki(t *testing.T) {
+	inner := &switchBot{content: "HALT\nthe bot is replying twice"}
+	sw := New(inner, Config{Subreddit: "sub", Page: "bot"})
+
+	if err := sw.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if halted, by := sw.Halted(); !halted || by != "wiki" {
+		t.Errorf("got halted %v by %q; wanted halted by the wiki", halted, by)
+	}
+
+	// Resuming by hand does not override the page.
+	sw.Resume()
+	if halted, _ := sw.Halted(); !halted {
+		t.Errorf("wanted the page to keep the switch thrown")
+	}
+
+	inner.content = "running"
+	if err := sw.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if halted, _ := sw.Halted(); halted {
+		t.Errorf("wanted the switch reset with the page")
+	}
+}
+
+func TestCommand(t *testing.T) {
+	sw := New(&switchBot{}, Config{Subreddit: "sub", Operators: []string{"Owner"}})
+
+	for i, test := range []struct {
+		author, body string
+		command      bool
+		halted       bool
+	}{
+		{"user", "halt", true, false},
+		{"mod", "hello", false, false},
+		{"mod", " Halt ", true, true},
+		{"user", "resume", true, true},
+		{"owner", "resume", true, false},
+	} {
+		command, err := sw.Command(&reddit.Message{Author: test.author, Body: test.body})
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if command != test.command {
+			t.Errorf("%d: got command %v; wanted %v", i, command, test.command)
+		}
+		if halted, _ := sw.Halted(); halted != test.halted {
+			t.Errorf("%d: got halted %v; wanted %v", i, halted, test.halted)
+		}
+	}
+}
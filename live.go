@@ -0,0 +1,48 @@
+package graw
+
+import (
+	"fmt"
+
+	"github.com/aldarisbm/graw/botfaces"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/streams"
+)
+
+var liveThreadHandlerErr = fmt.Errorf(
+	"You must implement LiveThreadHandler to take live thread feeds.",
+)
+
+// connectLiveThreads connects the config's live threads to the handler, if it
+// names any.
+func connectLiveThreads(
+	handler interface{},
+	bot reddit.Bot,
+	c Config,
+	kill <-chan bool,
+	errs chan<- error,
+) error {
+	if len(c.LiveThreads) == 0 {
+		return nil
+	}
+
+	lh, ok := handler.(botfaces.LiveThreadHandler)
+	if !ok {
+		return liveThreadHandlerErr
+	}
+
+	cfg := streams.LiveConfig{Fallback: c.LiveFallback}
+	for _, thread := range c.LiveThreads {
+		events, err := streams.LiveThread(bot, kill, errs, cfg, thread)
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			for e := range events {
+				errs <- lh.LiveEvent(e)
+			}
+		}()
+	}
+
+	return nil
+}
@@ -1,14 +1,7 @@
 package graw
 
-import (
-	"io/ioutil"
-	"log"
-)
+import "github.com/aldarisbm/graw/logging"
 
-func logger(l *log.Logger) *log.Logger {
-	if l == nil {
-		return log.New(ioutil.Discard, "", 0)
-	}
-
-	return l
+func logger(l logging.Logger) logging.Logger {
+	return logging.OrDiscard(l)
 }
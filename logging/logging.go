@@ -0,0 +1,121 @@
+// Package logging defines the Logger graw and its Reddit handles report what
+// they are doing to, with levels and structured fields, so bots can route
+// graw's logs into whatever logging system they already use:
+//
+//	type zapLogger struct{ l *zap.SugaredLogger }
+//
+//	func (z zapLogger) Log(level logging.Level, msg string, fields ...logging.Field) {
+//		...
+//	}
+//
+// Std adapts a standard library *log.Logger.
+package logging
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Level is the severity of a log entry.
+type Level int
+
+const (
+	// Debug entries trace what graw is doing, such as every request it
+	// makes. They are spammy.
+	Debug Level = iota
+	// Info entries report events of note, such as a run stopping.
+	Info
+	// Warn entries report trouble graw recovered from, such as Reddit
+	// being busy.
+	Warn
+	// Error entries report failures, such as the error a run stopped
+	// with.
+	Error
+)
+
+func (l Level) String() string {
+	switch l {
+	case Debug:
+		return "DEBUG"
+	case Info:
+		return "INFO"
+	case Warn:
+		return "WARN"
+	case Error:
+		return "ERROR"
+	}
+	return fmt.Sprintf("LEVEL(%d)", int(l))
+}
+
+// Field is a key and value describing a log entry, such as the path of a
+// request.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F returns a field.
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Logger takes graw's log entries. It must be safe to call from multiple
+// goroutines.
+type Logger interface {
+	Log(level Level, msg string, fields ...Field)
+}
+
+type discard struct{}
+
+func (discard) Log(Level, string, ...Field) {}
+
+// Discard returns a Logger which drops every entry.
+func Discard() Logger {
+	return discard{}
+}
+
+// OrDiscard returns the logger, or one which drops every entry if it is nil.
+func OrDiscard(l Logger) Logger {
+	if l == nil {
+		return discard{}
+	}
+	return l
+}
+
+type std struct {
+	l   *log.Logger
+	min Level
+}
+
+// Std returns a Logger which prints the entries of at least the min level to
+// l, one per line, as the level, message, and fields, e.g.
+//
+//	WARN Reddit was busy; staying up. path=/r/golang/new
+func Std(l *log.Logger, min Level) Logger {
+	return &std{l: l, min: min}
+}
+
+func (s *std) Log(level Level, msg string, fields ...Field) {
+	if level < s.min {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(level.String())
+	b.WriteString(" ")
+	b.WriteString(msg)
+	for _, f := range fields {
+		fmt.Fprintf(&b, " %s=%s", f.Key, format(f.Value))
+	}
+	s.l.Print(b.String())
+}
+
+// format formats a field's value, quoting it if it would not read as one.
+func format(v interface{}) string {
+	s := fmt.Sprint(v)
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"testing"
+)
+
+func TestStd(t *testing.T) {
+	var buf bytes.Buffer
+	l := Std(log.New(&buf, "", 0), Info)
+
+	l.Log(Debug, "request", F("path", "/r/golang"))
+	l.Log(Warn, "Reddit was busy; staying up.", F("path", "/r/golang"), F("status", 503))
+	l.Log(Error, "Stopping.", F("err", fmt.Errorf("bad request")), F("empty", ""))
+
+	expected := "WARN Reddit was busy; staying up. path=/r/golang status=503\n" +
+		"ERROR Stopping. err=\"bad request\" empty=\"\"\n"
+	if buf.String() != expected {
+		t.Errorf("got %q; wanted %q", buf.String(), expected)
+	}
+}
+
+func TestOrDiscard(t *testing.T) {
+	OrDiscard(nil).Log(Error, "dropped")
+
+	l := Discard()
+	if OrDiscard(l) != l {
+		t.Errorf("replaced a set logger")
+	}
+}
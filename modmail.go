@@ -0,0 +1,55 @@
+package graw
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aldarisbm/graw/botfaces"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/streams"
+)
+
+// defaultModmailInterval is how often modmail is read if the Config gives no
+// interval.
+const defaultModmailInterval = time.Minute
+
+var modmailHandlerErr = fmt.Errorf(
+	"You must implement ModmailHandler to take modmail feeds.",
+)
+
+// connectModmail connects the modmail of the config's subreddits to the
+// handler, if it names any.
+func connectModmail(
+	handler interface{},
+	bot reddit.Bot,
+	c Config,
+	kill <-chan bool,
+	errs chan<- error,
+) error {
+	if len(c.Modmail) == 0 {
+		return nil
+	}
+
+	mh, ok := handler.(botfaces.ModmailHandler)
+	if !ok {
+		return modmailHandlerErr
+	}
+
+	cfg := streams.ModmailConfig{Interval: c.ModmailInterval}
+	if cfg.Interval == 0 {
+		cfg.Interval = defaultModmailInterval
+	}
+
+	conversations, err := streams.Modmail(bot, kill, errs, cfg, c.Modmail...)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for conversation := range conversations {
+			errs <- mh.Modmail(conversation)
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package once
+
+import (
+	"context"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// onceBot is a Bot whose replies, messages, and posts are made at most once.
+type onceBot struct {
+	reddit.Bot
+	l *ledger
+}
+
+func (o *onceBot) ReadOnly() reddit.Bot {
+	return &onceBot{Bot: o.Bot.ReadOnly(), l: o.l}
+}
+
+func (o *onceBot) Restrict(p reddit.Permission) reddit.Bot {
+	return &onceBot{Bot: o.Bot.Restrict(p), l: o.l}
+}
+
+func (o *onceBot) WithContext(ctx context.Context) reddit.Bot {
+	return &onceBot{Bot: o.Bot.WithContext(ctx), l: o.l}
+}
+
+func (o *onceBot) Reply(parentName, text string) error {
+	return o.l.Do(Key("reply", parentName, text), func() error {
+		return o.Bot.Reply(parentName, text)
+	})
+}
+
+func (o *onceBot) GetReply(parentName, text string) (reddit.Submission, error) {
+	return o.submit(Key("reply", parentName, text), func() (reddit.Submission, error) {
+		return o.Bot.GetReply(parentName, text)
+	})
+}
+
+func (o *onceBot) SendMessage(user, subject, text string) error {
+	return o.l.Do(Key("message", user, subject, text), func() error {
+		return o.Bot.SendMessage(user, subject, text)
+	})
+}
+
+func (o *onceBot) Report(name, reason string) error {
+	return o.l.Do(Key("report", name, reason), func() error {
+		return o.Bot.Report(name, reason)
+	})
+}
+
+func (o *onceBot) ReplyModmail(id, text string, internal bool) error {
+	return o.l.Do(Key("modmail", id, text), func() error {
+		return o.Bot.ReplyModmail(id, text, internal)
+	})
+}
+
+func (o *onceBot) PostLiveUpdate(thread, text string) error {
+	return o.l.Do(Key("live", thread, text), func() error {
+		return o.Bot.PostLiveUpdate(thread, text)
+	})
+}
+
+func (o *onceBot) PostSelf(subreddit, title, text string) error {
+	return o.l.Do(Key("self", subreddit, title, text), func() error {
+		return o.Bot.PostSelf(subreddit, title, text)
+	})
+}
+
+func (o *onceBot) GetPostSelf(subreddit, title, text string) (reddit.Submission, error) {
+	return o.submit(Key("self", subreddit, title, text), func() (reddit.Submission, error) {
+		return o.Bot.GetPostSelf(subreddit, title, text)
+	})
+}
+
+func (o *onceBot) GetPostSelfWithFlair(
+	subreddit, title, text string,
+	flair reddit.PostFlair,
+) (reddit.Submission, error) {
+	key := Key("self", subreddit, title, text, flair.TemplateID, flair.Text)
+	return o.submit(key, func() (reddit.Submission, error) {
+		return o.Bot.GetPostSelfWithFlair(subreddit, title, text, flair)
+	})
+}
+
+func (o *onceBot) PostLink(subreddit, title, url string) error {
+	return o.l.Do(Key("link", subreddit, title, url), func() error {
+		return o.Bot.PostLink(subreddit, title, url)
+	})
+}
+
+func (o *onceBot) GetPostLink(subreddit, title, url string) (reddit.Submission, error) {
+	return o.submit(Key("link", subreddit, title, url), func() (reddit.Submission, error) {
+		return o.Bot.GetPostLink(subreddit, title, url)
+	})
+}
+
+func (o *onceBot) SubmitGallery(
+	subreddit, title string,
+	images []reddit.GalleryImage,
+) (reddit.Submission, error) {
+	parts := []string{subreddit, title}
+	for _, image := range images {
+		parts = append(parts, image.Image.Filename, image.Caption)
+	}
+	return o.submit(Key("gallery", parts...), func() (reddit.Submission, error) {
+		return o.Bot.SubmitGallery(subreddit, title, images)
+	})
+}
+
+func (o *onceBot) SubmitPoll(subreddit, title string, poll reddit.Poll) (reddit.Submission, error) {
+	parts := append([]string{subreddit, title, poll.Text}, poll.Options...)
+	return o.submit(Key("poll", parts...), func() (reddit.Submission, error) {
+		return o.Bot.SubmitPoll(subreddit, title, poll)
+	})
+}
+
+func (o *onceBot) SubmitVideo(subreddit, title string, video reddit.Video) (reddit.Submission, error) {
+	key := Key("video", subreddit, title, video.Video.Filename)
+	return o.submit(key, func() (reddit.Submission, error) {
+		return o.Bot.SubmitVideo(subreddit, title, video)
+	})
+}
+
+// submit makes a submission through the ledger. A submission made in an
+// earlier run is returned again; one which was interrupted is returned empty.
+func (o *onceBot) submit(
+	key string,
+	post func() (reddit.Submission, error),
+) (reddit.Submission, error) {
+	s, err := o.l.do(key, func() (*reddit.Submission, error) {
+		s, err := post()
+		if err != nil {
+			return nil, err
+		}
+		return &s, nil
+	})
+	if err != nil || s == nil {
+		return reddit.Submission{}, err
+	}
+	return *s, nil
+}
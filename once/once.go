@@ -0,0 +1,202 @@
+// Package once keeps a durable ledger of the writes a bot has made, keyed by
+// idempotency keys derived from what each write does, so an event handled
+// again after a restart never makes the same write twice.
+package once
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/store"
+)
+
+// stateKey is the key the ledger is stored under.
+const stateKey = "idempotency-ledger"
+
+// defaultMax is how many keys a ledger remembers if its config does not say.
+const defaultMax = 10000
+
+// Key returns a deterministic idempotency key for an action, such as
+// Key("reply", "t1_abc123", "welcome") for replying to t1_abc123 with the
+// welcome template. The same action always has the same key, in any run.
+func Key(action string, parts ...string) string {
+	h := sha256.New()
+	for _, p := range append([]string{action}, parts...) {
+		// Lengths keep ("ab", "c") and ("a", "bc") apart.
+		fmt.Fprintf(h, "%d:%s", len(p), p)
+	}
+	return action + "-" + hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// Config configures a Ledger.
+type Config struct {
+	// Store is where the ledger is kept, so that it survives restarts.
+	// Without one, writes are only deduplicated within a run.
+	Store store.Store
+	// Max is how many keys are remembered; the oldest are forgotten
+	// first. If zero, 10000 are.
+	Max int
+	// Clock stamps when each action was started. If nil, the wall clock
+	// is used.
+	Clock clock.Clock
+}
+
+// Record is an action in the ledger.
+type Record struct {
+	Key string `json:"key"`
+	// Started is when the action was started.
+	Started time.Time `json:"started"`
+	// Done is set once the action succeeded. A record which is not done
+	// belongs to an action which was interrupted, so it is unknown
+	// whether it took effect.
+	Done bool `json:"done"`
+	// Result is the submission the action made, if it made one.
+	Result *reddit.Submission `json:"result,omitempty"`
+}
+
+// Ledger runs actions at most once per idempotency key.
+type Ledger interface {
+	// Do runs the action unless an action with the same key was already
+	// started, in this run or an earlier one, in which case it returns
+	// nil without running it. The key is recorded before the action
+	// runs, so an action interrupted by a crash is never retried; if the
+	// action fails with an error, the key is forgotten and it may be
+	// tried again.
+	Do(key string, action func() error) error
+	// Interrupted returns the records of the actions which were started
+	// but never finished, in earlier runs, oldest first. Whether they
+	// took effect must be checked by hand.
+	Interrupted() []Record
+	// Bot wraps the bot so its replies, messages, and posts go through
+	// the ledger, keyed by where they go and what they say.
+	Bot(b reddit.Bot) reddit.Bot
+}
+
+type ledger struct {
+	store store.Store
+	max   int
+	clock clock.Clock
+
+	// mu guards the records, and is held while an action runs so that
+	// two events racing to make the same write can't both make it.
+	mu      sync.Mutex
+	records []Record
+	index   map[string]int
+	// interrupted holds the records which were not done when the ledger
+	// was loaded.
+	interrupted []Record
+}
+
+// New returns a Ledger, holding the records saved in the config's store.
+func New(c Config) (Ledger, error) {
+	l := &ledger{
+		store: c.Store,
+		max:   c.Max,
+		clock: c.Clock,
+	}
+	if l.max <= 0 {
+		l.max = defaultMax
+	}
+	if l.clock == nil {
+		l.clock = clock.Real()
+	}
+
+	if l.store != nil {
+		err := l.store.Load(stateKey, &l.records)
+		if err != nil && err != store.NotFoundErr {
+			return nil, err
+		}
+	}
+
+	for _, r := range l.records {
+		if !r.Done {
+			l.interrupted = append(l.interrupted, r)
+		}
+	}
+	l.reindex()
+
+	return l, nil
+}
+
+func (l *ledger) Do(key string, action func() error) error {
+	_, err := l.do(key, func() (*reddit.Submission, error) {
+		return nil, action()
+	})
+	return err
+}
+
+// do runs the action unless its key was started, returning the submission
+// the action made when it ran before.
+func (l *ledger) do(
+	key string,
+	action func() (*reddit.Submission, error),
+) (*reddit.Submission, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if i, ok := l.index[key]; ok {
+		return l.records[i].Result, nil
+	}
+
+	l.records = append(l.records, Record{Key: key, Started: l.clock.Now()})
+	l.index[key] = len(l.records) - 1
+	if err := l.save(); err != nil {
+		l.forget(key)
+		return nil, err
+	}
+
+	result, err := action()
+	if err != nil {
+		l.forget(key)
+		l.save()
+		return nil, err
+	}
+
+	i := l.index[key]
+	l.records[i].Done = true
+	l.records[i].Result = result
+	if len(l.records) > l.max {
+		l.records = append([]Record(nil), l.records[len(l.records)-l.max:]...)
+		l.reindex()
+	}
+	return result, l.save()
+}
+
+func (l *ledger) Interrupted() []Record {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Record(nil), l.interrupted...)
+}
+
+func (l *ledger) Bot(b reddit.Bot) reddit.Bot {
+	return &onceBot{Bot: b, l: l}
+}
+
+func (l *ledger) forget(key string) {
+	i, ok := l.index[key]
+	if !ok {
+		return
+	}
+
+	l.records = append(l.records[:i], l.records[i+1:]...)
+	l.reindex()
+}
+
+func (l *ledger) reindex() {
+	l.index = make(map[string]int, len(l.records))
+	for i, r := range l.records {
+		l.index[r.Key] = i
+	}
+}
+
+func (l *ledger) save() error {
+	if l.store == nil {
+		return nil
+	}
+	return l.store.Save(stateKey, l.records)
+}
@@ -0,0 +1,164 @@
+package once
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/store"
+)
+
+type replyBot struct {
+	reddit.Bot
+	replies []string
+	fail    bool
+}
+
+func (r *replyBot) Reply(parentName, text string) error {
+	if r.fail {
+		return fmt.Errorf("reddit is down")
+	}
+	r.replies = append(r.replies, parentName+":"+text)
+	return nil
+}
+
+func (r *replyBot) GetReply(parentName, text string) (reddit.Submission, error) {
+	if err := r.Reply(parentName, text); err != nil {
+		return reddit.Submission{}, err
+	}
+	return reddit.Submission{Name: fmt.Sprintf("t1_%d", len(r.replies))}, nil
+}
+
+func TestKey(t *testing.T) {
+	if Key("reply", "t1_a", "hi") != Key("reply", "t1_a", "hi") {
+		t.Errorf("keys of the same action differ")
+	}
+
+	for _, other := range []string{
+		Key("reply", "t1_a", "hello"),
+		Key("reply", "t1_ah", "i"),
+		Key("message", "t1_a", "hi"),
+		Key("reply", "t1_a", "hi", ""),
+	} {
+		if other == Key("reply", "t1_a", "hi") {
+			t.Errorf("keys of different actions are the same: %s", other)
+		}
+	}
+}
+
+func TestBotAcrossRestarts(t *testing.T) {
+	s := store.NewMemoryStore()
+	b := &replyBot{}
+
+	l, err := New(Config{Store: s})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l.Bot(b).Reply("t1_a", "hi"); err != nil {
+		t.Fatal(err)
+	}
+	first, err := l.Bot(b).GetReply("t1_b", "hi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err = New(Config{Store: s})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bot := l.Bot(b)
+	if err := bot.Reply("t1_a", "hi"); err != nil {
+		t.Fatal(err)
+	}
+	again, err := bot.GetReply("t1_b", "hi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bot.Reply("t1_a", "hello"); err != nil {
+		t.Fatal(err)
+	}
+
+	if again != first {
+		t.Errorf("got submission %+v again; wanted %+v", again, first)
+	}
+
+	wanted := []string{"t1_a:hi", "t1_b:hi", "t1_a:hello"}
+	if fmt.Sprint(b.replies) != fmt.Sprint(wanted) {
+		t.Errorf("got replies %v; wanted %v", b.replies, wanted)
+	}
+}
+
+func TestFailedActionsAreRetried(t *testing.T) {
+	b := &replyBot{fail: true}
+
+	l, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l.Bot(b).Reply("t1_a", "hi"); err == nil {
+		t.Fatalf("wanted the failed reply's error")
+	}
+
+	b.fail = false
+	if err := l.Bot(b).Reply("t1_a", "hi"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(b.replies) != 1 {
+		t.Errorf("got replies %v; wanted the retried reply", b.replies)
+	}
+}
+
+func TestInterrupted(t *testing.T) {
+	s := store.NewMemoryStore()
+	if err := s.Save(stateKey, []Record{
+		{Key: "a", Done: true},
+		{Key: "b"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := New(Config{Store: s})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ran := false
+	if err := l.Do("b", func() error {
+		ran = true
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if ran {
+		t.Errorf("the interrupted action was retried")
+	}
+
+	if in := l.Interrupted(); len(in) != 1 || in[0].Key != "b" {
+		t.Errorf("got interrupted %+v; wanted b", in)
+	}
+}
+
+func TestMax(t *testing.T) {
+	l, err := New(Config{Max: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runs := 0
+	for _, key := range []string{"a", "b", "c", "a"} {
+		if err := l.Do(key, func() error {
+			runs++
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if runs != 4 {
+		t.Errorf("got %d runs; wanted the forgotten key to run again", runs)
+	}
+}
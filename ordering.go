@@ -0,0 +1,161 @@
+package graw
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// Ordering is the order a run handles the posts, comments, and messages of its
+// streams in.
+//
+// Under AuthorOrder and ThreadOrder, a run holds at most 100 events waiting
+// to be handled, across all authors or threads; once it does, its streams
+// wait for a handler to take one, as they do under StreamOrder.
+type Ordering int
+
+const (
+	// StreamOrder, the default, handles the events of each stream one at
+	// a time, in the order they arrive, and the streams in parallel.
+	StreamOrder Ordering = iota
+	// AuthorOrder handles the events of each author one at a time, in the
+	// order they arrive from any stream, and those of different authors
+	// in parallel, so a conversational bot never handles two messages of
+	// the same user at once or out of order.
+	AuthorOrder
+	// ThreadOrder handles the events of each thread, post or private
+	// conversation, one at a time, in the order they arrive from any
+	// stream, and those of different threads in parallel.
+	ThreadOrder
+)
+
+// maxQueued is the most events a run holds waiting to be handled, as
+// documented on Ordering.
+const maxQueued = 100
+
+// dispatcher hands the events of a run's streams to their handlers in its
+// ordering, after translating them, sending the errors of the handlers to
+// errs until kill is closed.
+type dispatcher struct {
+	order Ordering
+	tr    translation
+	kill  <-chan bool
+	errs  chan<- error
+
+	// slots holds a value for each event waiting in queues, blocking
+	// dispatch once maxQueued are.
+	slots chan struct{}
+
+	mu sync.Mutex
+	// queues holds the events waiting to be handled, by key. A key is
+	// present while a goroutine is handling its events.
+	queues map[string][]func() error
+}
+
+func newDispatcher(c Config, kill <-chan bool, errs chan<- error) *dispatcher {
+	return &dispatcher{
+		order:  c.Ordering,
+		tr:     translation{c.Translation},
+		kill:   kill,
+		errs:   errs,
+		slots:  make(chan struct{}, maxQueued),
+		queues: make(map[string][]func() error),
+	}
+}
+
+func (d *dispatcher) post(p *reddit.Post, handle func(*reddit.Post) error) {
+	d.dispatch(p.Author, p.Name, func() error { return d.tr.post(p, handle) })
+}
+
+func (d *dispatcher) comment(c *reddit.Comment, handle func(*reddit.Comment) error) {
+	d.dispatch(c.Author, c.LinkID, func() error { return d.tr.comment(c, handle) })
+}
+
+func (d *dispatcher) message(m *reddit.Message, handle func(*reddit.Message) error) {
+	d.dispatch(m.Author, messageThread(m), func() error { return d.tr.message(m, handle) })
+}
+
+// dispatch handles an event of the author in the thread, waiting for room
+// in the queues if they are full, or dropping it once kill is closed.
+func (d *dispatcher) dispatch(author, thread string, handle func() error) {
+	var key string
+	switch d.order {
+	case AuthorOrder:
+		key = strings.ToLower(author)
+	case ThreadOrder:
+		key = thread
+	default:
+		d.send(handle())
+		return
+	}
+
+	select {
+	case d.slots <- struct{}{}:
+	case <-d.kill:
+		return
+	}
+	d.mu.Lock()
+	queue, running := d.queues[key]
+	d.queues[key] = append(queue, handle)
+	d.mu.Unlock()
+	if !running {
+		go d.run(key)
+	}
+}
+
+// run handles the events of a key until none are waiting, or drops those
+// waiting once kill is closed.
+func (d *dispatcher) run(key string) {
+	for {
+		d.mu.Lock()
+		queue := d.queues[key]
+		if len(queue) == 0 {
+			delete(d.queues, key)
+			d.mu.Unlock()
+			return
+		}
+		handle := queue[0]
+		d.queues[key] = queue[1:]
+		d.mu.Unlock()
+		<-d.slots
+
+		if !d.send(handle()) {
+			d.mu.Lock()
+			for range d.queues[key] {
+				<-d.slots
+			}
+			delete(d.queues, key)
+			d.mu.Unlock()
+			return
+		}
+	}
+}
+
+// send sends an error to errs, reporting false if kill was closed first.
+func (d *dispatcher) send(err error) bool {
+	select {
+	case d.errs <- err:
+		return true
+	case <-d.kill:
+		return false
+	}
+}
+
+// messageThread returns the thread of an inbox message: the post of a reply
+// to one of the bot's posts or comments, or the first message of a private
+// conversation.
+func messageThread(m *reddit.Message) string {
+	if m.WasComment {
+		// The context is the permalink of the comment,
+		// /r/sub/comments/<post>/<title>/<comment>/.
+		parts := strings.Split(strings.Trim(m.Context, "/"), "/")
+		if len(parts) > 3 && parts[2] == "comments" {
+			return "t3_" + parts[3]
+		}
+	}
+	if m.FirstMessageName != "" {
+		return m.FirstMessageName
+	}
+	return m.Name
+}
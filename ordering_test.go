@@ -0,0 +1,141 @@
+package graw
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+func TestAuthorOrder(t *testing.T) {
+	errs := make(chan error, 3)
+	d := newDispatcher(Config{Ordering: AuthorOrder}, nil, errs)
+
+	release := make(chan bool)
+	handled := make(chan string, 3)
+	handle := func(m *reddit.Message) error {
+		if m.Name == "t4_first" {
+			<-release
+		}
+		handled <- m.Name
+		return nil
+	}
+
+	d.message(&reddit.Message{Name: "t4_first", Author: "alice"}, handle)
+	d.message(&reddit.Message{Name: "t4_second", Author: "Alice"}, handle)
+	d.message(&reddit.Message{Name: "t4_other", Author: "bob"}, handle)
+
+	// Bob's message is handled while Alice's first is held up, and her
+	// second waits for it.
+	if name := <-handled; name != "t4_other" {
+		t.Fatalf("handled %s first; wanted t4_other", name)
+	}
+	select {
+	case name := <-handled:
+		t.Fatalf("handled %s before alice's first message", name)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(release)
+	for _, expected := range []string{"t4_first", "t4_second"} {
+		if name := <-handled; name != expected {
+			t.Errorf("handled %s; wanted %s", name, expected)
+		}
+	}
+}
+
+func TestMessageThread(t *testing.T) {
+	for _, test := range []struct {
+		m        *reddit.Message
+		expected string
+	}{
+		{&reddit.Message{Name: "t4_b", FirstMessageName: "t4_a"}, "t4_a"},
+		{&reddit.Message{Name: "t4_a"}, "t4_a"},
+		{
+			&reddit.Message{
+				Name:       "t1_c",
+				WasComment: true,
+				Context:    "/r/golang/comments/abc/title/c/?context=3",
+			},
+			"t3_abc",
+		},
+	} {
+		if thread := messageThread(test.m); thread != test.expected {
+			t.Errorf("got thread %s for %s; wanted %s", thread, test.m.Name, test.expected)
+		}
+	}
+}
+
+func TestDispatcherKill(t *testing.T) {
+	kill := make(chan bool)
+	d := newDispatcher(Config{Ordering: ThreadOrder}, kill, make(chan error))
+
+	// Nobody reads the errors, so the first message's worker is stuck
+	// sending its error when the run stops, with the second waiting.
+	handled := make(chan bool, 2)
+	handle := func(*reddit.Message) error {
+		handled <- true
+		return nil
+	}
+	d.message(&reddit.Message{Name: "t4_a"}, handle)
+	<-handled
+	d.message(&reddit.Message{Name: "t4_b", FirstMessageName: "t4_a"}, handle)
+	close(kill)
+
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(time.Millisecond) {
+		d.mu.Lock()
+		waiting := len(d.queues)
+		d.mu.Unlock()
+		if waiting == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("worker did not stop after kill")
+		}
+	}
+
+	select {
+	case <-handled:
+		t.Errorf("handled a message after kill")
+	default:
+	}
+}
+
+func TestDispatcherBound(t *testing.T) {
+	d := newDispatcher(Config{Ordering: AuthorOrder}, nil, make(chan error, 3))
+	d.slots = make(chan struct{}, 1)
+
+	release := make(chan bool)
+	handle := func(*reddit.Message) error {
+		<-release
+		return nil
+	}
+
+	// The first message is taken by its handler and the second waits, so
+	// the queues are full and the third blocks its stream.
+	d.message(&reddit.Message{Name: "t4_a", Author: "alice"}, handle)
+	for deadline := time.Now().Add(time.Second); len(d.slots) != 0; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("first message was not taken by its handler")
+		}
+	}
+	d.message(&reddit.Message{Name: "t4_b", Author: "alice"}, handle)
+
+	dispatched := make(chan bool)
+	go func() {
+		d.message(&reddit.Message{Name: "t4_c", Author: "bob"}, handle)
+		close(dispatched)
+	}()
+	select {
+	case <-dispatched:
+		t.Fatalf("dispatched past a full queue")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case <-dispatched:
+	case <-time.After(time.Second):
+		t.Fatalf("stream was not released once the queue had room")
+	}
+}
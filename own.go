@@ -0,0 +1,50 @@
+package graw
+
+import (
+	"strings"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// own recognizes the posts, comments, and messages made by the bot's own
+// account, tagging them, and telling the streams to skip them if the config
+// asks for that. The zero value, used by logged out scans, recognizes nothing.
+type own struct {
+	name string
+	skip bool
+}
+
+// ownContent returns the recognizer for the account the bot is logged in as.
+func ownContent(acc reddit.Account, c Config) (own, error) {
+	me, err := acc.Me()
+	if err != nil {
+		return own{}, err
+	}
+
+	return own{name: me.Name, skip: c.SkipOwnContent}, nil
+}
+
+func (o own) mine(author string) bool {
+	return o.name != "" && strings.EqualFold(author, o.name)
+}
+
+// post tags the post if it is the bot's own, and reports whether it should be
+// skipped.
+func (o own) post(p *reddit.Post) bool {
+	p.Own = o.mine(p.Author)
+	return p.Own && o.skip
+}
+
+// comment tags the comment if it is the bot's own, and reports whether it
+// should be skipped.
+func (o own) comment(c *reddit.Comment) bool {
+	c.Own = o.mine(c.Author)
+	return c.Own && o.skip
+}
+
+// message tags the message if it is the bot's own, and reports whether it
+// should be skipped.
+func (o own) message(m *reddit.Message) bool {
+	m.Own = o.mine(m.Author)
+	return m.Own && o.skip
+}
@@ -0,0 +1,48 @@
+package graw
+
+import (
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+func TestOwn(t *testing.T) {
+	for i, test := range []struct {
+		o      own
+		author string
+		tagged bool
+		skip   bool
+	}{
+		{own{}, "", false, false},
+		{own{}, "graw_bot", false, false},
+		{own{name: "graw_bot"}, "someone", false, false},
+		{own{name: "graw_bot"}, "graw_bot", true, false},
+		{own{name: "graw_bot"}, "Graw_Bot", true, false},
+		{own{name: "graw_bot", skip: true}, "someone", false, false},
+		{own{name: "graw_bot", skip: true}, "graw_bot", true, true},
+	} {
+		p := &reddit.Post{Author: test.author}
+		if skip := test.o.post(p); skip != test.skip || p.Own != test.tagged {
+			t.Errorf(
+				"%d: post: got tagged %v, skip %v; wanted %v, %v",
+				i, p.Own, skip, test.tagged, test.skip,
+			)
+		}
+
+		c := &reddit.Comment{Author: test.author}
+		if skip := test.o.comment(c); skip != test.skip || c.Own != test.tagged {
+			t.Errorf(
+				"%d: comment: got tagged %v, skip %v; wanted %v, %v",
+				i, c.Own, skip, test.tagged, test.skip,
+			)
+		}
+
+		m := &reddit.Message{Author: test.author}
+		if skip := test.o.message(m); skip != test.skip || m.Own != test.tagged {
+			t.Errorf(
+				"%d: message: got tagged %v, skip %v; wanted %v, %v",
+				i, m.Own, skip, test.tagged, test.skip,
+			)
+		}
+	}
+}
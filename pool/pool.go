@@ -0,0 +1,135 @@
+// Package pool spreads a bot's actions over several Reddit accounts, so a
+// large moderation team's bot stays under the rate limit of each. Every
+// account has its own token and rate limit, and each action is run through
+// whichever account the pool's dispatch picks:
+//
+//	p, err := pool.NewFromConfigs(pool.Config{Dispatch: pool.LeastLoaded}, configs...)
+//	...
+//	err = p.Do(func(bot reddit.Bot) error {
+//		return bot.Reply(name, text)
+//	})
+package pool
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// EmptyErr is returned when a pool is made without any accounts.
+var EmptyErr = fmt.Errorf("a pool needs at least one account")
+
+// Dispatch is how a pool picks the account for each action.
+type Dispatch int
+
+const (
+	// RoundRobin, the default, runs each action through the account after
+	// the last one's, in turn.
+	RoundRobin Dispatch = iota
+	// LeastLoaded runs each action through the account with the fewest
+	// actions in flight, in turn among those tied, so a slow account
+	// waiting out its rate limit is passed over until it catches up.
+	LeastLoaded
+)
+
+// Config configures a pool.
+type Config struct {
+	// Dispatch is how the pool picks the account for each action.
+	Dispatch Dispatch
+}
+
+// BotPool runs actions through several accounts. It is safe to use from
+// multiple goroutines.
+type BotPool interface {
+	// Do runs an action through the next account, and returns its error.
+	Do(action func(bot reddit.Bot) error) error
+	// Bots returns the accounts of the pool, in the order they were given.
+	Bots() []reddit.Bot
+	// Load returns the number of actions in flight through each account,
+	// in the order of Bots.
+	Load() []int
+}
+
+type pool struct {
+	dispatch Dispatch
+	bots     []reddit.Bot
+
+	mu sync.Mutex
+	// load is the number of actions in flight through each bot.
+	load []int
+	// next is the bot to try first on the next action.
+	next int
+}
+
+// New returns a pool of bots, which should each be logged in to a different
+// account; bots sharing an account share its rate limit.
+func New(c Config, bots ...reddit.Bot) (BotPool, error) {
+	if len(bots) == 0 {
+		return nil, EmptyErr
+	}
+
+	return &pool{
+		dispatch: c.Dispatch,
+		bots:     append([]reddit.Bot(nil), bots...),
+		load:     make([]int, len(bots)),
+	}, nil
+}
+
+// NewFromConfigs logs in a bot with each config, as NewBot does, and returns a
+// pool of them.
+func NewFromConfigs(c Config, accounts ...reddit.BotConfig) (BotPool, error) {
+	bots := make([]reddit.Bot, len(accounts))
+	for i, account := range accounts {
+		bot, err := reddit.NewBot(account)
+		if err != nil {
+			return nil, fmt.Errorf("account %d: %v", i, err)
+		}
+		bots[i] = bot
+	}
+
+	return New(c, bots...)
+}
+
+func (p *pool) Do(action func(bot reddit.Bot) error) error {
+	i := p.acquire()
+	defer p.release(i)
+	return action(p.bots[i])
+}
+
+func (p *pool) Bots() []reddit.Bot {
+	return append([]reddit.Bot(nil), p.bots...)
+}
+
+func (p *pool) Load() []int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]int(nil), p.load...)
+}
+
+// acquire picks the bot for an action and counts the action against it.
+func (p *pool) acquire() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	picked := p.next
+	if p.dispatch == LeastLoaded {
+		for n := 1; n < len(p.bots); n++ {
+			i := (p.next + n) % len(p.bots)
+			if p.load[i] < p.load[picked] {
+				picked = i
+			}
+		}
+	}
+
+	p.next = (picked + 1) % len(p.bots)
+	p.load[picked]++
+	return picked
+}
+
+// release marks an action through a bot as finished.
+func (p *pool) release(i int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.load[i]--
+}
@@ -0,0 +1,76 @@
+package pool
+
+import (
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+type namedBot struct {
+	reddit.Bot
+	name string
+}
+
+func testPool(d Dispatch, t *testing.T) BotPool {
+	p, err := New(
+		Config{Dispatch: d},
+		&namedBot{name: "a"},
+		&namedBot{name: "b"},
+		&namedBot{name: "c"},
+	)
+	if err != nil {
+		t.Fatalf("error making pool: %v", err)
+	}
+	return p
+}
+
+func name(p BotPool) string {
+	var n string
+	p.Do(func(bot reddit.Bot) error {
+		n = bot.(*namedBot).name
+		return nil
+	})
+	return n
+}
+
+func TestRoundRobin(t *testing.T) {
+	p := testPool(RoundRobin, t)
+	for _, expected := range []string{"a", "b", "c", "a", "b"} {
+		if n := name(p); n != expected {
+			t.Errorf("got account %s; wanted %s", n, expected)
+		}
+	}
+}
+
+func TestLeastLoaded(t *testing.T) {
+	p := testPool(LeastLoaded, t)
+
+	// Hold an action in flight through a, and one through b.
+	release := make(chan bool)
+	started := make(chan bool)
+	for i := 0; i < 2; i++ {
+		go p.Do(func(reddit.Bot) error {
+			started <- true
+			<-release
+			return nil
+		})
+		<-started
+	}
+
+	for i := 0; i < 3; i++ {
+		if n := name(p); n != "c" {
+			t.Errorf("got account %s; wanted the idle c", n)
+		}
+	}
+
+	if load := p.Load(); load[0] != 1 || load[1] != 1 || load[2] != 0 {
+		t.Errorf("got load %v; wanted [1 1 0]", load)
+	}
+	close(release)
+}
+
+func TestEmpty(t *testing.T) {
+	if _, err := New(Config{}); err != EmptyErr {
+		t.Errorf("got %v; wanted EmptyErr", err)
+	}
+}
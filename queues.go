@@ -0,0 +1,96 @@
+package graw
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aldarisbm/graw/botfaces"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/streams"
+)
+
+// defaultQueueInterval is how often moderation queues are read if the Config
+// gives no interval.
+const defaultQueueInterval = time.Minute
+
+var (
+	modqueueHandlerErr = fmt.Errorf(
+		"You must implement ModqueueHandler to take modqueue feeds.",
+	)
+	spamHandlerErr = fmt.Errorf(
+		"You must implement SpamHandler to take spam feeds.",
+	)
+	editedHandlerErr = fmt.Errorf(
+		"You must implement EditedHandler to take edited feeds.",
+	)
+)
+
+// connectQueues connects the moderation queues of the config's subreddits to
+// the handler, for each queue it names subreddits for.
+func connectQueues(
+	handler interface{},
+	bot reddit.Bot,
+	c Config,
+	kill <-chan bool,
+	errs chan<- error,
+) error {
+	cfg := streams.QueueConfig{Interval: c.QueueInterval}
+	if cfg.Interval == 0 {
+		cfg.Interval = defaultQueueInterval
+	}
+
+	if len(c.Modqueue) > 0 {
+		mh, ok := handler.(botfaces.ModqueueHandler)
+		if !ok {
+			return modqueueHandlerErr
+		}
+		if err := connectQueue(bot, kill, errs, cfg, streams.ModQueue, c.Modqueue, mh.Queued); err != nil {
+			return err
+		}
+	}
+
+	if len(c.Spam) > 0 {
+		sh, ok := handler.(botfaces.SpamHandler)
+		if !ok {
+			return spamHandlerErr
+		}
+		if err := connectQueue(bot, kill, errs, cfg, streams.SpamQueue, c.Spam, sh.Spam); err != nil {
+			return err
+		}
+	}
+
+	if len(c.Edited) > 0 {
+		eh, ok := handler.(botfaces.EditedHandler)
+		if !ok {
+			return editedHandlerErr
+		}
+		if err := connectQueue(bot, kill, errs, cfg, streams.EditedQueue, c.Edited, eh.Edited); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// connectQueue forwards the elements of one moderation queue to handle.
+func connectQueue(
+	bot reddit.Bot,
+	kill <-chan bool,
+	errs chan<- error,
+	cfg streams.QueueConfig,
+	queue string,
+	subreddits []string,
+	handle func(*streams.Queued) error,
+) error {
+	queued, err := streams.Queue(bot, kill, errs, cfg, queue, subreddits...)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for q := range queued {
+			errs <- handle(q)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,106 @@
+package reddit
+
+import "strings"
+
+// Accessors for fields which Reddit leaves empty or null when they do not
+// apply. Each reports with ok whether the field holds real data, so that, for
+// example, a post without flair can be told apart from one whose flair is
+// blank.
+
+// Flair returns the post's link flair text.
+func (p *Post) Flair() (text string, ok bool) {
+	return p.LinkFlairText, p.LinkFlairText != "" || p.LinkFlairCSSClass != ""
+}
+
+// AuthorFlair returns the flair text of the post's author.
+func (p *Post) AuthorFlair() (text string, ok bool) {
+	return p.AuthorFlairText, p.AuthorFlairText != "" || p.AuthorFlairCSSClass != ""
+}
+
+// MediaURLs returns the urls of the media the post holds: its images, their
+// animated renditions, and its video, in the order they appear.
+func (p *Post) MediaURLs() []string {
+	var urls []string
+	add := func(u string) {
+		if u != "" {
+			urls = append(urls, u)
+		}
+	}
+
+	if p.IsRedditMediaDomain {
+		add(p.URL)
+	}
+	for _, item := range p.Gallery.Items {
+		m := p.MediaMetadata[item.MediaID]
+		add(m.Source.URL)
+		add(m.Source.GIF)
+		add(m.Source.MP4)
+	}
+	if video := p.SecureMedia.RedditVideo.FallbackURL; video != "" && video != p.URL {
+		add(video)
+	}
+	return urls
+}
+
+// AuthorName returns the name of the post's author, unless the author deleted
+// their account.
+func (p *Post) AuthorName() (name string, ok bool) {
+	return authorName(p.Author)
+}
+
+// VisibleScore returns the post's score, unless it is hidden.
+func (p *Post) VisibleScore() (score int32, ok bool) {
+	return p.Score, !p.HideScore
+}
+
+// Vote returns whether the account upvoted the post, if it voted on it.
+func (p *Post) Vote() (up bool, ok bool) {
+	return p.Likes, p.Voted
+}
+
+// Reports returns the number of reports on the post, if they are visible.
+func (p *Post) Reports() (n int32, ok bool) {
+	return p.NumReports, p.ReportsVisible
+}
+
+// ParentComment returns the fullname of the comment this comment replies to,
+// unless it is a top level comment.
+func (c *Comment) ParentComment() (name string, ok bool) {
+	if strings.HasPrefix(c.ParentID, commentKind+"_") {
+		return c.ParentID, true
+	}
+	return "", false
+}
+
+// AuthorFlair returns the flair text of the comment's author.
+func (c *Comment) AuthorFlair() (text string, ok bool) {
+	return c.AuthorFlairText, c.AuthorFlairText != "" || c.AuthorFlairCSSClass != ""
+}
+
+// AuthorName returns the name of the comment's author, unless the author
+// deleted their account.
+func (c *Comment) AuthorName() (name string, ok bool) {
+	return authorName(c.Author)
+}
+
+// VisibleScore returns the comment's score, unless it is hidden.
+func (c *Comment) VisibleScore() (score int32, ok bool) {
+	return c.Score, !c.ScoreHidden
+}
+
+// Vote returns whether the account upvoted the comment, if it voted on it.
+func (c *Comment) Vote() (up bool, ok bool) {
+	return c.Likes, c.Voted
+}
+
+// Reports returns the number of reports on the comment, if they are visible.
+func (c *Comment) Reports() (n int32, ok bool) {
+	return c.NumReports, c.ReportsVisible
+}
+
+func authorName(author string) (string, bool) {
+	if author == "" || author == deletedKey {
+		return "", false
+	}
+	return author, true
+}
@@ -20,10 +20,94 @@ type Account interface {
 	// PostSelf makes a text (self) post to a subreddit.
 	PostSelf(subreddit, title, text string) error
 	GetPostSelf(subreddit, title, text string) (Submission, error)
+	// GetPostSelfWithFlair makes a text post with the given link flair.
+	GetPostSelfWithFlair(subreddit, title, text string, flair PostFlair) (Submission, error)
 
 	// PostLink makes a link post to a subreddit.
 	PostLink(subreddit, title, url string) error
 	GetPostLink(subreddit, title, url string) (Submission, error)
+
+	// SubmitGallery uploads the images and makes a gallery post of them.
+	// A gallery holds at least two images.
+	SubmitGallery(subreddit, title string, images []GalleryImage) (Submission, error)
+	// SubmitPoll makes a poll post.
+	SubmitPoll(subreddit, title string, poll Poll) (Submission, error)
+	// SubmitVideo uploads the video and its thumbnail and makes a video
+	// post of them. Reddit processes the video before posting it, which
+	// SubmitVideo waits for, up to five minutes or the deadline of the
+	// bot's context.
+	SubmitVideo(subreddit, title string, video Video) (Submission, error)
+
+	// Me returns the account the bot is logged in as.
+	Me() (*Redditor, error)
+	// PostInsights fetches the current reach of the posts with the given
+	// fullnames, such as the bot's announcements. Posts which no longer
+	// exist are left out. It costs a request per hundred posts.
+	PostInsights(posts ...string) ([]PostInsights, error)
+
+	// Inbox returns a page of the bot's inbox of messages and replies,
+	// UnreadMessages a page of the unread ones, and SentMessages a page of
+	// the messages the bot sent. The ListingInfo holds the After to page
+	// back with.
+	Inbox(opts InboxOptions) (Harvest, ListingInfo, error)
+	UnreadMessages(opts InboxOptions) (Harvest, ListingInfo, error)
+	SentMessages(opts InboxOptions) (Harvest, ListingInfo, error)
+	// MessageThread returns every message of a private conversation, in
+	// order, by the fullname of its first message, e.g. the
+	// FirstMessageName of a reply in it.
+	MessageThread(name string) ([]*Message, error)
+	// MarkRead and MarkUnread mark messages of the inbox, by their
+	// fullnames, read or unread, and MarkAllRead marks the whole inbox
+	// read.
+	MarkRead(names ...string) error
+	MarkUnread(names ...string) error
+	MarkAllRead() error
+	// CollapseMessages and UncollapseMessages collapse and expand
+	// messages of the inbox, as the site does.
+	CollapseMessages(names ...string) error
+	UncollapseMessages(names ...string) error
+	// BlockSender blocks the author of a message in the bot's inbox, by
+	// the message's fullname, for senders whose names are hidden.
+	BlockSender(name string) error
+
+	// BlockedUsers returns the names of the accounts the bot blocked.
+	BlockedUsers() ([]string, error)
+	// Block blocks a user, hiding their content and messages from the bot.
+	Block(user string) error
+	// Unblock lifts the bot's block of a user.
+	Unblock(user string) error
+
+	// Upvote and Downvote vote on a post or comment, by its fullname, and
+	// ClearVote takes the bot's vote back.
+	Upvote(name string) error
+	Downvote(name string) error
+	ClearVote(name string) error
+	// Save saves a post or comment to the bot's saved listing, and Unsave
+	// removes it.
+	Save(name string) error
+	Unsave(name string) error
+	// Hide hides a post from the bot's listings, and Unhide shows it
+	// again.
+	Hide(post string) error
+	Unhide(post string) error
+	// EditText replaces the text of one of the bot's comments or text
+	// posts, by its fullname, e.g. to update a scoreboard in place.
+	EditText(name, text string) error
+	// Delete deletes one of the bot's comments or posts.
+	Delete(name string) error
+	// Report reports a post, comment, or message to the moderators of its
+	// subreddit, or to Reddit for messages, for the reason given, e.g.
+	// one of the subreddit's rules.
+	Report(name, reason string) error
+}
+
+// PostFlair is the link flair a post is made with.
+type PostFlair struct {
+	// TemplateID is the ID of one of the subreddit's flair templates.
+	TemplateID string
+	// Text, if set, is the flair's text, for templates which allow it to
+	// be edited.
+	Text string
 }
 
 type account struct {
@@ -89,6 +173,25 @@ func (a *account) GetPostSelf(subreddit, title, text string) (Submission, error)
 	)
 }
 
+func (a *account) GetPostSelfWithFlair(
+	subreddit, title, text string,
+	flair PostFlair,
+) (Submission, error) {
+	values := map[string]string{
+		"sr":    subreddit,
+		"kind":  "self",
+		"title": title,
+		"text":  text,
+	}
+	if flair.TemplateID != "" {
+		values["flair_id"] = flair.TemplateID
+	}
+	if flair.Text != "" {
+		values["flair_text"] = flair.Text
+	}
+	return a.r.get_sow("/api/submit", values)
+}
+
 func (a *account) PostLink(subreddit, title, url string) error {
 	return a.r.sow(
 		"/api/submit", map[string]string{
@@ -110,3 +213,16 @@ func (a *account) GetPostLink(subreddit, title, url string) (Submission, error)
 		},
 	)
 }
+
+func (a *account) Me() (*Redditor, error) {
+	r := &Redditor{}
+	if err := a.r.reapInto(
+		"/api/v1/me",
+		map[string]string{"raw_json": "1"},
+		r,
+	); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
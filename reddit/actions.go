@@ -0,0 +1,88 @@
+package reddit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kinds of the things the standard user actions apply to.
+const (
+	commentPrefix = commentKind + "_"
+	postPrefix    = postKind + "_"
+	messagePrefix = messageKind + "_"
+)
+
+// checkName returns an error unless the name is the fullname of a thing of
+// one of the kinds given by their prefixes, e.g. "t3_" for posts.
+func checkName(name, what string, prefixes ...string) error {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not the fullname of a %s", name, what)
+}
+
+func (a *account) Upvote(name string) error {
+	return a.vote(name, "1")
+}
+
+func (a *account) Downvote(name string) error {
+	return a.vote(name, "-1")
+}
+
+func (a *account) ClearVote(name string) error {
+	return a.vote(name, "0")
+}
+
+// vote sets the bot's vote on the post or comment; dir is "1", "-1", or "0".
+func (a *account) vote(name, dir string) error {
+	if err := checkName(name, "post or comment", postPrefix, commentPrefix); err != nil {
+		return err
+	}
+	return a.r.sow("/api/vote", map[string]string{"id": name, "dir": dir})
+}
+
+func (a *account) Save(name string) error {
+	if err := checkName(name, "post or comment", postPrefix, commentPrefix); err != nil {
+		return err
+	}
+	return a.r.sow("/api/save", map[string]string{"id": name})
+}
+
+func (a *account) Unsave(name string) error {
+	if err := checkName(name, "post or comment", postPrefix, commentPrefix); err != nil {
+		return err
+	}
+	return a.r.sow("/api/unsave", map[string]string{"id": name})
+}
+
+func (a *account) Hide(post string) error {
+	if err := checkName(post, "post", postPrefix); err != nil {
+		return err
+	}
+	return a.r.sow("/api/hide", map[string]string{"id": post})
+}
+
+func (a *account) Unhide(post string) error {
+	if err := checkName(post, "post", postPrefix); err != nil {
+		return err
+	}
+	return a.r.sow("/api/unhide", map[string]string{"id": post})
+}
+
+func (a *account) Report(name, reason string) error {
+	if err := checkName(
+		name, "post, comment, or message",
+		postPrefix, commentPrefix, messagePrefix,
+	); err != nil {
+		return err
+	}
+	return a.r.sow(
+		"/api/report", map[string]string{
+			"api_type": "json",
+			"thing_id": name,
+			"reason":   reason,
+		},
+	)
+}
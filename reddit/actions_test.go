@@ -0,0 +1,23 @@
+package reddit
+
+import "testing"
+
+func TestActionFullnames(t *testing.T) {
+	a := newAccount(reaperWhich(Harvest{}, nil))
+	for _, test := range []struct {
+		name string
+		f    func() error
+		ok   bool
+	}{
+		{"vote on post", func() error { return a.Upvote("t3_post") }, true},
+		{"vote on bare id", func() error { return a.Upvote("post") }, false},
+		{"vote on prefix", func() error { return a.Upvote("t3_") }, false},
+		{"hide comment", func() error { return a.Hide("t1_comment") }, false},
+		{"save message", func() error { return a.Save("t4_message") }, false},
+		{"report message", func() error { return a.Report("t4_message", "spam") }, true},
+	} {
+		if err := test.f(); (err == nil) != test.ok {
+			t.Errorf("%s: got error %v", test.name, err)
+		}
+	}
+}
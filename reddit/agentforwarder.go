@@ -10,23 +10,41 @@ type agentForwarder struct {
 	agent string
 }
 
-// RoundTrip sets a predefined agent in the request and then forwards it to the
-// default RountTrip implementation.
+// RoundTrip sets a predefined agent in a copy of the request and then forwards
+// it to the default RountTrip implementation. The request itself is left as it
+// is, so retrying it does not send the agent twice.
 func (a *agentForwarder) RoundTrip(r *http.Request) (*http.Response, error) {
-	r.Header.Add("User-Agent", a.agent)
+	r = r.Clone(r.Context())
+	r.Header.Set("User-Agent", a.agent)
 	return a.RoundTripper.RoundTrip(r)
 }
 
+// patchWithAgent returns a copy of the client which sends the agent. The client
+// itself is left as it is, so it can be shared by several handles.
 func patchWithAgent(client *http.Client, agent string) *http.Client {
-	if client.Transport == nil {
-		client.Transport = http.DefaultTransport
+	patched := *client
+	if patched.Transport == nil {
+		patched.Transport = http.DefaultTransport
 	}
 
-	client.Transport = &agentForwarder{RoundTripper: client.Transport, agent: agent}
-	return client
+	patched.Transport = &agentForwarder{RoundTripper: patched.Transport, agent: agent}
+	return &patched
 }
 
 func clientWithAgent(agent string) *http.Client {
-	c := &http.Client{}
-	return patchWithAgent(c, agent)
+	return httpClient(nil, nil, agent)
+}
+
+// httpClient returns the client requests are sent through, sending the agent:
+// a copy of the custom client if there is one, or else a client over the
+// transport, or over http.DefaultTransport if it is nil too.
+func httpClient(
+	client *http.Client,
+	transport http.RoundTripper,
+	agent string,
+) *http.Client {
+	if client == nil {
+		client = &http.Client{Transport: transport}
+	}
+	return patchWithAgent(client, agent)
 }
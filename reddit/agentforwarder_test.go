@@ -1,8 +1,10 @@
 package reddit
 
 import (
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -64,9 +66,70 @@ func TestAgentForwarder_RoundTrip(t *testing.T) {
 
 	client := patchWithAgent(server.Client(), "agent")
 
-	_, err := client.Get("https://example.com")
+	_, err := client.Get(server.URL)
 	if err != nil {
 		t.Error(err)
 		return
 	}
 }
+
+func TestPatchWithAgentCopies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if v := req.Header["User-Agent"]; len(v) != 1 || v[0] != "agent" {
+			t.Errorf("expected one `agent`, got %v", v)
+		}
+	}))
+	defer server.Close()
+
+	shared := server.Client()
+	transport := shared.Transport
+	patchWithAgent(shared, "other")
+	client := patchWithAgent(shared, "agent")
+	if shared.Transport != transport {
+		t.Errorf("patched the shared client")
+	}
+
+	// The request is sent twice, as a retry would send it.
+	req, err := http.NewRequest("GET", server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+}
+
+// recordingTransport answers every request with an empty listing and records
+// its path.
+type recordingTransport struct {
+	paths []string
+}
+
+func (r *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.paths = append(r.paths, req.URL.Path)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(strings.NewReader(`{"kind": "Listing", "data": {"children": []}}`)),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
+
+func TestCustomTransport(t *testing.T) {
+	transport := &recordingTransport{}
+	s, err := NewScriptFromConfig(ScriptConfig{Agent: "agent", Transport: transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Listing("/r/golang", ""); err != nil {
+		t.Fatalf("failed to read listing: %v", err)
+	}
+	if len(transport.paths) != 1 || transport.paths[0] != "/r/golang.json" {
+		t.Errorf("got requests %v; wanted one over the transport", transport.paths)
+	}
+}
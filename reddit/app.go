@@ -14,7 +14,8 @@ var (
 // https://github.com/reddit/reddit/wiki/OAuth2
 type App struct {
 	// ID and Secret are used to claim an OAuth2 grant the bot's account
-	// previously authorized.
+	// previously authorized. Installed apps have no Secret, and must
+	// authorize with a RefreshToken.
 	ID     string
 	Secret string
 
@@ -22,12 +23,19 @@ type App struct {
 	Username string
 	Password string
 
+	// RefreshToken, if set, is used to authorize instead of Username and
+	// Password. See Authorize for obtaining one.
+	RefreshToken string
+
 	// tokenURL is the url of the token request location for OAuth2.
 	tokenURL string
+	// revokeURL is the url of the token revocation location for OAuth2.
+	revokeURL string
 }
 
 func (a App) unauthenticated() bool {
-	return a.ID == "" || a.Secret == ""
+	// Installed apps have no secret, and authorize with refresh tokens.
+	return a.ID == "" || (a.Secret == "" && a.RefreshToken == "")
 }
 
 func (a App) validateAuth() error {
@@ -9,12 +9,13 @@ func TestAppUnauthenticated(t *testing.T) {
 		input  App
 		output bool
 	}{
-		{App{"y", "", "", "", ""}, true},
-		{App{"", "y", "", "", ""}, true},
-		{App{"y", "y", "", "", ""}, false},
-		{App{"y", "y", "y", "", ""}, false},
-		{App{"y", "y", "", "y", ""}, false},
-		{App{"y", "y", "y", "y", ""}, false},
+		{App{ID: "y"}, true},
+		{App{Secret: "y"}, true},
+		{App{ID: "y", Secret: "y"}, false},
+		{App{ID: "y", RefreshToken: "y"}, false},
+		{App{ID: "y", Secret: "y", Username: "y"}, false},
+		{App{ID: "y", Secret: "y", Password: "y"}, false},
+		{App{ID: "y", Secret: "y", Username: "y", Password: "y"}, false},
 	} {
 		if actual := test.input.unauthenticated(); actual != test.output {
 			t.Errorf("wrong on %d; wanted %v", i, test.output)
@@ -27,13 +28,13 @@ func TestAppValidateAuth(t *testing.T) {
 		input  App
 		output error
 	}{
-		{App{"", "", "", "", ""}, errMissingOauthCredentials},
-		{App{"y", "", "", "", ""}, errMissingOauthCredentials},
-		{App{"", "y", "", "", ""}, errMissingOauthCredentials},
-		{App{"y", "y", "y", "", ""}, errMissingPassword},
-		{App{"y", "y", "", "y", ""}, errMissingUsername},
-		{App{"y", "y", "", "", ""}, nil},
-		{App{"y", "y", "y", "y", ""}, nil},
+		{App{}, errMissingOauthCredentials},
+		{App{ID: "y"}, errMissingOauthCredentials},
+		{App{Secret: "y"}, errMissingOauthCredentials},
+		{App{ID: "y", Secret: "y", Username: "y"}, errMissingPassword},
+		{App{ID: "y", Secret: "y", Password: "y"}, errMissingUsername},
+		{App{ID: "y", Secret: "y"}, nil},
+		{App{ID: "y", Secret: "y", Username: "y", Password: "y"}, nil},
 	} {
 		if actual := test.input.validateAuth(); actual != test.output {
 			t.Errorf("wrong on %d; wanted %v", i, test.output)
@@ -1,7 +1,11 @@
 package reddit
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/net/context"
@@ -9,6 +13,12 @@ import (
 	"golang.org/x/oauth2/clientcredentials"
 )
 
+// defaultRefreshMargin is how long before its access token expires an app
+// client will claim a new one, if not configured otherwise.
+const defaultRefreshMargin = 5 * time.Minute
+
+// oauthScopes are the scopes requested for app-only tokens, which act as no
+// account; bots authorizing as an account request BotScopes.
 var oauthScopes = []string{
 	"identity",
 	"read",
@@ -17,74 +27,258 @@ var oauthScopes = []string{
 	"history",
 }
 
+// BotScopes are the OAuth2 scopes a Bot needs to use every method of its
+// interface, and the scopes Authorize requests by default. Authorize an
+// account with fewer to limit what a leaked token can do; the methods which
+// need a missing scope fail with PermissionDeniedErr:
+//
+//	identity         Me
+//	read             listings, threads, users, and subreddits
+//	history          user histories and saved or hidden elements
+//	submit           Reply, PostSelf, PostLink, and the rest of MayPost
+//	privatemessages  SendMessage and the inbox
+//	edit             EditText and Delete
+//	vote             Upvote, Downvote, and ClearVote
+//	save             Save, Unsave, Hide, and Unhide
+//	report           Report
+//	account          Block, Unblock, BlockSender, and BlockedUsers
+//	mysubreddits     the bot's moderated subreddits
+//	flair            the bot's own flair
+//	modflair         SetLinkFlair, SetUserFlair, and SetFlairCSV
+//	modposts         Remove, Approve, Lock, Sticky, and the rest of
+//	                 MayModeratePosts
+//	modconfig        SetDefaultSort and UploadStyleImage
+//	modcontributors  BanUser, Unban, BannedUsers, and Muted
+//	modlog           the moderation log
+//	modmail          modmail conversations
+//	modwiki          wiki page settings
+//	wikiread         WikiPage
+//	wikiedit         EditWikiPage
+//	livemanage       live threads
+var BotScopes = []string{
+	"identity",
+	"read",
+	"history",
+	"submit",
+	"privatemessages",
+	"edit",
+	"vote",
+	"save",
+	"report",
+	"account",
+	"mysubreddits",
+	"flair",
+	"modflair",
+	"modposts",
+	"modconfig",
+	"modcontributors",
+	"modlog",
+	"modmail",
+	"modwiki",
+	"wikiread",
+	"wikiedit",
+	"livemanage",
+}
+
+// noTokenErr is returned by requests sent while an app client holds no access
+// token.
+var noTokenErr = fmt.Errorf("no access token has been claimed")
+
 type appClient struct {
 	baseClient
-	cfg    clientConfig
-	cli    *http.Client
+	cfg clientConfig
+	cli *http.Client
+	// tokens authorizes the requests of baseClient's client, which is made
+	// once so requests in flight never race a refresh.
+	tokens *tokenSource
+	token  *oauth2.Token
 	expiry time.Time
+	mu     sync.Mutex
+}
+
+// tokenSource holds the access token an app client authorizes its requests
+// with, so a refresh can swap it while requests are in flight.
+type tokenSource struct {
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+func (t *tokenSource) Token() (*oauth2.Token, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.token == nil {
+		return nil, noTokenErr
+	}
+	return t.token, nil
+}
+
+func (t *tokenSource) set(token *oauth2.Token) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.token = token
 }
 
 func (a *appClient) Do(req *http.Request) ([]byte, error) {
-	if time.Until(a.expiry) < time.Minute*5 {
-		if err := a.authorize(); err != nil {
-			return nil, err
-		}
+	if err := a.refresh(req.Context()); err != nil {
+		return nil, err
 	}
 
 	return a.baseClient.Do(req)
 }
 
-func (a *appClient) authorize() error {
-	ctx := context.WithValue(oauth2.NoContext, oauth2.HTTPClient, a.cli)
+// refresh claims a new access token if the current one expires within the
+// refresh margin, so requests are never made with an expired token. The claim
+// is made with the context of the request which needs the token.
+func (a *appClient) refresh(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 
-	if a.cfg.app.Username == "" || a.cfg.app.Password == "" {
-		a.baseClient.cli = a.clientCredentialsClient(ctx)
+	if time.Until(a.expiry) >= a.refreshMargin() {
 		return nil
 	}
 
+	return a.authorize(ctx)
+}
+
+func (a *appClient) refreshMargin() time.Duration {
+	if a.cfg.refreshMargin > 0 {
+		return a.cfg.refreshMargin
+	}
+	return defaultRefreshMargin
+}
+
+// tokenExpiresAt returns when the current access token expires.
+func (a *appClient) tokenExpiresAt() time.Time {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.expiry
+}
+
+func (a *appClient) authorize(ctx context.Context) error {
+	token, err := a.claim(context.WithValue(ctx, oauth2.HTTPClient, a.cli))
+	if err != nil {
+		return err
+	}
+
+	// The token is renewed by refresh() ahead of its expiry, rather than
+	// by the oauth2 package when it has already expired.
+	a.tokens.set(token)
+	a.token = token
+	a.expiry = token.Expiry
+	return nil
+}
+
+// revoke revokes the current access token, and if all is true, the refresh
+// token the app authorizes with. The next request will claim a new access
+// token, which fails if the refresh token was revoked.
+func (a *appClient) revoke(all bool) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if all && a.cfg.app.RefreshToken != "" {
+		// Revoking a refresh token revokes every access token claimed
+		// with it.
+		if err := a.revokeToken(a.cfg.app.RefreshToken, "refresh_token"); err != nil {
+			return err
+		}
+	} else if a.token != nil {
+		if err := a.revokeToken(a.token.AccessToken, "access_token"); err != nil {
+			return err
+		}
+	}
+
+	a.tokens.set(nil)
+	a.token = nil
+	a.expiry = time.Time{}
+	return nil
+}
+
+func (a *appClient) revokeToken(token, hint string) error {
+	req, err := http.NewRequest(
+		"POST",
+		a.cfg.app.revokeURL,
+		strings.NewReader(
+			url.Values{
+				"token":           {token},
+				"token_type_hint": {hint},
+			}.Encode(),
+		),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(a.cfg.app.ID, a.cfg.app.Secret)
+
+	resp, err := a.cli.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("bad response code revoking token: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// claim claims a new access token using the strongest credentials the app was
+// configured with.
+func (a *appClient) claim(ctx context.Context) (*oauth2.Token, error) {
+	if a.cfg.app.Username == "" && a.cfg.app.RefreshToken == "" {
+		return a.clientCredentialsConfig().Token(ctx)
+	}
+
 	cfg := &oauth2.Config{
 		ClientID:     a.cfg.app.ID,
 		ClientSecret: a.cfg.app.Secret,
 		Endpoint:     oauth2.Endpoint{TokenURL: a.cfg.app.tokenURL},
-		Scopes:       oauthScopes,
+		Scopes:       BotScopes,
+	}
+
+	if a.cfg.app.RefreshToken != "" {
+		return cfg.TokenSource(
+			ctx,
+			&oauth2.Token{RefreshToken: a.cfg.app.RefreshToken},
+		).Token()
 	}
 
-	token, err := cfg.PasswordCredentialsToken(
+	return cfg.PasswordCredentialsToken(
 		ctx,
 		a.cfg.app.Username,
 		a.cfg.app.Password,
 	)
-	if err != nil {
-		return err
-	}
-
-	a.baseClient.cli = cfg.Client(ctx, token)
-	a.expiry = token.Expiry
-	return err
 }
 
-func (a *appClient) clientCredentialsClient(ctx context.Context) *http.Client {
-	cfg := &clientcredentials.Config{
+func (a *appClient) clientCredentialsConfig() *clientcredentials.Config {
+	return &clientcredentials.Config{
 		ClientID:     a.cfg.app.ID,
 		ClientSecret: a.cfg.app.Secret,
 		TokenURL:     a.cfg.app.tokenURL,
 		Scopes:       oauthScopes,
 	}
-
-	return cfg.Client(ctx)
 }
 
 func newAppClient(c clientConfig) (*appClient, error) {
-	var client *http.Client
-	if c.client == nil {
-		client = clientWithAgent(c.agent)
-	} else {
-		client = patchWithAgent(c.client, c.agent)
-	}
+	client := httpClient(c.client, c.transport, c.agent)
+	tokens := &tokenSource{}
+
+	// The authorized client keeps the rest of the custom client's
+	// settings, such as its timeout.
+	authorized := *client
+	authorized.Transport = &oauth2.Transport{Source: tokens, Base: client.Transport}
 
 	a := &appClient{
-		cli: client,
-		cfg: c,
+		baseClient: baseClient{
+			cli:     &authorized,
+			quota:   c.quota,
+			trace:   c.trace,
+			metrics: c.metrics,
+		},
+		cli:    client,
+		cfg:    c,
+		tokens: tokens,
 	}
-	return a, a.authorize()
+	return a, a.authorize(context.Background())
 }
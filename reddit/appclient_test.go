@@ -0,0 +1,202 @@
+package reddit
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tokenServer serves access tokens which expire in an hour, counting how many
+// it has issued.
+func tokenServer(issued *int) *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				*issued++
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{
+					"access_token": "access",
+					"token_type": "bearer",
+					"expires_in": 3600
+				}`))
+			},
+		),
+	)
+}
+
+func TestAppClientRefreshMargin(t *testing.T) {
+	for _, test := range []struct {
+		margin time.Duration
+		issued int
+	}{
+		{time.Minute, 1},
+		{2 * time.Hour, 2},
+	} {
+		issued := 0
+		tokens := tokenServer(&issued)
+		api := serverWhich([]byte("{}"), http.StatusOK)
+
+		a, err := newAppClient(
+			clientConfig{
+				app: App{
+					ID:       "id",
+					Secret:   "secret",
+					tokenURL: tokens.URL,
+				},
+				refreshMargin: test.margin,
+			},
+		)
+		if err != nil {
+			t.Fatalf("failed to authorize: %v", err)
+		}
+
+		if until := time.Until(a.tokenExpiresAt()); until < 59*time.Minute || until > time.Hour {
+			t.Errorf("wanted expiry in an hour; got %v", until)
+		}
+
+		req, _ := http.NewRequest("GET", api.URL, nil)
+		if _, err := a.Do(req); err != nil {
+			t.Errorf("request failed: %v", err)
+		}
+
+		if issued != test.issued {
+			t.Errorf(
+				"margin %v: got %d tokens issued; wanted %d",
+				test.margin, issued, test.issued,
+			)
+		}
+
+		tokens.Close()
+		api.Close()
+	}
+}
+
+func TestAppClientRevoke(t *testing.T) {
+	for _, test := range []struct {
+		all          bool
+		refreshToken string
+		token        string
+		hint         string
+	}{
+		{false, "", "access", "access_token"},
+		{true, "", "access", "access_token"},
+		{true, "refresh", "refresh", "refresh_token"},
+	} {
+		issued := 0
+		tokens := tokenServer(&issued)
+		var token, hint string
+		revocations := httptest.NewServer(
+			http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					r.ParseForm()
+					token = r.PostForm.Get("token")
+					hint = r.PostForm.Get("token_type_hint")
+				},
+			),
+		)
+
+		a, err := newAppClient(
+			clientConfig{
+				app: App{
+					ID:           "id",
+					Secret:       "secret",
+					RefreshToken: test.refreshToken,
+					tokenURL:     tokens.URL,
+					revokeURL:    revocations.URL,
+				},
+			},
+		)
+		if err != nil {
+			t.Fatalf("failed to authorize: %v", err)
+		}
+
+		if err := a.revoke(test.all); err != nil {
+			t.Errorf("failed to revoke: %v", err)
+		}
+
+		if token != test.token || hint != test.hint {
+			t.Errorf(
+				"revoked %s (%s); wanted %s (%s)",
+				token, hint, test.token, test.hint,
+			)
+		}
+
+		if !a.tokenExpiresAt().IsZero() {
+			t.Errorf("wanted revoked token forgotten")
+		}
+
+		tokens.Close()
+		revocations.Close()
+	}
+}
+
+// tokenTransport answers token claims with tokens which expire in an hour, and
+// every other request with an empty object, counting the claims in issued. It
+// answers in process without synchronizing, so the race detector sees races
+// between requests that a pooled transport would hide.
+type tokenTransport struct {
+	tokenURL string
+	issued   *int
+	t        *testing.T
+}
+
+func (tt *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := "{}"
+	if req.URL.String() == tt.tokenURL {
+		*tt.issued++
+		body = `{"access_token": "access", "token_type": "bearer", "expires_in": 3600}`
+	} else if auth := req.Header.Get("Authorization"); auth != "Bearer access" {
+		tt.t.Errorf("request sent with authorization %q", auth)
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestAppClientConcurrentRefresh(t *testing.T) {
+	// Claims are made holding the client's lock, which guards issued.
+	issued := 0
+	tokenURL := "https://www.reddit.com/api/v1/access_token"
+
+	// A margin longer than the token's life refreshes it on every request,
+	// while the others are in flight.
+	a, err := newAppClient(
+		clientConfig{
+			app: App{
+				ID:       "id",
+				Secret:   "secret",
+				tokenURL: tokenURL,
+			},
+			transport:     &tokenTransport{tokenURL: tokenURL, issued: &issued, t: t},
+			refreshMargin: 2 * time.Hour,
+		},
+	)
+	if err != nil {
+		t.Fatalf("failed to authorize: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest("GET", "https://oauth.reddit.com/api/v1/me", nil)
+			if _, err := a.Do(req); err != nil {
+				t.Errorf("request failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if issued != 11 {
+		t.Errorf("got %d tokens issued; wanted 11", issued)
+	}
+}
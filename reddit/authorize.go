@@ -0,0 +1,258 @@
+package reddit
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// authURL is the url of the page where Reddit users grant apps access to their
+// accounts.
+const authURL = "https://www.reddit.com/api/v1/authorize"
+
+var (
+	errMissingRedirectURL = fmt.Errorf("missing redirect url")
+	errStateMismatch      = fmt.Errorf("authorization state did not match")
+	errAuthorizeTimeout   = fmt.Errorf("timed out waiting for authorization")
+)
+
+// AuthorizeConfig configures an interactive authorization with Authorize.
+type AuthorizeConfig struct {
+	// Agent is the user-agent sent in requests made while authorizing.
+	Agent string
+	// App is the registered app being authorized. Only ID and Secret are
+	// used. Installed apps, such as desktop tools shipped to their users,
+	// have no Secret.
+	App App
+	// RedirectURL is the redirect uri registered with the app. It must be
+	// an http url served by this machine, such as
+	// "http://localhost:8080/authorize_callback".
+	RedirectURL string
+	// Scopes are the OAuth2 scopes requested. If empty, BotScopes are
+	// requested.
+	Scopes []string
+	// Prompt is called with the url the user must open in a browser to
+	// authorize the app. If nil, the url is printed to stdout.
+	Prompt func(url string)
+	// If PKCE is set, the authorization is tied to a code challenge, so a
+	// code intercepted on its way to RedirectURL cannot be exchanged by
+	// anyone else. It is always used for installed apps.
+	PKCE bool
+	// Timeout is how long to wait for the user's browser to be redirected
+	// to RedirectURL before giving up. If zero, Authorize waits forever.
+	Timeout time.Duration
+}
+
+// Authorize walks a user through granting an app permanent access to their
+// account. It serves RedirectURL until the user's browser is redirected
+// there, and then returns a refresh token which can be set on App or stored
+// in an agent file with SaveRefreshToken.
+func Authorize(c AuthorizeConfig) (string, error) {
+	if c.RedirectURL == "" {
+		return "", errMissingRedirectURL
+	}
+
+	redirect, err := url.Parse(c.RedirectURL)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := randomState()
+	if err != nil {
+		return "", err
+	}
+
+	authParams := []oauth2.AuthCodeOption{
+		oauth2.SetAuthURLParam("duration", "permanent"),
+	}
+	var exchangeParams []oauth2.AuthCodeOption
+	if c.PKCE || c.App.Secret == "" {
+		verifier, err := codeVerifier()
+		if err != nil {
+			return "", err
+		}
+
+		authParams = append(
+			authParams,
+			oauth2.SetAuthURLParam("code_challenge", codeChallenge(verifier)),
+			oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+		)
+		exchangeParams = append(
+			exchangeParams,
+			oauth2.SetAuthURLParam("code_verifier", verifier),
+		)
+	}
+
+	listener, err := net.Listen("tcp", redirect.Host)
+	if err != nil {
+		return "", err
+	}
+
+	codes := make(chan string, 1)
+	errs := make(chan error, 1)
+	srv := &http.Server{
+		Handler: http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != redirect.Path {
+					http.NotFound(w, r)
+					return
+				}
+
+				q := r.URL.Query()
+				var err error
+				switch {
+				case q.Get("error") != "":
+					err = fmt.Errorf("authorization failed: %s", q.Get("error"))
+				case q.Get("state") != state:
+					err = errStateMismatch
+				}
+
+				// The page is flushed before Authorize is told the result,
+				// since it closes the server once it is.
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					fmt.Fprintf(w, "Could not authorize the app (%v); you may close this window.\n", err)
+				} else {
+					fmt.Fprintln(w, "Authorization finished; you may close this window.")
+				}
+				if f, ok := w.(http.Flusher); ok {
+					f.Flush()
+				}
+
+				if err != nil {
+					sendErr(errs, err)
+					return
+				}
+				select {
+				case codes <- q.Get("code"):
+				default:
+				}
+			},
+		),
+	}
+	go srv.Serve(listener)
+	defer srv.Close()
+
+	cfg := authorizeOAuthConfig(c)
+	prompt := c.Prompt
+	if prompt == nil {
+		prompt = func(u string) {
+			fmt.Printf("Open this url to authorize the app:\n\n%s\n\n", u)
+		}
+	}
+	prompt(cfg.AuthCodeURL(state, authParams...))
+
+	var timeout <-chan time.Time
+	if c.Timeout > 0 {
+		timer := time.NewTimer(c.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-timeout:
+		return "", errAuthorizeTimeout
+	case err := <-errs:
+		return "", err
+	case code := <-codes:
+		ctx := context.WithValue(
+			context.Background(),
+			oauth2.HTTPClient,
+			clientWithAgent(c.Agent),
+		)
+		token, err := cfg.Exchange(ctx, code, exchangeParams...)
+		if err != nil {
+			return "", err
+		}
+		return token.RefreshToken, nil
+	}
+}
+
+// AuthorizeAgentFile is Authorize for the app in an agent file, storing the
+// refresh token it returns in the file with SaveRefreshToken. Agent and App are
+// read from the file unless they are set on the config.
+func AuthorizeAgentFile(filename string, c AuthorizeConfig) error {
+	agent, app, err := load(filename)
+	if err != nil {
+		return err
+	}
+
+	if c.Agent == "" {
+		c.Agent = agent
+	}
+	if c.App.ID == "" {
+		c.App = app
+	}
+
+	refreshToken, err := Authorize(c)
+	if err != nil {
+		return err
+	}
+
+	return SaveRefreshToken(filename, refreshToken)
+}
+
+func authorizeOAuthConfig(c AuthorizeConfig) *oauth2.Config {
+	scopes := c.Scopes
+	if len(scopes) == 0 {
+		scopes = BotScopes
+	}
+
+	tokenEndpoint := c.App.tokenURL
+	if tokenEndpoint == "" {
+		tokenEndpoint = tokenURL
+	}
+
+	return &oauth2.Config{
+		ClientID:     c.App.ID,
+		ClientSecret: c.App.Secret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  authURL,
+			TokenURL: tokenEndpoint,
+		},
+		RedirectURL: c.RedirectURL,
+		Scopes:      scopes,
+	}
+}
+
+// randomState returns an unguessable value to tie an authorization redirect to
+// the request which started it.
+func randomState() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// codeVerifier returns a PKCE code verifier: a secret the token exchange must
+// present to prove it started the authorization.
+func codeVerifier() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// codeChallenge returns the S256 PKCE challenge of a code verifier.
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func sendErr(errs chan<- error, err error) {
+	select {
+	case errs <- err:
+	default:
+	}
+}
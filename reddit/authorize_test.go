@@ -0,0 +1,169 @@
+package reddit
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func freeAddr(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find a free port: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func TestAuthorize(t *testing.T) {
+	tokens := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil {
+					t.Errorf("failed to parse token request: %v", err)
+				}
+				if code := r.PostForm.Get("code"); code != "thecode" {
+					t.Errorf("wrong code exchanged: %s", code)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{
+					"access_token": "access",
+					"token_type": "bearer",
+					"expires_in": 3600,
+					"refresh_token": "refresh"
+				}`))
+			},
+		),
+	)
+	defer tokens.Close()
+
+	redirect := "http://" + freeAddr(t) + "/callback"
+	refreshToken, err := Authorize(
+		AuthorizeConfig{
+			Agent:       "agent",
+			App:         App{ID: "id", Secret: "secret", tokenURL: tokens.URL},
+			RedirectURL: redirect,
+			Prompt: func(authURL string) {
+				u, err := url.Parse(authURL)
+				if err != nil {
+					t.Fatalf("bad auth url: %v", err)
+				}
+				q := u.Query()
+				if q.Get("duration") != "permanent" {
+					t.Errorf("wanted permanent duration; got %s", authURL)
+				}
+				if scope := q.Get("scope"); scope != strings.Join(BotScopes, " ") {
+					t.Errorf("wanted every scope bots use; got %s", scope)
+				}
+				go http.Get(
+					redirect + "?code=thecode&state=" + q.Get("state"),
+				)
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("failed to authorize: %v", err)
+	}
+
+	if refreshToken != "refresh" {
+		t.Errorf("got refresh token %q; wanted %q", refreshToken, "refresh")
+	}
+}
+
+func TestAuthorizePKCE(t *testing.T) {
+	challenges := make(chan string, 1)
+	tokens := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil {
+					t.Errorf("failed to parse token request: %v", err)
+				}
+				verifier := r.PostForm.Get("code_verifier")
+				if challenge := <-challenges; codeChallenge(verifier) != challenge {
+					t.Errorf("verifier %q does not match challenge %q", verifier, challenge)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"access_token": "access", "refresh_token": "refresh"}`))
+			},
+		),
+	)
+	defer tokens.Close()
+
+	// Installed apps have no secret, so they always use PKCE.
+	redirect := "http://" + freeAddr(t) + "/callback"
+	refreshToken, err := Authorize(
+		AuthorizeConfig{
+			App:         App{ID: "id", tokenURL: tokens.URL},
+			RedirectURL: redirect,
+			Prompt: func(authURL string) {
+				u, err := url.Parse(authURL)
+				if err != nil {
+					t.Fatalf("bad auth url: %v", err)
+				}
+				q := u.Query()
+				if q.Get("code_challenge_method") != "S256" {
+					t.Errorf("wanted an S256 challenge; got %s", authURL)
+				}
+				challenges <- q.Get("code_challenge")
+				go http.Get(
+					redirect + "?code=thecode&state=" + q.Get("state"),
+				)
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("failed to authorize: %v", err)
+	}
+
+	if refreshToken != "refresh" {
+		t.Errorf("got refresh token %q; wanted %q", refreshToken, "refresh")
+	}
+}
+
+func TestAuthorizeStateMismatch(t *testing.T) {
+	redirect := "http://" + freeAddr(t) + "/callback"
+	pages := make(chan string, 1)
+	if _, err := Authorize(
+		AuthorizeConfig{
+			App:         App{ID: "id", Secret: "secret"},
+			RedirectURL: redirect,
+			Prompt: func(string) {
+				go func() {
+					resp, err := http.Get(redirect + "?code=thecode&state=forged")
+					if err != nil {
+						pages <- err.Error()
+						return
+					}
+					defer resp.Body.Close()
+					body, _ := ioutil.ReadAll(resp.Body)
+					pages <- string(body)
+				}()
+			},
+		},
+	); err != errStateMismatch {
+		t.Errorf("wanted state mismatch; got %v", err)
+	}
+
+	// The user is told the authorization failed, not that it finished.
+	if page := <-pages; !strings.HasPrefix(page, "Could not authorize") {
+		t.Errorf("wanted a failure page; got %q", page)
+	}
+}
+
+func TestAuthorizeTimeout(t *testing.T) {
+	if _, err := Authorize(
+		AuthorizeConfig{
+			App:         App{ID: "id", Secret: "secret"},
+			RedirectURL: "http://" + freeAddr(t) + "/callback",
+			Prompt:      func(string) {},
+			Timeout:     10 * time.Millisecond,
+		},
+	); err != errAuthorizeTimeout {
+		t.Errorf("wanted a timeout; got %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package reddit
+
+// blockedResponse is the shape of Reddit's listing of the accounts a user
+// blocked.
+type blockedResponse struct {
+	Data struct {
+		Children []struct {
+			Name string `mapstructure:"name"`
+		} `mapstructure:"children"`
+	} `mapstructure:"data"`
+}
+
+func (a *account) BlockedUsers() ([]string, error) {
+	resp := &blockedResponse{}
+	if err := a.r.reapInto("/prefs/blocked", map[string]string{}, resp); err != nil {
+		return nil, err
+	}
+
+	users := make([]string, 0, len(resp.Data.Children))
+	for _, c := range resp.Data.Children {
+		users = append(users, c.Name)
+	}
+	return users, nil
+}
+
+func (a *account) Block(user string) error {
+	return a.r.sow("/api/block_user", map[string]string{"name": user})
+}
+
+func (a *account) Unblock(user string) error {
+	// Blocks are a relationship of the account's own, which Reddit needs
+	// the fullname of.
+	me, err := a.Me()
+	if err != nil {
+		return err
+	}
+
+	return a.r.sow(
+		"/api/unfriend", map[string]string{
+			"container": "t2_" + me.ID,
+			"name":      user,
+			"type":      "enemy",
+		},
+	)
+}
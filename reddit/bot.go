@@ -1,8 +1,12 @@
 package reddit
 
 import (
+	"context"
 	"net/http"
 	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/logging"
 )
 
 // BotConfig configures a Reddit bot's behavior with the Reddit package.
@@ -16,48 +20,226 @@ type BotConfig struct {
 	App App
 	// Rate is the minimum amount of time between requests. If Rate is
 	// configured lower than 1 second, the it will be ignored; Reddit's API
-	// rules cap OAuth2 clients at 60 requests per minute. See package
-	// overview for rate limit information.
+	// rules cap OAuth2 clients at 60 requests per minute. Requests are
+	// spaced further apart when Reddit reports the quota running low. See
+	// package overview for rate limit information.
 	Rate time.Duration
-	// Custom HTTP client
+	// Client, if set, is the HTTP client the bot's requests are sent
+	// through, e.g. with a timeout or cookie jar of its own. It is copied,
+	// not modified, so it can be shared.
 	Client *http.Client
+	// Transport, if set and Client is not, is the transport the bot's
+	// requests are sent over, e.g. an *http.Transport through a proxy or
+	// with custom TLS, dial timeouts, or connection pooling. If neither is
+	// set, http.DefaultTransport is used, which honors the HTTPS_PROXY
+	// environment variable.
+	Transport http.RoundTripper
+	// TokenRefreshMargin is how long before the OAuth2 access token
+	// expires that a new one is claimed. Renewing ahead of expiry keeps
+	// long running streams from ever sending an expired token. If zero,
+	// the margin is 5 minutes.
+	TokenRefreshMargin time.Duration
+	// Clock times the rate limit between requests. Set it to the clock a
+	// replay is simulating so the rate limit costs simulated time instead
+	// of real time. If nil, the wall clock is used.
+	Clock clock.Clock
+	// WrapClient, if set, wraps the client the bot sends its requests
+	// through. See Doer.
+	WrapClient func(Doer) Doer
+	// Middleware wraps the client the bot sends its requests through, in
+	// order, inside WrapClient. See Chain.
+	Middleware []Middleware
+	// Retry configures how requests which fail transiently are retried.
+	// If it is zero, they are not.
+	Retry RetryConfig
+	// ReplyGuard, if set, keeps the bot from replying to old or archived
+	// posts and comments.
+	ReplyGuard ReplyGuard
+	// Trace, if set, is sent a Debug entry for every request the bot
+	// makes, with its method, path, status, rate limit headers, and
+	// latency.
+	Trace logging.Logger
+	// Metrics, if set, measures the requests the bot makes, and the
+	// streams reading through it report the events they drop to it.
+	Metrics MetricsCollector
 }
 
 // Bot defines the behaviors of a logged in Reddit bot.
 type Bot interface {
 	Account
 	Lurker
+	UserLurker
 	Scanner
+	Wiki
+	Moderator
+	MediaUploader
+	Modmail
+	Live
+
+	// TokenExpiresAt returns when the bot's current OAuth2 access token
+	// expires. It is renewed automatically; this is for health checks.
+	TokenExpiresAt() time.Time
+	// RevokeToken revokes the bot's current access token. Any later
+	// request will claim a new one. Bots should call this when they shut
+	// down so no live token outlives them.
+	RevokeToken() error
+	// RevokeAll revokes the bot's refresh token, which revokes every
+	// access token claimed with it, so that no request will succeed until
+	// the app is authorized again. Bots authorizing with a username and
+	// password have no refresh token; only their current access token is
+	// revoked, and the password must be changed to lock the bot out.
+	RevokeAll() error
+	// Metrics returns the collector the bot's config set, or nil, so the
+	// streams reading through the bot can report to it too.
+	Metrics() MetricsCollector
+
+	// ReadOnly returns a view of the bot which can read from Reddit but
+	// fails with ReadOnlyErr on any method that would write to it. It is
+	// safe to hand to analytics code or plugins that are not fully
+	// trusted.
+	ReadOnly() Bot
+	// Restrict returns a view of the bot which can read from Reddit but
+	// fails with NotPermittedErr on any write it was not given permission
+	// for. Give each plugin or third party handler a view restricted to
+	// the actions it needs, so a buggy one cannot act destructively.
+	// Restricting a restricted view can only remove permissions.
+	Restrict(p Permission) Bot
+	// WithContext returns a view of the bot whose requests are made with
+	// ctx, so they fail with its error once it is cancelled or its
+	// deadline passes, including while they wait their turn under the
+	// rate limit. The view shares the bot's rate limit and token.
+	WithContext(ctx context.Context) Bot
 }
 
 type bot struct {
 	Account
 	Lurker
+	UserLurker
 	Scanner
+	Wiki
+	Moderator
+	MediaUploader
+	Modmail
+	Live
+
+	cli     client
+	r       reaper
+	metrics MetricsCollector
 }
 
 // NewBot returns a logged in handle to the Reddit API.
 func NewBot(c BotConfig) (Bot, error) {
-	cli, err := newClient(clientConfig{agent: c.Agent, app: c.App, client: c.Client})
+	q := newQuota(c.Clock)
+	cli, err := newClient(
+		clientConfig{
+			agent:         c.Agent,
+			app:           c.App,
+			client:        c.Client,
+			transport:     c.Transport,
+			refreshMargin: c.TokenRefreshMargin,
+			quota:         q,
+			trace:         c.Trace,
+			metrics:       c.Metrics,
+		},
+	)
 	r := newReaper(
 		reaperConfig{
-			client:   cli,
+			client:   wrapClient(cli, c.WrapClient, c.Middleware),
 			parser:   newParser(),
 			hostname: "oauth.reddit.com",
 			tls:      true,
 			rate:     maxOf(c.Rate, time.Second),
+			clock:    c.Clock,
+			quota:    q,
+			retries:  c.Retry,
+			uploader: httpClient(c.Client, c.Transport, c.Agent),
 		},
 	)
+	b := &bot{
+		Account:       newAccount(r),
+		Lurker:        newLurker(r),
+		UserLurker:    newUserLurker(r),
+		Scanner:       newScanner(r),
+		Wiki:          newWiki(r),
+		Moderator:     newModerator(r),
+		MediaUploader: newMediaUploader(r),
+		Modmail:       newModmail(r),
+		Live:          newLive(r),
+		cli:           cli,
+		r:             r,
+		metrics:       c.Metrics,
+	}
+	return guard(b, r, c.ReplyGuard, c.Clock), err
+}
+
+func (b *bot) TokenExpiresAt() time.Time {
+	if te, ok := b.cli.(tokenExpirer); ok {
+		return te.tokenExpiresAt()
+	}
+
+	return time.Time{}
+}
+
+func (b *bot) Metrics() MetricsCollector {
+	return b.metrics
+}
+
+func (b *bot) ReadOnly() Bot {
+	return &restrictedBot{Bot: b, err: ReadOnlyErr}
+}
+
+func (b *bot) Restrict(p Permission) Bot {
+	return &restrictedBot{Bot: b, allowed: p, err: NotPermittedErr}
+}
+
+func (b *bot) WithContext(ctx context.Context) Bot {
+	r := b.r.withContext(ctx)
 	return &bot{
-		Account: newAccount(r),
-		Lurker:  newLurker(r),
-		Scanner: newScanner(r),
-	}, err
+		Account:       newAccount(r),
+		Lurker:        newLurker(r),
+		UserLurker:    newUserLurker(r),
+		Scanner:       newScanner(r),
+		Wiki:          newWiki(r),
+		Moderator:     newModerator(r),
+		MediaUploader: newMediaUploader(r),
+		Modmail:       newModmail(r),
+		Live:          newLive(r),
+		cli:           b.cli,
+		r:             r,
+		metrics:       b.metrics,
+	}
+}
+
+func (b *bot) RevokeToken() error {
+	if r, ok := b.cli.(revoker); ok {
+		return r.revoke(false)
+	}
+
+	return nil
+}
+
+func (b *bot) RevokeAll() error {
+	if r, ok := b.cli.(revoker); ok {
+		return r.revoke(true)
+	}
+
+	return nil
 }
 
 // NewBotFromAgentFile calls NewBot with a config built from an agent file. An
 // agent file is a convenient way to store your bot's account information. See
 // https://github.com/turnage/graw/wiki/agent-files
+//
+// To configure the bot further, e.g. to send its requests through a proxy,
+// build the config from the agent file with LoadAgentFile:
+//
+//	agent, app, err := reddit.LoadAgentFile("bot.agent")
+//	...
+//	bot, err := reddit.NewBot(reddit.BotConfig{
+//		Agent:     agent,
+//		App:       app,
+//		Transport: &http.Transport{Proxy: http.ProxyURL(proxy)},
+//	})
 func NewBotFromAgentFile(filename string, rate time.Duration) (Bot, error) {
 	agent, app, err := load(filename)
 	if err != nil {
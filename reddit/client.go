@@ -2,12 +2,23 @@ package reddit
 
 import (
 	"bytes"
-	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aldarisbm/graw/logging"
 )
 
-// tokenURL is the url of reddit's oauth2 authorization service.
-const tokenURL = "https://www.reddit.com/api/v1/access_token"
+const (
+	// tokenURL is the url of reddit's oauth2 authorization service.
+	tokenURL = "https://www.reddit.com/api/v1/access_token"
+	// revokeURL is the url of reddit's oauth2 token revocation service.
+	revokeURL = "https://www.reddit.com/api/v1/revoke_token"
+	// maxResponseSize is the largest response body the client will read.
+	// Listings of a hundred things are a few megabytes at most.
+	maxResponseSize = 32 << 20
+)
 
 // clientConfig holds all the information needed to define Client behavior, such
 // as who the client will identify as externally and where to authorize.
@@ -21,6 +32,23 @@ type clientConfig struct {
 
 	// Custom http client, if nil default should be used
 	client *http.Client
+
+	// transport, if set and client is not, is the transport requests are
+	// sent over.
+	transport http.RoundTripper
+
+	// refreshMargin is how long before its expiry an access token is
+	// renewed. If zero, a default is used.
+	refreshMargin time.Duration
+
+	// quota, if set, records the request quota Reddit reports.
+	quota *quota
+
+	// trace, if set, logs every request the client makes.
+	trace logging.Logger
+
+	// metrics, if set, measures every request the client makes.
+	metrics MetricsCollector
 }
 
 // client executes http Requests and invisibly handles OAuth2 authorization.
@@ -28,51 +56,164 @@ type client interface {
 	Do(*http.Request) ([]byte, error)
 }
 
+// Doer is the client a Bot or Script sends its requests to Reddit through. Set
+// Middleware or WrapClient in their configs to wrap it, e.g. to inject latency
+// or faults in tests, record traffic, or mirror requests to a staging server.
+//
+// Do sends req, which is already authorized, and returns the body of Reddit's
+// response. It returns PermissionDeniedErr, BusyErr, RateLimitErr,
+// ConflictErr, GatewayErr, or GatewayTimeoutErr for the corresponding status
+// codes, ResponseTooLargeErr for bodies over 32MB, and other errors for other
+// failures; the rest of the package relies on these to retry and back off.
+// Requests start no faster than the configured rate, but Do must be safe to
+// call concurrently.
+type Doer interface {
+	Do(req *http.Request) ([]byte, error)
+}
+
+// tokenExpirer is implemented by clients which authorize with an expiring
+// access token.
+type tokenExpirer interface {
+	tokenExpiresAt() time.Time
+}
+
+// revoker is implemented by clients which can revoke their OAuth2 tokens.
+type revoker interface {
+	revoke(all bool) error
+}
+
 type baseClient struct {
-	cli *http.Client
+	cli     *http.Client
+	quota   *quota
+	trace   logging.Logger
+	metrics MetricsCollector
 }
 
 func (b *baseClient) Do(req *http.Request) ([]byte, error) {
+	start := time.Now()
 	resp, err := b.cli.Do(req)
 	if resp != nil && resp.Body != nil {
 		defer resp.Body.Close()
 	}
+	latency := time.Since(start)
+	b.traceRequest(req, resp, err, latency)
+	b.measureRequest(req, resp, latency)
 	if err != nil {
 		return nil, err
 	}
 
+	b.quota.observe(resp.Header)
+
 	switch resp.StatusCode {
 	case http.StatusOK:
 	case http.StatusForbidden:
 		return nil, PermissionDeniedErr
+	case http.StatusNotFound:
+		return nil, NotFoundErr
 	case http.StatusServiceUnavailable:
 		return nil, BusyErr
 	case http.StatusTooManyRequests:
 		return nil, RateLimitErr
+	case http.StatusConflict:
+		return nil, ConflictErr
 	case http.StatusBadGateway:
 		return nil, GatewayErr
 	case http.StatusGatewayTimeout:
 		return nil, GatewayTimeoutErr
 	default:
-		return nil, fmt.Errorf("bad response code: %d", resp.StatusCode)
+		return nil, &StatusError{Code: resp.StatusCode}
 	}
 
 	var buf bytes.Buffer
-	if _, err := buf.ReadFrom(resp.Body); err != nil {
+	if _, err := buf.ReadFrom(io.LimitReader(resp.Body, maxResponseSize+1)); err != nil {
 		return nil, err
 	}
 
+	if buf.Len() > maxResponseSize {
+		return nil, ResponseTooLargeErr
+	}
+
 	return buf.Bytes(), nil
 }
 
+// traceRequest logs a request the client made, if it traces them, with the
+// status and rate limit headers of Reddit's response.
+func (b *baseClient) traceRequest(
+	req *http.Request,
+	resp *http.Response,
+	err error,
+	latency time.Duration,
+) {
+	if b.trace == nil {
+		return
+	}
+
+	fields := []logging.Field{
+		logging.F("method", req.Method),
+		logging.F("path", req.URL.Path),
+	}
+	if err != nil {
+		b.trace.Log(
+			logging.Debug, "request failed",
+			append(fields, logging.F("latency", latency), logging.F("err", err))...,
+		)
+		return
+	}
+
+	fields = append(fields, logging.F("status", resp.StatusCode))
+	for _, h := range []struct{ key, header string }{
+		{"ratelimit_remaining", "X-Ratelimit-Remaining"},
+		{"ratelimit_used", "X-Ratelimit-Used"},
+		{"ratelimit_reset", "X-Ratelimit-Reset"},
+	} {
+		if v := resp.Header.Get(h.header); v != "" {
+			fields = append(fields, logging.F(h.key, v))
+		}
+	}
+	b.trace.Log(logging.Debug, "request", append(fields, logging.F("latency", latency))...)
+}
+
+// measureRequest reports a request the client made to its metrics, if it has
+// any.
+func (b *baseClient) measureRequest(
+	req *http.Request,
+	resp *http.Response,
+	latency time.Duration,
+) {
+	if b.metrics == nil {
+		return
+	}
+
+	endpoint := Endpoint(req)
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+		remaining, err := strconv.ParseFloat(resp.Header.Get("X-Ratelimit-Remaining"), 64)
+		if err == nil {
+			b.metrics.SetRateLimitRemaining(remaining)
+		}
+	}
+	b.metrics.CountRequest(endpoint, status)
+	b.metrics.ObserveLatency(endpoint, latency)
+}
+
 // newClient returns a new client using the given user to make requests.
 func newClient(c clientConfig) (client, error) {
 	if c.app.tokenURL == "" {
 		c.app.tokenURL = tokenURL
 	}
 
+	if c.app.revokeURL == "" {
+		c.app.revokeURL = revokeURL
+	}
+
 	if c.app.unauthenticated() {
-		return &baseClient{clientWithAgent(c.agent)}, nil
+		return &baseClient{
+			cli:     httpClient(c.client, c.transport, c.agent),
+			quota:   c.quota,
+			trace:   c.trace,
+			metrics: c.metrics,
+		}, nil
 	}
 
 	if err := c.app.validateAuth(); err != nil {
@@ -4,6 +4,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/logging"
 )
 
 func serverWhich(body []byte, code int) *httptest.Server {
@@ -70,3 +73,104 @@ func TestDo(t *testing.T) {
 		}
 	}
 }
+
+func TestDoTooLarge(t *testing.T) {
+	serv := serverWhich(make([]byte, maxResponseSize+1), http.StatusOK)
+	defer serv.Close()
+
+	req, err := http.NewRequest("GET", serv.URL, nil)
+	if err != nil {
+		t.Fatalf("failed to prepare request for test: %v", err)
+	}
+
+	r := &baseClient{cli: &http.Client{}}
+	if _, err := r.Do(req); err != ResponseTooLargeErr {
+		t.Errorf("got %v; wanted %v", err, ResponseTooLargeErr)
+	}
+}
+
+// traceLog records the entries logged to it.
+type traceLog struct {
+	msgs   []string
+	fields []map[string]interface{}
+}
+
+func (l *traceLog) Log(level logging.Level, msg string, fields ...logging.Field) {
+	l.msgs = append(l.msgs, msg)
+	m := make(map[string]interface{})
+	for _, f := range fields {
+		m[f.Key] = f.Value
+	}
+	l.fields = append(l.fields, m)
+}
+
+func TestDoTrace(t *testing.T) {
+	serv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Ratelimit-Remaining", "598.0")
+				w.Header().Set("X-Ratelimit-Used", "2")
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+		),
+	)
+	defer serv.Close()
+
+	req, err := http.NewRequest("GET", serv.URL+"/r/golang/new", nil)
+	if err != nil {
+		t.Fatalf("failed to prepare request for test: %v", err)
+	}
+
+	l := &traceLog{}
+	r := &baseClient{cli: &http.Client{}, trace: l}
+	if _, err := r.Do(req); err != BusyErr {
+		t.Fatalf("got %v; wanted %v", err, BusyErr)
+	}
+
+	if len(l.msgs) != 1 {
+		t.Fatalf("got %d entries; wanted 1", len(l.msgs))
+	}
+	f := l.fields[0]
+	if f["method"] != "GET" ||
+		f["path"] != "/r/golang/new" ||
+		f["status"] != http.StatusServiceUnavailable ||
+		f["ratelimit_remaining"] != "598.0" ||
+		f["ratelimit_used"] != "2" {
+		t.Errorf("got fields %v", f)
+	}
+	if _, ok := f["ratelimit_reset"]; ok {
+		t.Errorf("traced a rate limit header the response did not have")
+	}
+	if _, ok := f["latency"].(time.Duration); !ok {
+		t.Errorf("got latency %v; wanted a duration", f["latency"])
+	}
+}
+
+// recordingDoer answers every request with an empty listing and records it.
+type recordingDoer struct {
+	paths []string
+}
+
+func (r *recordingDoer) Do(req *http.Request) ([]byte, error) {
+	r.paths = append(r.paths, req.URL.Path)
+	return []byte(`{"kind": "Listing", "data": {"children": []}}`), nil
+}
+
+func TestWrapClient(t *testing.T) {
+	d := &recordingDoer{}
+	s, err := NewScriptFromConfig(ScriptConfig{
+		Agent:      "graw test",
+		WrapClient: func(Doer) Doer { return d },
+	})
+	if err != nil {
+		t.Fatalf("failed to make script: %v", err)
+	}
+
+	if _, err := s.Listing("/r/golang", ""); err != nil {
+		t.Fatalf("failed to read listing: %v", err)
+	}
+
+	if len(d.paths) != 1 || d.paths[0] != "/r/golang.json" {
+		t.Errorf("got requests %v; wanted one through the wrapped client", d.paths)
+	}
+}
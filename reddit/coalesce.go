@@ -0,0 +1,68 @@
+package reddit
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// flights coalesces identical reads made at once, so streams and enrichers
+// reading the same listing or thread together spend one request of the rate
+// limit between them, not one each.
+type flights struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+// flight is a read in flight, which the readers who join it share.
+type flight struct {
+	done chan struct{}
+	body []byte
+	err  error
+	// joined counts the readers who joined the read, besides the one who
+	// made it.
+	joined int
+}
+
+func newFlights() *flights {
+	return &flights{calls: make(map[string]*flight)}
+}
+
+// do returns the body fetch reads for the key, joining the read of the key in
+// flight if there is one instead of fetching it again. Readers who join a read
+// stop waiting for it once ctx is done. If the read fails because the context
+// of the reader who made it is done, those who joined it read again.
+func (f *flights) do(
+	ctx context.Context,
+	key string,
+	fetch func() ([]byte, error),
+) ([]byte, error) {
+	f.mu.Lock()
+	if c, ok := f.calls[key]; ok {
+		c.joined++
+		f.mu.Unlock()
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if errors.Is(c.err, context.Canceled) ||
+			errors.Is(c.err, context.DeadlineExceeded) {
+			return f.do(ctx, key, fetch)
+		}
+		return c.body, c.err
+	}
+
+	c := &flight{done: make(chan struct{})}
+	f.calls[key] = c
+	f.mu.Unlock()
+
+	c.body, c.err = fetch()
+
+	f.mu.Lock()
+	delete(f.calls, key)
+	f.mu.Unlock()
+	close(c.done)
+	return c.body, c.err
+}
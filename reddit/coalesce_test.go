@@ -0,0 +1,95 @@
+package reddit
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// waitForJoin waits for a reader to join the read of the key in flight.
+func waitForJoin(f *flights, key string) {
+	for {
+		f.mu.Lock()
+		joined := f.calls[key] != nil && f.calls[key].joined > 0
+		f.mu.Unlock()
+		if joined {
+			return
+		}
+		runtime.Gosched()
+	}
+}
+
+func TestFlights(t *testing.T) {
+	f := newFlights()
+	release := make(chan bool)
+	fetches := 0
+	fetch := func() ([]byte, error) {
+		fetches++
+		<-release
+		return []byte("listing"), nil
+	}
+
+	var wg sync.WaitGroup
+	bodies := make(chan string, 2)
+	started := make(chan bool)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		body, _ := f.do(context.Background(), "/r/golang/new", func() ([]byte, error) {
+			close(started)
+			return fetch()
+		})
+		bodies <- string(body)
+	}()
+
+	<-started
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		body, _ := f.do(context.Background(), "/r/golang/new", fetch)
+		bodies <- string(body)
+	}()
+
+	waitForJoin(f, "/r/golang/new")
+	close(release)
+	wg.Wait()
+
+	if fetches != 1 {
+		t.Errorf("fetched %d times; wanted once", fetches)
+	}
+	for i := 0; i < 2; i++ {
+		if body := <-bodies; body != "listing" {
+			t.Errorf("got body %q", body)
+		}
+	}
+}
+
+func TestFlightsCancelledLeader(t *testing.T) {
+	f := newFlights()
+	failing := make(chan bool)
+	fail := make(chan bool)
+	go f.do(context.Background(), "/r/golang/new", func() ([]byte, error) {
+		close(failing)
+		<-fail
+		return nil, context.Canceled
+	})
+	<-failing
+
+	result := make(chan string)
+	go func() {
+		body, err := f.do(context.Background(), "/r/golang/new", func() ([]byte, error) {
+			return []byte("listing"), nil
+		})
+		if err != nil {
+			t.Errorf("error reading: %v", err)
+		}
+		result <- string(body)
+	}()
+
+	waitForJoin(f, "/r/golang/new")
+	close(fail)
+	if body := <-result; body != "listing" {
+		t.Errorf("got body %q; wanted to read again after the leader was cancelled", body)
+	}
+}
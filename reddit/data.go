@@ -11,18 +11,32 @@ type Comment struct {
 
 	CreatedUTC uint64 `mapstructure:"created_utc"`
 	Deleted    bool   `mapstructure:"deleted"`
+	// EditedUTC is set by the parser to when the element was last edited,
+	// or zero if it never was.
+	EditedUTC uint64 `mapstructure:"-"`
 
 	Ups   int32 `mapstructure:"ups"`
 	Downs int32 `mapstructure:"downs"`
 	Likes bool  `mapstructure:"likes"`
+	Score int32 `mapstructure:"score"`
+	// ScoreHidden is set while the subreddit hides the scores of new
+	// comments, and in contest mode threads. Reddit reports a score of 1
+	// for such comments. See VisibleScore.
+	ScoreHidden bool `mapstructure:"score_hidden"`
+	// Voted is set by the parser when the account has voted on the
+	// comment; Likes is only meaningful if it is. See Vote.
+	Voted bool
 
 	Author              string `mapstructure:"author"`
 	AuthorFlairCSSClass string `mapstructure:"author_flair_css_class"`
 	AuthorFlairText     string `mapstructure:"author_flair_text"`
 
 	LinkAuthor string `mapstructure:"link_author"`
-	LinkURL    string `mapstructure:"link_url"`
-	LinkTitle  string `mapstructure:"link_title"`
+	// IsSubmitter is set when the comment's author is the author of the
+	// post it is on.
+	IsSubmitter bool   `mapstructure:"is_submitter"`
+	LinkURL     string `mapstructure:"link_url"`
+	LinkTitle   string `mapstructure:"link_title"`
 
 	Subreddit   string `mapstructure:"subreddit"`
 	SubredditID string `mapstructure:"subreddit_id"`
@@ -30,12 +44,44 @@ type Comment struct {
 	Body     string `mapstructure:"body"`
 	BodyHTML string `mapstructure:"body_html"`
 
+	// LinkID is the fullname of the post the comment is on.
+	LinkID   string     `mapstructure:"link_id"`
 	ParentID string     `mapstructure:"parent_id"`
 	Replies  []*Comment `mapstructure:"reply_tree"`
 	More     *More
+	// Depth is how deep the comment is in its thread's tree, from zero
+	// for top level comments. Reddit only reports it in comment trees.
+	Depth int `mapstructure:"depth"`
+
+	// Archived is set once the comment's thread is too old to vote or
+	// comment in.
+	Archived bool `mapstructure:"archived"`
 
 	Gilded        int32  `mapstructure:"gilded"`
 	Distinguished string `mapstructure:"distinguished"`
+	// Awards are the awards the comment received, and TotalAwards how
+	// many there are in all.
+	Awards      []Award `mapstructure:"all_awardings"`
+	TotalAwards int32   `mapstructure:"total_awards_received"`
+
+	// NumReports, UserReports, and ModReports are only visible to
+	// moderators of the subreddit. See ParseReports and Reports.
+	NumReports  int32           `mapstructure:"num_reports"`
+	UserReports [][]interface{} `mapstructure:"user_reports"`
+	ModReports  [][]interface{} `mapstructure:"mod_reports"`
+	// ReportsVisible is set by the parser when NumReports is visible.
+	ReportsVisible bool
+
+	// BannedBy, BanNote, and Removed are only visible to moderators of
+	// the subreddit. BannedBy is the name of the moderator who removed
+	// the comment, or true if Reddit's spam filter did. See Removal.
+	BannedBy interface{} `mapstructure:"banned_by"`
+	BanNote  string      `mapstructure:"ban_note"`
+	Removed  bool        `mapstructure:"removed"`
+
+	// Own is set by graw when the comment was made by the bot's own
+	// account.
+	Own bool
 }
 
 // IsTopLevel is true when the comment is a top level comment.
@@ -74,6 +120,45 @@ type Media struct {
 	} `mapstructure:"reddit_video"`
 }
 
+// Gallery lists the images of a gallery post, in order.
+type Gallery struct {
+	Items []struct {
+		MediaID     string `mapstructure:"media_id"`
+		Caption     string `mapstructure:"caption"`
+		OutboundURL string `mapstructure:"outbound_url"`
+	} `mapstructure:"items"`
+}
+
+// MediaMetadata describes an image or video embedded in a post, such as an
+// image of a gallery.
+type MediaMetadata struct {
+	ID     string `mapstructure:"id"`
+	Status string `mapstructure:"status"`
+	// Kind is e.g. "Image" or "AnimatedImage".
+	Kind     string `mapstructure:"e"`
+	MimeType string `mapstructure:"m"`
+	// Source is the original, largest rendition of the media.
+	Source struct {
+		URL    string `mapstructure:"u"`
+		GIF    string `mapstructure:"gif"`
+		MP4    string `mapstructure:"mp4"`
+		Width  int    `mapstructure:"x"`
+		Height int    `mapstructure:"y"`
+	} `mapstructure:"s"`
+}
+
+// Award is a kind of award a post or comment received, such as Gold.
+type Award struct {
+	ID          string `mapstructure:"id"`
+	Name        string `mapstructure:"name"`
+	Description string `mapstructure:"description"`
+	// Count is how many of the award the element received.
+	Count int32 `mapstructure:"count"`
+	// CoinPrice is what one of the award cost in Reddit coins.
+	CoinPrice int32  `mapstructure:"coin_price"`
+	IconURL   string `mapstructure:"icon_url"`
+}
+
 // Post represents posts on Reddit (Reddit type t3_).
 // https://github.com/reddit/reddit/wiki/JSON#link-implements-votable--created
 type Post struct {
@@ -83,10 +168,16 @@ type Post struct {
 
 	CreatedUTC uint64 `mapstructure:"created_utc"`
 	Deleted    bool   `mapstructure:"deleted"`
+	// EditedUTC is set by the parser to when the element was last edited,
+	// or zero if it never was.
+	EditedUTC uint64 `mapstructure:"-"`
 
 	Ups   int32 `mapstructure:"ups"`
 	Downs int32 `mapstructure:"downs"`
 	Likes bool  `mapstructure:"likes"`
+	// Voted is set by the parser when the account has voted on the post;
+	// Likes is only meaningful if it is. See Vote.
+	Voted bool
 
 	Author              string `mapstructure:"author"`
 	AuthorFlairCSSClass string `mapstructure:"author_flair_css_class"`
@@ -115,14 +206,62 @@ type Post struct {
 	NumComments int32  `mapstructure:"num_comments"`
 	Locked      bool   `mapstructure:"locked"`
 	Thumbnail   string `mapstructure:"thumbnail"`
+	// Archived is set once the post is too old to vote or comment on.
+	Archived bool `mapstructure:"archived"`
+
+	// UpvoteRatio is the fraction of the post's votes which are upvotes.
+	// NumCrossposts is how many times it was crossposted. ViewCount is
+	// how many times it was viewed; Reddit only reports it to the post's
+	// author and the subreddit's moderators, and not for every post. See
+	// Insights.
+	UpvoteRatio   float64 `mapstructure:"upvote_ratio"`
+	NumCrossposts int32   `mapstructure:"num_crossposts"`
+	ViewCount     *int64  `mapstructure:"view_count"`
 
 	Gilded        int32  `mapstructure:"gilded"`
 	Distinguished string `mapstructure:"distinguished"`
 	Stickied      bool   `mapstructure:"stickied"`
+	// Awards are the awards the post received, and TotalAwards how many
+	// there are in all.
+	Awards      []Award `mapstructure:"all_awardings"`
+	TotalAwards int32   `mapstructure:"total_awards_received"`
+
+	// HideScore is set while the subreddit hides the post's score. See
+	// VisibleScore.
+	HideScore bool `mapstructure:"hide_score"`
+	// ContestMode is set on threads whose comments are shown in random
+	// order with their scores hidden.
+	ContestMode bool `mapstructure:"contest_mode"`
+
+	// NumReports, UserReports, and ModReports are only visible to
+	// moderators of the subreddit. See ParseReports and Reports.
+	NumReports  int32           `mapstructure:"num_reports"`
+	UserReports [][]interface{} `mapstructure:"user_reports"`
+	ModReports  [][]interface{} `mapstructure:"mod_reports"`
+	// ReportsVisible is set by the parser when NumReports is visible.
+	ReportsVisible bool
+
+	// RemovedByCategory is who removed the post, such as "moderator",
+	// "automod_filtered", "reddit", or "deleted" by its author. BannedBy,
+	// BanNote, and Removed are only visible to moderators of the
+	// subreddit. BannedBy is the name of the moderator who removed the
+	// post, or true if Reddit's spam filter did. See Removal.
+	RemovedByCategory string      `mapstructure:"removed_by_category"`
+	BannedBy          interface{} `mapstructure:"banned_by"`
+	BanNote           string      `mapstructure:"ban_note"`
+	Removed           bool        `mapstructure:"removed"`
 
 	IsRedditMediaDomain bool  `mapstructure:"is_reddit_media_domain"`
 	Media               Media `mapstructure:"media"`
 	SecureMedia         Media `mapstructure:"secure_media"`
+	// IsGallery is set on gallery posts, whose images are listed in
+	// Gallery and described in MediaMetadata by their media ids.
+	IsGallery     bool                     `mapstructure:"is_gallery"`
+	Gallery       Gallery                  `mapstructure:"gallery_data"`
+	MediaMetadata map[string]MediaMetadata `mapstructure:"media_metadata"`
+
+	// Own is set by graw when the post was made by the bot's own account.
+	Own bool
 }
 
 // Message represents messages on Reddit (Reddit type t4_).
@@ -148,6 +287,14 @@ type Message struct {
 
 	Subreddit  string `mapstructure:"subreddit"`
 	WasComment bool   `mapstructure:"was_comment"`
+
+	// Replies are the replies to the message, set only for messages
+	// fetched as a thread.
+	Replies []*Message `mapstructure:"reply_tree"`
+
+	// Own is set by graw when the message was sent by the bot's own
+	// account.
+	Own bool
 }
 
 // More represents a more comments list on Reddit
@@ -163,6 +310,30 @@ type More struct {
 	Children []string `mapstructure:"children"`
 }
 
+// Redditor represents a Reddit account (Reddit type t2_).
+// https://github.com/reddit-archive/reddit/wiki/JSON#account-implements-created
+type Redditor struct {
+	ID   string `mapstructure:"id"`
+	Name string `mapstructure:"name"`
+
+	CreatedUTC uint64 `mapstructure:"created_utc"`
+
+	LinkKarma    int32 `mapstructure:"link_karma"`
+	CommentKarma int32 `mapstructure:"comment_karma"`
+
+	HasVerifiedEmail bool `mapstructure:"has_verified_email"`
+	IsEmployee       bool `mapstructure:"is_employee"`
+	IsGold           bool `mapstructure:"is_gold"`
+	IsMod            bool `mapstructure:"is_mod"`
+	IsSuspended      bool `mapstructure:"is_suspended"`
+
+	// Subreddit is the account's profile, through which other accounts
+	// follow it.
+	Subreddit struct {
+		Subscribers int32 `mapstructure:"subscribers"`
+	} `mapstructure:"subreddit"`
+}
+
 // Harvest is a set of all possible elements that Reddit could return in a
 // listing.
 //
@@ -177,6 +348,19 @@ type Harvest struct {
 	Mores    []*More
 }
 
+// ListingInfo is the metadata of a page of a listing.
+type ListingInfo struct {
+	// After and Before are the fullnames to page to older and younger
+	// elements from. They are empty at either end of the listing.
+	After  string `mapstructure:"after"`
+	Before string `mapstructure:"before"`
+	// Dist is how many elements are in the page.
+	Dist    int    `mapstructure:"dist"`
+	Modhash string `mapstructure:"modhash"`
+	// GeoFilter is the region the listing was filtered to, if any.
+	GeoFilter string `mapstructure:"geo_filter"`
+}
+
 type Submission struct {
 	ID   string `mapstructure:"id"`
 	Name string `mapstructure:"name"`
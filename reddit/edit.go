@@ -0,0 +1,21 @@
+package reddit
+
+func (a *account) EditText(name, text string) error {
+	if err := checkName(name, "post or comment", postPrefix, commentPrefix); err != nil {
+		return err
+	}
+	return a.r.sow(
+		"/api/editusertext", map[string]string{
+			"api_type": "json",
+			"thing_id": name,
+			"text":     text,
+		},
+	)
+}
+
+func (a *account) Delete(name string) error {
+	if err := checkName(name, "post or comment", postPrefix, commentPrefix); err != nil {
+		return err
+	}
+	return a.r.sow("/api/del", map[string]string{"id": name})
+}
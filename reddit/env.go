@@ -0,0 +1,147 @@
+package reddit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aldarisbm/graw/logging"
+)
+
+// The environment variables a bot is configured from by NewBotFromEnv.
+const (
+	// EnvUserAgent holds the user agent; it is required.
+	EnvUserAgent = "GRAW_USER_AGENT"
+	// EnvClientID and EnvClientSecret hold the app's id and secret.
+	EnvClientID     = "GRAW_CLIENT_ID"
+	EnvClientSecret = "GRAW_CLIENT_SECRET"
+	// EnvUsername and EnvPassword hold the bot account's credentials.
+	EnvUsername = "GRAW_USERNAME"
+	EnvPassword = "GRAW_PASSWORD"
+	// EnvRefreshToken holds a refresh token to authorize with instead of
+	// the password.
+	EnvRefreshToken = "GRAW_REFRESH_TOKEN"
+	// EnvRate holds the minimum time between requests, as a duration such
+	// as "2s".
+	EnvRate = "GRAW_RATE"
+	// EnvTokenRefreshMargin holds the BotConfig's TokenRefreshMargin, as a
+	// duration such as "10m".
+	EnvTokenRefreshMargin = "GRAW_TOKEN_REFRESH_MARGIN"
+	// EnvTrace, if true, traces every request the bot makes to stderr, as
+	// a boolean such as "1" or "true". See BotConfig's Trace.
+	EnvTrace = "GRAW_TRACE"
+)
+
+// envFileSuffix is appended to a variable's name to give the path of a file
+// holding its value instead, such as a mounted container secret.
+const envFileSuffix = "_FILE"
+
+var noUserAgentErr = fmt.Errorf("%s must be set", EnvUserAgent)
+
+// BotConfigFromEnv returns a BotConfig read from the Env environment variables,
+// so a containerized bot needs no agent file. Any variable can be given as the
+// path to a file holding its value instead, by appending _FILE to its name,
+// e.g. GRAW_CLIENT_SECRET_FILE=/run/secrets/client_secret.
+func BotConfigFromEnv() (BotConfig, error) {
+	return botConfigFromEnv(os.LookupEnv)
+}
+
+// NewBotFromEnv calls NewBot with the config returned by BotConfigFromEnv.
+func NewBotFromEnv() (Bot, error) {
+	c, err := BotConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBot(c)
+}
+
+func botConfigFromEnv(lookup func(string) (string, bool)) (BotConfig, error) {
+	env := func(name string) (string, error) {
+		return getenv(lookup, name)
+	}
+
+	var c BotConfig
+	var err error
+	for _, field := range []struct {
+		name  string
+		value *string
+	}{
+		{EnvUserAgent, &c.Agent},
+		{EnvClientID, &c.App.ID},
+		{EnvClientSecret, &c.App.Secret},
+		{EnvUsername, &c.App.Username},
+		{EnvPassword, &c.App.Password},
+		{EnvRefreshToken, &c.App.RefreshToken},
+	} {
+		if *field.value, err = env(field.name); err != nil {
+			return BotConfig{}, err
+		}
+	}
+
+	if c.Agent == "" {
+		return BotConfig{}, noUserAgentErr
+	}
+
+	for _, field := range []struct {
+		name  string
+		value *time.Duration
+	}{
+		{EnvRate, &c.Rate},
+		{EnvTokenRefreshMargin, &c.TokenRefreshMargin},
+	} {
+		value, err := env(field.name)
+		if err != nil {
+			return BotConfig{}, err
+		}
+
+		if value == "" {
+			continue
+		}
+
+		if *field.value, err = time.ParseDuration(value); err != nil {
+			return BotConfig{}, fmt.Errorf("%s: %v", field.name, err)
+		}
+	}
+
+	trace, err := env(EnvTrace)
+	if err != nil {
+		return BotConfig{}, err
+	}
+	if trace != "" {
+		on, err := strconv.ParseBool(trace)
+		if err != nil {
+			return BotConfig{}, fmt.Errorf("%s: %v", EnvTrace, err)
+		}
+		if on {
+			c.Trace = logging.Std(log.New(os.Stderr, "", log.LstdFlags), logging.Debug)
+		}
+	}
+
+	return c, nil
+}
+
+// getenv returns the value of the environment variable name found with lookup,
+// or the trimmed contents of the file named by the variable name+"_FILE" if
+// only that is set. It returns "" if neither is set.
+func getenv(lookup func(string) (string, bool), name string) (string, error) {
+	if value, ok := lookup(name); ok {
+		return value, nil
+	}
+
+	path, ok := lookup(name + envFileSuffix)
+	if !ok {
+		return "", nil
+	}
+
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("%s%s: %v", name, envFileSuffix, err)
+	}
+
+	return strings.TrimSpace(string(buf)), nil
+}
@@ -0,0 +1,62 @@
+package reddit
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func lookupIn(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+func TestBotConfigFromEnv(t *testing.T) {
+	secret, err := ioutil.TempFile("", "secret")
+	if err != nil {
+		t.Fatalf("failed to make secret file: %v", err)
+	}
+	defer os.Remove(secret.Name())
+	if _, err := secret.WriteString("hunter2\n"); err != nil {
+		t.Fatalf("failed to write secret file: %v", err)
+	}
+
+	c, err := botConfigFromEnv(lookupIn(map[string]string{
+		EnvUserAgent:              "agent",
+		EnvClientID:               "id",
+		EnvClientSecret + "_FILE": secret.Name(),
+		EnvRefreshToken:           "token",
+		EnvRate:                   "2s",
+	}))
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+
+	if c.Agent != "agent" || c.App.ID != "id" || c.App.Secret != "hunter2" ||
+		c.App.RefreshToken != "token" || c.Rate != 2*time.Second ||
+		c.TokenRefreshMargin != 0 || c.Trace != nil {
+		t.Errorf("got %+v", c)
+	}
+
+	c, err = botConfigFromEnv(lookupIn(map[string]string{
+		EnvUserAgent: "agent",
+		EnvTrace:     "true",
+	}))
+	if err != nil || c.Trace == nil {
+		t.Errorf("wanted a trace with %s set; got %v", EnvTrace, err)
+	}
+
+	if _, err := botConfigFromEnv(lookupIn(nil)); err != noUserAgentErr {
+		t.Errorf("wanted noUserAgentErr without a user agent; got %v", err)
+	}
+
+	if _, err := botConfigFromEnv(lookupIn(map[string]string{
+		EnvUserAgent: "agent",
+		EnvRate:      "soon",
+	})); err == nil {
+		t.Errorf("wanted an error for a malformed rate")
+	}
+}
@@ -1,7 +1,9 @@
 package reddit
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
 )
 
 var (
@@ -11,4 +13,138 @@ var (
 	GatewayErr            = fmt.Errorf("502 bad gateway code from Reddit")
 	GatewayTimeoutErr     = fmt.Errorf("504 gateway timeout from Reddit")
 	ThreadDoesNotExistErr = fmt.Errorf("The requested post does not exist.")
+	ResponseTooLargeErr   = fmt.Errorf("response from Reddit is too large")
+	ConflictErr           = fmt.Errorf("the edit conflicts with a newer revision")
+	NotFoundErr           = fmt.Errorf("the requested thing does not exist")
+	LockedErr             = fmt.Errorf("the thread is locked or archived")
 )
+
+// StatusError is returned for responses with a status code which has no error
+// of its own above.
+type StatusError struct {
+	Code int
+}
+
+func (s *StatusError) Error() string {
+	return fmt.Sprintf("bad response code: %d", s.Code)
+}
+
+// APIError is an error Reddit reported in the body of a response, such as
+// ["RATELIMIT", "you are doing that too much", "ratelimit"]. Reddit sometimes
+// reports several; the first is returned. Check for the errors above with
+// errors.Is, e.g. errors.Is(err, RateLimitErr), or switch on the Code.
+type APIError struct {
+	// Code is Reddit's name for the error, e.g. "SUBREDDIT_NOEXIST".
+	Code string
+	// Message is the error as Reddit would show it to a user.
+	Message string
+	// Field is the field of the request the error is about, if any.
+	Field string
+}
+
+func (a *APIError) Error() string {
+	if a.Field == "" {
+		return fmt.Sprintf("%s: %s", a.Code, a.Message)
+	}
+	return fmt.Sprintf("%s: %s (%s)", a.Code, a.Message, a.Field)
+}
+
+// apiErrorKinds are the errors above which Reddit's error codes are instances
+// of.
+var apiErrorKinds = map[string]error{
+	"RATELIMIT":             RateLimitErr,
+	"SUBREDDIT_NOEXIST":     NotFoundErr,
+	"USER_DOESNT_EXIST":     NotFoundErr,
+	"NO_THING_ID":           NotFoundErr,
+	"NO_USER":               NotFoundErr,
+	"DELETED_LINK":          NotFoundErr,
+	"DELETED_COMMENT":       NotFoundErr,
+	"THREAD_LOCKED":         LockedErr,
+	"TOO_OLD":               LockedErr,
+	"USER_REQUIRED":         PermissionDeniedErr,
+	"SUBREDDIT_NOTALLOWED":  PermissionDeniedErr,
+	"NOT_AUTHOR":            PermissionDeniedErr,
+	"BANNED_FROM_SUBREDDIT": PermissionDeniedErr,
+	"EDIT_CONFLICT":         ConflictErr,
+}
+
+// Is reports whether the error is an instance of target, one of the errors
+// above.
+func (a *APIError) Is(target error) bool {
+	kind, ok := apiErrorKinds[a.Code]
+	return ok && kind == target
+}
+
+// apiError returns an *APIError for the first error in errs, as Reddit lists
+// them in a response, or nil if there are none.
+func apiError(errs []interface{}) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	fields, ok := errs[0].([]interface{})
+	if !ok {
+		return fmt.Errorf("API errors were returned: %v", errs)
+	}
+
+	var parts [3]string
+	for i := 0; i < len(fields) && i < len(parts); i++ {
+		parts[i], _ = fields[i].(string)
+	}
+	return &APIError{Code: parts[0], Message: parts[1], Field: parts[2]}
+}
+
+// jqueryError matches the selectors of the error spans in responses in Reddit's
+// jquery format, e.g. ".error.RATELIMIT.field-ratelimit".
+var jqueryError = regexp.MustCompile(`^\.error\.([A-Z_]+)(?:\.field-(\w+))?$`)
+
+// sowError returns the error Reddit reported in the response to a POST, if it
+// reported one. Responses come in the api_type=json envelope or Reddit's
+// jquery format; others are assumed to be successes.
+func sowError(blob []byte) error {
+	var resp struct {
+		JSON struct {
+			Errors []interface{} `json:"errors"`
+		} `json:"json"`
+		JQuery  [][]interface{} `json:"jquery"`
+		Success *bool           `json:"success"`
+	}
+	if err := json.Unmarshal(blob, &resp); err != nil {
+		return nil
+	}
+
+	if err := apiError(resp.JSON.Errors); err != nil {
+		return err
+	}
+
+	if resp.Success == nil || *resp.Success {
+		return nil
+	}
+
+	// The error span's selector is followed by a call setting its text to
+	// the message.
+	var found *APIError
+	for _, call := range resp.JQuery {
+		if len(call) < 4 {
+			continue
+		}
+		args, _ := call[3].([]interface{})
+		if len(args) != 1 {
+			continue
+		}
+		arg, _ := args[0].(string)
+
+		if found != nil && call[2] == "call" {
+			found.Message = arg
+			return found
+		}
+		if m := jqueryError.FindStringSubmatch(arg); m != nil {
+			found = &APIError{Code: m[1], Field: m[2]}
+		}
+	}
+
+	if found != nil {
+		return found
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package reddit
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	_, err := unwrapJSON([]byte(
+		`{"json": {"errors": [["THREAD_LOCKED", "that thread is locked", "parent"]]}}`,
+	))
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("got %v; wanted an *APIError", err)
+	}
+	if *apiErr != (APIError{Code: "THREAD_LOCKED", Message: "that thread is locked", Field: "parent"}) {
+		t.Errorf("got %+v", apiErr)
+	}
+	if !errors.Is(err, LockedErr) || errors.Is(err, RateLimitErr) {
+		t.Errorf("%v is not only a LockedErr", err)
+	}
+}
+
+func TestSowError(t *testing.T) {
+	for i, test := range []struct {
+		resp string
+		err  *APIError
+	}{
+		{``, nil},
+		{`{}`, nil},
+		{`{"json": {"errors": []}}`, nil},
+		{
+			`{"json": {"errors": [["RATELIMIT", "you are doing that too much", "ratelimit"]]}}`,
+			&APIError{"RATELIMIT", "you are doing that too much", "ratelimit"},
+		},
+		{`{"jquery": [[0, 1, "call", ["body"]]], "success": true}`, nil},
+		{
+			`{"jquery": [
+				[0, 1, "call", ["body"]],
+				[1, 2, "attr", "find"],
+				[2, 3, "call", [".error.SUBREDDIT_NOEXIST.field-sr"]],
+				[3, 4, "attr", "show"],
+				[4, 5, "call", []],
+				[5, 6, "attr", "text"],
+				[6, 7, "call", ["that subreddit doesn't exist"]]
+			], "success": false}`,
+			&APIError{"SUBREDDIT_NOEXIST", "that subreddit doesn't exist", "sr"},
+		},
+	} {
+		err := sowError([]byte(test.resp))
+		if test.err == nil {
+			if err != nil {
+				t.Errorf("%d: got %v; wanted no error", i, err)
+			}
+			continue
+		}
+
+		apiErr, ok := err.(*APIError)
+		if !ok || *apiErr != *test.err {
+			t.Errorf("%d: got %v; wanted %+v", i, err, test.err)
+		}
+	}
+}
+
+func TestStatusErrors(t *testing.T) {
+	for code, wanted := range map[int]error{
+		http.StatusNotFound:  NotFoundErr,
+		http.StatusForbidden: PermissionDeniedErr,
+	} {
+		s := serverWhich(nil, code)
+		_, err := (&baseClient{cli: s.Client()}).Do(mustRequest(s.URL, t))
+		s.Close()
+		if err != wanted {
+			t.Errorf("%d: got %v; wanted %v", code, err, wanted)
+		}
+	}
+
+	s := serverWhich(nil, http.StatusTeapot)
+	defer s.Close()
+	_, err := (&baseClient{cli: s.Client()}).Do(mustRequest(s.URL, t))
+	if statusErr, ok := err.(*StatusError); !ok || statusErr.Code != http.StatusTeapot {
+		t.Errorf("got %v; wanted a StatusError for %d", err, http.StatusTeapot)
+	}
+}
+
+func mustRequest(url string, t *testing.T) *http.Request {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
@@ -0,0 +1,242 @@
+package reddit
+
+import (
+	"strings"
+)
+
+// moreBatch is the most children /api/morechildren expands in one request.
+const moreBatch = 100
+
+// expansion replaces the "more comments" stubs in a thread's comment tree with
+// the comments they stand for.
+type expansion struct {
+	r      reaper
+	thread *Post
+	// nodes are the thread's comments by fullname.
+	nodes map[string]*Comment
+	// max caps the requests made, if it is positive.
+	max      int
+	requests int
+}
+
+func newExpansion(r reaper, thread *Post, max int) *expansion {
+	e := &expansion{
+		r:      r,
+		thread: thread,
+		nodes:  make(map[string]*Comment),
+		max:    max,
+	}
+	e.index(thread.Replies)
+	return e
+}
+
+// index records comments and their replies as nodes of the thread.
+func (e *expansion) index(comments []*Comment) {
+	for _, c := range comments {
+		e.nodes[c.Name] = c
+		e.index(c.Replies)
+	}
+}
+
+// stubs detaches the stubs of the thread and its comments, returning them.
+func (e *expansion) stubs() []*More {
+	var stubs []*More
+	var walk func(comments []*Comment)
+	walk = func(comments []*Comment) {
+		for _, c := range comments {
+			if c.More != nil {
+				stubs = append(stubs, c.More)
+				c.More = nil
+			}
+			walk(c.Replies)
+		}
+	}
+	walk(e.thread.Replies)
+
+	if e.thread.More != nil {
+		stubs = append(stubs, e.thread.More)
+		e.thread.More = nil
+	}
+	return stubs
+}
+
+func (e *expansion) spent() bool {
+	return e.max > 0 && e.requests >= e.max
+}
+
+// run expands the stubs, and the stubs they turn out to hold, until they are
+// all expanded or the requests run out. Stubs left unexpanded are put back in
+// the tree. It returns the comments added in place of the first stub.
+func (e *expansion) run(pending []*More) ([]*Comment, error) {
+	var first []*Comment
+	for i := 0; len(pending) > 0; i++ {
+		more := pending[0]
+		if e.spent() {
+			break
+		}
+		pending = pending[1:]
+
+		added, mores, err := e.expand(more)
+		if err != nil {
+			e.restore(append([]*More{more}, pending...))
+			return nil, err
+		}
+
+		if i == 0 {
+			first = added
+		}
+		pending = append(pending, mores...)
+	}
+
+	e.restore(pending)
+	return first, nil
+}
+
+// expand expands one stub, returning the comments it added under the stub's
+// parent and the stubs found among them.
+func (e *expansion) expand(more *More) ([]*Comment, []*More, error) {
+	if len(more.Children) == 0 {
+		return e.continueThread(more)
+	}
+
+	var added []*Comment
+	var mores []*More
+	children := more.Children
+	for len(children) > 0 {
+		if e.spent() {
+			// Keep what is left for the caller to restore.
+			mores = append(mores, &More{
+				ParentID: more.ParentID,
+				Depth:    more.Depth,
+				Children: children,
+				Count:    len(children),
+			})
+			break
+		}
+
+		batch := children
+		if len(batch) > moreBatch {
+			batch = batch[:moreBatch]
+		}
+		children = children[len(batch):]
+
+		h, err := e.r.reap(
+			"/api/morechildren",
+			map[string]string{
+				"api_type": "json",
+				"raw_json": "1",
+				"link_id":  e.thread.Name,
+				"children": strings.Join(batch, ","),
+			},
+		)
+		e.requests++
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, c := range h.Comments {
+			if _, seen := e.nodes[c.Name]; seen {
+				continue
+			}
+			e.nodes[c.Name] = c
+			e.graft(c)
+			if c.ParentID == more.ParentID {
+				added = append(added, c)
+			}
+		}
+		mores = append(mores, h.Mores...)
+	}
+
+	return added, mores, nil
+}
+
+// continueThread expands a stub with no children, which deep threads end in,
+// by reading the thread from the stub's parent comment.
+func (e *expansion) continueThread(more *More) ([]*Comment, []*More, error) {
+	parent, ok := e.nodes[more.ParentID]
+	if !ok || e.thread.ID == "" {
+		return nil, nil, nil
+	}
+
+	harvest, err := e.r.reap(
+		"/comments/"+e.thread.ID+"/_/"+strings.TrimPrefix(parent.Name, "t1_")+".json",
+		map[string]string{"raw_json": "1"},
+	)
+	e.requests++
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(harvest.Posts) != 1 || len(harvest.Posts[0].Replies) == 0 {
+		return nil, nil, nil
+	}
+
+	var added []*Comment
+	for _, c := range harvest.Posts[0].Replies[0].Replies {
+		if _, seen := e.nodes[c.Name]; seen {
+			continue
+		}
+		e.index([]*Comment{c})
+		parent.Replies = append(parent.Replies, c)
+		added = append(added, c)
+	}
+
+	sub := &expansion{thread: &Post{Replies: added}}
+	return added, sub.stubs(), nil
+}
+
+// graft attaches an expanded comment to its parent in the thread.
+func (e *expansion) graft(c *Comment) {
+	if parent, ok := e.nodes[c.ParentID]; ok {
+		parent.Replies = append(parent.Replies, c)
+	} else {
+		e.thread.Replies = append(e.thread.Replies, c)
+	}
+}
+
+// restore puts unexpanded stubs back where they were in the tree.
+func (e *expansion) restore(stubs []*More) {
+	for _, more := range stubs {
+		slot := &e.thread.More
+		if parent, ok := e.nodes[more.ParentID]; ok {
+			slot = &parent.More
+		}
+
+		if *slot == nil {
+			*slot = more
+			continue
+		}
+		(*slot).Children = append((*slot).Children, more.Children...)
+		(*slot).Count += more.Count
+	}
+}
+
+func (s *lurker) ExpandMore(thread *Post, more *More) ([]*Comment, error) {
+	if thread.More == more {
+		thread.More = nil
+	}
+	if parent, ok := findComment(thread.Replies, more.ParentID); ok && parent.More == more {
+		parent.More = nil
+	}
+
+	return newExpansion(s.r, thread, 0).run([]*More{more})
+}
+
+func (s *lurker) ExpandThread(thread *Post, maxRequests int) error {
+	e := newExpansion(s.r, thread, maxRequests)
+	_, err := e.run(e.stubs())
+	return err
+}
+
+// findComment finds a comment in a comment tree by its fullname.
+func findComment(comments []*Comment, name string) (*Comment, bool) {
+	for _, c := range comments {
+		if c.Name == name {
+			return c, true
+		}
+		if found, ok := findComment(c.Replies, name); ok {
+			return found, true
+		}
+	}
+	return nil, false
+}
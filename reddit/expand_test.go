@@ -0,0 +1,130 @@
+package reddit
+
+import (
+	"testing"
+)
+
+// moreReaper serves /api/morechildren and continued threads from canned
+// harvests.
+type moreReaper struct {
+	mockReaper
+	// more are the harvests of morechildren requests by their children.
+	more map[string]Harvest
+	// threads are the harvests of continued threads by path.
+	threads map[string]Harvest
+	paths   []string
+}
+
+func (m *moreReaper) reap(path string, values map[string]string) (Harvest, error) {
+	m.paths = append(m.paths, path)
+	if path == "/api/morechildren" {
+		return m.more[values["children"]], nil
+	}
+	return m.threads[path], nil
+}
+
+func testExpansionThread() (*Post, *moreReaper) {
+	thread := &Post{
+		ID:   "p",
+		Name: "t3_p",
+		Replies: []*Comment{{
+			Name:     "t1_a",
+			ParentID: "t3_p",
+			More:     &More{ParentID: "t1_a", Children: []string{"t1_b", "t1_c"}},
+		}},
+		More: &More{ParentID: "t3_p", Children: []string{"t1_e"}},
+	}
+
+	r := &moreReaper{
+		more: map[string]Harvest{
+			"t1_b,t1_c": {
+				Comments: []*Comment{
+					{Name: "t1_b", ParentID: "t1_a"},
+					{Name: "t1_c", ParentID: "t1_b"},
+				},
+				Mores: []*More{{ParentID: "t1_c"}},
+			},
+			"t1_e": {Comments: []*Comment{{Name: "t1_e", ParentID: "t3_p"}}},
+		},
+		threads: map[string]Harvest{
+			"/comments/p/_/c.json": {Posts: []*Post{{
+				Replies: []*Comment{{
+					Name: "t1_c",
+					Replies: []*Comment{{
+						Name:     "t1_d",
+						ParentID: "t1_c",
+					}},
+				}},
+			}}},
+		},
+	}
+	return thread, r
+}
+
+func TestExpandThread(t *testing.T) {
+	thread, r := testExpansionThread()
+	l := &lurker{r: r}
+	if err := l.ExpandThread(thread, 0); err != nil {
+		t.Fatalf("failed to expand thread: %v", err)
+	}
+
+	if len(thread.Replies) != 2 || thread.Replies[1].Name != "t1_e" {
+		t.Fatalf("top level stub was not expanded: %+v", thread.Replies)
+	}
+
+	a := thread.Replies[0]
+	if len(a.Replies) != 1 || len(a.Replies[0].Replies) != 1 {
+		t.Fatalf("expanded comments were not grafted under their parents")
+	}
+
+	c := a.Replies[0].Replies[0]
+	if len(c.Replies) != 1 || c.Replies[0].Name != "t1_d" {
+		t.Errorf("continued thread was not grafted: %+v", c.Replies)
+	}
+
+	if thread.More != nil || a.More != nil || c.More != nil {
+		t.Errorf("stubs were left in a fully expanded thread")
+	}
+}
+
+func TestExpandThreadLimit(t *testing.T) {
+	thread, r := testExpansionThread()
+	l := &lurker{r: r}
+	if err := l.ExpandThread(thread, 1); err != nil {
+		t.Fatalf("failed to expand thread: %v", err)
+	}
+
+	if len(r.paths) != 1 {
+		t.Errorf("made %d requests; wanted at most 1", len(r.paths))
+	}
+
+	if thread.More == nil || thread.More.Children[0] != "t1_e" {
+		t.Errorf("unexpanded stub was not put back: %+v", thread.More)
+	}
+
+	c := thread.Replies[0].Replies[0].Replies[0]
+	if c.More == nil {
+		t.Errorf("stub found while expanding was not put in the tree")
+	}
+}
+
+func TestExpandMore(t *testing.T) {
+	thread, r := testExpansionThread()
+	l := &lurker{r: r}
+	added, err := l.ExpandMore(thread, thread.Replies[0].More)
+	if err != nil {
+		t.Fatalf("failed to expand stub: %v", err)
+	}
+
+	if len(added) != 1 || added[0].Name != "t1_b" {
+		t.Fatalf("got %+v; wanted the comment which took the stub's place", added)
+	}
+
+	if thread.Replies[0].More != nil {
+		t.Errorf("expanded stub was left in the tree")
+	}
+
+	if thread.More == nil {
+		t.Errorf("another stub was expanded")
+	}
+}
@@ -0,0 +1,169 @@
+package reddit
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+)
+
+// flairCSVLimit is the most rows Reddit accepts in one flair CSV.
+const flairCSVLimit = 100
+
+// Flair is a user's flair in a subreddit.
+type Flair struct {
+	User     string `mapstructure:"user"`
+	Text     string `mapstructure:"flair_text"`
+	CSSClass string `mapstructure:"flair_css_class"`
+}
+
+// FlairTemplate is one of the link or user flairs a subreddit offers.
+type FlairTemplate struct {
+	ID       string `mapstructure:"id"`
+	Text     string `mapstructure:"text"`
+	CSSClass string `mapstructure:"css_class"`
+	// TextEditable is set on templates whose text the flair's holder may
+	// change.
+	TextEditable bool `mapstructure:"text_editable"`
+	// ModOnly is set on templates only moderators may assign.
+	ModOnly         bool   `mapstructure:"mod_only"`
+	BackgroundColor string `mapstructure:"background_color"`
+	// TextColor is "dark" or "light".
+	TextColor string `mapstructure:"text_color"`
+}
+
+// FlairChoice is the flair a moderator assigns to a post or user. If
+// TemplateID is set, the flair is the template's, with Text replacing its
+// text if set; otherwise the flair is the Text and CSSClass given. The zero
+// value clears the flair.
+type FlairChoice struct {
+	TemplateID string
+	Text       string
+	CSSClass   string
+}
+
+// FlairResult is Reddit's result of setting one row of a flair CSV.
+type FlairResult struct {
+	OK     bool   `mapstructure:"ok"`
+	Status string `mapstructure:"status"`
+	// Errors and Warnings are keyed by the column they are about, e.g.
+	// "css".
+	Errors   map[string]string `mapstructure:"errors"`
+	Warnings map[string]string `mapstructure:"warnings"`
+}
+
+// flairListResponse is the shape of Reddit's listing of a subreddit's user
+// flair.
+type flairListResponse struct {
+	Users []Flair `mapstructure:"users"`
+	Next  string  `mapstructure:"next"`
+}
+
+func (m *moderator) UserFlair(subreddit, user string) (Flair, error) {
+	resp := &flairListResponse{}
+	if err := m.r.reapInto(
+		"/r/"+subreddit+"/api/flairlist",
+		map[string]string{"name": user, "raw_json": "1"},
+		resp,
+	); err != nil {
+		return Flair{}, err
+	}
+
+	for _, f := range resp.Users {
+		if strings.EqualFold(f.User, user) {
+			return f, nil
+		}
+	}
+	return Flair{User: user}, nil
+}
+
+func (m *moderator) LinkFlairTemplates(subreddit string) ([]FlairTemplate, error) {
+	return m.flairTemplates(subreddit, "link_flair_v2")
+}
+
+func (m *moderator) UserFlairTemplates(subreddit string) ([]FlairTemplate, error) {
+	return m.flairTemplates(subreddit, "user_flair_v2")
+}
+
+// flairTemplates returns the templates listed by the subreddit's endpoint.
+func (m *moderator) flairTemplates(
+	subreddit, endpoint string,
+) ([]FlairTemplate, error) {
+	var templates []FlairTemplate
+	if err := m.r.reapInto(
+		"/r/"+subreddit+"/api/"+endpoint,
+		map[string]string{"raw_json": "1"},
+		&templates,
+	); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (m *moderator) SetLinkFlair(subreddit, post string, flair FlairChoice) error {
+	return m.setFlair(subreddit, "link", post, flair)
+}
+
+func (m *moderator) SetUserFlair(subreddit, user string, flair FlairChoice) error {
+	return m.setFlair(subreddit, "name", user, flair)
+}
+
+// setFlair assigns the flair to the post or user given as the value of key,
+// "link" or "name".
+func (m *moderator) setFlair(
+	subreddit, key, target string,
+	flair FlairChoice,
+) error {
+	values := map[string]string{
+		"api_type": "json",
+		key:        target,
+		"text":     flair.Text,
+	}
+	if flair.TemplateID != "" {
+		values["flair_template_id"] = flair.TemplateID
+		return m.r.sow("/r/"+subreddit+"/api/selectflair", values)
+	}
+	values["css_class"] = flair.CSSClass
+	return m.r.sow("/r/"+subreddit+"/api/flair", values)
+}
+
+func (m *moderator) SetFlairCSV(
+	subreddit string,
+	flairs []Flair,
+) ([]FlairResult, error) {
+	results := make([]FlairResult, 0, len(flairs))
+	for start := 0; start < len(flairs); start += flairCSVLimit {
+		end := start + flairCSVLimit
+		if end > len(flairs) {
+			end = len(flairs)
+		}
+
+		body, err := flairCSV(flairs[start:end])
+		if err != nil {
+			return results, err
+		}
+
+		var batch []FlairResult
+		if err := m.r.sowInto(
+			"/r/"+subreddit+"/api/flaircsv",
+			map[string]string{"flair_csv": body},
+			&batch,
+		); err != nil {
+			return results, err
+		}
+		results = append(results, batch...)
+	}
+	return results, nil
+}
+
+// flairCSV encodes flairs as the rows of a flair CSV.
+func flairCSV(flairs []Flair) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	for _, f := range flairs {
+		if err := w.Write([]string{f.User, f.Text, f.CSSClass}); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
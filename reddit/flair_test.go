@@ -0,0 +1,48 @@
+package reddit
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestSetFlairCSV(t *testing.T) {
+	r := &mockReaper{
+		raw: []interface{}{
+			map[string]interface{}{"ok": true, "status": "added flair"},
+		},
+	}
+
+	flairs := make([]Flair, 150)
+	for i := range flairs {
+		flairs[i] = Flair{User: "user" + strconv.Itoa(i), Text: "Helper"}
+	}
+
+	results, err := newModerator(r).SetFlairCSV("sub", flairs)
+	if err != nil {
+		t.Fatalf("error setting flair: %v", err)
+	}
+
+	// The mock answers each request with one result, so there is one per
+	// request made.
+	if len(results) != 2 {
+		t.Errorf("got %d results; wanted one for each of 2 requests", len(results))
+	}
+	if r.path != "/r/sub/api/flaircsv" {
+		t.Errorf("got path %s", r.path)
+	}
+}
+
+func TestFlairCSV(t *testing.T) {
+	csv, err := flairCSV([]Flair{
+		{User: "a", Text: `say "hi"`, CSSClass: "x"},
+		{User: "b"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "a,\"say \"\"hi\"\"\",x\nb,,\n"
+	if csv != expected {
+		t.Errorf("got %q; wanted %q", csv, expected)
+	}
+}
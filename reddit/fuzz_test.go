@@ -0,0 +1,45 @@
+//go:build go1.18
+// +build go1.18
+
+package reddit
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// seedFuzz adds the sanitized fixtures to the fuzz seed corpus. The captured
+// feeds in internal/testdata are too large to mutate usefully.
+func seedFuzz(f *testing.F) {
+	f.Add([]byte(`{"kind": "Listing", "data": {"children": []}}`))
+	f.Add([]byte(`{"json": {"errors": [], "data": {"things": [{"kind": "more", "data": {"children": ["a"]}}]}}}`))
+
+	fixtures, _ := filepath.Glob(filepath.Join("testdata", "*.json"))
+	for _, fixture := range fixtures {
+		if blob, err := ioutil.ReadFile(fixture); err == nil {
+			f.Add(blob)
+		}
+	}
+}
+
+// FuzzParse checks that no response, however malformed, panics the parser.
+//
+//	go test ./reddit -run '^$' -fuzz 'FuzzParse$'
+func FuzzParse(f *testing.F) {
+	seedFuzz(f)
+	f.Fuzz(func(t *testing.T, blob []byte) {
+		newParser().parse(blob)
+	})
+}
+
+// FuzzParseSubmitted checks that no response to a submission panics the
+// parser.
+func FuzzParseSubmitted(f *testing.F) {
+	f.Add([]byte(`{"json": {"errors": [], "data": {"id": "a", "name": "t3_a"}}}`))
+	f.Add([]byte(`{"json": {"errors": [], "data": {"things": [{"kind": "t1", "data": {"id": "a"}}]}}}`))
+	f.Add([]byte(`{"json": {"errors": [["RATELIMIT", "slow down", "ratelimit"]]}}`))
+	f.Fuzz(func(t *testing.T, blob []byte) {
+		newParser().parse_submitted(blob)
+	})
+}
@@ -0,0 +1,123 @@
+package reddit
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Golden tests parse each sanitized Reddit response in testdata/ and compare
+// everything the parser extracted to the matching .golden file, so that a
+// parser change can't silently drop fields. After an intended change, or
+// after capturing new fixtures with reddit/internal/refresh, rewrite the
+// golden files with
+//
+//	go test ./reddit -run TestGolden -update
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func TestGolden(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		t.Fatalf("failed to list fixtures: %v", err)
+	}
+
+	for _, fixture := range fixtures {
+		blob, err := ioutil.ReadFile(fixture)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", fixture, err)
+		}
+
+		actual, err := goldenHarvest(blob)
+		if err != nil {
+			t.Errorf("failed to parse %s: %v", fixture, err)
+			continue
+		}
+
+		checkGolden(t, fixture, actual)
+	}
+}
+
+// TestModmailGolden reads the modmail conversations in testdata/modmail/, which
+// are not listings, and compares them to the matching .golden files.
+func TestModmailGolden(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "modmail", "*.json"))
+	if err != nil {
+		t.Fatalf("failed to list fixtures: %v", err)
+	}
+
+	for _, fixture := range fixtures {
+		blob, err := ioutil.ReadFile(fixture)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", fixture, err)
+		}
+
+		r, _ := modmailReaper(map[string]string{"/api/mod/conversations": string(blob)})
+		page, err := newModmail(r).Conversations(ModmailQuery{})
+		if err != nil {
+			t.Errorf("failed to read %s: %v", fixture, err)
+			continue
+		}
+
+		actual, err := goldenJSON(page)
+		if err != nil {
+			t.Fatalf("failed to render %s: %v", fixture, err)
+		}
+		checkGolden(t, fixture, actual)
+	}
+}
+
+// checkGolden compares what was read from a fixture to its .golden file, or
+// rewrites the golden file with -update.
+func checkGolden(t *testing.T, fixture string, actual []byte) {
+	golden := strings.TrimSuffix(fixture, ".json") + ".golden"
+	if *update {
+		if err := ioutil.WriteFile(golden, actual, 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", golden, err)
+		}
+		return
+	}
+
+	expected, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Errorf("failed to read %s (run with -update?): %v", golden, err)
+		return
+	}
+
+	if !bytes.Equal(actual, expected) {
+		t.Errorf(
+			"parse of %s differs from %s; got:\n%s",
+			fixture, golden, actual,
+		)
+	}
+}
+
+// goldenHarvest parses a response and renders the result for comparison.
+func goldenHarvest(blob []byte) ([]byte, error) {
+	comments, posts, messages, mores, err := newParser().parse(blob)
+	if err != nil {
+		return nil, err
+	}
+
+	return goldenJSON(
+		Harvest{
+			Comments: comments,
+			Posts:    posts,
+			Messages: messages,
+			Mores:    mores,
+		},
+	)
+}
+
+// goldenJSON renders what was read from a fixture for comparison.
+func goldenJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(v)
+	return buf.Bytes(), err
+}
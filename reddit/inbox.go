@@ -0,0 +1,123 @@
+package reddit
+
+import (
+	"strconv"
+	"strings"
+)
+
+// InboxOptions select a page of one of the bot's message listings. The zero
+// value selects the newest hundred messages, without marking them read.
+type InboxOptions struct {
+	// Limit is the most messages returned, up to 100. If zero, 100 are.
+	Limit int
+	// After and Before are the fullnames of messages to page from, e.g.
+	// the After of the previous page's ListingInfo, and Count is how many
+	// messages were already seen.
+	After  string
+	Before string
+	Count  int
+	// Mark marks the unread messages returned as read, as reading the
+	// inbox on the site does.
+	Mark bool
+}
+
+// params returns the request parameters of the options.
+func (o InboxOptions) params() map[string]string {
+	params := map[string]string{
+		"raw_json": "1",
+		"limit":    "100",
+		"mark":     strconv.FormatBool(o.Mark),
+	}
+	if o.After != "" {
+		params["after"] = o.After
+	}
+	if o.Before != "" {
+		params["before"] = o.Before
+	}
+	if o.Limit > 0 {
+		params["limit"] = strconv.Itoa(o.Limit)
+	}
+	if o.Count > 0 {
+		params["count"] = strconv.Itoa(o.Count)
+	}
+	return params
+}
+
+func (a *account) Inbox(opts InboxOptions) (Harvest, ListingInfo, error) {
+	return a.r.reapPage("/message/inbox", opts.params())
+}
+
+func (a *account) UnreadMessages(opts InboxOptions) (Harvest, ListingInfo, error) {
+	return a.r.reapPage("/message/unread", opts.params())
+}
+
+func (a *account) SentMessages(opts InboxOptions) (Harvest, ListingInfo, error) {
+	return a.r.reapPage("/message/sent", opts.params())
+}
+
+func (a *account) MessageThread(name string) ([]*Message, error) {
+	if err := checkName(name, "message", messagePrefix); err != nil {
+		return nil, err
+	}
+
+	h, err := a.r.reap(
+		"/message/messages/"+strings.TrimPrefix(name, messagePrefix),
+		map[string]string{"raw_json": "1"},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var thread []*Message
+	var walk func(msgs []*Message)
+	walk = func(msgs []*Message) {
+		for _, m := range msgs {
+			thread = append(thread, m)
+			walk(m.Replies)
+		}
+	}
+	walk(h.Messages)
+	return thread, nil
+}
+
+func (a *account) MarkRead(names ...string) error {
+	return a.messageAction("/api/read_message", names)
+}
+
+func (a *account) MarkUnread(names ...string) error {
+	return a.messageAction("/api/unread_message", names)
+}
+
+func (a *account) MarkAllRead() error {
+	return a.r.sow("/api/read_all_messages", map[string]string{})
+}
+
+func (a *account) CollapseMessages(names ...string) error {
+	return a.messageAction("/api/collapse_message", names)
+}
+
+func (a *account) UncollapseMessages(names ...string) error {
+	return a.messageAction("/api/uncollapse_message", names)
+}
+
+func (a *account) BlockSender(name string) error {
+	if err := checkName(name, "message or comment", messagePrefix, commentPrefix); err != nil {
+		return err
+	}
+	return a.r.sow("/api/block", map[string]string{"id": name})
+}
+
+// messageAction applies an action to the messages with the given fullnames
+// in one request. Replies to the bot's posts and comments are messages of the
+// inbox too, so their comments' fullnames are accepted.
+func (a *account) messageAction(path string, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	for _, name := range names {
+		if err := checkName(name, "message or comment", messagePrefix, commentPrefix); err != nil {
+			return err
+		}
+	}
+	return a.r.sow(path, map[string]string{"id": strings.Join(names, ",")})
+}
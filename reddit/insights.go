@@ -0,0 +1,61 @@
+package reddit
+
+import "strings"
+
+// infoBatch is the most fullnames /api/info accepts in one request.
+const infoBatch = 100
+
+// PostInsights is the reach of a post, as far as Reddit's API reports it.
+// Reddit does not report shares.
+type PostInsights struct {
+	Post      string
+	Permalink string
+	// Views is how many times the post was viewed, or nil if Reddit did
+	// not report it. It is only reported to the post's author and the
+	// subreddit's moderators, and not for every post.
+	Views       *int64
+	Crossposts  int
+	Comments    int
+	Score       int
+	UpvoteRatio float64
+}
+
+// Insights returns the reach of the post as of when it was fetched.
+func (p *Post) Insights() PostInsights {
+	return PostInsights{
+		Post:        p.Name,
+		Permalink:   p.Permalink,
+		Views:       p.ViewCount,
+		Crossposts:  int(p.NumCrossposts),
+		Comments:    int(p.NumComments),
+		Score:       int(p.Score),
+		UpvoteRatio: p.UpvoteRatio,
+	}
+}
+
+func (a *account) PostInsights(posts ...string) ([]PostInsights, error) {
+	var insights []PostInsights
+	for i := 0; i < len(posts); i += infoBatch {
+		end := i + infoBatch
+		if end > len(posts) {
+			end = len(posts)
+		}
+
+		h, err := a.r.reap(
+			"/api/info",
+			map[string]string{
+				"raw_json": "1",
+				"id":       strings.Join(posts[i:end], ","),
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range h.Posts {
+			insights = append(insights, p.Insights())
+		}
+	}
+
+	return insights, nil
+}
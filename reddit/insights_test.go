@@ -0,0 +1,34 @@
+package reddit
+
+import (
+	"testing"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+func TestPostInsights(t *testing.T) {
+	for i, test := range []struct {
+		data  map[string]interface{}
+		views int64
+		known bool
+	}{
+		{map[string]interface{}{"view_count": nil, "num_crossposts": 2.0}, 0, false},
+		{map[string]interface{}{"view_count": 1500.0, "num_crossposts": 2.0}, 1500, true},
+	} {
+		p := &Post{}
+		if err := mapstructure.Decode(test.data, p); err != nil {
+			t.Fatalf("%d: failed to decode post: %v", i, err)
+		}
+
+		in := p.Insights()
+		if in.Crossposts != 2 {
+			t.Errorf("%d: got %d crossposts; wanted 2", i, in.Crossposts)
+		}
+
+		if (in.Views != nil) != test.known {
+			t.Errorf("%d: views known is %v; wanted %v", i, in.Views != nil, test.known)
+		} else if test.known && *in.Views != test.views {
+			t.Errorf("%d: got %d views; wanted %d", i, *in.Views, test.views)
+		}
+	}
+}
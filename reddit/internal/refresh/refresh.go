@@ -0,0 +1,151 @@
+// Command refresh captures a live Reddit response as a parser fixture.
+//
+// It fetches a url, replaces the names of every account in the response with
+// stable placeholders, and writes the result to reddit/testdata, where the
+// golden tests will pick it up:
+//
+//	go run ./reddit/internal/refresh --name poll \
+//	  --url https://www.reddit.com/by_id/t3_k31d0x.json
+//	go test ./reddit -run TestGolden -update
+//
+// Responses which need a logged in account, such as modmail, can be saved by
+// hand and sanitized with --file instead of --url.
+//
+// Review the diff of the golden file before committing it.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	app   = kingpin.New("refresh", "A tool for capturing parser fixtures from Reddit.")
+	name  = app.Flag("name", "Name of the fixture to write.").Required().String()
+	url   = app.Flag("url", "Url of the Reddit response to capture.").String()
+	file  = app.Flag("file", "Saved Reddit response to sanitize instead of fetching a url, e.g. one which needs a logged in account.").String()
+	dir   = app.Flag("dir", "Directory of the fixtures.").Default("reddit/testdata").String()
+	agent = app.Flag("agent", "User agent to send.").Default("graw:fixture refresh:0.1").String()
+)
+
+// accountKeys are the fields of Reddit things that name an account.
+var accountKeys = map[string]bool{
+	"author":          true,
+	"author_fullname": true,
+	"link_author":     true,
+	"dest":            true,
+	"banned_by":       true,
+	"approved_by":     true,
+	"mod_reason_by":   true,
+}
+
+// sanitizer replaces account names with placeholders, mapping each name to
+// the same placeholder everywhere it appears.
+type sanitizer struct {
+	names map[string]string
+}
+
+func (s *sanitizer) placeholder(name string) string {
+	if name == "" || name == "[deleted]" {
+		return name
+	}
+
+	if p, ok := s.names[name]; ok {
+		return p
+	}
+
+	p := fmt.Sprintf("user%d", len(s.names)+1)
+	s.names[name] = p
+	return p
+}
+
+func (s *sanitizer) sanitize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		// The authors and participants of modmail are objects naming an
+		// account in "name".
+		_, modmailAccount := t["isMod"]
+		for key, value := range t {
+			if str, ok := value.(string); ok && (accountKeys[key] || modmailAccount && key == "name") {
+				t[key] = s.placeholder(str)
+			} else if key == "modhash" {
+				t[key] = ""
+			} else {
+				t[key] = s.sanitize(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range t {
+			t[i] = s.sanitize(value)
+		}
+	}
+	return v
+}
+
+func fetch(u string) ([]byte, error) {
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", *agent)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bad response code: %d", resp.StatusCode)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+func main() {
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	if (*url == "") == (*file == "") {
+		log.Fatalf("Pass exactly one of --url and --file.\n")
+	}
+
+	var blob []byte
+	var err error
+	if *file != "" {
+		blob, err = ioutil.ReadFile(*file)
+	} else {
+		blob, err = fetch(*url)
+	}
+	if err != nil {
+		log.Fatalf("Failed to read the response: %v\n", err)
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(blob, &v); err != nil {
+		log.Fatalf("Response was not json: %v\n", err)
+	}
+
+	s := &sanitizer{names: make(map[string]string)}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(s.sanitize(v)); err != nil {
+		log.Fatalf("Failed to encode fixture: %v\n", err)
+	}
+
+	filename := filepath.Join(*dir, *name+".json")
+	if err := ioutil.WriteFile(filename, buf.Bytes(), 0644); err != nil {
+		log.Fatalf("Failed to write %s: %v\n", filename, err)
+	}
+
+	fmt.Printf("Wrote %s; replaced %d account names.\n", filename, len(s.names))
+}
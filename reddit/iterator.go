@@ -0,0 +1,108 @@
+package reddit
+
+import "strconv"
+
+// maxPageSize is the most elements Reddit returns in a page of a listing.
+const maxPageSize = 100
+
+// PageOptions configures a ListingIterator.
+type PageOptions struct {
+	// Limit is how many elements to request per page, at most 100. If
+	// zero, it is 100.
+	Limit int
+	// After, if set, is the fullname of the element to start after.
+	After string
+	// Params are sent with every page's request, e.g. "sort" and "t" for
+	// top listings.
+	Params map[string]string
+}
+
+// ListingIterator walks a listing page by page, following the after token
+// each page returns, so it can read back through a subreddit's history or a
+// user's comments:
+//
+//	it := reddit.NewListingIterator(bot, "/user/spez/comments", reddit.PageOptions{})
+//	for it.Next() {
+//		for _, c := range it.Page().Comments {
+//			...
+//		}
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// Each page costs a request under the rate limit. Reddit lists at most about a
+// thousand elements of a listing, however far back it goes.
+type ListingIterator interface {
+	// Next fetches the next page, returning false when the listing is
+	// exhausted or a request fails.
+	Next() bool
+	// Page returns the elements of the page Next fetched.
+	Page() Harvest
+	// Info returns the metadata of the page Next fetched.
+	Info() ListingInfo
+	// Err returns the error which stopped the iterator, if one did.
+	Err() error
+}
+
+type listingIterator struct {
+	sc    Scanner
+	path  string
+	opts  PageOptions
+	after string
+	// count is how many elements were seen, which Reddit uses to number
+	// the elements of later pages.
+	count int
+	done  bool
+	page  Harvest
+	info  ListingInfo
+	err   error
+}
+
+// NewListingIterator returns an iterator over the pages of the listing at
+// path, read through the scanner.
+func NewListingIterator(sc Scanner, path string, opts PageOptions) ListingIterator {
+	if opts.Limit <= 0 || opts.Limit > maxPageSize {
+		opts.Limit = maxPageSize
+	}
+
+	return &listingIterator{sc: sc, path: path, opts: opts, after: opts.After}
+}
+
+func (l *listingIterator) Next() bool {
+	if l.done {
+		return false
+	}
+
+	params := map[string]string{"limit": strconv.Itoa(l.opts.Limit)}
+	for key, value := range l.opts.Params {
+		params[key] = value
+	}
+	if l.after != "" {
+		params["after"] = l.after
+		params["count"] = strconv.Itoa(l.count)
+	}
+
+	page, info, err := l.sc.ListingPage(l.path, params)
+	if err != nil {
+		l.err, l.done = err, true
+		return false
+	}
+
+	size := len(page.Comments) + len(page.Posts) + len(page.Messages)
+	if size == 0 {
+		l.done = true
+		return false
+	}
+
+	l.page, l.info = page, info
+	l.count += size
+	l.after = info.After
+	// The last page has no after token; it is still returned.
+	l.done = info.After == ""
+	return true
+}
+
+func (l *listingIterator) Page() Harvest     { return l.page }
+func (l *listingIterator) Info() ListingInfo { return l.info }
+func (l *listingIterator) Err() error        { return l.err }
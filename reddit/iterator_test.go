@@ -0,0 +1,106 @@
+package reddit
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// pagedScanner serves a listing of posts in pages of the requested size.
+type pagedScanner struct {
+	Scanner
+	posts  []string
+	params []map[string]string
+	err    error
+}
+
+func (p *pagedScanner) ListingPage(path string, params map[string]string) (
+	Harvest,
+	ListingInfo,
+	error,
+) {
+	p.params = append(p.params, params)
+	if p.err != nil {
+		return Harvest{}, ListingInfo{}, p.err
+	}
+
+	start := 0
+	for i, name := range p.posts {
+		if name == params["after"] {
+			start = i + 1
+		}
+	}
+
+	var limit int
+	fmt.Sscan(params["limit"], &limit)
+	end := start + limit
+	if end > len(p.posts) {
+		end = len(p.posts)
+	}
+
+	h := Harvest{}
+	for _, name := range p.posts[start:end] {
+		h.Posts = append(h.Posts, &Post{Name: name})
+	}
+
+	info := ListingInfo{}
+	if end < len(p.posts) {
+		info.After = p.posts[end-1]
+	}
+	return h, info, nil
+}
+
+func TestListingIterator(t *testing.T) {
+	sc := &pagedScanner{posts: []string{"t3_a", "t3_b", "t3_c", "t3_d", "t3_e"}}
+	it := NewListingIterator(sc, "/r/golang/new", PageOptions{
+		Limit:  2,
+		Params: map[string]string{"sort": "new"},
+	})
+
+	var pages [][]string
+	for it.Next() {
+		var page []string
+		for _, p := range it.Page().Posts {
+			page = append(page, p.Name)
+		}
+		pages = append(pages, page)
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatalf("iterator failed: %v", err)
+	}
+
+	want := [][]string{{"t3_a", "t3_b"}, {"t3_c", "t3_d"}, {"t3_e"}}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("got pages %v; wanted %v", pages, want)
+	}
+
+	wantParams := []map[string]string{
+		{"limit": "2", "sort": "new"},
+		{"limit": "2", "sort": "new", "after": "t3_b", "count": "2"},
+		{"limit": "2", "sort": "new", "after": "t3_d", "count": "4"},
+	}
+	if !reflect.DeepEqual(sc.params, wantParams) {
+		t.Errorf("got params %v; wanted %v", sc.params, wantParams)
+	}
+
+	if it.Next() {
+		t.Errorf("iterator continued past the end of the listing")
+	}
+}
+
+func TestListingIteratorError(t *testing.T) {
+	sc := &pagedScanner{err: BusyErr}
+	it := NewListingIterator(sc, "/r/golang/new", PageOptions{})
+	if it.Next() {
+		t.Errorf("iterator returned a page it failed to fetch")
+	}
+
+	if it.Err() != BusyErr {
+		t.Errorf("got error %v; wanted %v", it.Err(), BusyErr)
+	}
+
+	if sc.params[0]["limit"] != "100" {
+		t.Errorf("requested pages of %s; wanted the default of 100", sc.params[0]["limit"])
+	}
+}
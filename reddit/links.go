@@ -0,0 +1,55 @@
+package reddit
+
+import (
+	"net/url"
+	"strconv"
+)
+
+const (
+	// wwwHostname is the hostname used for links meant for humans.
+	wwwHostname = "www.reddit.com"
+	// npHostname is the hostname of Reddit's non participation mirror.
+	// Many subreddits require links into other communities to use it so
+	// that readers do not vote or comment in a thread they were sent to.
+	npHostname = "np.reddit.com"
+)
+
+// Link returns a full url to a permalink on Reddit.
+//
+// If np is true, the link points at np.reddit.com, which x-post and citation
+// bots should prefer when linking into another community. If context is
+// greater than zero and the permalink is to a comment, the link will display
+// that many of the comment's parents as well.
+func Link(permalink string, np bool, context int) string {
+	host := wwwHostname
+	if np {
+		host = npHostname
+	}
+
+	u := &url.URL{
+		Scheme: "https",
+		Host:   host,
+		Path:   permalink,
+	}
+	if context > 0 {
+		u.RawQuery = url.Values{"context": {strconv.Itoa(context)}}.Encode()
+	}
+
+	return u.String()
+}
+
+// NPLink returns a non participation link to the post.
+func (p *Post) NPLink() string {
+	return Link(p.Permalink, true, 0)
+}
+
+// NPLink returns a non participation link to the comment.
+func (c *Comment) NPLink() string {
+	return Link(c.Permalink, true, 0)
+}
+
+// ContextLink returns a link to the comment which displays the given number of
+// its parent comments.
+func (c *Comment) ContextLink(np bool, context int) string {
+	return Link(c.Permalink, np, context)
+}
@@ -0,0 +1,23 @@
+package reddit
+
+import (
+	"testing"
+)
+
+func TestLink(t *testing.T) {
+	for i, test := range []struct {
+		permalink string
+		np        bool
+		context   int
+		correct   string
+	}{
+		{"/r/a/comments/b/c/", false, 0, "https://www.reddit.com/r/a/comments/b/c/"},
+		{"/r/a/comments/b/c/", true, 0, "https://np.reddit.com/r/a/comments/b/c/"},
+		{"/r/a/comments/b/c/d/", false, 3, "https://www.reddit.com/r/a/comments/b/c/d/?context=3"},
+		{"/r/a/comments/b/c/d/", true, 3, "https://np.reddit.com/r/a/comments/b/c/d/?context=3"},
+	} {
+		if link := Link(test.permalink, test.np, test.context); link != test.correct {
+			t.Errorf("%d: got %s; wanted %s", i, link, test.correct)
+		}
+	}
+}
@@ -0,0 +1,360 @@
+package reddit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mitchellh/mapstructure"
+	"golang.org/x/net/websocket"
+)
+
+// Kinds of the events a live thread's websocket announces, which
+// LiveEvent.Type is one of. Other kinds Reddit adds are passed on with only
+// their Type set.
+const (
+	// LiveUpdateEvent announces a new update, in LiveEvent.Update.
+	LiveUpdateEvent = "update"
+	// LiveStrikeEvent and LiveDeleteEvent announce that the update named
+	// in LiveEvent.UpdateName was struck through or deleted.
+	LiveStrikeEvent = "strike"
+	LiveDeleteEvent = "delete"
+	// LiveActivityEvent announces how many are watching the thread, in
+	// LiveEvent.Viewers.
+	LiveActivityEvent = "activity"
+	// LiveSettingsEvent announces a change of the thread's settings, in
+	// LiveEvent.Settings.
+	LiveSettingsEvent = "settings"
+	// LiveCompleteEvent announces that the thread was closed. No events
+	// follow it.
+	LiveCompleteEvent = "complete"
+)
+
+// liveUpdatePrefix begins the fullnames of live updates.
+const liveUpdatePrefix = "LiveUpdate_"
+
+// LiveCompleteErr is returned when listening to a live thread which was
+// closed, and so has no websocket.
+var LiveCompleteErr = fmt.Errorf("the live thread is complete")
+
+// LiveThread is a Reddit live thread, in which its contributors post a
+// stream of updates, e.g. about a game or breaking news.
+type LiveThread struct {
+	ID          string `mapstructure:"id"`
+	Name        string `mapstructure:"name"`
+	Title       string `mapstructure:"title"`
+	Description string `mapstructure:"description"`
+	// Resources is the markdown of the thread's sidebar.
+	Resources string `mapstructure:"resources"`
+	// State is "live", or "complete" once the thread is closed.
+	State        string `mapstructure:"state"`
+	NSFW         bool   `mapstructure:"nsfw"`
+	ViewerCount  int    `mapstructure:"viewer_count"`
+	CreatedUTC   uint64 `mapstructure:"created_utc"`
+	WebsocketURL string `mapstructure:"websocket_url"`
+}
+
+// LiveUpdate is an update posted in a live thread.
+type LiveUpdate struct {
+	ID         string `mapstructure:"id"`
+	Name       string `mapstructure:"name"`
+	Author     string `mapstructure:"author"`
+	Body       string `mapstructure:"body"`
+	BodyHTML   string `mapstructure:"body_html"`
+	CreatedUTC uint64 `mapstructure:"created_utc"`
+	// Stricken is set on updates struck through as wrong.
+	Stricken bool `mapstructure:"stricken"`
+}
+
+// LiveThreadSettings are the settings a live thread is created or edited
+// with. Editing sets every one of them.
+type LiveThreadSettings struct {
+	Title       string
+	Description string
+	Resources   string
+	NSFW        bool
+}
+
+// values returns the request parameters of the settings.
+func (s LiveThreadSettings) values() map[string]string {
+	return map[string]string{
+		"api_type":    "json",
+		"title":       s.Title,
+		"description": s.Description,
+		"resources":   s.Resources,
+		"nsfw":        strconv.FormatBool(s.NSFW),
+	}
+}
+
+// LiveEvent is an event announced on a live thread's websocket.
+type LiveEvent struct {
+	// Thread is the ID of the live thread.
+	Thread string
+	// Type is the kind of the event, e.g. LiveUpdateEvent.
+	Type string
+	// Update is set on LiveUpdateEvents.
+	Update *LiveUpdate
+	// UpdateName is the fullname of the update struck or deleted, on
+	// LiveStrikeEvents and LiveDeleteEvents.
+	UpdateName string
+	// Viewers is set on LiveActivityEvents.
+	Viewers int
+	// Settings holds the settings changed, on LiveSettingsEvents.
+	Settings *LiveThread
+}
+
+// LiveStream is a connection to a live thread's websocket. It is not safe to
+// call Next from multiple goroutines, but Close may be called from any.
+type LiveStream interface {
+	// Next blocks until the next event of the thread, and returns it. It
+	// returns an error once the connection fails or is closed.
+	Next() (*LiveEvent, error)
+	// Close closes the connection.
+	Close() error
+}
+
+// Live defines behaviors for following and running Reddit live threads.
+type Live interface {
+	// LiveThread returns a live thread, by its ID.
+	LiveThread(thread string) (*LiveThread, error)
+	// LiveUpdates returns up to limit of a live thread's newest updates,
+	// newest first. If limit is zero, up to 100 are returned.
+	LiveUpdates(thread string, limit int) ([]*LiveUpdate, error)
+	// ListenLive connects to a live thread's websocket, to follow its
+	// updates as they are posted. The connection closes with the context
+	// of the bot.
+	ListenLive(thread string) (LiveStream, error)
+
+	// CreateLiveThread creates a live thread and returns its ID. The bot
+	// is its only contributor.
+	CreateLiveThread(settings LiveThreadSettings) (string, error)
+	// EditLiveThread replaces the settings of a live thread.
+	EditLiveThread(thread string, settings LiveThreadSettings) error
+	// PostLiveUpdate posts an update to a live thread the bot contributes
+	// to.
+	PostLiveUpdate(thread, text string) error
+	// StrikeLiveUpdate strikes an update of a live thread through, by its
+	// fullname, and DeleteLiveUpdate deletes it.
+	StrikeLiveUpdate(thread, update string) error
+	DeleteLiveUpdate(thread, update string) error
+	// CloseLiveThread closes a live thread for good.
+	CloseLiveThread(thread string) error
+}
+
+type live struct {
+	r reaper
+}
+
+func newLive(r reaper) Live {
+	return &live{r: r}
+}
+
+// liveAboutResponse is the shape of Reddit's response about a live thread.
+type liveAboutResponse struct {
+	Data LiveThread `mapstructure:"data"`
+}
+
+func (l *live) LiveThread(thread string) (*LiveThread, error) {
+	resp := &liveAboutResponse{}
+	if err := l.r.reapInto(
+		"/live/"+thread+"/about",
+		map[string]string{"raw_json": "1"},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// liveUpdatesResponse is the shape of Reddit's listing of a live thread's
+// updates.
+type liveUpdatesResponse struct {
+	Data struct {
+		Children []struct {
+			Data LiveUpdate `mapstructure:"data"`
+		} `mapstructure:"children"`
+	} `mapstructure:"data"`
+}
+
+func (l *live) LiveUpdates(thread string, limit int) ([]*LiveUpdate, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	resp := &liveUpdatesResponse{}
+	if err := l.r.reapInto(
+		"/live/"+thread,
+		map[string]string{"raw_json": "1", "limit": strconv.Itoa(limit)},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+
+	updates := make([]*LiveUpdate, 0, len(resp.Data.Children))
+	for i := range resp.Data.Children {
+		updates = append(updates, &resp.Data.Children[i].Data)
+	}
+	return updates, nil
+}
+
+func (l *live) ListenLive(thread string) (LiveStream, error) {
+	about, err := l.LiveThread(thread)
+	if err != nil {
+		return nil, err
+	}
+	if about.WebsocketURL == "" {
+		return nil, LiveCompleteErr
+	}
+	return l.r.listen(thread, about.WebsocketURL)
+}
+
+// liveCreateResponse is the shape of Reddit's response to a new live thread.
+type liveCreateResponse struct {
+	JSON struct {
+		Data struct {
+			ID string `mapstructure:"id"`
+		} `mapstructure:"data"`
+	} `mapstructure:"json"`
+}
+
+func (l *live) CreateLiveThread(settings LiveThreadSettings) (string, error) {
+	resp := &liveCreateResponse{}
+	if err := l.r.sowInto("/api/live/create", settings.values(), resp); err != nil {
+		return "", err
+	}
+	return resp.JSON.Data.ID, nil
+}
+
+func (l *live) EditLiveThread(thread string, settings LiveThreadSettings) error {
+	return l.r.sow("/api/live/"+thread+"/edit", settings.values())
+}
+
+func (l *live) PostLiveUpdate(thread, text string) error {
+	return l.r.sow(
+		"/api/live/"+thread+"/update", map[string]string{
+			"api_type": "json",
+			"body":     text,
+		},
+	)
+}
+
+func (l *live) StrikeLiveUpdate(thread, update string) error {
+	return l.updateAction(thread, "strike_update", update)
+}
+
+func (l *live) DeleteLiveUpdate(thread, update string) error {
+	return l.updateAction(thread, "delete_update", update)
+}
+
+// updateAction applies an action to an update of a live thread.
+func (l *live) updateAction(thread, action, update string) error {
+	if err := checkName(update, "live update", liveUpdatePrefix); err != nil {
+		return err
+	}
+	return l.r.sow(
+		"/api/live/"+thread+"/"+action, map[string]string{
+			"api_type": "json",
+			"id":       update,
+		},
+	)
+}
+
+func (l *live) CloseLiveThread(thread string) error {
+	return l.r.sow(
+		"/api/live/"+thread+"/close_thread",
+		map[string]string{"api_type": "json"},
+	)
+}
+
+func (r *reaperImpl) listen(thread, websocketURL string) (LiveStream, error) {
+	conn, err := websocket.Dial(websocketURL, "", "https://www.reddit.com")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &liveStream{conn: conn, thread: thread, done: make(chan struct{})}
+	ctx := r.context()
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Close()
+		case <-s.done:
+		}
+	}()
+	return s, nil
+}
+
+// liveStream reads the events of a live thread from its websocket.
+type liveStream struct {
+	conn   *websocket.Conn
+	thread string
+	// done is closed once the stream is.
+	done  chan struct{}
+	close sync.Once
+}
+
+// liveMessage is the shape of the messages of a live thread's websocket.
+type liveMessage struct {
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+func (s *liveStream) Next() (*LiveEvent, error) {
+	var msg liveMessage
+	if err := websocket.JSON.Receive(s.conn, &msg); err != nil {
+		return nil, err
+	}
+	return parseLiveEvent(s.thread, msg)
+}
+
+func (s *liveStream) Close() error {
+	err := error(nil)
+	s.close.Do(func() {
+		close(s.done)
+		err = s.conn.Close()
+	})
+	return err
+}
+
+// parseLiveEvent parses a message of a live thread's websocket.
+func parseLiveEvent(thread string, msg liveMessage) (*LiveEvent, error) {
+	e := &LiveEvent{Thread: thread, Type: msg.Type}
+	switch msg.Type {
+	case LiveUpdateEvent:
+		var t thing
+		if err := json.Unmarshal(msg.Payload, &t); err != nil {
+			return nil, err
+		}
+		e.Update = &LiveUpdate{}
+		if err := mapstructure.Decode(t.Data, e.Update); err != nil {
+			return nil, mapDecodeError(err, t.Data)
+		}
+	case LiveStrikeEvent, LiveDeleteEvent:
+		if err := json.Unmarshal(msg.Payload, &e.UpdateName); err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(e.UpdateName, liveUpdatePrefix) {
+			e.UpdateName = liveUpdatePrefix + e.UpdateName
+		}
+	case LiveActivityEvent:
+		var activity struct {
+			Count int `json:"count"`
+		}
+		if err := json.Unmarshal(msg.Payload, &activity); err != nil {
+			return nil, err
+		}
+		e.Viewers = activity.Count
+	case LiveSettingsEvent:
+		var settings map[string]interface{}
+		if err := json.Unmarshal(msg.Payload, &settings); err != nil {
+			return nil, err
+		}
+		e.Settings = &LiveThread{}
+		if err := mapstructure.Decode(settings, e.Settings); err != nil {
+			return nil, mapDecodeError(err, settings)
+		}
+	}
+	return e, nil
+}
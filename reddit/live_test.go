@@ -0,0 +1,111 @@
+package reddit
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/kylelemons/godebug/pretty"
+	"golang.org/x/net/websocket"
+)
+
+func TestListenLive(t *testing.T) {
+	announcer := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		for _, msg := range []map[string]interface{}{
+			{"type": "update", "payload": map[string]interface{}{
+				"kind": "LiveUpdate",
+				"data": map[string]interface{}{"name": "LiveUpdate_a", "body": "Kickoff!"},
+			}},
+			{"type": "strike", "payload": "a"},
+			{"type": "activity", "payload": map[string]interface{}{"count": 42, "fuzzed": false}},
+			{"type": "settings", "payload": map[string]interface{}{"title": "Final"}},
+			{"type": "complete", "payload": nil},
+		} {
+			websocket.JSON.Send(ws, msg)
+		}
+	}))
+	defer announcer.Close()
+
+	c := &routeClient{
+		resps: map[string]string{
+			"/live/thread/about.json": `{"kind": "LiveUpdateEvent", "data": {
+				"id": "thread", "state": "live",
+				"websocket_url": "ws` + strings.TrimPrefix(announcer.URL, "http") + `"
+			}}`,
+		},
+		bodies: make(map[string][]string),
+	}
+	r := &reaperImpl{
+		cli:        c,
+		parser:     newParser(),
+		hostname:   "oauth.reddit.com",
+		reapSuffix: ".json",
+		scheme:     "https",
+		clock:      clock.Real(),
+		limit:      newRateLimit(),
+	}
+
+	stream, err := newLive(r).ListenLive("thread")
+	if err != nil {
+		t.Fatalf("error listening: %v", err)
+	}
+	defer stream.Close()
+
+	var events []*LiveEvent
+	for {
+		e, err := stream.Next()
+		if err != nil {
+			t.Fatalf("error reading event %d: %v", len(events), err)
+		}
+		events = append(events, e)
+		if e.Type == LiveCompleteEvent {
+			break
+		}
+	}
+
+	expected := []*LiveEvent{
+		{Thread: "thread", Type: LiveUpdateEvent, Update: &LiveUpdate{Name: "LiveUpdate_a", Body: "Kickoff!"}},
+		{Thread: "thread", Type: LiveStrikeEvent, UpdateName: "LiveUpdate_a"},
+		{Thread: "thread", Type: LiveActivityEvent, Viewers: 42},
+		{Thread: "thread", Type: LiveSettingsEvent, Settings: &LiveThread{Title: "Final"}},
+		{Thread: "thread", Type: LiveCompleteEvent},
+	}
+	if diff := pretty.Compare(events, expected); diff != "" {
+		t.Errorf("events incorrect; diff: %s", diff)
+	}
+}
+
+func TestListenLiveComplete(t *testing.T) {
+	r := &mockReaper{
+		raw: map[string]interface{}{
+			"data": map[string]interface{}{"id": "thread", "state": "complete", "websocket_url": nil},
+		},
+	}
+	if _, err := newLive(r).ListenLive("thread"); err != LiveCompleteErr {
+		t.Errorf("got %v; wanted LiveCompleteErr", err)
+	}
+}
+
+func TestCreateLiveThread(t *testing.T) {
+	r := &mockReaper{
+		raw: map[string]interface{}{
+			"json": map[string]interface{}{
+				"errors": []interface{}{},
+				"data":   map[string]interface{}{"id": "16f5vfcmpykwg"},
+			},
+		},
+	}
+
+	id, err := newLive(r).CreateLiveThread(LiveThreadSettings{Title: "Match thread"})
+	if err != nil {
+		t.Fatalf("error creating live thread: %v", err)
+	}
+	if id != "16f5vfcmpykwg" || r.path != "/api/live/create" {
+		t.Errorf("got id %q from %s", id, r.path)
+	}
+
+	if err := newLive(r).StrikeLiveUpdate("16f5vfcmpykwg", "t1_comment"); err == nil {
+		t.Errorf("struck a comment")
+	}
+}
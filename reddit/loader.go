@@ -2,31 +2,183 @@ package reddit
 
 import (
 	"bytes"
+	"encoding/base64"
+	"fmt"
 	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
 
+	"github.com/aldarisbm/graw/store"
 	"github.com/golang/protobuf/proto"
 	"github.com/turnage/redditproto"
 )
 
+// refreshTokenKey is the agent file key holding an OAuth2 refresh token. The
+// legacy agent file format has no such field, so lines with this key are read
+// and written separately from the rest of the file.
+const refreshTokenKey = "refresh_token:"
+
+// sealedPrefix marks a refresh token encrypted by SaveSealedRefreshToken.
+const sealedPrefix = "sealed:"
+
+// refreshTokenLabel is the label refresh tokens are sealed under.
+const refreshTokenLabel = "refresh-token"
+
+// SealedTokenErr is returned when loading an agent file holding an encrypted
+// refresh token without its key.
+var SealedTokenErr = fmt.Errorf("the refresh token is encrypted; load the agent file with LoadSealedAgentFile")
+
+// LoadAgentFile returns the user agent and App stored in an agent file,
+// without authorizing with Reddit.
+func LoadAgentFile(filename string) (string, App, error) {
+	return load(filename)
+}
+
+// LoadSealedAgentFile is LoadAgentFile for agent files whose refresh token was
+// encrypted with k by SaveSealedRefreshToken. Other agent files load as usual.
+func LoadSealedAgentFile(filename string, k store.Keyring) (string, App, error) {
+	return loadSealed(filename, k)
+}
+
 // load loads the user agent and App config from an AgentFile (legacy graw 0.3.0
 // file format).
 func load(filename string) (string, App, error) {
-	agentPB, err := loadAgentFile(filename)
+	return loadSealed(filename, nil)
+}
+
+// loadSealed loads an agent file, decrypting its refresh token with k if it is
+// encrypted.
+func loadSealed(filename string, k store.Keyring) (string, App, error) {
+	agentPB, refreshToken, err := loadAgentFile(filename)
+	if agentPB == nil {
+		return "", App{}, err
+	}
+
+	if strings.HasPrefix(refreshToken, sealedPrefix) {
+		if k == nil {
+			return "", App{}, SealedTokenErr
+		}
+
+		var openErr error
+		refreshToken, openErr = openRefreshToken(refreshToken, k)
+		if openErr != nil {
+			return "", App{}, openErr
+		}
+	}
+
 	return agentPB.GetUserAgent(), App{
-		ID:       agentPB.GetClientId(),
-		Secret:   agentPB.GetClientSecret(),
-		Username: agentPB.GetUsername(),
-		Password: agentPB.GetPassword(),
+		ID:           agentPB.GetClientId(),
+		Secret:       agentPB.GetClientSecret(),
+		Username:     agentPB.GetUsername(),
+		Password:     agentPB.GetPassword(),
+		RefreshToken: refreshToken,
 	}, err
 }
 
-// loadAgentFile reads a user agent from a protobuffer file and returns it.
-func loadAgentFile(filename string) (*redditproto.UserAgent, error) {
+// loadAgentFile reads a user agent from a protobuffer file and returns it,
+// along with the refresh token stored in the file, if any.
+func loadAgentFile(filename string) (*redditproto.UserAgent, string, error) {
 	buf, err := ioutil.ReadFile(filename)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
+	text, refreshToken := splitRefreshToken(bytes.NewBuffer(buf).String())
+
 	agent := &redditproto.UserAgent{}
-	return agent, proto.UnmarshalText(bytes.NewBuffer(buf).String(), agent)
+	err = proto.UnmarshalText(text, agent)
+	// The legacy format requires a username and password, but apps which
+	// authorize with a refresh token, or have yet to obtain one, have
+	// neither.
+	if _, ok := err.(*proto.RequiredNotSetError); ok && identified(agent) {
+		err = nil
+	}
+
+	return agent, refreshToken, err
+}
+
+// identified returns whether an agent file has the fields needed to identify
+// the app.
+func identified(agent *redditproto.UserAgent) bool {
+	return agent.UserAgent != nil &&
+		agent.ClientId != nil &&
+		agent.ClientSecret != nil
+}
+
+// SaveRefreshToken stores an OAuth2 refresh token in an agent file, replacing
+// any refresh token already stored there. Bots built from the agent file will
+// authorize with the refresh token instead of the username and password.
+func SaveRefreshToken(filename, refreshToken string) error {
+	return saveRefreshToken(filename, refreshToken)
+}
+
+// SaveSealedRefreshToken is SaveRefreshToken for agent files on shared or less
+// trusted hosts; the refresh token is encrypted with k, and the agent file must
+// be loaded with LoadSealedAgentFile and the same key. The rest of the file is
+// left as it is, so prefer a refresh token to a stored password.
+func SaveSealedRefreshToken(filename, refreshToken string, k store.Keyring) error {
+	sealed, err := store.Seal(k, refreshTokenLabel, []byte(refreshToken))
+	if err != nil {
+		return err
+	}
+
+	return saveRefreshToken(
+		filename,
+		sealedPrefix+base64.StdEncoding.EncodeToString(sealed),
+	)
+}
+
+// openRefreshToken decrypts a refresh token saved by SaveSealedRefreshToken.
+func openRefreshToken(value string, k store.Keyring) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(
+		strings.TrimPrefix(value, sealedPrefix),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	refreshToken, err := store.Open(k, refreshTokenLabel, sealed)
+	return string(refreshToken), err
+}
+
+func saveRefreshToken(filename, refreshToken string) error {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+
+	buf, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	text, _ := splitRefreshToken(string(buf))
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	text += refreshTokenKey + " " + strconv.Quote(refreshToken) + "\n"
+
+	return ioutil.WriteFile(filename, []byte(text), info.Mode())
+}
+
+// splitRefreshToken separates refresh token lines from the rest of an agent
+// file, returning the remaining text and the last refresh token found.
+func splitRefreshToken(text string) (string, string) {
+	var rest []string
+	refreshToken := ""
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, refreshTokenKey) {
+			rest = append(rest, line)
+			continue
+		}
+
+		value := strings.TrimSpace(strings.TrimPrefix(trimmed, refreshTokenKey))
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			refreshToken = unquoted
+		}
+	}
+
+	return strings.Join(rest, "\n"), refreshToken
 }
@@ -2,8 +2,10 @@ package reddit
 
 import (
 	"io/ioutil"
+	"strings"
 	"testing"
 
+	"github.com/aldarisbm/graw/store"
 	"github.com/golang/protobuf/proto"
 	"github.com/turnage/redditproto"
 )
@@ -29,11 +31,11 @@ func TestLoad(t *testing.T) {
 		t.Errorf("failed to write test input file: %v", err)
 	}
 
-	if _, err := loadAgentFile("notarealfile"); err == nil {
+	if _, _, err := loadAgentFile("notarealfile"); err == nil {
 		t.Errorf("wanted error returned with nonexistent file as input")
 	}
 
-	actual, err := loadAgentFile(testFile.Name())
+	actual, _, err := loadAgentFile(testFile.Name())
 	if err != nil {
 		t.Errorf("failed: %v", err)
 	}
@@ -42,3 +44,85 @@ func TestLoad(t *testing.T) {
 		t.Errorf("got %v; wanted %v", actual, expected)
 	}
 }
+
+func TestSaveRefreshToken(t *testing.T) {
+	testFile, err := ioutil.TempFile("", "user_agent")
+	if err != nil {
+		t.Fatalf("failed to make test input file: %v", err)
+	}
+
+	if _, err := testFile.WriteString(`
+		user_agent: "test"
+		client_id: "id"
+		client_secret: "secret"
+	`); err != nil {
+		t.Fatalf("failed to write test input file: %v", err)
+	}
+
+	for _, token := range []string{"first", "second"} {
+		if err := SaveRefreshToken(testFile.Name(), token); err != nil {
+			t.Fatalf("failed to save refresh token: %v", err)
+		}
+
+		agent, app, err := load(testFile.Name())
+		if err != nil {
+			t.Fatalf("failed to load agent file: %v", err)
+		}
+
+		if agent != "test" || app.ID != "id" || app.Secret != "secret" {
+			t.Errorf("agent file fields lost: %s, %+v", agent, app)
+		}
+
+		if app.RefreshToken != token {
+			t.Errorf("got refresh token %q; wanted %q", app.RefreshToken, token)
+		}
+	}
+}
+
+func TestSaveSealedRefreshToken(t *testing.T) {
+	testFile, err := ioutil.TempFile("", "user_agent")
+	if err != nil {
+		t.Fatalf("failed to make test input file: %v", err)
+	}
+
+	if _, err := testFile.WriteString(`
+		user_agent: "test"
+		client_id: "id"
+		client_secret: "secret"
+	`); err != nil {
+		t.Fatalf("failed to write test input file: %v", err)
+	}
+
+	key := store.StaticKey([]byte("hunter2"))
+	if err := SaveSealedRefreshToken(testFile.Name(), "token", key); err != nil {
+		t.Fatalf("failed to save refresh token: %v", err)
+	}
+
+	buf, err := ioutil.ReadFile(testFile.Name())
+	if err != nil {
+		t.Fatalf("failed to read agent file: %v", err)
+	}
+	if strings.Contains(string(buf), `"token"`) {
+		t.Errorf("the refresh token was saved in the clear:\n%s", buf)
+	}
+
+	if _, _, err := load(testFile.Name()); err != SealedTokenErr {
+		t.Errorf("wanted SealedTokenErr loading without a key; got %v", err)
+	}
+
+	if _, _, err := LoadSealedAgentFile(
+		testFile.Name(),
+		store.StaticKey([]byte("wrong")),
+	); err == nil {
+		t.Errorf("wanted an error loading with the wrong key")
+	}
+
+	agent, app, err := LoadSealedAgentFile(testFile.Name(), key)
+	if err != nil {
+		t.Fatalf("failed to load agent file: %v", err)
+	}
+
+	if agent != "test" || app.RefreshToken != "token" {
+		t.Errorf("got %s, %+v; wanted the decrypted refresh token", agent, app)
+	}
+}
@@ -1,9 +1,61 @@
 package reddit
 
+import "strconv"
+
+// CommentSort is an order of a thread's comment tree.
+type CommentSort string
+
+const (
+	// SortBest sorts comments by the lower bound of their upvote ratio.
+	SortBest CommentSort = "confidence"
+	SortTop  CommentSort = "top"
+	SortNew  CommentSort = "new"
+	SortOld  CommentSort = "old"
+	// SortControversial sorts comments with many votes either way first.
+	SortControversial CommentSort = "controversial"
+	SortRandom        CommentSort = "random"
+	// SortQA sorts comments with replies from the post's author first.
+	SortQA CommentSort = "qa"
+	// SortLive shows new comments as they are posted. It is only offered
+	// as a suggested sort, for live discussion threads.
+	SortLive CommentSort = "live"
+)
+
+// ThreadOptions choose how much of a thread's comment tree is fetched, and
+// in what order.
+type ThreadOptions struct {
+	// Sort orders the comment tree. If empty, the thread's suggested sort
+	// or the account's preference is used.
+	Sort CommentSort
+	// Limit is the most comments to fetch. If zero, Reddit decides.
+	Limit int
+	// Depth is the deepest reply chain to fetch. If zero, Reddit decides.
+	Depth int
+	// Context is the number of parents to fetch of the comment at a
+	// comment permalink.
+	Context int
+}
+
 // Lurker defines browsing behavior.
 type Lurker interface {
 	// Thread returns a Reddit post with a fully parsed comment tree.
 	Thread(permalink string) (*Post, error)
+	// Context returns the Reddit post containing the comment at the given
+	// permalink, with a comment tree holding only that comment and up to
+	// context of its parent comments.
+	Context(permalink string, context int) (*Post, error)
+	// ThreadWithOptions returns a Reddit post with the part of its comment
+	// tree chosen by the options.
+	ThreadWithOptions(permalink string, opts ThreadOptions) (*Post, error)
+	// ExpandMore replaces a "more comments" stub in the thread's comment
+	// tree with the comments it stands for, expanding the stubs among
+	// them too, and returns the comments which took its place, with their
+	// replies attached.
+	ExpandMore(thread *Post, more *More) ([]*Comment, error)
+	// ExpandThread expands every stub in the thread's comment tree, so
+	// it holds every comment, making at most maxRequests requests if it is
+	// positive. Stubs left unexpanded stay in the tree.
+	ExpandThread(thread *Post, maxRequests int) error
 }
 
 type lurker struct {
@@ -15,10 +67,41 @@ func newLurker(r reaper) Lurker {
 }
 
 func (s *lurker) Thread(permalink string) (*Post, error) {
-	harvest, err := s.r.reap(
-		permalink+".json",
-		map[string]string{"raw_json": "1"},
+	return s.thread(permalink, map[string]string{"raw_json": "1"})
+}
+
+func (s *lurker) Context(permalink string, context int) (*Post, error) {
+	return s.thread(
+		permalink,
+		map[string]string{
+			"raw_json": "1",
+			"context":  strconv.Itoa(context),
+		},
 	)
+}
+
+func (s *lurker) ThreadWithOptions(
+	permalink string,
+	opts ThreadOptions,
+) (*Post, error) {
+	values := map[string]string{"raw_json": "1"}
+	if opts.Sort != "" {
+		values["sort"] = string(opts.Sort)
+	}
+	if opts.Limit > 0 {
+		values["limit"] = strconv.Itoa(opts.Limit)
+	}
+	if opts.Depth > 0 {
+		values["depth"] = strconv.Itoa(opts.Depth)
+	}
+	if opts.Context > 0 {
+		values["context"] = strconv.Itoa(opts.Context)
+	}
+	return s.thread(permalink, values)
+}
+
+func (s *lurker) thread(permalink string, values map[string]string) (*Post, error) {
+	harvest, err := s.r.reap(permalink+".json", values)
 	if err != nil {
 		return nil, err
 	}
@@ -33,3 +33,61 @@ func TestThreadReturnsEmpty(t *testing.T) {
 		t.Errorf("err unexpected; wanted DoesNotExistErr; got %v", err)
 	}
 }
+
+func TestThreadWithOptions(t *testing.T) {
+	r := reaperWhich(Harvest{Posts: []*Post{&Post{}}}, nil)
+	s := newLurker(r)
+
+	if _, err := s.ThreadWithOptions(
+		"/r/a/comments/b",
+		ThreadOptions{Sort: SortNew, Limit: 50},
+	); err != nil {
+		t.Fatalf("error pulling thread: %v", err)
+	}
+
+	expected := map[string]string{
+		"raw_json": "1",
+		"sort":     "new",
+		"limit":    "50",
+	}
+	if diff := pretty.Compare(r.values, expected); diff != "" {
+		t.Errorf("values incorrect; diff: %s", diff)
+	}
+}
+
+// countingLurker counts the threads it is asked for.
+type countingLurker struct {
+	Lurker
+	fetches int
+}
+
+func (c *countingLurker) ThreadWithOptions(
+	permalink string,
+	opts ThreadOptions,
+) (*Post, error) {
+	c.fetches++
+	return &Post{Permalink: permalink}, nil
+}
+
+func TestThreadCache(t *testing.T) {
+	l := &countingLurker{}
+	c := NewThreadCache(l, ThreadOptions{Sort: SortTop})
+
+	for _, permalink := range []string{"/r/a/comments/b/", "/r/a/comments/b"} {
+		if _, err := c.Thread(permalink); err != nil {
+			t.Fatalf("error pulling thread: %v", err)
+		}
+	}
+
+	if l.fetches != 1 {
+		t.Errorf("got %d fetches of one thread; wanted 1", l.fetches)
+	}
+
+	if _, err := c.Context("/r/a/comments/b", 3); err != nil {
+		t.Fatalf("error pulling context: %v", err)
+	}
+
+	if l.fetches != 2 {
+		t.Errorf("context was served from the thread's cache entry")
+	}
+}
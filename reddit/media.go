@@ -0,0 +1,292 @@
+package reddit
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// mediaTimeout is how long Reddit is given to process an uploaded video into
+// a post.
+const mediaTimeout = 5 * time.Minute
+
+var (
+	mediaTypeErr   = fmt.Errorf("the media type of the file is not known from its extension")
+	mediaFailedErr = fmt.Errorf("reddit failed to process the uploaded media")
+)
+
+// MediaFile is a file uploaded to Reddit's media storage.
+type MediaFile struct {
+	// Filename is the file's name, e.g. "chart.png". Its extension sets
+	// the file's media type.
+	Filename string
+	Content  io.Reader
+}
+
+// Upload is a file in Reddit's media storage.
+type Upload struct {
+	// AssetID identifies the file to Reddit, e.g. as an image of a
+	// gallery post.
+	AssetID string
+	// URL is where the file is served from.
+	URL string
+}
+
+// Markdown returns the markdown embedding the upload in a comment or self post
+// as an inline image, with an optional caption.
+func (m Upload) Markdown(caption string) string {
+	if caption == "" {
+		return "![img](" + m.AssetID + ")"
+	}
+	return "![img](" + m.AssetID + " \"" + strings.Replace(caption, "\"", "'", -1) + "\")"
+}
+
+// Style images are the images of a subreddit's style an UploadStyleImage
+// can be for.
+const (
+	BannerImage          = "bannerBackgroundImage"
+	BannerOverlayImage   = "bannerPositionedImage"
+	MobileBannerImage    = "mobileBannerImage"
+	SecondaryBannerImage = "secondaryBannerPositionedImage"
+)
+
+// MediaUploader defines behaviors for uploading files to Reddit's media
+// storage, which posts, comments, and subreddit styles refer to.
+type MediaUploader interface {
+	// UploadMedia uploads a file, e.g. an image to post in a gallery or
+	// to embed in a comment with Upload.Markdown.
+	UploadMedia(file MediaFile) (Upload, error)
+	// UploadStyleImage uploads an image for the subreddit's style, such
+	// as its BannerImage, and returns its url, to be set in the style.
+	UploadStyleImage(subreddit, image string, file MediaFile) (string, error)
+}
+
+type mediaUploader struct {
+	r reaper
+}
+
+func newMediaUploader(r reaper) MediaUploader {
+	return &mediaUploader{r: r}
+}
+
+func (m *mediaUploader) UploadMedia(file MediaFile) (Upload, error) {
+	lease, url, err := uploadMedia(m.r, file)
+	if err != nil {
+		return Upload{}, err
+	}
+	return Upload{AssetID: lease.Asset.ID, URL: url}, nil
+}
+
+// styleLease is Reddit's permission to upload an image of a subreddit's
+// style.
+type styleLease struct {
+	Lease struct {
+		Action string `mapstructure:"action"`
+		Fields []struct {
+			Name  string `mapstructure:"name"`
+			Value string `mapstructure:"value"`
+		} `mapstructure:"fields"`
+	} `mapstructure:"s3UploadLease"`
+}
+
+func (m *mediaUploader) UploadStyleImage(
+	subreddit, image string,
+	file MediaFile,
+) (string, error) {
+	t, err := mediaType(file.Filename)
+	if err != nil {
+		return "", err
+	}
+
+	var style styleLease
+	if err := m.r.sowInto(
+		"/api/v1/style_asset_upload_s3/"+subreddit,
+		map[string]string{
+			"filepath":  filepath.Base(file.Filename),
+			"mimetype":  t,
+			"imagetype": image,
+		},
+		&style,
+	); err != nil {
+		return "", err
+	}
+
+	var lease mediaLease
+	lease.Args.Action = style.Lease.Action
+	lease.Args.Fields = style.Lease.Fields
+	return m.r.upload(lease, file)
+}
+
+// mediaLease is Reddit's permission to upload a file to its media storage.
+type mediaLease struct {
+	Args struct {
+		// Action is the url the file is uploaded to, without a scheme.
+		Action string `mapstructure:"action"`
+		// Fields must be sent with the file, in order.
+		Fields []struct {
+			Name  string `mapstructure:"name"`
+			Value string `mapstructure:"value"`
+		} `mapstructure:"fields"`
+	} `mapstructure:"args"`
+	Asset struct {
+		ID           string `mapstructure:"asset_id"`
+		WebsocketURL string `mapstructure:"websocket_url"`
+	} `mapstructure:"asset"`
+}
+
+// mediaType returns the media type of a file from its extension.
+func mediaType(filename string) (string, error) {
+	t := mime.TypeByExtension(strings.ToLower(filepath.Ext(filename)))
+	if t == "" {
+		return "", mediaTypeErr
+	}
+	return strings.SplitN(t, ";", 2)[0], nil
+}
+
+// uploadMedia leases space for a file in Reddit's media storage and uploads
+// it there, returning the lease and the file's url.
+func uploadMedia(r reaper, file MediaFile) (mediaLease, string, error) {
+	t, err := mediaType(file.Filename)
+	if err != nil {
+		return mediaLease{}, "", err
+	}
+
+	var lease mediaLease
+	if err := r.sowInto(
+		"/api/media/asset.json",
+		map[string]string{
+			"filepath": filepath.Base(file.Filename),
+			"mimetype": t,
+		},
+		&lease,
+	); err != nil {
+		return mediaLease{}, "", err
+	}
+
+	url, err := r.upload(lease, file)
+	return lease, url, err
+}
+
+func (r *reaperImpl) upload(lease mediaLease, file MediaFile) (string, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	var key string
+	for _, field := range lease.Args.Fields {
+		if field.Name == "key" {
+			key = field.Value
+		}
+		if err := form.WriteField(field.Name, field.Value); err != nil {
+			return "", err
+		}
+	}
+
+	part, err := form.CreateFormFile("file", filepath.Base(file.Filename))
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(part, file.Content); err != nil {
+		return "", err
+	}
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	action := lease.Args.Action
+	if strings.HasPrefix(action, "//") {
+		action = "https:" + action
+	}
+
+	req, err := http.NewRequest("POST", action, &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req = req.WithContext(r.context())
+
+	cli := r.uploader
+	if cli == nil {
+		cli = http.DefaultClient
+	}
+	resp, err := cli.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("bad response code uploading media: %d", resp.StatusCode)
+	}
+
+	// The storage reports where it put the file, but recent responses may
+	// leave it out; the file's key locates it just as well.
+	var posted struct {
+		Location string `xml:"Location"`
+	}
+	if buf, err := ioutil.ReadAll(resp.Body); err == nil {
+		xml.Unmarshal(buf, &posted)
+	}
+	if posted.Location != "" {
+		return posted.Location, nil
+	}
+	return action + "/" + key, nil
+}
+
+// mediaEvent is a message on a media asset's websocket.
+type mediaEvent struct {
+	Type    string `json:"type"`
+	Payload struct {
+		Redirect string `json:"redirect"`
+	} `json:"payload"`
+}
+
+func (r *reaperImpl) awaitMedia(websocketURL string) (string, error) {
+	conn, err := websocket.Dial(websocketURL, "", "https://www.reddit.com")
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	ctx := r.context()
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(mediaTimeout)
+	}
+	conn.SetDeadline(deadline)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		var event mediaEvent
+		if err := websocket.JSON.Receive(conn, &event); err != nil {
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			return "", err
+		}
+
+		switch event.Type {
+		case "success":
+			return event.Payload.Redirect, nil
+		case "failed":
+			return "", mediaFailedErr
+		}
+	}
+}
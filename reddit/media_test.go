@@ -0,0 +1,41 @@
+package reddit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUploadMedia(t *testing.T) {
+	storage, announcer := mediaServers(t)
+	defer storage.Close()
+	defer announcer.Close()
+	r, c := mediaReaper(storage, announcer)
+	m := newMediaUploader(r)
+
+	upload, err := m.UploadMedia(MediaFile{Filename: "chart.PNG", Content: strings.NewReader("png")})
+	if err != nil {
+		t.Fatalf("failed to upload: %v", err)
+	}
+
+	if upload.AssetID != "abc" || upload.URL != storage.URL+"/media/abc" {
+		t.Errorf("got upload %+v", upload)
+	}
+	if upload.Markdown(`a "chart"`) != `![img](abc "a 'chart'")` {
+		t.Errorf("got markdown %s", upload.Markdown(`a "chart"`))
+	}
+
+	url, err := m.UploadStyleImage("sub", BannerImage, MediaFile{Filename: "banner.jpg", Content: strings.NewReader("jpg")})
+	if err != nil {
+		t.Fatalf("failed to upload style image: %v", err)
+	}
+	if url != storage.URL+"/style/banner" {
+		t.Errorf("got style image url %s", url)
+	}
+	if body := c.bodies["/api/v1/style_asset_upload_s3/sub"][0]; !strings.Contains(body, "imagetype=bannerBackgroundImage") {
+		t.Errorf("leased style image with %s", body)
+	}
+
+	if _, err := m.UploadMedia(MediaFile{Filename: "notes"}); err != mediaTypeErr {
+		t.Errorf("wanted files of unknown type rejected; got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package reddit
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MetricsCollector takes the measurements of a handle's requests and of the
+// streams reading through it, e.g. to export them to Prometheus and monitor a
+// fleet of bots. Set it as the Metrics of a BotConfig or ScriptConfig. Its
+// methods are called as requests are made, so they must be quick and safe to
+// call concurrently.
+type MetricsCollector interface {
+	// CountRequest counts a request to an endpoint, as named by Endpoint,
+	// which Reddit answered with the status code, or 0 if the request
+	// failed without an answer.
+	CountRequest(endpoint string, status int)
+	// ObserveLatency records how long a request to an endpoint took.
+	ObserveLatency(endpoint string, latency time.Duration)
+	// SetRateLimitRemaining records how many requests Reddit reported are
+	// left before the rate limit resets.
+	SetRateLimitRemaining(remaining float64)
+	// CountDropped counts events a stream dropped without delivering, by
+	// the name of the stream, such as "thresholds".
+	CountDropped(stream string, n int)
+}
+
+// endpointNames are the path segments followed by a name, which endpoints are grouped
+// without. The names after those marked true run to the end of the path, like
+// a thread's ID, title, and comment, or a wiki page's name.
+var endpointNames = map[string]bool{
+	"r":             false,
+	"user":          false,
+	"u":             false,
+	"conversations": false,
+	"by_id":         false,
+	"comments":      true,
+	"wiki":          true,
+}
+
+// Endpoint names the endpoint of a request, by its method and path with the
+// names of subreddits, users, threads, and the like replaced by *, e.g.
+// "GET /r/*/new", so the requests of a bot can be grouped by what they do.
+func Endpoint(req *http.Request) string {
+	path := ""
+	if req.URL != nil {
+		path = strings.TrimSuffix(req.URL.Path, ".json")
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i < len(segments)-1; i++ {
+		toEnd, ok := endpointNames[segments[i]]
+		if !ok {
+			continue
+		}
+		if toEnd {
+			segments = append(segments[:i+1], "*")
+			break
+		}
+		segments[i+1] = "*"
+		i++
+	}
+	return req.Method + " /" + strings.Join(segments, "/")
+}
@@ -0,0 +1,98 @@
+package reddit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEndpoint(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/r/golang+rust/new.json":           "GET /r/*/new",
+		"/r/sub/comments/abc/title/def":     "GET /r/*/comments/*",
+		"/r/sub/comments":                   "GET /r/*/comments",
+		"/api/v1/user/someone/trophies":     "GET /api/v1/user/*/trophies",
+		"/r/sub/wiki/revisions/config.json": "GET /r/*/wiki/*",
+		"/api/mod/conversations/1a/archive": "GET /api/mod/conversations/*/archive",
+		"/api/info":                         "GET /api/info",
+	} {
+		req := &http.Request{Method: "GET", URL: &url.URL{Path: path}}
+		if got := Endpoint(req); got != expected {
+			t.Errorf("%s: got %q; wanted %q", path, got, expected)
+		}
+	}
+}
+
+// countingMetrics counts the measurements reported to it.
+type countingMetrics struct {
+	mu        sync.Mutex
+	requests  map[string]int
+	latencies int
+	remaining float64
+	dropped   map[string]int
+}
+
+func newCountingMetrics() *countingMetrics {
+	return &countingMetrics{
+		requests: make(map[string]int),
+		dropped:  make(map[string]int),
+	}
+}
+
+func (c *countingMetrics) CountRequest(endpoint string, status int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests[endpoint+" "+http.StatusText(status)]++
+}
+
+func (c *countingMetrics) ObserveLatency(endpoint string, latency time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.latencies++
+}
+
+func (c *countingMetrics) SetRateLimitRemaining(remaining float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remaining = remaining
+}
+
+func (c *countingMetrics) CountDropped(stream string, n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dropped[stream] += n
+}
+
+func TestDoMetrics(t *testing.T) {
+	serv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Ratelimit-Remaining", "41.0")
+				if r.URL.Path == "/r/golang/about.json" {
+					w.WriteHeader(http.StatusNotFound)
+				}
+			},
+		),
+	)
+	defer serv.Close()
+
+	m := newCountingMetrics()
+	r := &baseClient{cli: &http.Client{}, metrics: m}
+	for _, path := range []string{"/r/golang/new.json", "/r/rust/new.json", "/r/golang/about.json"} {
+		req, err := http.NewRequest("GET", serv.URL+path, nil)
+		if err != nil {
+			t.Fatalf("failed to prepare request for test: %v", err)
+		}
+		r.Do(req)
+	}
+
+	if m.requests["GET /r/*/new OK"] != 2 || m.requests["GET /r/*/about Not Found"] != 1 {
+		t.Errorf("got requests %v", m.requests)
+	}
+	if m.latencies != 3 || m.remaining != 41 {
+		t.Errorf("got %d latencies and %v remaining", m.latencies, m.remaining)
+	}
+}
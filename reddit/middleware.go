@@ -0,0 +1,67 @@
+package reddit
+
+import "net/http"
+
+// Middleware wraps the client a Bot or Script sends its requests through, e.g.
+// to set custom headers, log an audit trail, sign requests, or break the
+// circuit while Reddit is down, without forking the package. Middleware calls
+// next to pass a request on, or answers it itself.
+type Middleware func(next Doer) Doer
+
+// DoerFunc adapts a function to a Doer, as http.HandlerFunc does for handlers,
+// so middleware can be written inline:
+//
+//	func audit(next reddit.Doer) reddit.Doer {
+//		return reddit.DoerFunc(func(req *http.Request) ([]byte, error) {
+//			log.Printf("%s %s", req.Method, req.URL.Path)
+//			return next.Do(req)
+//		})
+//	}
+type DoerFunc func(req *http.Request) ([]byte, error)
+
+// Do calls f(req).
+func (f DoerFunc) Do(req *http.Request) ([]byte, error) {
+	return f(req)
+}
+
+// Chain returns middleware which runs the given middleware in order: the first
+// sees each request first, and its response last. It suits the WrapClient field
+// of BotConfig and ScriptConfig, to combine wrappers such as a health monitor
+// and a fault injector.
+func Chain(middleware ...Middleware) Middleware {
+	return func(next Doer) Doer {
+		for i := len(middleware) - 1; i >= 0; i-- {
+			next = middleware[i](next)
+		}
+		return next
+	}
+}
+
+// Header returns middleware which sets a header on every request.
+func Header(key, value string) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) ([]byte, error) {
+			if req.Header == nil {
+				req.Header = make(http.Header)
+			}
+			req.Header.Set(key, value)
+			return next.Do(req)
+		})
+	}
+}
+
+// wrapClient wraps cli with the middleware, then with wrap, if they are set.
+func wrapClient(cli client, wrap func(Doer) Doer, middleware []Middleware) client {
+	if cli == nil {
+		return cli
+	}
+
+	var d Doer = cli
+	if len(middleware) > 0 {
+		d = Chain(middleware...)(d)
+	}
+	if wrap != nil {
+		d = wrap(d)
+	}
+	return d
+}
@@ -0,0 +1,72 @@
+package reddit
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// tag returns middleware which appends its name to the trail as requests
+// pass through it, before and after the rest of the chain.
+func tag(name string, trail *[]string) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) ([]byte, error) {
+			*trail = append(*trail, name)
+			body, err := next.Do(req)
+			*trail = append(*trail, "/"+name)
+			return body, err
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	var trail []string
+	d := Chain(tag("a", &trail), tag("b", &trail))(DoerFunc(
+		func(req *http.Request) ([]byte, error) {
+			trail = append(trail, "client")
+			return nil, nil
+		},
+	))
+
+	if _, err := d.Do(&http.Request{}); err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	expected := []string{"a", "b", "client", "/b", "/a"}
+	if !reflect.DeepEqual(trail, expected) {
+		t.Errorf("got trail %v; wanted %v", trail, expected)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var trail []string
+	d := &recordingDoer{}
+	s, err := NewScriptFromConfig(ScriptConfig{
+		Agent: "graw test",
+		Middleware: []Middleware{
+			tag("middleware", &trail),
+			Header("X-Bot-Instance", "7"),
+			func(next Doer) Doer {
+				return DoerFunc(func(req *http.Request) ([]byte, error) {
+					if h := req.Header.Get("X-Bot-Instance"); h != "7" {
+						t.Errorf("got header %q; wanted 7", h)
+					}
+					return d.Do(req)
+				})
+			},
+		},
+		WrapClient: tag("wrap", &trail),
+	})
+	if err != nil {
+		t.Fatalf("failed to make script: %v", err)
+	}
+
+	if _, err := s.Listing("/r/golang", ""); err != nil {
+		t.Fatalf("failed to read listing: %v", err)
+	}
+
+	expected := []string{"wrap", "middleware", "/middleware", "/wrap"}
+	if !reflect.DeepEqual(trail, expected) || len(d.paths) != 1 {
+		t.Errorf("got trail %v and requests %v", trail, d.paths)
+	}
+}
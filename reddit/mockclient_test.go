@@ -1,15 +1,39 @@
 package reddit
 
 import (
+	"io/ioutil"
 	"net/http"
+	"strconv"
 )
 
 // mockClient stores the request it receives.
 type mockClient struct {
 	request *http.Request
+	// body is the consumed body of the most recent request, if it had one.
+	body string
+	// resp is returned as the response to every request.
+	resp []byte
 }
 
 func (m *mockClient) Do(r *http.Request) ([]byte, error) {
+	m.body = ""
+	if r.Body != nil {
+		buf, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		m.body = string(buf)
+		r.Body = nil
+	}
 	m.request = r
-	return nil, nil
+	return m.resp, nil
+}
+
+// formHeader returns the headers expected on a form encoded POST request with
+// the given body.
+func formHeader(body string) http.Header {
+	return http.Header{
+		"Content-Type":   []string{"application/x-www-form-urlencoded"},
+		"Content-Length": []string{strconv.Itoa(len(body))},
+	}
 }
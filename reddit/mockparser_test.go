@@ -24,6 +24,10 @@ func (m *mockParser) parse_submitted(
 	return m.submission, nil
 }
 
+func (m *mockParser) decode(blob json.RawMessage, v interface{}) error {
+	return nil
+}
+
 func parserWhich(h Harvest) parser {
 	return &mockParser{
 		comments: h.Comments,
@@ -1,21 +1,61 @@
 package reddit
 
+import (
+	"context"
+
+	"github.com/mitchellh/mapstructure"
+)
+
 // mockReaper saves the paths it is sent and returns preconfigured results.
 type mockReaper struct {
 	// path is the path received by the most recent Reap or Sow call.
 	path string
+	// values are the values received by the most recent reap or sow call.
+	values map[string]string
 
-	h   Harvest
-	s   Submission
-	err error
+	h    Harvest
+	info ListingInfo
+	s    Submission
+	err  error
+	// raw is decoded into the values given to reapInto.
+	raw interface{}
 }
 
-func (m *mockReaper) reap(path string, _ map[string]string) (Harvest, error) {
+func (m *mockReaper) reap(path string, values map[string]string) (Harvest, error) {
 	m.path = path
+	m.values = values
 	return m.h, m.err
 }
 
-func (m *mockReaper) sow(path string, _ map[string]string) error {
+func (m *mockReaper) reapPage(path string, values map[string]string) (
+	Harvest,
+	ListingInfo,
+	error,
+) {
+	m.path = path
+	m.values = values
+	return m.h, m.info, m.err
+}
+
+func (m *mockReaper) reapInto(
+	path string,
+	_ map[string]string,
+	v interface{},
+) error {
+	m.path = path
+	if m.err != nil {
+		return m.err
+	}
+	return mapstructure.Decode(m.raw, v)
+}
+
+func (m *mockReaper) sow(path string, values map[string]string) error {
+	m.path = path
+	m.values = values
+	return m.err
+}
+
+func (m *mockReaper) uproot(path string, _ map[string]string) error {
 	m.path = path
 	return m.err
 }
@@ -25,9 +65,42 @@ func (m *mockReaper) get_sow(path string, _ map[string]string) (Submission, erro
 	return m.s, m.err
 }
 
+func (m *mockReaper) sowInto(
+	path string,
+	_ map[string]string,
+	v interface{},
+) error {
+	m.path = path
+	if m.err != nil {
+		return m.err
+	}
+	return mapstructure.Decode(m.raw, v)
+}
+
+func (m *mockReaper) sowJSON(path string, _ interface{}) (Submission, error) {
+	m.path = path
+	return m.s, m.err
+}
+
+func (m *mockReaper) upload(_ mediaLease, _ MediaFile) (string, error) {
+	return "", m.err
+}
+
+func (m *mockReaper) awaitMedia(_ string) (string, error) {
+	return "", m.err
+}
+
+func (m *mockReaper) listen(_, _ string) (LiveStream, error) {
+	return nil, m.err
+}
+
 func reaperWhich(h Harvest, err error) *mockReaper {
 	return &mockReaper{
 		h:   h,
 		err: err,
 	}
 }
+
+func (m *mockReaper) withContext(ctx context.Context) reaper {
+	return m
+}
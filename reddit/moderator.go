@@ -0,0 +1,325 @@
+package reddit
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ModAction is an entry in a subreddit's moderation log.
+type ModAction struct {
+	ID         string `mapstructure:"id"`
+	Action     string `mapstructure:"action"`
+	Moderator  string `mapstructure:"mod"`
+	Subreddit  string `mapstructure:"subreddit"`
+	CreatedUTC uint64 `mapstructure:"created_utc"`
+
+	TargetAuthor    string `mapstructure:"target_author"`
+	TargetFullname  string `mapstructure:"target_fullname"`
+	TargetPermalink string `mapstructure:"target_permalink"`
+	TargetTitle     string `mapstructure:"target_title"`
+	TargetBody      string `mapstructure:"target_body"`
+
+	// Details and Description depend on the action. For bans, Details is
+	// the duration, e.g. "7 days" or "permanent", and Description is the
+	// note the moderator left.
+	Details     string `mapstructure:"details"`
+	Description string `mapstructure:"description"`
+}
+
+// ModLogPage is a page of a subreddit's moderation log, newest first.
+type ModLogPage struct {
+	Actions []*ModAction
+	// After is the token for the next, older, page. It is empty on the
+	// last page.
+	After string
+}
+
+// Ban describes a ban from a subreddit.
+type Ban struct {
+	// Days is how long the ban lasts, up to 999. If zero, it is permanent.
+	Days int
+	// Reason is the rule broken, shown in the moderation log.
+	Reason string
+	// Note is a note for the other moderators.
+	Note string
+	// Message, if set, is sent to the user with the ban.
+	Message string
+}
+
+// Distinctions are the ways a moderator can Distinguish their post or comment.
+const (
+	// DistinguishModerator marks the content as a moderator's.
+	DistinguishModerator = "yes"
+	// DistinguishAdmin marks the content as an admin's. Only admins may
+	// use it.
+	DistinguishAdmin = "admin"
+	// DistinguishSpecial marks the content with a special distinction,
+	// which only some accounts may use.
+	DistinguishSpecial = "special"
+	// Undistinguish removes the content's distinction.
+	Undistinguish = "no"
+)
+
+// Moderator defines behaviors only a moderator of a subreddit can perform.
+type Moderator interface {
+	// ModLog returns a page of the subreddit's moderation log. Useful
+	// params are "type" to filter by action, e.g. "banuser", "mod" to
+	// filter by moderator, "limit", and "after" to page back.
+	ModLog(subreddit string, params map[string]string) (ModLogPage, error)
+	// BanUser bans a user from the subreddit.
+	BanUser(subreddit, user string, ban Ban) error
+	// Unban lifts a user's ban from the subreddit.
+	Unban(subreddit, user string) error
+	// BannedUsers returns the names of every user banned from the
+	// subreddit. It costs a request per hundred bans.
+	BannedUsers(subreddit string) ([]string, error)
+	// Banned reports whether a user is banned from the subreddit.
+	Banned(subreddit, user string) (bool, error)
+	// UserFlair returns a user's flair in the subreddit, which is empty
+	// if they have none.
+	UserFlair(subreddit, user string) (Flair, error)
+	// LinkFlairTemplates and UserFlairTemplates return the subreddit's
+	// templates of post and user flair.
+	LinkFlairTemplates(subreddit string) ([]FlairTemplate, error)
+	UserFlairTemplates(subreddit string) ([]FlairTemplate, error)
+	// SetLinkFlair assigns flair to a post in the subreddit, by its
+	// fullname, and SetUserFlair assigns flair to a user.
+	SetLinkFlair(subreddit, post string, flair FlairChoice) error
+	SetUserFlair(subreddit, user string, flair FlairChoice) error
+	// SetFlairCSV assigns the flair of many users of the subreddit,
+	// clearing it for flairs with neither text nor a CSS class. It costs a
+	// request per hundred flairs, and returns Reddit's result of setting
+	// each, in order.
+	SetFlairCSV(subreddit string, flairs []Flair) ([]FlairResult, error)
+	// IsModerator reports whether a user moderates the subreddit.
+	IsModerator(subreddit, user string) (bool, error)
+	// Muted reports whether a user is muted from messaging the
+	// subreddit's moderators.
+	Muted(subreddit, user string) (bool, error)
+	// Remove removes a post or comment, by its fullname, from the
+	// subreddit. If spam is set, it is also marked as spam, training the
+	// spam filter.
+	Remove(name string, spam bool) error
+	// Approve approves a post or comment, restoring it if it was removed
+	// and clearing its reports.
+	Approve(name string) error
+	// Lock locks a post or comment so nobody but moderators can reply to
+	// it.
+	Lock(name string) error
+	// Unlock lifts the lock of a post or comment.
+	Unlock(name string) error
+	// Sticky pins a post to the top of its subreddit, or unpins it.
+	Sticky(post string, on bool) error
+	// Distinguish marks a post or comment of the bot's, e.g. with
+	// DistinguishModerator, or removes its mark with Undistinguish.
+	Distinguish(name, how string) error
+	// IgnoreReports stops or resumes notifying moderators of new reports
+	// of a post or comment.
+	IgnoreReports(name string, on bool) error
+	// SetContestMode turns contest mode on or off for a post. In contest
+	// mode, top level comments are shown in random order with their scores
+	// hidden, so early entries have no advantage.
+	SetContestMode(post string, on bool) error
+	// SetSuggestedSort sets the sort a post's comments are shown in by
+	// default, e.g. SortLive for a live discussion thread, or clears it if
+	// sort is empty.
+	SetSuggestedSort(post string, sort CommentSort) error
+	// SetDefaultSort sets the sort the comments of the subreddit's posts
+	// are shown in when they have no suggested sort of their own, or
+	// clears it if sort is empty. It costs a request to read the
+	// subreddit's settings, since Reddit only takes them all at once, and
+	// fails without changing anything if one of them is of a kind it
+	// cannot send back.
+	SetDefaultSort(subreddit string, sort CommentSort) error
+}
+
+type moderator struct {
+	r reaper
+}
+
+func newModerator(r reaper) Moderator {
+	return &moderator{r: r}
+}
+
+// modLogResponse is the shape of Reddit's moderation log listings.
+type modLogResponse struct {
+	Data struct {
+		After    string `mapstructure:"after"`
+		Children []struct {
+			Data ModAction `mapstructure:"data"`
+		} `mapstructure:"children"`
+	} `mapstructure:"data"`
+}
+
+func (m *moderator) ModLog(
+	subreddit string,
+	params map[string]string,
+) (ModLogPage, error) {
+	values := map[string]string{"raw_json": "1"}
+	for k, v := range params {
+		values[k] = v
+	}
+
+	resp := &modLogResponse{}
+	if err := m.r.reapInto("/r/"+subreddit+"/about/log", values, resp); err != nil {
+		return ModLogPage{}, err
+	}
+
+	page := ModLogPage{After: resp.Data.After}
+	for _, c := range resp.Data.Children {
+		action := c.Data
+		page.Actions = append(page.Actions, &action)
+	}
+	return page, nil
+}
+
+// relationshipResponse is the shape of Reddit's listings of the users banned,
+// muted, or otherwise related to a subreddit.
+type relationshipResponse struct {
+	Data struct {
+		After    string `mapstructure:"after"`
+		Children []struct {
+			Name string `mapstructure:"name"`
+		} `mapstructure:"children"`
+	} `mapstructure:"data"`
+}
+
+func (m *moderator) Banned(subreddit, user string) (bool, error) {
+	return m.related(subreddit, "banned", user)
+}
+
+func (m *moderator) IsModerator(subreddit, user string) (bool, error) {
+	return m.related(subreddit, "moderators", user)
+}
+
+func (m *moderator) Muted(subreddit, user string) (bool, error) {
+	return m.related(subreddit, "muted", user)
+}
+
+// related reports whether the user is in the subreddit's listing of users of
+// the relationship, e.g. "banned".
+func (m *moderator) related(subreddit, relationship, user string) (bool, error) {
+	resp := &relationshipResponse{}
+	if err := m.r.reapInto(
+		"/r/"+subreddit+"/about/"+relationship,
+		map[string]string{"user": user},
+		resp,
+	); err != nil {
+		return false, err
+	}
+
+	for _, c := range resp.Data.Children {
+		if strings.EqualFold(c.Name, user) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *moderator) BanUser(subreddit, user string, ban Ban) error {
+	values := map[string]string{
+		"type": "banned",
+		"name": user,
+	}
+	if ban.Days > 0 {
+		values["duration"] = strconv.Itoa(ban.Days)
+	}
+	if ban.Reason != "" {
+		values["ban_reason"] = ban.Reason
+	}
+	if ban.Note != "" {
+		values["note"] = ban.Note
+	}
+	if ban.Message != "" {
+		values["ban_message"] = ban.Message
+	}
+	return m.r.sow("/r/"+subreddit+"/api/friend", values)
+}
+
+func (m *moderator) BannedUsers(subreddit string) ([]string, error) {
+	var users []string
+	params := map[string]string{"limit": "100"}
+	for {
+		resp := &relationshipResponse{}
+		if err := m.r.reapInto("/r/"+subreddit+"/about/banned", params, resp); err != nil {
+			return nil, err
+		}
+
+		for _, c := range resp.Data.Children {
+			users = append(users, c.Name)
+		}
+
+		if resp.Data.After == "" {
+			return users, nil
+		}
+		params = map[string]string{"limit": "100", "after": resp.Data.After}
+	}
+}
+
+func (m *moderator) Unban(subreddit, user string) error {
+	return m.r.sow(
+		"/r/"+subreddit+"/api/unfriend", map[string]string{
+			"type": "banned",
+			"name": user,
+		},
+	)
+}
+
+func (m *moderator) SetContestMode(post string, on bool) error {
+	return m.r.sow(
+		"/api/set_contest_mode", map[string]string{
+			"id":       post,
+			"state":    strconv.FormatBool(on),
+			"api_type": "json",
+		},
+	)
+}
+
+func (m *moderator) Remove(name string, spam bool) error {
+	return m.r.sow(
+		"/api/remove", map[string]string{
+			"id":   name,
+			"spam": strconv.FormatBool(spam),
+		},
+	)
+}
+
+func (m *moderator) Approve(name string) error {
+	return m.r.sow("/api/approve", map[string]string{"id": name})
+}
+
+func (m *moderator) Lock(name string) error {
+	return m.r.sow("/api/lock", map[string]string{"id": name})
+}
+
+func (m *moderator) Unlock(name string) error {
+	return m.r.sow("/api/unlock", map[string]string{"id": name})
+}
+
+func (m *moderator) Sticky(post string, on bool) error {
+	return m.r.sow(
+		"/api/set_subreddit_sticky", map[string]string{
+			"id":       post,
+			"state":    strconv.FormatBool(on),
+			"api_type": "json",
+		},
+	)
+}
+
+func (m *moderator) Distinguish(name, how string) error {
+	return m.r.sow(
+		"/api/distinguish", map[string]string{
+			"id":       name,
+			"how":      how,
+			"api_type": "json",
+		},
+	)
+}
+
+func (m *moderator) IgnoreReports(name string, on bool) error {
+	path := "/api/unignore_reports"
+	if on {
+		path = "/api/ignore_reports"
+	}
+	return m.r.sow(path, map[string]string{"id": name})
+}
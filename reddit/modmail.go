@@ -0,0 +1,313 @@
+package reddit
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Conversation states, which ModmailQuery.State filters by.
+const (
+	ModmailAll         = "all"
+	ModmailNew         = "new"
+	ModmailInProgress  = "inprogress"
+	ModmailArchived    = "archived"
+	ModmailHighlighted = "highlighted"
+	// ModmailMod holds the conversations among moderators.
+	ModmailMod = "mod"
+	// ModmailNotifications holds Reddit's automatic notifications.
+	ModmailNotifications = "notifications"
+)
+
+// ModmailConversation is a conversation in the new modmail between a
+// subreddit's moderators and a user, or among the moderators.
+type ModmailConversation struct {
+	ID        string
+	Subject   string
+	Subreddit string
+	// Participant is the user the moderators are talking to. It is empty
+	// in conversations among moderators.
+	Participant string
+	// Internal is set on conversations among moderators, Highlighted on
+	// those a moderator highlighted, and Auto on Reddit's notifications.
+	Internal    bool
+	Highlighted bool
+	Auto        bool
+	NumMessages int
+	// LastUpdated is when anyone last wrote in the conversation, and
+	// LastUserUpdate and LastModUpdate when the participant and the
+	// moderators last did. They are zero if nobody did.
+	LastUpdated    time.Time
+	LastUserUpdate time.Time
+	LastModUpdate  time.Time
+	// Messages are the messages of the conversation, oldest first. Lists
+	// of conversations hold only the newest message of each.
+	Messages []*ModmailMessage
+}
+
+// ModmailMessage is a message in a modmail conversation.
+type ModmailMessage struct {
+	ID     string
+	Author string
+	// AuthorIsMod is set on moderators' messages, and AuthorHidden on
+	// those sent as the subreddit instead of as the moderator.
+	AuthorIsMod  bool
+	AuthorHidden bool
+	// Body is the message's markdown, and BodyHTML its rendering.
+	Body     string
+	BodyHTML string
+	// Internal is set on private moderator notes.
+	Internal bool
+	Date     time.Time
+}
+
+// ModmailQuery selects the modmail conversations to list.
+type ModmailQuery struct {
+	// Subreddits are the subreddits whose conversations are listed. If
+	// empty, those of every subreddit the bot moderates are.
+	Subreddits []string
+	// State is e.g. ModmailNew. If empty, ModmailAll is used.
+	State string
+	// Sort is "recent", "mod", "user", or "unread". If empty, Reddit
+	// sorts by recent activity.
+	Sort string
+	// Limit is the most conversations listed, up to 100.
+	Limit int
+	// After is the ID of the conversation to list from, e.g. the After of
+	// the previous page.
+	After string
+}
+
+// ModmailPage is a page of modmail conversations.
+type ModmailPage struct {
+	Conversations []*ModmailConversation
+	// After is the query's After for the next page. It is empty when the
+	// page is not full.
+	After string
+}
+
+// Modmail defines behaviors for moderating subreddits through the new
+// modmail.
+type Modmail interface {
+	// Conversations lists modmail conversations, most recently active
+	// first unless the query sorts them otherwise.
+	Conversations(q ModmailQuery) (ModmailPage, error)
+	// Conversation returns a modmail conversation with every message in
+	// it.
+	Conversation(id string) (*ModmailConversation, error)
+	// ReplyModmail replies to a modmail conversation. Internal replies are
+	// private notes, which only the moderators see.
+	ReplyModmail(id, text string, internal bool) error
+	// ArchiveModmail archives a modmail conversation, and UnarchiveModmail
+	// returns it to the inbox.
+	ArchiveModmail(id string) error
+	UnarchiveModmail(id string) error
+	// HighlightModmail highlights a modmail conversation, or lifts its
+	// highlight.
+	HighlightModmail(id string, on bool) error
+	// MuteModmail mutes the participant of a modmail conversation for 3,
+	// 7, or 28 days, so they can't message the moderators, and
+	// UnmuteModmail lifts their mute.
+	MuteModmail(id string, days int) error
+	UnmuteModmail(id string) error
+}
+
+type modmail struct {
+	r reaper
+}
+
+func newModmail(r reaper) Modmail {
+	return &modmail{r: r}
+}
+
+// modmailAuthor is the shape of the authors of modmail messages.
+type modmailAuthor struct {
+	Name     string `mapstructure:"name"`
+	IsMod    bool   `mapstructure:"isMod"`
+	IsHidden bool   `mapstructure:"isHidden"`
+}
+
+// rawModmailMessage is the shape of a modmail message.
+type rawModmailMessage struct {
+	ID           string        `mapstructure:"id"`
+	Author       modmailAuthor `mapstructure:"author"`
+	Body         string        `mapstructure:"body"`
+	BodyMarkdown string        `mapstructure:"bodyMarkdown"`
+	IsInternal   bool          `mapstructure:"isInternal"`
+	Date         string        `mapstructure:"date"`
+}
+
+// rawModmailConversation is the shape of a modmail conversation.
+type rawModmailConversation struct {
+	ID             string `mapstructure:"id"`
+	Subject        string `mapstructure:"subject"`
+	IsInternal     bool   `mapstructure:"isInternal"`
+	IsHighlighted  bool   `mapstructure:"isHighlighted"`
+	IsAuto         bool   `mapstructure:"isAuto"`
+	NumMessages    int    `mapstructure:"numMessages"`
+	LastUpdated    string `mapstructure:"lastUpdated"`
+	LastUserUpdate string `mapstructure:"lastUserUpdate"`
+	LastModUpdate  string `mapstructure:"lastModUpdate"`
+	Owner          struct {
+		DisplayName string `mapstructure:"displayName"`
+	} `mapstructure:"owner"`
+	Participant struct {
+		Name string `mapstructure:"name"`
+	} `mapstructure:"participant"`
+	// ObjIDs lists the conversation's messages and moderator actions in
+	// order.
+	ObjIDs []struct {
+		ID  string `mapstructure:"id"`
+		Key string `mapstructure:"key"`
+	} `mapstructure:"objIds"`
+}
+
+// modmailTime parses the timestamps of modmail, which are empty or null for
+// things which never happened.
+func modmailTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// conversation builds a conversation from its shape and the messages of the
+// response it came in.
+func (raw *rawModmailConversation) conversation(
+	messages map[string]rawModmailMessage,
+) *ModmailConversation {
+	c := &ModmailConversation{
+		ID:             raw.ID,
+		Subject:        raw.Subject,
+		Subreddit:      raw.Owner.DisplayName,
+		Participant:    raw.Participant.Name,
+		Internal:       raw.IsInternal,
+		Highlighted:    raw.IsHighlighted,
+		Auto:           raw.IsAuto,
+		NumMessages:    raw.NumMessages,
+		LastUpdated:    modmailTime(raw.LastUpdated),
+		LastUserUpdate: modmailTime(raw.LastUserUpdate),
+		LastModUpdate:  modmailTime(raw.LastModUpdate),
+	}
+
+	for _, obj := range raw.ObjIDs {
+		m, ok := messages[obj.ID]
+		if obj.Key != "messages" || !ok {
+			continue
+		}
+		c.Messages = append(c.Messages, &ModmailMessage{
+			ID:           m.ID,
+			Author:       m.Author.Name,
+			AuthorIsMod:  m.Author.IsMod,
+			AuthorHidden: m.Author.IsHidden,
+			Body:         m.BodyMarkdown,
+			BodyHTML:     m.Body,
+			Internal:     m.IsInternal,
+			Date:         modmailTime(m.Date),
+		})
+	}
+	return c
+}
+
+// conversationsResponse is the shape of Reddit's lists of modmail
+// conversations.
+type conversationsResponse struct {
+	Conversations   map[string]rawModmailConversation `mapstructure:"conversations"`
+	ConversationIDs []string                          `mapstructure:"conversationIds"`
+	Messages        map[string]rawModmailMessage      `mapstructure:"messages"`
+}
+
+func (m *modmail) Conversations(q ModmailQuery) (ModmailPage, error) {
+	values := map[string]string{"state": ModmailAll}
+	if q.State != "" {
+		values["state"] = q.State
+	}
+	if len(q.Subreddits) > 0 {
+		values["entity"] = strings.Join(q.Subreddits, ",")
+	}
+	if q.Sort != "" {
+		values["sort"] = q.Sort
+	}
+	if q.Limit > 0 {
+		values["limit"] = strconv.Itoa(q.Limit)
+	}
+	if q.After != "" {
+		values["after"] = q.After
+	}
+
+	resp := &conversationsResponse{}
+	if err := m.r.reapInto("/api/mod/conversations", values, resp); err != nil {
+		return ModmailPage{}, err
+	}
+
+	var page ModmailPage
+	for _, id := range resp.ConversationIDs {
+		raw, ok := resp.Conversations[id]
+		if !ok {
+			continue
+		}
+		page.Conversations = append(page.Conversations, raw.conversation(resp.Messages))
+	}
+	if n := len(resp.ConversationIDs); q.Limit > 0 && n >= q.Limit {
+		page.After = resp.ConversationIDs[n-1]
+	}
+	return page, nil
+}
+
+// conversationResponse is the shape of Reddit's response with one modmail
+// conversation.
+type conversationResponse struct {
+	Conversation rawModmailConversation       `mapstructure:"conversation"`
+	Messages     map[string]rawModmailMessage `mapstructure:"messages"`
+}
+
+func (m *modmail) Conversation(id string) (*ModmailConversation, error) {
+	resp := &conversationResponse{}
+	if err := m.r.reapInto(
+		"/api/mod/conversations/"+id,
+		map[string]string{"markRead": "false"},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp.Conversation.conversation(resp.Messages), nil
+}
+
+func (m *modmail) ReplyModmail(id, text string, internal bool) error {
+	return m.r.sow(
+		"/api/mod/conversations/"+id, map[string]string{
+			"body":           text,
+			"isInternal":     strconv.FormatBool(internal),
+			"isAuthorHidden": "false",
+		},
+	)
+}
+
+func (m *modmail) ArchiveModmail(id string) error {
+	return m.r.sow("/api/mod/conversations/"+id+"/archive", map[string]string{})
+}
+
+func (m *modmail) UnarchiveModmail(id string) error {
+	return m.r.sow("/api/mod/conversations/"+id+"/unarchive", map[string]string{})
+}
+
+func (m *modmail) HighlightModmail(id string, on bool) error {
+	path := "/api/mod/conversations/" + id + "/highlight"
+	if on {
+		return m.r.sow(path, map[string]string{})
+	}
+	return m.r.uproot(path, map[string]string{})
+}
+
+func (m *modmail) MuteModmail(id string, days int) error {
+	return m.r.sow(
+		"/api/mod/conversations/"+id+"/mute", map[string]string{
+			"num_hours": strconv.Itoa(days * 24),
+		},
+	)
+}
+
+func (m *modmail) UnmuteModmail(id string) error {
+	return m.r.sow("/api/mod/conversations/"+id+"/unmute", map[string]string{})
+}
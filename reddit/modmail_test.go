@@ -0,0 +1,69 @@
+package reddit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+)
+
+func modmailReaper(resps map[string]string) (reaper, *routeClient) {
+	c := &routeClient{resps: resps, bodies: make(map[string][]string)}
+	return &reaperImpl{
+		cli:      c,
+		parser:   newParser(),
+		hostname: "oauth.reddit.com",
+		scheme:   "https",
+		clock:    clock.Real(),
+		limit:    newRateLimit(),
+	}, c
+}
+
+func TestConversations(t *testing.T) {
+	r, _ := modmailReaper(map[string]string{
+		"/api/mod/conversations": `{
+			"conversationIds": ["b", "a"],
+			"conversations": {
+				"a": {
+					"id": "a", "subject": "appeal", "numMessages": 2,
+					"owner": {"displayName": "sub"},
+					"participant": {"name": "user"},
+					"lastUpdated": "2020-01-02T03:04:05.123456+00:00",
+					"lastUserUpdate": "2020-01-02T03:04:05+00:00",
+					"lastModUpdate": null,
+					"objIds": [{"id": "m1", "key": "messages"}, {"id": "x", "key": "modActions"}]
+				},
+				"b": {"id": "b", "isInternal": true, "owner": {"displayName": "sub"}, "participant": {}}
+			},
+			"messages": {
+				"m1": {
+					"id": "m1", "body": "<p>hi</p>", "bodyMarkdown": "hi",
+					"author": {"name": "user", "isMod": false},
+					"date": "2020-01-02T03:04:05+00:00"
+				}
+			}
+		}`,
+	})
+
+	page, err := newModmail(r).Conversations(ModmailQuery{Subreddits: []string{"sub"}, Limit: 2})
+	if err != nil {
+		t.Fatalf("failed to list conversations: %v", err)
+	}
+
+	if len(page.Conversations) != 2 || page.After != "a" {
+		t.Fatalf("got page %+v", page)
+	}
+	if b := page.Conversations[0]; b.ID != "b" || !b.Internal || b.Participant != "" {
+		t.Errorf("got conversation %+v", b)
+	}
+
+	a := page.Conversations[1]
+	if a.Subreddit != "sub" || a.Participant != "user" || a.NumMessages != 2 ||
+		!a.LastUserUpdate.Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)) ||
+		!a.LastModUpdate.IsZero() {
+		t.Errorf("got conversation %+v", a)
+	}
+	if len(a.Messages) != 1 || a.Messages[0].Body != "hi" || a.Messages[0].Author != "user" {
+		t.Errorf("got messages %+v", a.Messages)
+	}
+}
@@ -0,0 +1,78 @@
+package reddit
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// redditHosts are the hosts Reddit serves itself from. The links of elements
+// read through one name the others, so links to them are rewritten to one.
+var redditHosts = []string{
+	"https://www.reddit.com",
+	"https://oauth.reddit.com",
+	"https://old.reddit.com",
+	"https://reddit.com",
+}
+
+// canonicalHost is the host absolute links to Reddit are rewritten to.
+const canonicalHost = "https://www.reddit.com"
+
+// ParseHarvest parses a Reddit listing, thread, or morechildren response, as
+// a Scanner does, e.g. from JSON fetched by other means. Responses from
+// Reddit's .json pages, which logged out Scripts read, and from its OAuth API,
+// which Bots read, parse the same; see normalize.
+func ParseHarvest(blob []byte) (Harvest, error) {
+	comments, posts, messages, mores, err := newParser().parse(json.RawMessage(blob))
+	return Harvest{
+		Comments: comments,
+		Posts:    posts,
+		Messages: messages,
+		Mores:    mores,
+	}, err
+}
+
+// normalize rewrites the fields of a thing's data which Reddit's .json pages
+// and its OAuth API report in different forms, so an element parses the same
+// read from either:
+//
+//   - Permalinks and contexts are paths, like "/r/golang/comments/abc/t/",
+//     where some responses give them as links to the host they were read
+//     from.
+//   - Links to Reddit itself, like self posts' URLs, are absolute and name
+//     www.reddit.com, where some responses give them as paths or name the
+//     OAuth host.
+func normalize(data map[string]interface{}) {
+	for _, field := range []string{"permalink", "context"} {
+		if s, ok := data[field].(string); ok {
+			data[field] = relativeLink(s)
+		}
+	}
+
+	for _, field := range []string{"url", "link_url"} {
+		if s, ok := data[field].(string); ok {
+			data[field] = absoluteLink(s)
+		}
+	}
+}
+
+// relativeLink returns the path of a link to Reddit.
+func relativeLink(link string) string {
+	for _, host := range redditHosts {
+		if strings.HasPrefix(link, host+"/") {
+			return strings.TrimPrefix(link, host)
+		}
+	}
+	return link
+}
+
+// absoluteLink returns a link to Reddit, given as a path or on any of its
+// hosts, as a link to www.reddit.com. Other links are returned as they are.
+func absoluteLink(link string) string {
+	if strings.HasPrefix(link, "/") && !strings.HasPrefix(link, "//") {
+		return canonicalHost + link
+	}
+	if path := relativeLink(link); path != link {
+		return canonicalHost + path
+	}
+	return link
+}
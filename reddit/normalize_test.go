@@ -0,0 +1,64 @@
+package reddit
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+// TestSourcesAgree parses each response in testdata/sources/ captured from
+// Reddit's .json pages and the matching one from its OAuth API, and checks
+// they parse the same.
+func TestSourcesAgree(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "sources", "*.www.json"))
+	if err != nil {
+		t.Fatalf("failed to list fixtures: %v", err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatalf("found no fixtures")
+	}
+
+	for _, www := range fixtures {
+		oauth := strings.TrimSuffix(www, ".www.json") + ".oauth.json"
+		var harvests []Harvest
+		for _, fixture := range []string{www, oauth} {
+			blob, err := ioutil.ReadFile(fixture)
+			if err != nil {
+				t.Fatalf("failed to read %s: %v", fixture, err)
+			}
+
+			h, err := ParseHarvest(blob)
+			if err != nil {
+				t.Fatalf("failed to parse %s: %v", fixture, err)
+			}
+			harvests = append(harvests, h)
+		}
+
+		if diff := pretty.Compare(harvests[0], harvests[1]); diff != "" {
+			t.Errorf("%s and %s parse differently; diff: %s", www, oauth, diff)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	data := map[string]interface{}{
+		"permalink": "https://old.reddit.com/r/golang/comments/abc/t/",
+		"context":   "/r/golang/comments/abc/t/def/?context=3",
+		"url":       "/r/golang/comments/abc/t/",
+		"link_url":  "https://golang.org/doc/",
+	}
+	normalize(data)
+
+	expected := map[string]interface{}{
+		"permalink": "/r/golang/comments/abc/t/",
+		"context":   "/r/golang/comments/abc/t/def/?context=3",
+		"url":       "https://www.reddit.com/r/golang/comments/abc/t/",
+		"link_url":  "https://golang.org/doc/",
+	}
+	if diff := pretty.Compare(data, expected); diff != "" {
+		t.Errorf("normalized incorrectly; diff: %s", diff)
+	}
+}
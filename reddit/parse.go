@@ -19,6 +19,11 @@ const (
 // their post.
 const deletedKey = "[deleted]"
 
+var (
+	errMissingData    = fmt.Errorf("response has no data")
+	errMalformedThing = fmt.Errorf("response has a malformed thing")
+)
+
 // thing is a Reddit type that holds all of their subtypes.
 type thing struct {
 	Kind string                 `json:"kind"`
@@ -41,11 +46,21 @@ type comment struct {
 	Replies thing `mapstructure:"replies"`
 }
 
+// message wraps the user facing Message type with a Replies field for
+// intermediate parsing.
+type message struct {
+	Message `mapstructure:",squash"`
+	Replies thing `mapstructure:"replies"`
+}
+
 // parser parses Reddit responses..
 type parser interface {
 	// parse parses any Reddit response and provides the elements in it.
 	parse(blob json.RawMessage) ([]*Comment, []*Post, []*Message, []*More, error)
 	parse_submitted(blob json.RawMessage) (Submission, error)
+	// decode decodes a Reddit response which is not a listing into v,
+	// using v's mapstructure tags.
+	decode(blob json.RawMessage, v interface{}) error
 }
 
 type parserImpl struct{}
@@ -82,26 +97,23 @@ func (p *parserImpl) parse(
 // parse_submitted parses a response from reddit describing
 // the status of some resource that was submitted
 func (p *parserImpl) parse_submitted(blob json.RawMessage) (Submission, error) {
-	var wrapped map[string]interface{}
-	err := json.Unmarshal(blob, &wrapped)
+	data, err := unwrapJSON(blob)
 	if err != nil {
 		return Submission{}, err
 	}
 
-	wrapped = wrapped["json"].(map[string]interface{})
-	if len(wrapped["errors"].([]interface{})) != 0 {
-		return Submission{}, fmt.Errorf("API errors were returned: %v", wrapped["errors"])
-	}
-
-	data := wrapped["data"].(map[string]interface{})
-
 	// Comment submissions are further wrapped in a things block
 	// because of ... something? There only appears to be a single thing
 	// This transformes var data to be the data of the single thing
 	// This also mirrors https://reddit.com/ + permalink -> url
 	things, has_things := data["things"].([]interface{})
 	if has_things && len(things) == 1 {
-		data = things[0].(map[string]interface{})["data"].(map[string]interface{})
+		thing, _ := things[0].(map[string]interface{})
+		thingData, ok := thing["data"].(map[string]interface{})
+		if !ok {
+			return Submission{}, errMalformedThing
+		}
+		data = thingData
 		data["url"] = fmt.Sprintf("https://reddit.com%s", data["permalink"])
 	}
 
@@ -110,6 +122,44 @@ func (p *parserImpl) parse_submitted(blob json.RawMessage) (Submission, error) {
 	return submission, err
 }
 
+// unwrapJSON returns the data of the envelope Reddit wraps responses to
+// api_type=json requests in, or the errors reported in it.
+func unwrapJSON(blob json.RawMessage) (map[string]interface{}, error) {
+	var wrapped struct {
+		JSON struct {
+			Errors []interface{}          `json:"errors"`
+			Data   map[string]interface{} `json:"data"`
+		} `json:"json"`
+	}
+	if err := json.Unmarshal(blob, &wrapped); err != nil {
+		return nil, err
+	}
+
+	if err := apiError(wrapped.JSON.Errors); err != nil {
+		return nil, err
+	}
+
+	if wrapped.JSON.Data == nil {
+		return nil, errMissingData
+	}
+
+	return wrapped.JSON.Data, nil
+}
+
+// decode decodes a Reddit response which is not a listing into v.
+func (p *parserImpl) decode(blob json.RawMessage, v interface{}) error {
+	var raw interface{}
+	if err := json.Unmarshal(blob, &raw); err != nil {
+		return err
+	}
+
+	if err := mapstructure.Decode(raw, v); err != nil {
+		return mapDecodeError(err, raw)
+	}
+
+	return nil
+}
+
 // parseRawListing parses a listing json blob and returns the elements in it.
 func parseRawListing(
 	blob json.RawMessage,
@@ -126,18 +176,11 @@ func parseRawListing(
 func parseMoreChildren(
 	blob json.RawMessage,
 ) ([]*Comment, []*More, error) {
-	var wrapped map[string]interface{}
-	err := json.Unmarshal(blob, &wrapped)
+	data, err := unwrapJSON(blob)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	wrapped = wrapped["json"].(map[string]interface{})
-	if len(wrapped["errors"].([]interface{})) != 0 {
-		return nil, nil, fmt.Errorf("API errors were returned: %v", wrapped["errors"])
-	}
-
-	data := wrapped["data"].(map[string]interface{})
 	// More submissions are further wrapped in a things block,
 	// so reorganize data so that it makes sense
 	things, hasThings := data["things"].([]interface{})
@@ -153,8 +196,8 @@ func parseMoreChildren(
 
 	if err != nil {
 		return nil, nil, err
-	} else if m.Errors != nil {
-		return nil, nil, fmt.Errorf("%v", m.Errors)
+	} else if err := apiError(m.Errors); err != nil {
+		return nil, nil, err
 	}
 
 	comments, _, _, mores, err := parseChildren(m.Data)
@@ -222,6 +265,7 @@ func parseChildren(children []thing) ([]*Comment, []*Post, []*Message, []*More,
 		if err != nil {
 			break
 		}
+		normalize(c.Data)
 
 		var comment *Comment
 		var post *Post
@@ -280,6 +324,9 @@ func parseComment(t *thing) (*Comment, error) {
 	}
 
 	c.Comment.Deleted = c.Comment.Body == deletedKey
+	c.Comment.EditedUTC = editedAt(t.Data["edited"])
+	c.Comment.Voted = t.Data["likes"] != nil
+	c.Comment.ReportsVisible = t.Data["num_reports"] != nil
 
 	return &c.Comment, err
 
@@ -293,13 +340,41 @@ func parsePost(t *thing) (*Post, error) {
 	}
 
 	p.Deleted = p.SelfText == deletedKey
+	p.EditedUTC = editedAt(t.Data["edited"])
+	p.Voted = t.Data["likes"] != nil
+	p.ReportsVisible = t.Data["num_reports"] != nil
 	return p, nil
 }
 
+// editedAt returns the time of an edit from Reddit's "edited" field, which is
+// false for elements which were never edited and a timestamp for the rest.
+func editedAt(edited interface{}) uint64 {
+	if t, ok := edited.(float64); ok {
+		return uint64(t)
+	}
+	return 0
+}
+
 // parseMessage parses a message into the user facing Message struct.
 func parseMessage(t *thing) (*Message, error) {
-	m := &Message{}
-	return m, mapstructure.Decode(t.Data, m)
+	// As for comments, the replies field is a string if it is empty.
+	value, present := t.Data["replies"]
+	if present {
+		if str, ok := value.(string); ok && str == "" {
+			delete(t.Data, "replies")
+		}
+	}
+
+	m := &message{}
+	if err := mapstructure.Decode(t.Data, m); err != nil {
+		return &m.Message, err
+	}
+
+	var err error
+	if m.Replies.Kind == listingKind {
+		_, _, m.Message.Replies, _, err = parseListing(&m.Replies)
+	}
+	return &m.Message, err
 }
 
 // parseMore parses a more comment list into the user facing More struct.
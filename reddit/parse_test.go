@@ -11,7 +11,7 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/turnage/graw/reddit/internal/testdata"
+	"github.com/aldarisbm/graw/reddit/internal/testdata"
 )
 
 func TestParse(t *testing.T) {
@@ -180,3 +180,98 @@ func TestParseMoreChildren(t *testing.T) {
 		t.Fatalf("found unexpected number of mores: %v", len(mores))
 	}
 }
+
+func TestParseMalformed(t *testing.T) {
+	for i, blob := range []string{
+		`null`,
+		`[]`,
+		`{}`,
+		`{"json": 1}`,
+		`{"json": {"errors": 1}}`,
+		`{"json": {"errors": [], "data": 1}}`,
+		`{"json": {"errors": [], "data": {"things": [1]}}}`,
+		`{"json": {"errors": [], "data": {"things": [{"data": 1}]}}}`,
+		`{"kind": "Listing", "data": {"children": [{"kind": "t1", "data": {"replies": {"kind": "Listing", "data": 1}}}]}}`,
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("[%d] parsing %s panicked: %v", i, blob, r)
+				}
+			}()
+			newParser().parse([]byte(blob))
+			if _, err := newParser().parse_submitted([]byte(blob)); err == nil {
+				t.Errorf("[%d] expected error parsing submission %s", i, blob)
+			}
+		}()
+	}
+}
+
+func TestParseNulls(t *testing.T) {
+	comments, posts, _, _, err := newParser().parse([]byte(`{
+		"kind": "Listing",
+		"data": {"children": [
+			{"kind": "t3", "data": {"likes": false, "num_reports": 0, "edited": false}},
+			{"kind": "t3", "data": {"likes": null, "num_reports": null, "edited": 1500000000.0}},
+			{"kind": "t1", "data": {"likes": true, "parent_id": "t1_a", "edited": 1500000001}},
+			{"kind": "t1", "data": {"author": "[deleted]", "parent_id": "t3_a"}}
+		]}
+	}`))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if up, ok := posts[0].Vote(); up || !ok {
+		t.Errorf("wanted a downvote; got %v, %v", up, ok)
+	}
+	if _, ok := posts[0].Reports(); !ok {
+		t.Errorf("wanted zero reports to be visible")
+	}
+	if _, ok := posts[1].Vote(); ok {
+		t.Errorf("wanted no vote")
+	}
+	if _, ok := posts[1].Reports(); ok {
+		t.Errorf("wanted reports to be hidden")
+	}
+	if posts[0].EditedUTC != 0 || posts[1].EditedUTC != 1500000000 ||
+		comments[0].EditedUTC != 1500000001 {
+		t.Errorf("got edit times %d, %d, %d", posts[0].EditedUTC, posts[1].EditedUTC, comments[0].EditedUTC)
+	}
+
+	if up, ok := comments[0].Vote(); !up || !ok {
+		t.Errorf("wanted an upvote; got %v, %v", up, ok)
+	}
+	if parent, ok := comments[0].ParentComment(); parent != "t1_a" || !ok {
+		t.Errorf("got parent comment %q, %v", parent, ok)
+	}
+	if _, ok := comments[1].ParentComment(); ok {
+		t.Errorf("top level comment has a parent comment")
+	}
+	if _, ok := comments[1].AuthorName(); ok {
+		t.Errorf("deleted author has a name")
+	}
+}
+
+func TestParseMessageThread(t *testing.T) {
+	_, _, msgs, _, err := newParser().parse([]byte(`{
+		"kind": "Listing",
+		"data": {"children": [
+			{"kind": "t4", "data": {"name": "t4_a", "replies": {
+				"kind": "Listing",
+				"data": {"children": [
+					{"kind": "t4", "data": {"name": "t4_b", "replies": ""}}
+				]}
+			}}}
+		]}
+	}`))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if len(msgs) != 1 || msgs[0].Name != "t4_a" {
+		t.Fatalf("got messages %v", msgs)
+	}
+	if len(msgs[0].Replies) != 1 || msgs[0].Replies[0].Name != "t4_b" {
+		t.Errorf("got replies %v", msgs[0].Replies)
+	}
+}
@@ -0,0 +1,125 @@
+package reddit
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+)
+
+// quota tracks the request quota Reddit reports in the X-Ratelimit headers of
+// its responses, so requests can be spaced to spend it evenly instead of at a
+// fixed rate which either wastes it or runs out.
+type quota struct {
+	mu    sync.Mutex
+	clock clock.Clock
+	// known is set once a response reported the quota.
+	known     bool
+	remaining float64
+	// reset is when the quota is next replenished.
+	reset time.Time
+	// retryAt is when a Retry-After header said requests may resume.
+	retryAt time.Time
+}
+
+func newQuota(c clock.Clock) *quota {
+	if c == nil {
+		c = clock.Real()
+	}
+	return &quota{clock: c}
+}
+
+// observe records the quota reported by a response's headers, if it reports
+// one, and when to retry, if the response says.
+func (q *quota) observe(h http.Header) {
+	if q == nil {
+		return
+	}
+
+	q.observeRetryAfter(h.Get("Retry-After"))
+
+	remaining, err := strconv.ParseFloat(h.Get("X-Ratelimit-Remaining"), 64)
+	if err != nil {
+		return
+	}
+
+	reset, err := strconv.ParseFloat(h.Get("X-Ratelimit-Reset"), 64)
+	if err != nil {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.known = true
+	q.remaining = remaining
+	q.reset = q.clock.Now().Add(time.Duration(reset * float64(time.Second)))
+}
+
+// observeRetryAfter records a Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func (q *quota) observeRetryAfter(value string) {
+	if value == "" {
+		return
+	}
+
+	now := q.clock.Now()
+	var at time.Time
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		at = now.Add(time.Duration(seconds * float64(time.Second)))
+	} else if date, err := http.ParseTime(value); err == nil {
+		at = date
+	} else {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if at.After(q.retryAt) {
+		q.retryAt = at
+	}
+}
+
+// wait returns how long after now to wait before the next request, which
+// follows a request made at last, to spread the remaining quota evenly until it
+// resets. When the quota is spent, it waits for the reset, and after a
+// Retry-After header, it waits as long as the header said.
+func (q *quota) wait(now, last time.Time) time.Duration {
+	if q == nil {
+		return 0
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if now.Before(q.retryAt) {
+		return q.retryAt.Sub(now)
+	}
+
+	if !q.known || !now.Before(q.reset) {
+		return 0
+	}
+
+	untilReset := q.reset.Sub(now)
+	if q.remaining < 1 {
+		return untilReset
+	}
+
+	gap := time.Duration(float64(untilReset) / q.remaining)
+	return last.Add(gap).Sub(now)
+}
+
+// spend counts a request made against the quota, so requests made before
+// Reddit's next report don't overspend it.
+func (q *quota) spend() {
+	if q == nil {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.known && q.remaining > 0 {
+		q.remaining--
+	}
+}
@@ -0,0 +1,88 @@
+package reddit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+)
+
+func TestQuota(t *testing.T) {
+	start := time.Unix(1000, 0)
+	q := newQuota(clock.NewSimulation(start))
+
+	if wait := q.wait(start, start); wait != 0 {
+		t.Errorf("waited %v before any quota was reported", wait)
+	}
+
+	q.observe(http.Header{"X-Ratelimit-Remaining": {"not a number"}})
+	if q.known {
+		t.Errorf("a malformed quota was recorded")
+	}
+
+	q.observe(http.Header{
+		"X-Ratelimit-Remaining": {"10.0"},
+		"X-Ratelimit-Used":      {"590"},
+		"X-Ratelimit-Reset":     {"100"},
+	})
+	if wait := q.wait(start, start); wait != 10*time.Second {
+		t.Errorf("got wait %v; wanted the 100s until reset spread over 10 requests", wait)
+	}
+
+	q.observe(http.Header{
+		"X-Ratelimit-Remaining": {"0"},
+		"X-Ratelimit-Reset":     {"42"},
+	})
+	if wait := q.wait(start, start); wait != 42*time.Second {
+		t.Errorf("got wait %v; wanted to wait for the reset", wait)
+	}
+
+	if wait := q.wait(start.Add(time.Minute), start); wait != 0 {
+		t.Errorf("got wait %v after the reset", wait)
+	}
+}
+
+func TestRateBlockQuota(t *testing.T) {
+	sim := clock.NewSimulation(time.Unix(0, 0))
+	q := newQuota(sim)
+	q.observe(http.Header{
+		"X-Ratelimit-Remaining": {"1"},
+		"X-Ratelimit-Reset":     {"60"},
+	})
+	r := &reaperImpl{
+		cli:    &mockClient{},
+		parser: &mockParser{},
+		rate:   time.Second,
+		clock:  sim,
+		limit:  newRateLimit(),
+		quota:  q,
+	}
+
+	// The last request of the quota is made at once, and the next waits
+	// for the reset.
+	r.rateBlock()
+	r.rateBlock()
+	if now := sim.Now(); now != time.Unix(60, 0) {
+		t.Errorf("requests were made until %v; wanted them held to the reset", now)
+	}
+}
+
+func TestQuotaRetryAfter(t *testing.T) {
+	start := time.Unix(1000, 0)
+	q := newQuota(clock.NewSimulation(start))
+
+	q.observe(http.Header{"Retry-After": {"30"}})
+	if wait := q.wait(start, start); wait != 30*time.Second {
+		t.Errorf("got wait %v; wanted the 30s Retry-After asked", wait)
+	}
+
+	q.observe(http.Header{"Retry-After": {start.Add(time.Minute).UTC().Format(http.TimeFormat)}})
+	if wait := q.wait(start, start); wait != time.Minute {
+		t.Errorf("got wait %v; wanted to wait until the Retry-After date", wait)
+	}
+
+	if wait := q.wait(start.Add(2*time.Minute), start); wait != 0 {
+		t.Errorf("got wait %v after the Retry-After passed", wait)
+	}
+}
@@ -1,14 +1,18 @@
 package reddit
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
+
+	"github.com/aldarisbm/graw/clock"
 )
 
 var (
@@ -26,6 +30,17 @@ type reaperConfig struct {
 	reapSuffix string
 	tls        bool
 	rate       time.Duration
+	// clock times the rate limit. If nil, the wall clock is used.
+	clock clock.Clock
+	// quota, if set, is the request quota Reddit reports, which spaces
+	// requests further apart than rate when it runs low.
+	quota *quota
+	// retries configures how transient failures are retried.
+	retries RetryConfig
+	// uploader sends files to Reddit's media storage, which is not part
+	// of the API and takes no OAuth2 token. If nil, http.DefaultClient
+	// is used.
+	uploader *http.Client
 }
 
 // reaper is a high level api for Reddit HTTP requests.
@@ -33,11 +48,37 @@ type reaper interface {
 	// reap executes a GET request to Reddit and returns the elements from
 	// the endpoint.
 	reap(path string, values map[string]string) (Harvest, error)
+	// reapPage is reap for listing endpoints, which also returns the
+	// metadata of the page.
+	reapPage(path string, values map[string]string) (Harvest, ListingInfo, error)
+	// reapInto executes a GET request to Reddit and decodes the response,
+	// which need not be a listing, into v.
+	reapInto(path string, values map[string]string, v interface{}) error
 	// sow executes a POST request to Reddit.
 	sow(path string, values map[string]string) error
+	// uproot executes a DELETE request to Reddit.
+	uproot(path string, values map[string]string) error
 	// get_sow executes a POST request to Reddit
 	// and returns the response, usually the posted item
 	get_sow(path string, values map[string]string) (Submission, error)
+	// sowInto executes a POST request to Reddit and decodes the response
+	// into v.
+	sowInto(path string, values map[string]string, v interface{}) error
+	// sowJSON executes a POST request to Reddit with v encoded as its JSON
+	// body, and returns the posted item.
+	sowJSON(path string, v interface{}) (Submission, error)
+	// upload sends a file to Reddit's media storage under a lease, and
+	// returns the url of the uploaded file.
+	upload(lease mediaLease, file MediaFile) (string, error)
+	// awaitMedia waits for Reddit to announce on a media asset's websocket
+	// that the post made with it is up, and returns the post's url.
+	awaitMedia(websocketURL string) (string, error)
+	// listen connects to a live thread's websocket, closing the
+	// connection when the reaper's context is done.
+	listen(thread, websocketURL string) (LiveStream, error)
+	// withContext returns a view of the reaper whose requests are made
+	// with ctx, sharing its rate limit.
+	withContext(ctx context.Context) reaper
 }
 
 type reaperImpl struct {
@@ -47,11 +88,35 @@ type reaperImpl struct {
 	reapSuffix string
 	scheme     string
 	rate       time.Duration
-	last       time.Time
-	mu         *sync.Mutex
+	limit      *rateLimit
+	quota      *quota
+	clock      clock.Clock
+	retries    RetryConfig
+	uploader   *http.Client
+	// ctx is the context requests are made with. If nil, they can't be
+	// cancelled.
+	ctx context.Context
+	// flights, if set, coalesces the reaper's identical reads, and those
+	// of its views.
+	flights *flights
+}
+
+// rateLimit is the rate limit state a reaper shares with its views.
+type rateLimit struct {
+	// turn is held by the request waiting its turn under the rate limit.
+	turn chan struct{}
+	last time.Time
+}
+
+func newRateLimit() *rateLimit {
+	return &rateLimit{turn: make(chan struct{}, 1)}
 }
 
 func newReaper(c reaperConfig) reaper {
+	if c.clock == nil {
+		c.clock = clock.Real()
+	}
+
 	return &reaperImpl{
 		cli:        c.client,
 		parser:     c.parser,
@@ -59,13 +124,62 @@ func newReaper(c reaperConfig) reaper {
 		reapSuffix: c.reapSuffix,
 		scheme:     scheme[c.tls],
 		rate:       c.rate,
-		mu:         &sync.Mutex{},
+		clock:      c.clock,
+		limit:      newRateLimit(),
+		quota:      c.quota,
+		retries:    c.retries,
+		uploader:   c.uploader,
+		flights:    newFlights(),
 	}
 }
 
+func (r *reaperImpl) withContext(ctx context.Context) reaper {
+	view := *r
+	view.ctx = ctx
+	return &view
+}
+
+func (r *reaperImpl) context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+// do makes the request, retrying it if it fails transiently and the reaper is
+// configured to. Reads of a URL already being read share its response.
+func (r *reaperImpl) do(req *http.Request) ([]byte, error) {
+	if req.Method == "GET" && r.flights != nil {
+		return r.flights.do(r.context(), req.URL.String(), func() ([]byte, error) {
+			return r.send(req)
+		})
+	}
+	return r.send(req)
+}
+
+// send makes the request, retrying it if the reaper is configured to.
+func (r *reaperImpl) send(req *http.Request) ([]byte, error) {
+	if r.retries.MaxAttempts > 1 {
+		return r.retry(req)
+	}
+	return r.attempt(req)
+}
+
+// attempt waits for the request's turn under the rate limit and makes it,
+// unless the reaper's context is done first.
+func (r *reaperImpl) attempt(req *http.Request) ([]byte, error) {
+	if err := r.rateBlock(); err != nil {
+		return nil, err
+	}
+
+	if r.ctx != nil {
+		req = req.WithContext(r.ctx)
+	}
+	return r.cli.Do(req)
+}
+
 func (r *reaperImpl) reap(path string, values map[string]string) (Harvest, error) {
-	r.rateBlock()
-	resp, err := r.cli.Do(
+	resp, err := r.do(
 		&http.Request{
 			Method: "GET",
 			URL:    r.url(r.path(path, r.reapSuffix), values),
@@ -85,9 +199,63 @@ func (r *reaperImpl) reap(path string, values map[string]string) (Harvest, error
 	}, err
 }
 
+func (r *reaperImpl) reapPage(path string, values map[string]string) (
+	Harvest,
+	ListingInfo,
+	error,
+) {
+	resp, err := r.do(
+		&http.Request{
+			Method: "GET",
+			URL:    r.url(r.path(path, r.reapSuffix), values),
+			Host:   r.hostname,
+		},
+	)
+	if err != nil {
+		return Harvest{}, ListingInfo{}, err
+	}
+
+	comments, posts, messages, mores, err := r.parser.parse(resp)
+	if err != nil {
+		return Harvest{}, ListingInfo{}, err
+	}
+
+	var page struct {
+		Data ListingInfo `mapstructure:"data"`
+	}
+	if err := r.parser.decode(resp, &page); err != nil {
+		return Harvest{}, ListingInfo{}, err
+	}
+
+	return Harvest{
+		Comments: comments,
+		Posts:    posts,
+		Messages: messages,
+		Mores:    mores,
+	}, page.Data, nil
+}
+
+func (r *reaperImpl) reapInto(
+	path string,
+	values map[string]string,
+	v interface{},
+) error {
+	resp, err := r.do(
+		&http.Request{
+			Method: "GET",
+			URL:    r.url(r.path(path, r.reapSuffix), values),
+			Host:   r.hostname,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return r.parser.decode(resp, v)
+}
+
 func (r *reaperImpl) sow(path string, values map[string]string) error {
-	r.rateBlock()
-	_, err := r.cli.Do(
+	resp, err := r.do(
 		&http.Request{
 			Method: "POST",
 			Header: r.getHeaders(values),
@@ -96,14 +264,53 @@ func (r *reaperImpl) sow(path string, values map[string]string) error {
 			Body:   r.getBody(values),
 		},
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	return sowError(resp)
+}
+
+func (r *reaperImpl) uproot(path string, values map[string]string) error {
+	resp, err := r.do(
+		&http.Request{
+			Method: "DELETE",
+			Host:   r.hostname,
+			URL:    r.url(path, values),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return sowError(resp)
 }
 
 func (r *reaperImpl) get_sow(path string, values map[string]string) (Submission, error) {
-	r.rateBlock()
 	values["api_type"] = "json"
-	resp, err := r.cli.Do(
+	resp, err := r.do(
+		&http.Request{
+			Method: "POST",
+			Header: r.getHeaders(values),
+			Host:   r.hostname,
+			URL:    r.postURL(path),
+			Body:   r.getBody(values),
+		},
+	)
+
+	if err != nil {
+		return Submission{}, err
+	}
+
+	return r.parser.parse_submitted(resp)
+}
+
+func (r *reaperImpl) sowInto(
+	path string,
+	values map[string]string,
+	v interface{},
+) error {
+	resp, err := r.do(
 		&http.Request{
 			Method: "POST",
 			Header: r.getHeaders(values),
@@ -112,7 +319,34 @@ func (r *reaperImpl) get_sow(path string, values map[string]string) (Submission,
 			Body:   r.getBody(values),
 		},
 	)
+	if err != nil {
+		return err
+	}
 
+	if err := sowError(resp); err != nil {
+		return err
+	}
+	return r.parser.decode(resp, v)
+}
+
+func (r *reaperImpl) sowJSON(path string, v interface{}) (Submission, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return Submission{}, err
+	}
+
+	resp, err := r.do(
+		&http.Request{
+			Method: "POST",
+			Header: map[string][]string{
+				"Content-Type":   {"application/json"},
+				"Content-Length": {strconv.Itoa(len(body))},
+			},
+			Host: r.hostname,
+			URL:  r.postURL(path),
+			Body: ioutil.NopCloser(bytes.NewReader(body)),
+		},
+	)
 	if err != nil {
 		return Submission{}, err
 	}
@@ -120,14 +354,27 @@ func (r *reaperImpl) get_sow(path string, values map[string]string) (Submission,
 	return r.parser.parse_submitted(resp)
 }
 
-func (r *reaperImpl) rateBlock() {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+func (r *reaperImpl) rateBlock() error {
+	ctx := r.context()
+	select {
+	case r.limit.turn <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-r.limit.turn }()
+
+	now := r.clock.Now()
+	wait := r.limit.last.Add(r.rate).Sub(now)
+	if spread := r.quota.wait(now, r.limit.last); spread > wait {
+		wait = spread
+	}
 
-	if time.Since(r.last) < r.rate {
-		<-time.After(r.last.Add(r.rate).Sub(time.Now()))
+	if err := r.sleep(ctx, wait); err != nil {
+		return err
 	}
-	r.last = time.Now()
+	r.limit.last = r.clock.Now()
+	r.quota.spend()
+	return nil
 }
 
 func (r *reaperImpl) url(path string, values map[string]string) *url.URL {
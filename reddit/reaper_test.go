@@ -1,12 +1,13 @@
 package reddit
 
 import (
+	"context"
 	"net/http"
 	"net/url"
-	"sync"
 	"testing"
 	"time"
 
+	"github.com/aldarisbm/graw/clock"
 	"github.com/kylelemons/godebug/pretty"
 )
 
@@ -24,7 +25,9 @@ func TestNew(t *testing.T) {
 		parser:   par,
 		hostname: "com",
 		scheme:   "https",
-		mu:       &sync.Mutex{},
+		clock:    clock.Real(),
+		limit:    newRateLimit(),
+		flights:  newFlights(),
 	}
 
 	if diff := pretty.Compare(newReaper(cfg), expected); diff != "" {
@@ -92,7 +95,8 @@ func TestReap(t *testing.T) {
 			parser:   parserWhich(expected),
 			hostname: "com",
 			scheme:   "http",
-			mu:       &sync.Mutex{},
+			clock:    clock.Real(),
+			limit:    newRateLimit(),
 		}
 
 		Harvest, err := r.reap(test.path, test.values)
@@ -110,45 +114,82 @@ func TestReap(t *testing.T) {
 	}
 }
 
+func TestReapPage(t *testing.T) {
+	c := &mockClient{resp: []byte(`{
+		"kind": "Listing",
+		"data": {
+			"after": "t3_b",
+			"before": null,
+			"dist": 2,
+			"modhash": "",
+			"geo_filter": "",
+			"children": [
+				{"kind": "t3", "data": {"name": "t3_a"}},
+				{"kind": "t3", "data": {"name": "t3_b"}}
+			]
+		}
+	}`)}
+	r := &reaperImpl{
+		cli:      c,
+		parser:   newParser(),
+		hostname: "com",
+		scheme:   "http",
+		clock:    clock.Real(),
+		limit:    newRateLimit(),
+	}
+
+	h, info, err := r.reapPage("/r/all", nil)
+	if err != nil {
+		t.Fatalf("error reaping page: %v", err)
+	}
+
+	if len(h.Posts) != 2 || h.Posts[1].Name != "t3_b" {
+		t.Errorf("got posts %v; wanted t3_a and t3_b", h.Posts)
+	}
+
+	expected := ListingInfo{After: "t3_b", Dist: 2}
+	if info != expected {
+		t.Errorf("got info %+v; wanted %+v", info, expected)
+	}
+}
+
 func TestSow(t *testing.T) {
 	for i, test := range []struct {
 		path    string
 		values  map[string]string
 		correct http.Request
+		body    string
 	}{
 		{"", nil, http.Request{
 			Method: "POST",
-			Header: formEncoding,
+			Header: formHeader(""),
 			Host:   "com",
 			URL: &url.URL{
-				Scheme:   "http",
-				Host:     "com",
-				Path:     "",
-				RawQuery: "",
+				Scheme: "http",
+				Host:   "com",
+				Path:   "",
 			},
-		}},
+		}, ""},
 		{"", map[string]string{"key": "value"}, http.Request{
 			Method: "POST",
-			Header: formEncoding,
+			Header: formHeader("key=value"),
 			Host:   "com",
 			URL: &url.URL{
-				Scheme:   "http",
-				Host:     "com",
-				Path:     "",
-				RawQuery: "key=value",
+				Scheme: "http",
+				Host:   "com",
+				Path:   "",
 			},
-		}},
+		}, "key=value"},
 		{"path", nil, http.Request{
 			Method: "POST",
-			Header: formEncoding,
+			Header: formHeader(""),
 			Host:   "com",
 			URL: &url.URL{
-				Scheme:   "http",
-				Host:     "com",
-				Path:     "path",
-				RawQuery: "",
+				Scheme: "http",
+				Host:   "com",
+				Path:   "path",
 			},
-		}},
+		}, ""},
 	} {
 		c := &mockClient{}
 		r := &reaperImpl{
@@ -156,7 +197,8 @@ func TestSow(t *testing.T) {
 			parser:   &mockParser{},
 			hostname: "com",
 			scheme:   "http",
-			mu:       &sync.Mutex{},
+			clock:    clock.Real(),
+			limit:    newRateLimit(),
 		}
 
 		if err := r.sow(test.path, test.values); err != nil {
@@ -166,6 +208,10 @@ func TestSow(t *testing.T) {
 		if diff := pretty.Compare(c.request, test.correct); diff != "" {
 			t.Errorf("request incorrect; diff: %s", diff)
 		}
+
+		if c.body != test.body {
+			t.Errorf("body incorrect; got %q; wanted %q", c.body, test.body)
+		}
 	}
 }
 
@@ -183,16 +229,80 @@ func testRateBlock(f func(reaper), t *testing.T) {
 		cli:    &mockClient{},
 		parser: &mockParser{},
 		rate:   10 * time.Millisecond,
-		last:   start,
-		mu:     &sync.Mutex{},
+		clock:  clock.Real(),
+		limit:  newRateLimit(),
 	}
+	r.limit.last = start
 
 	f(r)
 	end := time.Now()
 
 	if block := end.Sub(start); block < r.rate {
 		t.Errorf("wanted block for %v; blocked for %v", r.rate, block)
-	} else if r.last == start {
+	} else if r.limit.last == start {
 		t.Errorf("wanted updated timestamp; found same timestamp")
 	}
 }
+
+func TestRateBlockSimulated(t *testing.T) {
+	sim := clock.NewSimulation(time.Unix(0, 0))
+	r := &reaperImpl{
+		cli:    &mockClient{},
+		parser: &mockParser{},
+		rate:   time.Hour,
+		clock:  sim,
+		limit:  newRateLimit(),
+	}
+	r.limit.last = sim.Now()
+
+	start := time.Now()
+	r.rateBlock()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("simulated rate block took %v of real time", elapsed)
+	}
+
+	if now := sim.Now(); !now.Equal(time.Unix(0, 0).Add(time.Hour)) {
+		t.Errorf("simulated clock reads %v; wanted an hour later", now)
+	}
+}
+
+type contextKey string
+
+func TestWithContext(t *testing.T) {
+	c := &mockClient{}
+	r := &reaperImpl{
+		cli:    c,
+		parser: &mockParser{},
+		rate:   time.Hour,
+		clock:  clock.Real(),
+		limit:  newRateLimit(),
+	}
+
+	ctx := context.WithValue(context.Background(), contextKey("k"), "v")
+	if err := r.withContext(ctx).sow("/api/comment", nil); err != nil {
+		t.Fatal(err)
+	}
+	if c.request.Context().Value(contextKey("k")) != "v" {
+		t.Errorf("the request was not made with the view's context")
+	}
+
+	// The view shares the rate limit, so the next request waits an hour,
+	// unless it is cancelled.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := r.withContext(ctx).reap("/r/golang", nil); err != context.DeadlineExceeded {
+		t.Errorf("wanted the deadline's error; got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("the cancelled request waited %v", elapsed)
+	}
+
+	// A request waiting on another's turn is cancelled too.
+	r.limit.turn <- struct{}{}
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if err := r.withContext(ctx).sow("/api/comment", nil); err != context.Canceled {
+		t.Errorf("wanted the cancellation's error; got %v", err)
+	}
+}
@@ -25,10 +25,22 @@
 //   bot, _ := NewBot(cfg)
 //   bot.SendMessage("roxven", "Thanks for making this Reddit API!", "It's ok.")
 //
+// Bots which were authorized with a refresh token instead of a password can be
+// claimed with NewTokenBot, and bots which only read, with no account at all,
+// can claim a Script through the OAuth2 API with NewReadOnlyClient. Access
+// tokens are renewed ahead of their expiry in every mode.
+//
 // Requests made by this API are rate limited with no bursting. All interfaces
 // exported by this package have goroutine safe implementations, but when shared
 // by many goroutines some calls may block for multiples of the rate limit
-// interval.
+// interval. Beyond the configured rate, requests are spaced to spread the
+// quota Reddit reports in its X-Ratelimit response headers evenly until it
+// resets, and wait for the reset when it is spent, so a bot never runs into
+// 429s.
+//
+// Identical reads made at once, such as two handlers fetching the same thread,
+// share one request: the reads which arrive while another is in flight wait for
+// its response rather than spending the rate limit again.
 //
 // This API for accessing feeds from Reddit is low level, built specifically for
 // graw. If you are interested in a simple high level event feed, see graw.
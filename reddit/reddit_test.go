@@ -3,9 +3,9 @@ package reddit
 import (
 	"net/http"
 	"net/url"
-	"sync"
 	"testing"
 
+	"github.com/aldarisbm/graw/clock"
 	"github.com/kylelemons/godebug/pretty"
 )
 
@@ -16,6 +16,7 @@ type testCase struct {
 	err     error
 	f       func(Bot) error
 	correct http.Request
+	body    string
 }
 
 func TestAccount(t *testing.T) {
@@ -29,14 +30,14 @@ func TestAccount(t *testing.T) {
 				correct: http.Request{
 					Method: "POST",
 					URL: &url.URL{
-						Scheme:   "https",
-						Host:     "reddit.com",
-						Path:     "/api/comment",
-						RawQuery: "text=text&thing_id=name",
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/comment",
 					},
 					Host:   "reddit.com",
-					Header: formEncoding,
+					Header: formHeader("text=text&thing_id=name"),
 				},
+				body: "text=text&thing_id=name",
 			},
 			testCase{
 				name: "GetReply",
@@ -47,14 +48,14 @@ func TestAccount(t *testing.T) {
 				correct: http.Request{
 					Method: "POST",
 					URL: &url.URL{
-						Scheme:   "https",
-						Host:     "reddit.com",
-						Path:     "/api/comment",
-						RawQuery: "api_type=json&text=text&thing_id=name",
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/comment",
 					},
 					Host:   "reddit.com",
-					Header: formEncoding,
+					Header: formHeader("api_type=json&text=text&thing_id=name"),
 				},
+				body: "api_type=json&text=text&thing_id=name",
 			},
 			testCase{
 				name: "SendMessage",
@@ -64,14 +65,14 @@ func TestAccount(t *testing.T) {
 				correct: http.Request{
 					Method: "POST",
 					URL: &url.URL{
-						Scheme:   "https",
-						Host:     "reddit.com",
-						Path:     "/api/compose",
-						RawQuery: "subject=subject&text=text&to=user",
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/compose",
 					},
 					Host:   "reddit.com",
-					Header: formEncoding,
+					Header: formHeader("subject=subject&text=text&to=user"),
 				},
+				body: "subject=subject&text=text&to=user",
 			},
 			testCase{
 				name: "PostSelf",
@@ -81,14 +82,14 @@ func TestAccount(t *testing.T) {
 				correct: http.Request{
 					Method: "POST",
 					URL: &url.URL{
-						Scheme:   "https",
-						Host:     "reddit.com",
-						Path:     "/api/submit",
-						RawQuery: "kind=self&sr=self&text=text&title=title",
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/submit",
 					},
 					Host:   "reddit.com",
-					Header: formEncoding,
+					Header: formHeader("kind=self&sr=self&text=text&title=title"),
 				},
+				body: "kind=self&sr=self&text=text&title=title",
 			},
 			testCase{
 				name: "GetPostSelf",
@@ -100,14 +101,32 @@ func TestAccount(t *testing.T) {
 				correct: http.Request{
 					Method: "POST",
 					URL: &url.URL{
-						Scheme:   "https",
-						Host:     "reddit.com",
-						Path:     "/api/submit",
-						RawQuery: "api_type=json&kind=self&sr=self&text=text&title=title",
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/submit",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("api_type=json&kind=self&sr=self&text=text&title=title"),
+				},
+				body: "api_type=json&kind=self&sr=self&text=text&title=title",
+			},
+			testCase{
+				name: "GetPostSelfWithFlair",
+				f: func(b Bot) error {
+					_, err := b.GetPostSelfWithFlair("self", "title", "text", PostFlair{TemplateID: "id"})
+					return err
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/submit",
 					},
 					Host:   "reddit.com",
-					Header: formEncoding,
+					Header: formHeader("api_type=json&flair_id=id&kind=self&sr=self&text=text&title=title"),
 				},
+				body: "api_type=json&flair_id=id&kind=self&sr=self&text=text&title=title",
 			},
 			testCase{
 				name: "PostLink",
@@ -117,14 +136,14 @@ func TestAccount(t *testing.T) {
 				correct: http.Request{
 					Method: "POST",
 					URL: &url.URL{
-						Scheme:   "https",
-						Host:     "reddit.com",
-						Path:     "/api/submit",
-						RawQuery: "kind=link&sr=link&title=title&url=url",
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/submit",
 					},
 					Host:   "reddit.com",
-					Header: formEncoding,
+					Header: formHeader("kind=link&sr=link&title=title&url=url"),
 				},
+				body: "kind=link&sr=link&title=title&url=url",
 			},
 			testCase{
 				name: "GetPostLink",
@@ -134,14 +153,234 @@ func TestAccount(t *testing.T) {
 				},
 				correct: http.Request{
 					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/submit",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("api_type=json&kind=link&sr=link&title=title&url=url"),
+				},
+				body: "api_type=json&kind=link&sr=link&title=title&url=url",
+			},
+			testCase{
+				name: "Me",
+				f: func(b Bot) error {
+					_, err := b.Me()
+					return err
+				},
+				correct: http.Request{
+					Method: "GET",
 					URL: &url.URL{
 						Scheme:   "https",
 						Host:     "reddit.com",
-						Path:     "/api/submit",
-						RawQuery: "api_type=json&kind=link&sr=link&title=title&url=url",
+						Path:     "/api/v1/me.json",
+						RawQuery: "raw_json=1",
+					},
+					Host: "reddit.com",
+				},
+			},
+			testCase{
+				name: "BlockedUsers",
+				f: func(b Bot) error {
+					_, err := b.BlockedUsers()
+					return err
+				},
+				correct: http.Request{
+					Method: "GET",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/prefs/blocked.json",
+					},
+					Host: "reddit.com",
+				},
+			},
+			testCase{
+				name: "Downvote",
+				f: func(b Bot) error {
+					return b.Downvote("t1_comment")
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/vote",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("dir=-1&id=t1_comment"),
+				},
+				body: "dir=-1&id=t1_comment",
+			},
+			testCase{
+				name: "Save",
+				f: func(b Bot) error {
+					return b.Save("t3_post")
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/save",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("id=t3_post"),
+				},
+				body: "id=t3_post",
+			},
+			testCase{
+				name: "Hide",
+				f: func(b Bot) error {
+					return b.Hide("t3_post")
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/hide",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("id=t3_post"),
+				},
+				body: "id=t3_post",
+			},
+			testCase{
+				name: "Report",
+				f: func(b Bot) error {
+					return b.Report("t1_comment", "spam")
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/report",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("api_type=json&reason=spam&thing_id=t1_comment"),
+				},
+				body: "api_type=json&reason=spam&thing_id=t1_comment",
+			},
+			testCase{
+				name: "EditText",
+				f: func(b Bot) error {
+					return b.EditText("t1_comment", "updated")
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/editusertext",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("api_type=json&text=updated&thing_id=t1_comment"),
+				},
+				body: "api_type=json&text=updated&thing_id=t1_comment",
+			},
+			testCase{
+				name: "Delete",
+				f: func(b Bot) error {
+					return b.Delete("t3_post")
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/del",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("id=t3_post"),
+				},
+				body: "id=t3_post",
+			},
+			testCase{
+				name: "MarkRead",
+				f: func(b Bot) error {
+					return b.MarkRead("t4_message", "t1_reply")
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/read_message",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("id=t4_message%2Ct1_reply"),
+				},
+				body: "id=t4_message%2Ct1_reply",
+			},
+			testCase{
+				name: "BlockSender",
+				f: func(b Bot) error {
+					return b.BlockSender("t4_message")
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/block",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("id=t4_message"),
+				},
+				body: "id=t4_message",
+			},
+			testCase{
+				name: "Block",
+				f: func(b Bot) error {
+					return b.Block("user")
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/block_user",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("name=user"),
+				},
+				body: "name=user",
+			},
+			testCase{
+				name: "Unblock",
+				f: func(b Bot) error {
+					return b.Unblock("user")
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/unfriend",
 					},
 					Host:   "reddit.com",
-					Header: formEncoding,
+					Header: formHeader("container=t2_&name=user&type=enemy"),
+				},
+				body: "container=t2_&name=user&type=enemy",
+			},
+			testCase{
+				name: "PostInsights",
+				f: func(b Bot) error {
+					_, err := b.PostInsights("t3_a", "t3_b")
+					return err
+				},
+				correct: http.Request{
+					Method: "GET",
+					URL: &url.URL{
+						Scheme:   "https",
+						Host:     "reddit.com",
+						Path:     "/api/info.json",
+						RawQuery: "id=t3_a%2Ct3_b&raw_json=1",
+					},
+					Host: "reddit.com",
 				},
 			},
 		}, t,
@@ -168,6 +407,68 @@ func TestScanner(t *testing.T) {
 					Host: "reddit.com",
 				},
 			},
+			testCase{
+				name: "ListingPage",
+				f: func(b Bot) error {
+					_, _, err := b.ListingPage(
+						"/r/all",
+						map[string]string{"after": "t3_a", "limit": "25"},
+					)
+					return err
+				},
+				correct: http.Request{
+					Method: "GET",
+					URL: &url.URL{
+						Scheme:   "https",
+						Host:     "reddit.com",
+						Path:     "/r/all.json",
+						RawQuery: "after=t3_a&limit=25&raw_json=1",
+					},
+					Host: "reddit.com",
+				},
+			},
+			testCase{
+				name: "Search",
+				f: func(b Bot) error {
+					_, err := b.Search("golang", "generics", SearchOptions{
+						Sort:              "top",
+						Time:              "week",
+						RestrictSubreddit: true,
+						Types:             []string{SearchPosts, SearchComments},
+						Limit:             25,
+						After:             "t3_a",
+						Count:             25,
+					})
+					return err
+				},
+				correct: http.Request{
+					Method: "GET",
+					URL: &url.URL{
+						Scheme:   "https",
+						Host:     "reddit.com",
+						Path:     "/r/golang/search.json",
+						RawQuery: "after=t3_a&count=25&limit=25&q=generics&raw_json=1&restrict_sr=on&sort=top&t=week&type=link%2Ccomment",
+					},
+					Host: "reddit.com",
+				},
+			},
+			testCase{
+				name: "SearchAll",
+				f: func(b Bot) error {
+					_, err := b.Search("", "graw", SearchOptions{})
+					return err
+				},
+				correct: http.Request{
+					Method: "GET",
+					URL: &url.URL{
+						Scheme:   "https",
+						Host:     "reddit.com",
+						Path:     "/search.json",
+						RawQuery: "limit=100&q=graw&raw_json=1",
+					},
+					Host: "reddit.com",
+				},
+			},
 		}, t,
 	)
 }
@@ -193,35 +494,619 @@ func TestLurker(t *testing.T) {
 					Host: "reddit.com",
 				},
 			},
+			testCase{
+				name: "Context",
+				err:  ThreadDoesNotExistErr,
+				f: func(b Bot) error {
+					_, err := b.Context("/permalink", 3)
+					return err
+				},
+				correct: http.Request{
+					Method: "GET",
+					URL: &url.URL{
+						Scheme:   "https",
+						Host:     "reddit.com",
+						Path:     "/permalink.json",
+						RawQuery: "context=3&raw_json=1",
+					},
+					Host: "reddit.com",
+				},
+			},
 		}, t,
 	)
 }
 
-func testRequests(cases []testCase, t *testing.T) {
-	c := &mockClient{}
-	r := &reaperImpl{
-		cli:        c,
-		parser:     &mockParser{},
-		hostname:   "reddit.com",
-		reapSuffix: ".json",
-		scheme:     "https",
-		mu:         &sync.Mutex{},
-	}
-	b := &bot{
-		Account: newAccount(r),
-		Lurker:  newLurker(r),
-		Scanner: newScanner(r),
-	}
-	for _, test := range cases {
-		if err := test.f(b); err != test.err {
-			t.Errorf("[%s] unexpected error: %v", test.name, err)
-		}
-
-		if diff := pretty.Compare(c.request, test.correct); diff != "" {
-			t.Errorf(
-				"[%s] request incorrect; diff: %s",
-				test.name,
-				diff,
+func TestWiki(t *testing.T) {
+	testRequests(
+		[]testCase{
+			testCase{
+				name: "WikiPage",
+				f: func(b Bot) error {
+					_, err := b.WikiPage("sub", "config")
+					return err
+				},
+				correct: http.Request{
+					Method: "GET",
+					URL: &url.URL{
+						Scheme:   "https",
+						Host:     "reddit.com",
+						Path:     "/r/sub/wiki/config.json",
+						RawQuery: "raw_json=1",
+					},
+					Host: "reddit.com",
+				},
+			},
+			testCase{
+				name: "WikiRevisions",
+				f: func(b Bot) error {
+					_, err := b.WikiRevisions("sub", "config", 10)
+					return err
+				},
+				correct: http.Request{
+					Method: "GET",
+					URL: &url.URL{
+						Scheme:   "https",
+						Host:     "reddit.com",
+						Path:     "/r/sub/wiki/revisions/config.json",
+						RawQuery: "limit=10&raw_json=1",
+					},
+					Host: "reddit.com",
+				},
+			},
+			testCase{
+				name: "EditWikiPage",
+				f: func(b Bot) error {
+					return b.EditWikiPage("sub", "config", WikiEdit{
+						Content:  "text",
+						Reason:   "why",
+						Previous: "rev",
+					})
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/r/sub/api/wiki/edit",
+					},
+					Host: "reddit.com",
+					Header: formHeader(
+						"content=text&page=config&previous=rev&reason=why",
+					),
+				},
+				body: "content=text&page=config&previous=rev&reason=why",
+			},
+		}, t,
+	)
+}
+
+func TestModerator(t *testing.T) {
+	testRequests(
+		[]testCase{
+			testCase{
+				name: "ModLog",
+				f: func(b Bot) error {
+					_, err := b.ModLog("sub", map[string]string{"type": "banuser"})
+					return err
+				},
+				correct: http.Request{
+					Method: "GET",
+					URL: &url.URL{
+						Scheme:   "https",
+						Host:     "reddit.com",
+						Path:     "/r/sub/about/log.json",
+						RawQuery: "raw_json=1&type=banuser",
+					},
+					Host: "reddit.com",
+				},
+			},
+			testCase{
+				name: "Banned",
+				f: func(b Bot) error {
+					_, err := b.Banned("sub", "user")
+					return err
+				},
+				correct: http.Request{
+					Method: "GET",
+					URL: &url.URL{
+						Scheme:   "https",
+						Host:     "reddit.com",
+						Path:     "/r/sub/about/banned.json",
+						RawQuery: "user=user",
+					},
+					Host: "reddit.com",
+				},
+			},
+			testCase{
+				name: "Muted",
+				f: func(b Bot) error {
+					_, err := b.Muted("sub", "user")
+					return err
+				},
+				correct: http.Request{
+					Method: "GET",
+					URL: &url.URL{
+						Scheme:   "https",
+						Host:     "reddit.com",
+						Path:     "/r/sub/about/muted.json",
+						RawQuery: "user=user",
+					},
+					Host: "reddit.com",
+				},
+			},
+			testCase{
+				name: "ReplyModmail",
+				f: func(b Bot) error {
+					return b.ReplyModmail("abc", "text", true)
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/mod/conversations/abc",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("body=text&isAuthorHidden=false&isInternal=true"),
+				},
+				body: "body=text&isAuthorHidden=false&isInternal=true",
+			},
+			testCase{
+				name: "HighlightModmail",
+				f: func(b Bot) error {
+					return b.HighlightModmail("abc", false)
+				},
+				correct: http.Request{
+					Method: "DELETE",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/mod/conversations/abc/highlight",
+					},
+					Host: "reddit.com",
+				},
+			},
+			testCase{
+				name: "IsModerator",
+				f: func(b Bot) error {
+					_, err := b.IsModerator("sub", "user")
+					return err
+				},
+				correct: http.Request{
+					Method: "GET",
+					URL: &url.URL{
+						Scheme:   "https",
+						Host:     "reddit.com",
+						Path:     "/r/sub/about/moderators.json",
+						RawQuery: "user=user",
+					},
+					Host: "reddit.com",
+				},
+			},
+			testCase{
+				name: "AboutUser",
+				f: func(b Bot) error {
+					_, err := b.AboutUser("user")
+					return err
+				},
+				correct: http.Request{
+					Method: "GET",
+					URL: &url.URL{
+						Scheme:   "https",
+						Host:     "reddit.com",
+						Path:     "/user/user/about.json",
+						RawQuery: "raw_json=1",
+					},
+					Host: "reddit.com",
+				},
+			},
+			testCase{
+				name: "UserComments",
+				f: func(b Bot) error {
+					_, _, err := b.UserComments("user", HistoryOptions{
+						Sort:  "top",
+						Time:  "month",
+						Limit: 25,
+						After: "t1_a",
+						Count: 25,
+					})
+					return err
+				},
+				correct: http.Request{
+					Method: "GET",
+					URL: &url.URL{
+						Scheme:   "https",
+						Host:     "reddit.com",
+						Path:     "/user/user/comments.json",
+						RawQuery: "after=t1_a&count=25&limit=25&raw_json=1&sort=top&t=month",
+					},
+					Host: "reddit.com",
+				},
+			},
+			testCase{
+				name: "UserPosts",
+				f: func(b Bot) error {
+					_, _, err := b.UserPosts("user", HistoryOptions{})
+					return err
+				},
+				correct: http.Request{
+					Method: "GET",
+					URL: &url.URL{
+						Scheme:   "https",
+						Host:     "reddit.com",
+						Path:     "/user/user/submitted.json",
+						RawQuery: "limit=100&raw_json=1",
+					},
+					Host: "reddit.com",
+				},
+			},
+			testCase{
+				name: "UserTrophies",
+				f: func(b Bot) error {
+					_, err := b.UserTrophies("user")
+					return err
+				},
+				correct: http.Request{
+					Method: "GET",
+					URL: &url.URL{
+						Scheme:   "https",
+						Host:     "reddit.com",
+						Path:     "/api/v1/user/user/trophies.json",
+						RawQuery: "raw_json=1",
+					},
+					Host: "reddit.com",
+				},
+			},
+			testCase{
+				name: "UserFlair",
+				f: func(b Bot) error {
+					_, err := b.UserFlair("sub", "user")
+					return err
+				},
+				correct: http.Request{
+					Method: "GET",
+					URL: &url.URL{
+						Scheme:   "https",
+						Host:     "reddit.com",
+						Path:     "/r/sub/api/flairlist.json",
+						RawQuery: "name=user&raw_json=1",
+					},
+					Host: "reddit.com",
+				},
+			},
+			testCase{
+				name: "LinkFlairTemplates",
+				f: func(b Bot) error {
+					_, err := b.LinkFlairTemplates("sub")
+					return err
+				},
+				correct: http.Request{
+					Method: "GET",
+					URL: &url.URL{
+						Scheme:   "https",
+						Host:     "reddit.com",
+						Path:     "/r/sub/api/link_flair_v2.json",
+						RawQuery: "raw_json=1",
+					},
+					Host: "reddit.com",
+				},
+			},
+			testCase{
+				name: "SetLinkFlair",
+				f: func(b Bot) error {
+					return b.SetLinkFlair("sub", "t3_post", FlairChoice{
+						TemplateID: "abc",
+						Text:       "Solved",
+					})
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/r/sub/api/selectflair",
+					},
+					Host: "reddit.com",
+					Header: formHeader(
+						"api_type=json&flair_template_id=abc&link=t3_post&text=Solved",
+					),
+				},
+				body: "api_type=json&flair_template_id=abc&link=t3_post&text=Solved",
+			},
+			testCase{
+				name: "SetUserFlair",
+				f: func(b Bot) error {
+					return b.SetUserFlair("sub", "user", FlairChoice{
+						Text:     "Helper",
+						CSSClass: "helper",
+					})
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/r/sub/api/flair",
+					},
+					Host: "reddit.com",
+					Header: formHeader(
+						"api_type=json&css_class=helper&name=user&text=Helper",
+					),
+				},
+				body: "api_type=json&css_class=helper&name=user&text=Helper",
+			},
+			testCase{
+				name: "SetFlairCSV",
+				f: func(b Bot) error {
+					_, err := b.SetFlairCSV("sub", []Flair{
+						{User: "a", Text: "Helper, first class", CSSClass: "helper"},
+						{User: "b"},
+					})
+					return err
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/r/sub/api/flaircsv",
+					},
+					Host: "reddit.com",
+					Header: formHeader(
+						"flair_csv=a%2C%22Helper%2C+first+class%22%2Chelper%0Ab%2C%2C%0A",
+					),
+				},
+				body: "flair_csv=a%2C%22Helper%2C+first+class%22%2Chelper%0Ab%2C%2C%0A",
+			},
+			testCase{
+				name: "BanUser",
+				f: func(b Bot) error {
+					return b.BanUser("sub", "user", Ban{Days: 3, Reason: "spam"})
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/r/sub/api/friend",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("ban_reason=spam&duration=3&name=user&type=banned"),
+				},
+				body: "ban_reason=spam&duration=3&name=user&type=banned",
+			},
+			testCase{
+				name: "BannedUsers",
+				f: func(b Bot) error {
+					_, err := b.BannedUsers("sub")
+					return err
+				},
+				correct: http.Request{
+					Method: "GET",
+					URL: &url.URL{
+						Scheme:   "https",
+						Host:     "reddit.com",
+						Path:     "/r/sub/about/banned.json",
+						RawQuery: "limit=100",
+					},
+					Host: "reddit.com",
+				},
+			},
+			testCase{
+				name: "Unban",
+				f: func(b Bot) error {
+					return b.Unban("sub", "user")
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/r/sub/api/unfriend",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("name=user&type=banned"),
+				},
+				body: "name=user&type=banned",
+			},
+			testCase{
+				name: "SetContestMode",
+				f: func(b Bot) error {
+					return b.SetContestMode("t3_post", true)
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/set_contest_mode",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("api_type=json&id=t3_post&state=true"),
+				},
+				body: "api_type=json&id=t3_post&state=true",
+			},
+			testCase{
+				name: "Remove",
+				f: func(b Bot) error {
+					return b.Remove("t3_post", true)
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/remove",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("id=t3_post&spam=true"),
+				},
+				body: "id=t3_post&spam=true",
+			},
+			testCase{
+				name: "Approve",
+				f: func(b Bot) error {
+					return b.Approve("t1_comment")
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/approve",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("id=t1_comment"),
+				},
+				body: "id=t1_comment",
+			},
+			testCase{
+				name: "Lock",
+				f: func(b Bot) error {
+					return b.Lock("t3_post")
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/lock",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("id=t3_post"),
+				},
+				body: "id=t3_post",
+			},
+			testCase{
+				name: "Unlock",
+				f: func(b Bot) error {
+					return b.Unlock("t3_post")
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/unlock",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("id=t3_post"),
+				},
+				body: "id=t3_post",
+			},
+			testCase{
+				name: "Sticky",
+				f: func(b Bot) error {
+					return b.Sticky("t3_post", false)
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/set_subreddit_sticky",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("api_type=json&id=t3_post&state=false"),
+				},
+				body: "api_type=json&id=t3_post&state=false",
+			},
+			testCase{
+				name: "SetSuggestedSort",
+				f: func(b Bot) error {
+					return b.SetSuggestedSort("t3_post", SortLive)
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/set_suggested_sort",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("api_type=json&id=t3_post&sort=live"),
+				},
+				body: "api_type=json&id=t3_post&sort=live",
+			},
+			testCase{
+				name: "Distinguish",
+				f: func(b Bot) error {
+					return b.Distinguish("t1_comment", DistinguishModerator)
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/distinguish",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("api_type=json&how=yes&id=t1_comment"),
+				},
+				body: "api_type=json&how=yes&id=t1_comment",
+			},
+			testCase{
+				name: "IgnoreReports",
+				f: func(b Bot) error {
+					return b.IgnoreReports("t3_post", true)
+				},
+				correct: http.Request{
+					Method: "POST",
+					URL: &url.URL{
+						Scheme: "https",
+						Host:   "reddit.com",
+						Path:   "/api/ignore_reports",
+					},
+					Host:   "reddit.com",
+					Header: formHeader("id=t3_post"),
+				},
+				body: "id=t3_post",
+			},
+		}, t,
+	)
+}
+
+func testRequests(cases []testCase, t *testing.T) {
+	c := &mockClient{}
+	r := &reaperImpl{
+		cli:        c,
+		parser:     &mockParser{},
+		hostname:   "reddit.com",
+		reapSuffix: ".json",
+		scheme:     "https",
+		clock:      clock.Real(),
+		limit:      newRateLimit(),
+	}
+	b := &bot{
+		Account:       newAccount(r),
+		Lurker:        newLurker(r),
+		UserLurker:    newUserLurker(r),
+		Scanner:       newScanner(r),
+		Wiki:          newWiki(r),
+		Moderator:     newModerator(r),
+		MediaUploader: newMediaUploader(r),
+		Modmail:       newModmail(r),
+		Live:          newLive(r),
+	}
+	for _, test := range cases {
+		if err := test.f(b); err != test.err {
+			t.Errorf("[%s] unexpected error: %v", test.name, err)
+		}
+
+		if diff := pretty.Compare(c.request, test.correct); diff != "" {
+			t.Errorf(
+				"[%s] request incorrect; diff: %s",
+				test.name,
+				diff,
+			)
+		}
+
+		if c.body != test.body {
+			t.Errorf(
+				"[%s] body incorrect; got %q; wanted %q",
+				test.name,
+				c.body,
+				test.body,
 			)
 		}
 	}
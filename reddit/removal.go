@@ -0,0 +1,126 @@
+package reddit
+
+// RemovalSource classifies who removed a post or comment.
+type RemovalSource string
+
+const (
+	// RemovedByAuthor is an element its author deleted.
+	RemovedByAuthor RemovalSource = "author"
+	// RemovedByModerator is an element a moderator of the subreddit
+	// removed by hand.
+	RemovedByModerator RemovalSource = "moderator"
+	// RemovedByAutomod is an element AutoModerator removed or filtered.
+	RemovedByAutomod RemovalSource = "automod"
+	// RemovedByReddit is an element Reddit removed: its admins, spam
+	// filter, or a legal or copyright takedown.
+	RemovedByReddit RemovalSource = "reddit"
+	// RemovedByUnknown is a removed element whose remover is not visible,
+	// or whose removal category is new to this package.
+	RemovedByUnknown RemovalSource = "unknown"
+)
+
+// automod is the name of Reddit's AutoModerator account.
+const automod = "AutoModerator"
+
+// removalCategories are the sources of Reddit's removed_by_category values.
+var removalCategories = map[string]RemovalSource{
+	"deleted":            RemovedByAuthor,
+	"author":             RemovedByAuthor,
+	"moderator":          RemovedByModerator,
+	"automod_filtered":   RemovedByAutomod,
+	"reddit":             RemovedByReddit,
+	"anti_evil_ops":      RemovedByReddit,
+	"community_ops":      RemovedByReddit,
+	"legal_operations":   RemovedByReddit,
+	"copyright_takedown": RemovedByReddit,
+	"content_takedown":   RemovedByReddit,
+}
+
+// Removal describes how a post or comment was removed.
+type Removal struct {
+	Source RemovalSource
+	// Category is the post's removed_by_category as Reddit reports it,
+	// such as "copyright_takedown". Comments have none.
+	Category string
+	// By is the name of the moderator who removed the element and Note
+	// their ban note, if they are visible; only moderators of the
+	// subreddit see them.
+	By   string
+	Note string
+	// Spam is set when the element was removed as spam.
+	Spam bool
+}
+
+// Removal returns how the post was removed, if it was.
+func (p *Post) Removal() (r Removal, ok bool) {
+	r = removal(p.BannedBy, p.BanNote)
+	r.Category = p.RemovedByCategory
+
+	switch {
+	case p.RemovedByCategory != "":
+		r.Source = removalCategories[p.RemovedByCategory]
+		if r.Source == "" {
+			r.Source = RemovedByUnknown
+		}
+		if r.Source == RemovedByModerator && r.By == automod {
+			r.Source = RemovedByAutomod
+		}
+	case r.Source != "":
+	case p.Removed:
+		r.Source = RemovedByUnknown
+	case p.Author == deletedKey && p.IsSelf && p.SelfText == deletedKey:
+		r.Source = RemovedByAuthor
+	case p.IsSelf && p.SelfText == removedBody:
+		r.Source = RemovedByUnknown
+	default:
+		return Removal{}, false
+	}
+
+	return r, true
+}
+
+// Removal returns how the comment was removed, if it was.
+func (c *Comment) Removal() (r Removal, ok bool) {
+	r = removal(c.BannedBy, c.BanNote)
+
+	switch {
+	case r.Source != "":
+	case c.Removed:
+		r.Source = RemovedByUnknown
+	case c.Body == deletedKey:
+		r.Source = RemovedByAuthor
+	case c.Body == removedBody:
+		r.Source = RemovedByUnknown
+	default:
+		return Removal{}, false
+	}
+
+	return r, true
+}
+
+// removedBody is the body Reddit shows in place of a removed element's to
+// those who may not see it.
+const removedBody = "[removed]"
+
+// removal classifies an element's removal by its banned_by and ban_note, as
+// moderators see them. The source is left empty if banned_by is.
+func removal(bannedBy interface{}, note string) Removal {
+	r := Removal{Note: note}
+	switch by := bannedBy.(type) {
+	case string:
+		if by == "" {
+			break
+		}
+		r.By = by
+		r.Source = RemovedByModerator
+		if by == automod {
+			r.Source = RemovedByAutomod
+		}
+	case bool:
+		if by {
+			r.Source = RemovedByReddit
+			r.Spam = true
+		}
+	}
+	return r
+}
@@ -0,0 +1,85 @@
+package reddit
+
+import (
+	"testing"
+)
+
+func TestPostRemoval(t *testing.T) {
+	for i, test := range []struct {
+		post    Post
+		removal Removal
+		ok      bool
+	}{
+		{Post{IsSelf: true, SelfText: "hi"}, Removal{}, false},
+		{
+			Post{RemovedByCategory: "automod_filtered"},
+			Removal{Source: RemovedByAutomod, Category: "automod_filtered"},
+			true,
+		},
+		{
+			Post{RemovedByCategory: "moderator", BannedBy: "AutoModerator"},
+			Removal{Source: RemovedByAutomod, Category: "moderator", By: "AutoModerator"},
+			true,
+		},
+		{
+			Post{RemovedByCategory: "moderator", BannedBy: "mod", BanNote: "spam link"},
+			Removal{
+				Source:   RemovedByModerator,
+				Category: "moderator",
+				By:       "mod",
+				Note:     "spam link",
+			},
+			true,
+		},
+		{
+			Post{RemovedByCategory: "copyright_takedown"},
+			Removal{Source: RemovedByReddit, Category: "copyright_takedown"},
+			true,
+		},
+		{
+			Post{RemovedByCategory: "deleted"},
+			Removal{Source: RemovedByAuthor, Category: "deleted"},
+			true,
+		},
+		{
+			Post{RemovedByCategory: "something_new"},
+			Removal{Source: RemovedByUnknown, Category: "something_new"},
+			true,
+		},
+		{
+			Post{BannedBy: true, Removed: true},
+			Removal{Source: RemovedByReddit, Spam: true},
+			true,
+		},
+		{
+			Post{IsSelf: true, SelfText: "[removed]"},
+			Removal{Source: RemovedByUnknown},
+			true,
+		},
+	} {
+		removal, ok := test.post.Removal()
+		if ok != test.ok || removal != test.removal {
+			t.Errorf("%d: got %+v, %t; wanted %+v, %t", i, removal, ok, test.removal, test.ok)
+		}
+	}
+}
+
+func TestCommentRemoval(t *testing.T) {
+	for i, test := range []struct {
+		comment Comment
+		source  RemovalSource
+		ok      bool
+	}{
+		{Comment{Body: "hi"}, "", false},
+		{Comment{Body: "[deleted]", Author: "[deleted]"}, RemovedByAuthor, true},
+		{Comment{Body: "[removed]"}, RemovedByUnknown, true},
+		{Comment{Body: "hi", BannedBy: "AutoModerator", Removed: true}, RemovedByAutomod, true},
+		{Comment{Body: "hi", BannedBy: "mod", Removed: true}, RemovedByModerator, true},
+		{Comment{Body: "hi", BannedBy: false}, "", false},
+	} {
+		removal, ok := test.comment.Removal()
+		if ok != test.ok || removal.Source != test.source {
+			t.Errorf("%d: got %+v, %t; wanted %s, %t", i, removal, ok, test.source, test.ok)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package reddit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+)
+
+// TooOldErr is returned by the replies of a guarded Bot to posts and comments
+// its ReplyGuard refuses.
+var TooOldErr = fmt.Errorf("the post or comment is too old to reply to")
+
+// ReplyGuard keeps a bot from replying to old posts and comments, such as the
+// years old threads a search stream turns up. Each reply to a post or comment
+// costs a request more, to look up its age.
+type ReplyGuard struct {
+	// MaxAge is the age past which posts and comments are not replied
+	// to. If zero, any age is.
+	MaxAge time.Duration
+	// If Archived is set, archived posts and comments are not replied to
+	// either, whatever their age.
+	Archived bool
+}
+
+// allowOldKey is the context key of AllowOldReplies.
+type allowOldKey struct{}
+
+// AllowOldReplies returns a context which lifts the bot's ReplyGuard from the
+// replies of a view of the bot made with it by WithContext, for the rare reply
+// meant for an old thread.
+func AllowOldReplies(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allowOldKey{}, true)
+}
+
+// guardedBot is a Bot whose replies are checked against a ReplyGuard.
+type guardedBot struct {
+	Bot
+	r     reaper
+	guard ReplyGuard
+	clock clock.Clock
+	// allowed is set on views made with AllowOldReplies.
+	allowed bool
+}
+
+// guard returns b guarded by g, or b itself if g guards nothing.
+func guard(b Bot, r reaper, g ReplyGuard, c clock.Clock) Bot {
+	if g == (ReplyGuard{}) {
+		return b
+	}
+	if c == nil {
+		c = clock.Real()
+	}
+	return &guardedBot{Bot: b, r: r, guard: g, clock: c}
+}
+
+func (g *guardedBot) view(b Bot) Bot {
+	return &guardedBot{Bot: b, r: g.r, guard: g.guard, clock: g.clock, allowed: g.allowed}
+}
+
+func (g *guardedBot) ReadOnly() Bot {
+	return g.view(g.Bot.ReadOnly())
+}
+
+func (g *guardedBot) Restrict(p Permission) Bot {
+	return g.view(g.Bot.Restrict(p))
+}
+
+func (g *guardedBot) WithContext(ctx context.Context) Bot {
+	allowed, _ := ctx.Value(allowOldKey{}).(bool)
+	return &guardedBot{
+		Bot:     g.Bot.WithContext(ctx),
+		r:       g.r.withContext(ctx),
+		guard:   g.guard,
+		clock:   g.clock,
+		allowed: allowed,
+	}
+}
+
+func (g *guardedBot) Reply(parentName, text string) error {
+	if err := g.check(parentName); err != nil {
+		return err
+	}
+	return g.Bot.Reply(parentName, text)
+}
+
+func (g *guardedBot) GetReply(parentName, text string) (Submission, error) {
+	if err := g.check(parentName); err != nil {
+		return Submission{}, err
+	}
+	return g.Bot.GetReply(parentName, text)
+}
+
+// check returns TooOldErr if the guard refuses replies to the parent. Messages
+// are always replied to.
+func (g *guardedBot) check(parentName string) error {
+	if g.allowed || strings.HasPrefix(parentName, "t4_") {
+		return nil
+	}
+
+	h, err := g.r.reap(
+		"/api/info",
+		map[string]string{"raw_json": "1", "id": parentName},
+	)
+	if err != nil {
+		return err
+	}
+
+	var created uint64
+	var archived bool
+	switch {
+	case len(h.Posts) > 0:
+		created, archived = h.Posts[0].CreatedUTC, h.Posts[0].Archived
+	case len(h.Comments) > 0:
+		created, archived = h.Comments[0].CreatedUTC, h.Comments[0].Archived
+	default:
+		// Reddit will report what is wrong with the parent itself.
+		return nil
+	}
+
+	if g.guard.Archived && archived {
+		return TooOldErr
+	}
+
+	age := g.clock.Now().Sub(time.Unix(int64(created), 0))
+	if g.guard.MaxAge > 0 && age > g.guard.MaxAge {
+		return TooOldErr
+	}
+	return nil
+}
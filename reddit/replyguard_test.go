@@ -0,0 +1,60 @@
+package reddit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+)
+
+// replyBot counts the replies it makes.
+type replyBot struct {
+	Bot
+	replies int
+}
+
+func (r *replyBot) Reply(parentName, text string) error {
+	r.replies++
+	return nil
+}
+
+func (r *replyBot) WithContext(ctx context.Context) Bot {
+	return r
+}
+
+func TestReplyGuard(t *testing.T) {
+	now := time.Unix(100*24*60*60, 0)
+	day := uint64(24 * 60 * 60)
+	for i, test := range []struct {
+		h       Harvest
+		parent  string
+		allow   bool
+		replied bool
+	}{
+		{h: Harvest{Posts: []*Post{{CreatedUTC: 99 * day}}}, parent: "t3_new", replied: true},
+		{h: Harvest{Posts: []*Post{{CreatedUTC: 50 * day}}}, parent: "t3_old"},
+		{h: Harvest{Comments: []*Comment{{CreatedUTC: 99 * day, Archived: true}}}, parent: "t1_archived"},
+		{h: Harvest{Posts: []*Post{{CreatedUTC: 50 * day}}}, parent: "t3_old", allow: true, replied: true},
+		{parent: "t4_message", replied: true},
+	} {
+		inner := &replyBot{}
+		r := &mockReaper{h: test.h}
+		var b Bot = guard(
+			inner, r,
+			ReplyGuard{MaxAge: 30 * 24 * time.Hour, Archived: true},
+			clock.NewSimulation(now),
+		)
+		if test.allow {
+			b = b.WithContext(AllowOldReplies(context.Background()))
+		}
+
+		err := b.Reply(test.parent, "text")
+		if test.replied && (err != nil || inner.replies != 1) {
+			t.Errorf("%d: wanted a reply; got %v", i, err)
+		}
+		if !test.replied && (err != TooOldErr || inner.replies != 0) {
+			t.Errorf("%d: wanted the reply refused; got %v", i, err)
+		}
+	}
+}
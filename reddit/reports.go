@@ -0,0 +1,58 @@
+package reddit
+
+// Report is one reason an element was reported for.
+type Report struct {
+	Reason string
+	// Count is how many users reported the element for this reason.
+	// Moderator reports count once each.
+	Count int
+	// Moderators are the names of the moderators who reported the element
+	// for this reason.
+	Moderators []string
+}
+
+// ParseReports merges the user and moderator reports of an element, as
+// Reddit returns them in user_reports and mod_reports, into one report per
+// reason, in the order the reasons first appear. Malformed entries are
+// skipped.
+func ParseReports(userReports, modReports [][]interface{}) []Report {
+	var reports []Report
+	index := make(map[string]int)
+	add := func(reason string) *Report {
+		i, ok := index[reason]
+		if !ok {
+			i = len(reports)
+			index[reason] = i
+			reports = append(reports, Report{Reason: reason})
+		}
+		return &reports[i]
+	}
+
+	for _, r := range userReports {
+		if len(r) < 2 {
+			continue
+		}
+		reason, _ := r[0].(string)
+		count, ok := r[1].(float64)
+		if !ok {
+			continue
+		}
+		add(reason).Count += int(count)
+	}
+
+	for _, r := range modReports {
+		if len(r) < 2 {
+			continue
+		}
+		reason, _ := r[0].(string)
+		moderator, ok := r[1].(string)
+		if !ok {
+			continue
+		}
+		report := add(reason)
+		report.Count++
+		report.Moderators = append(report.Moderators, moderator)
+	}
+
+	return reports
+}
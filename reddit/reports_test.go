@@ -0,0 +1,27 @@
+package reddit
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestParseReports(t *testing.T) {
+	reports := ParseReports(
+		[][]interface{}{
+			{"spam", float64(3), false, false},
+			{"rule 1", float64(1)},
+			{"malformed"},
+		},
+		[][]interface{}{{"spam", "mod"}, {"rule 2", "other"}},
+	)
+
+	expected := []Report{
+		{Reason: "spam", Count: 4, Moderators: []string{"mod"}},
+		{Reason: "rule 1", Count: 1},
+		{Reason: "rule 2", Count: 1, Moderators: []string{"other"}},
+	}
+	if diff := pretty.Compare(reports, expected); diff != "" {
+		t.Errorf("reports incorrect; diff: %s", diff)
+	}
+}
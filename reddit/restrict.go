@@ -0,0 +1,557 @@
+package reddit
+
+import (
+	"context"
+	"fmt"
+)
+
+var (
+	// ReadOnlyErr is returned by the write methods of a read only Bot.
+	ReadOnlyErr = fmt.Errorf("this bot handle is read only")
+	// NotPermittedErr is returned by the methods of a restricted Bot
+	// which it was not granted permission to use.
+	NotPermittedErr = fmt.Errorf("this bot handle is not permitted to do that")
+)
+
+// Permission is a set of actions a restricted Bot may perform. Permissions
+// combine with |, e.g. MayReply|MayMessage.
+type Permission uint
+
+const (
+	// MayReply permits Reply and GetReply.
+	MayReply Permission = 1 << iota
+	// MayMessage permits SendMessage.
+	MayMessage
+	// MayPost permits PostSelf, GetPostSelf, GetPostSelfWithFlair, PostLink,
+	// GetPostLink, SubmitGallery, SubmitPoll, SubmitVideo, UploadMedia, and
+	// UploadStyleImage.
+	MayPost
+	// MayRevoke permits RevokeToken and RevokeAll.
+	MayRevoke
+	// MayEditWiki permits EditWikiPage.
+	MayEditWiki
+	// MayBan permits BanUser and Unban.
+	MayBan
+	// MayModeratePosts permits Remove, Approve, Lock, Unlock, Sticky,
+	// Distinguish, IgnoreReports, SetContestMode, and SetSuggestedSort.
+	MayModeratePosts
+	// MayBlock permits Block, Unblock, and BlockSender.
+	MayBlock
+	// MayModmail permits ReplyModmail, ArchiveModmail, UnarchiveModmail,
+	// HighlightModmail, MuteModmail, and UnmuteModmail.
+	MayModmail
+	// MayFlair permits SetLinkFlair, SetUserFlair, and SetFlairCSV.
+	MayFlair
+	// MayVote permits Upvote, Downvote, and ClearVote.
+	MayVote
+	// MaySave permits Save, Unsave, Hide, and Unhide.
+	MaySave
+	// MayReport permits Report.
+	MayReport
+	// MayEdit permits EditText and Delete.
+	MayEdit
+	// MayManageInbox permits MarkRead, MarkUnread, MarkAllRead,
+	// CollapseMessages, and UncollapseMessages.
+	MayManageInbox
+	// MayLive permits CreateLiveThread, EditLiveThread, PostLiveUpdate,
+	// StrikeLiveUpdate, DeleteLiveUpdate, and CloseLiveThread.
+	MayLive
+	// MayConfigure permits SetDefaultSort.
+	MayConfigure
+)
+
+// restrictedBot is a Bot whose write methods fail unless it was granted the
+// permission for them. Every method of Bot which changes anything on Reddit
+// must be overridden here.
+type restrictedBot struct {
+	Bot
+	allowed Permission
+	// err is returned when a method is not permitted.
+	err error
+	// gate, if set, is asked before every permitted write, which fails
+	// with the error it returns.
+	gate func() error
+}
+
+// allPermissions permits every write method.
+const allPermissions = ^Permission(0)
+
+// Gate returns a view of the bot which asks gate before every write, and
+// fails the write with the error it returns, if it returns one. Reads, and
+// RevokeToken and RevokeAll, are unaffected. Gates are kept by Restrict and WithContext, so a kill switch,
+// for example, can halt every write of a bot and its views at once.
+func Gate(b Bot, gate func() error) Bot {
+	return &restrictedBot{Bot: b, allowed: allPermissions, gate: gate}
+}
+
+func (r *restrictedBot) check(p Permission) error {
+	if r.allowed&p != p {
+		return r.err
+	}
+	// Revoking is never gated, so a bot halted by a kill switch can still
+	// have its credentials revoked.
+	if r.gate != nil && p != MayRevoke {
+		return r.gate()
+	}
+	return nil
+}
+
+func (r *restrictedBot) ReadOnly() Bot {
+	return &restrictedBot{Bot: r.Bot, err: ReadOnlyErr}
+}
+
+func (r *restrictedBot) Restrict(p Permission) Bot {
+	err := r.err
+	if err == nil {
+		err = NotPermittedErr
+	}
+	return &restrictedBot{Bot: r.Bot, allowed: r.allowed & p, err: err, gate: r.gate}
+}
+
+func (r *restrictedBot) WithContext(ctx context.Context) Bot {
+	return &restrictedBot{
+		Bot:     r.Bot.WithContext(ctx),
+		allowed: r.allowed,
+		err:     r.err,
+		gate:    r.gate,
+	}
+}
+
+func (r *restrictedBot) Reply(parentName, text string) error {
+	if err := r.check(MayReply); err != nil {
+		return err
+	}
+	return r.Bot.Reply(parentName, text)
+}
+
+func (r *restrictedBot) GetReply(parentName, text string) (Submission, error) {
+	if err := r.check(MayReply); err != nil {
+		return Submission{}, err
+	}
+	return r.Bot.GetReply(parentName, text)
+}
+
+func (r *restrictedBot) SendMessage(user, subject, text string) error {
+	if err := r.check(MayMessage); err != nil {
+		return err
+	}
+	return r.Bot.SendMessage(user, subject, text)
+}
+
+func (r *restrictedBot) PostSelf(subreddit, title, text string) error {
+	if err := r.check(MayPost); err != nil {
+		return err
+	}
+	return r.Bot.PostSelf(subreddit, title, text)
+}
+
+func (r *restrictedBot) GetPostSelf(subreddit, title, text string) (Submission, error) {
+	if err := r.check(MayPost); err != nil {
+		return Submission{}, err
+	}
+	return r.Bot.GetPostSelf(subreddit, title, text)
+}
+
+func (r *restrictedBot) GetPostSelfWithFlair(
+	subreddit, title, text string,
+	flair PostFlair,
+) (Submission, error) {
+	if err := r.check(MayPost); err != nil {
+		return Submission{}, err
+	}
+	return r.Bot.GetPostSelfWithFlair(subreddit, title, text, flair)
+}
+
+func (r *restrictedBot) PostLink(subreddit, title, url string) error {
+	if err := r.check(MayPost); err != nil {
+		return err
+	}
+	return r.Bot.PostLink(subreddit, title, url)
+}
+
+func (r *restrictedBot) GetPostLink(subreddit, title, url string) (Submission, error) {
+	if err := r.check(MayPost); err != nil {
+		return Submission{}, err
+	}
+	return r.Bot.GetPostLink(subreddit, title, url)
+}
+
+func (r *restrictedBot) SubmitGallery(
+	subreddit, title string,
+	images []GalleryImage,
+) (Submission, error) {
+	if err := r.check(MayPost); err != nil {
+		return Submission{}, err
+	}
+	return r.Bot.SubmitGallery(subreddit, title, images)
+}
+
+func (r *restrictedBot) SubmitPoll(subreddit, title string, poll Poll) (Submission, error) {
+	if err := r.check(MayPost); err != nil {
+		return Submission{}, err
+	}
+	return r.Bot.SubmitPoll(subreddit, title, poll)
+}
+
+func (r *restrictedBot) SubmitVideo(subreddit, title string, video Video) (Submission, error) {
+	if err := r.check(MayPost); err != nil {
+		return Submission{}, err
+	}
+	return r.Bot.SubmitVideo(subreddit, title, video)
+}
+
+func (r *restrictedBot) UploadMedia(file MediaFile) (Upload, error) {
+	if err := r.check(MayPost); err != nil {
+		return Upload{}, err
+	}
+	return r.Bot.UploadMedia(file)
+}
+
+func (r *restrictedBot) UploadStyleImage(
+	subreddit, image string,
+	file MediaFile,
+) (string, error) {
+	if err := r.check(MayPost); err != nil {
+		return "", err
+	}
+	return r.Bot.UploadStyleImage(subreddit, image, file)
+}
+
+func (r *restrictedBot) RevokeToken() error {
+	if err := r.check(MayRevoke); err != nil {
+		return err
+	}
+	return r.Bot.RevokeToken()
+}
+
+func (r *restrictedBot) RevokeAll() error {
+	if err := r.check(MayRevoke); err != nil {
+		return err
+	}
+	return r.Bot.RevokeAll()
+}
+
+func (r *restrictedBot) EditWikiPage(subreddit, page string, edit WikiEdit) error {
+	if err := r.check(MayEditWiki); err != nil {
+		return err
+	}
+	return r.Bot.EditWikiPage(subreddit, page, edit)
+}
+
+func (r *restrictedBot) BanUser(subreddit, user string, ban Ban) error {
+	if err := r.check(MayBan); err != nil {
+		return err
+	}
+	return r.Bot.BanUser(subreddit, user, ban)
+}
+
+func (r *restrictedBot) Unban(subreddit, user string) error {
+	if err := r.check(MayBan); err != nil {
+		return err
+	}
+	return r.Bot.Unban(subreddit, user)
+}
+
+func (r *restrictedBot) Remove(name string, spam bool) error {
+	if err := r.check(MayModeratePosts); err != nil {
+		return err
+	}
+	return r.Bot.Remove(name, spam)
+}
+
+func (r *restrictedBot) Approve(name string) error {
+	if err := r.check(MayModeratePosts); err != nil {
+		return err
+	}
+	return r.Bot.Approve(name)
+}
+
+func (r *restrictedBot) Lock(name string) error {
+	if err := r.check(MayModeratePosts); err != nil {
+		return err
+	}
+	return r.Bot.Lock(name)
+}
+
+func (r *restrictedBot) Unlock(name string) error {
+	if err := r.check(MayModeratePosts); err != nil {
+		return err
+	}
+	return r.Bot.Unlock(name)
+}
+
+func (r *restrictedBot) Sticky(post string, on bool) error {
+	if err := r.check(MayModeratePosts); err != nil {
+		return err
+	}
+	return r.Bot.Sticky(post, on)
+}
+
+func (r *restrictedBot) Distinguish(name, how string) error {
+	if err := r.check(MayModeratePosts); err != nil {
+		return err
+	}
+	return r.Bot.Distinguish(name, how)
+}
+
+func (r *restrictedBot) IgnoreReports(name string, on bool) error {
+	if err := r.check(MayModeratePosts); err != nil {
+		return err
+	}
+	return r.Bot.IgnoreReports(name, on)
+}
+
+func (r *restrictedBot) SetContestMode(post string, on bool) error {
+	if err := r.check(MayModeratePosts); err != nil {
+		return err
+	}
+	return r.Bot.SetContestMode(post, on)
+}
+
+func (r *restrictedBot) SetSuggestedSort(post string, sort CommentSort) error {
+	if err := r.check(MayModeratePosts); err != nil {
+		return err
+	}
+	return r.Bot.SetSuggestedSort(post, sort)
+}
+
+func (r *restrictedBot) SetDefaultSort(subreddit string, sort CommentSort) error {
+	if err := r.check(MayConfigure); err != nil {
+		return err
+	}
+	return r.Bot.SetDefaultSort(subreddit, sort)
+}
+
+func (r *restrictedBot) Block(user string) error {
+	if err := r.check(MayBlock); err != nil {
+		return err
+	}
+	return r.Bot.Block(user)
+}
+
+func (r *restrictedBot) Unblock(user string) error {
+	if err := r.check(MayBlock); err != nil {
+		return err
+	}
+	return r.Bot.Unblock(user)
+}
+
+func (r *restrictedBot) ReplyModmail(id, text string, internal bool) error {
+	if err := r.check(MayModmail); err != nil {
+		return err
+	}
+	return r.Bot.ReplyModmail(id, text, internal)
+}
+
+func (r *restrictedBot) ArchiveModmail(id string) error {
+	if err := r.check(MayModmail); err != nil {
+		return err
+	}
+	return r.Bot.ArchiveModmail(id)
+}
+
+func (r *restrictedBot) UnarchiveModmail(id string) error {
+	if err := r.check(MayModmail); err != nil {
+		return err
+	}
+	return r.Bot.UnarchiveModmail(id)
+}
+
+func (r *restrictedBot) HighlightModmail(id string, on bool) error {
+	if err := r.check(MayModmail); err != nil {
+		return err
+	}
+	return r.Bot.HighlightModmail(id, on)
+}
+
+func (r *restrictedBot) MuteModmail(id string, days int) error {
+	if err := r.check(MayModmail); err != nil {
+		return err
+	}
+	return r.Bot.MuteModmail(id, days)
+}
+
+func (r *restrictedBot) UnmuteModmail(id string) error {
+	if err := r.check(MayModmail); err != nil {
+		return err
+	}
+	return r.Bot.UnmuteModmail(id)
+}
+
+func (r *restrictedBot) SetLinkFlair(subreddit, post string, flair FlairChoice) error {
+	if err := r.check(MayFlair); err != nil {
+		return err
+	}
+	return r.Bot.SetLinkFlair(subreddit, post, flair)
+}
+
+func (r *restrictedBot) SetUserFlair(subreddit, user string, flair FlairChoice) error {
+	if err := r.check(MayFlair); err != nil {
+		return err
+	}
+	return r.Bot.SetUserFlair(subreddit, user, flair)
+}
+
+func (r *restrictedBot) SetFlairCSV(
+	subreddit string,
+	flairs []Flair,
+) ([]FlairResult, error) {
+	if err := r.check(MayFlair); err != nil {
+		return nil, err
+	}
+	return r.Bot.SetFlairCSV(subreddit, flairs)
+}
+
+func (r *restrictedBot) Upvote(name string) error {
+	if err := r.check(MayVote); err != nil {
+		return err
+	}
+	return r.Bot.Upvote(name)
+}
+
+func (r *restrictedBot) Downvote(name string) error {
+	if err := r.check(MayVote); err != nil {
+		return err
+	}
+	return r.Bot.Downvote(name)
+}
+
+func (r *restrictedBot) ClearVote(name string) error {
+	if err := r.check(MayVote); err != nil {
+		return err
+	}
+	return r.Bot.ClearVote(name)
+}
+
+func (r *restrictedBot) Save(name string) error {
+	if err := r.check(MaySave); err != nil {
+		return err
+	}
+	return r.Bot.Save(name)
+}
+
+func (r *restrictedBot) Unsave(name string) error {
+	if err := r.check(MaySave); err != nil {
+		return err
+	}
+	return r.Bot.Unsave(name)
+}
+
+func (r *restrictedBot) Hide(post string) error {
+	if err := r.check(MaySave); err != nil {
+		return err
+	}
+	return r.Bot.Hide(post)
+}
+
+func (r *restrictedBot) Unhide(post string) error {
+	if err := r.check(MaySave); err != nil {
+		return err
+	}
+	return r.Bot.Unhide(post)
+}
+
+func (r *restrictedBot) Report(name, reason string) error {
+	if err := r.check(MayReport); err != nil {
+		return err
+	}
+	return r.Bot.Report(name, reason)
+}
+
+func (r *restrictedBot) EditText(name, text string) error {
+	if err := r.check(MayEdit); err != nil {
+		return err
+	}
+	return r.Bot.EditText(name, text)
+}
+
+func (r *restrictedBot) Delete(name string) error {
+	if err := r.check(MayEdit); err != nil {
+		return err
+	}
+	return r.Bot.Delete(name)
+}
+
+func (r *restrictedBot) MarkRead(names ...string) error {
+	if err := r.check(MayManageInbox); err != nil {
+		return err
+	}
+	return r.Bot.MarkRead(names...)
+}
+
+func (r *restrictedBot) MarkUnread(names ...string) error {
+	if err := r.check(MayManageInbox); err != nil {
+		return err
+	}
+	return r.Bot.MarkUnread(names...)
+}
+
+func (r *restrictedBot) MarkAllRead() error {
+	if err := r.check(MayManageInbox); err != nil {
+		return err
+	}
+	return r.Bot.MarkAllRead()
+}
+
+func (r *restrictedBot) CollapseMessages(names ...string) error {
+	if err := r.check(MayManageInbox); err != nil {
+		return err
+	}
+	return r.Bot.CollapseMessages(names...)
+}
+
+func (r *restrictedBot) UncollapseMessages(names ...string) error {
+	if err := r.check(MayManageInbox); err != nil {
+		return err
+	}
+	return r.Bot.UncollapseMessages(names...)
+}
+
+func (r *restrictedBot) BlockSender(name string) error {
+	if err := r.check(MayBlock); err != nil {
+		return err
+	}
+	return r.Bot.BlockSender(name)
+}
+
+func (r *restrictedBot) CreateLiveThread(settings LiveThreadSettings) (string, error) {
+	if err := r.check(MayLive); err != nil {
+		return "", err
+	}
+	return r.Bot.CreateLiveThread(settings)
+}
+
+func (r *restrictedBot) EditLiveThread(thread string, settings LiveThreadSettings) error {
+	if err := r.check(MayLive); err != nil {
+		return err
+	}
+	return r.Bot.EditLiveThread(thread, settings)
+}
+
+func (r *restrictedBot) PostLiveUpdate(thread, text string) error {
+	if err := r.check(MayLive); err != nil {
+		return err
+	}
+	return r.Bot.PostLiveUpdate(thread, text)
+}
+
+func (r *restrictedBot) StrikeLiveUpdate(thread, update string) error {
+	if err := r.check(MayLive); err != nil {
+		return err
+	}
+	return r.Bot.StrikeLiveUpdate(thread, update)
+}
+
+func (r *restrictedBot) DeleteLiveUpdate(thread, update string) error {
+	if err := r.check(MayLive); err != nil {
+		return err
+	}
+	return r.Bot.DeleteLiveUpdate(thread, update)
+}
+
+func (r *restrictedBot) CloseLiveThread(thread string) error {
+	if err := r.check(MayLive); err != nil {
+		return err
+	}
+	return r.Bot.CloseLiveThread(thread)
+}
@@ -0,0 +1,222 @@
+package reddit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// readMethods are the methods of Bot which do not write to Reddit.
+var readMethods = map[string]bool{
+	"AboutUser":          true,
+	"UserOverview":       true,
+	"UserPosts":          true,
+	"UserComments":       true,
+	"UserTrophies":       true,
+	"Context":            true,
+	"Conversation":       true,
+	"Conversations":      true,
+	"ExpandMore":         true,
+	"ExpandThread":       true,
+	"Listing":            true,
+	"ListingWithParams":  true,
+	"Search":             true,
+	"ListingPage":        true,
+	"Me":                 true,
+	"ModLog":             true,
+	"Banned":             true,
+	"BannedUsers":        true,
+	"BlockedUsers":       true,
+	"Muted":              true,
+	"IsModerator":        true,
+	"PostInsights":       true,
+	"ReadOnly":           true,
+	"Restrict":           true,
+	"WithContext":        true,
+	"Thread":             true,
+	"ThreadWithOptions":  true,
+	"TokenExpiresAt":     true,
+	"AuthorState":        true,
+	"Metrics":            true,
+	"UserFlair":          true,
+	"LinkFlairTemplates": true,
+	"UserFlairTemplates": true,
+	"WikiPage":           true,
+	"WikiRevisions":      true,
+	"Inbox":              true,
+	"UnreadMessages":     true,
+	"SentMessages":       true,
+	"MessageThread":      true,
+	"LiveThread":         true,
+	"LiveUpdates":        true,
+	"ListenLive":         true,
+}
+
+// writePermissions maps the write methods of Bot to the permission they need.
+var writePermissions = map[string]Permission{
+	"Reply":                MayReply,
+	"GetReply":             MayReply,
+	"SendMessage":          MayMessage,
+	"PostSelf":             MayPost,
+	"GetPostSelf":          MayPost,
+	"GetPostSelfWithFlair": MayPost,
+	"PostLink":             MayPost,
+	"GetPostLink":          MayPost,
+	"SubmitGallery":        MayPost,
+	"SubmitPoll":           MayPost,
+	"SubmitVideo":          MayPost,
+	"UploadMedia":          MayPost,
+	"UploadStyleImage":     MayPost,
+	"RevokeToken":          MayRevoke,
+	"RevokeAll":            MayRevoke,
+	"EditWikiPage":         MayEditWiki,
+	"BanUser":              MayBan,
+	"Unban":                MayBan,
+	"Block":                MayBlock,
+	"Unblock":              MayBlock,
+	"Remove":               MayModeratePosts,
+	"Approve":              MayModeratePosts,
+	"Lock":                 MayModeratePosts,
+	"Unlock":               MayModeratePosts,
+	"Sticky":               MayModeratePosts,
+	"Distinguish":          MayModeratePosts,
+	"IgnoreReports":        MayModeratePosts,
+	"SetContestMode":       MayModeratePosts,
+	"SetSuggestedSort":     MayModeratePosts,
+	"SetDefaultSort":       MayConfigure,
+	"ReplyModmail":         MayModmail,
+	"ArchiveModmail":       MayModmail,
+	"UnarchiveModmail":     MayModmail,
+	"HighlightModmail":     MayModmail,
+	"MuteModmail":          MayModmail,
+	"UnmuteModmail":        MayModmail,
+	"SetLinkFlair":         MayFlair,
+	"SetUserFlair":         MayFlair,
+	"SetFlairCSV":          MayFlair,
+	"Upvote":               MayVote,
+	"Downvote":             MayVote,
+	"ClearVote":            MayVote,
+	"Save":                 MaySave,
+	"Unsave":               MaySave,
+	"Hide":                 MaySave,
+	"Unhide":               MaySave,
+	"Report":               MayReport,
+	"EditText":             MayEdit,
+	"Delete":               MayEdit,
+	"MarkRead":             MayManageInbox,
+	"MarkUnread":           MayManageInbox,
+	"MarkAllRead":          MayManageInbox,
+	"CollapseMessages":     MayManageInbox,
+	"UncollapseMessages":   MayManageInbox,
+	"BlockSender":          MayBlock,
+	"CreateLiveThread":     MayLive,
+	"EditLiveThread":       MayLive,
+	"PostLiveUpdate":       MayLive,
+	"StrikeLiveUpdate":     MayLive,
+	"DeleteLiveUpdate":     MayLive,
+	"CloseLiveThread":      MayLive,
+}
+
+// callWrites calls each write method of Bot on b with zero arguments, and
+// reports the error each returned.
+func callWrites(b Bot, t *testing.T) map[string]interface{} {
+	errs := make(map[string]interface{})
+	v := reflect.ValueOf(b)
+	botType := reflect.TypeOf((*Bot)(nil)).Elem()
+	for i := 0; i < botType.NumMethod(); i++ {
+		method := botType.Method(i)
+		if readMethods[method.Name] {
+			continue
+		}
+
+		if _, ok := writePermissions[method.Name]; !ok {
+			t.Errorf("%s is neither a read nor a write method", method.Name)
+			continue
+		}
+
+		f := v.MethodByName(method.Name)
+		args := make([]reflect.Value, f.Type().NumIn())
+		for j := range args {
+			args[j] = reflect.Zero(f.Type().In(j))
+		}
+
+		call := f.Call
+		if f.Type().IsVariadic() {
+			call = f.CallSlice
+		}
+		results := call(args)
+		errs[method.Name] = results[len(results)-1].Interface()
+	}
+	return errs
+}
+
+func TestReadOnly(t *testing.T) {
+	// The wrapped bot is nil, so any write method which is not guarded
+	// will panic and fail the test.
+	for name, err := range callWrites((&bot{}).ReadOnly().(*restrictedBot), t) {
+		if err != ReadOnlyErr {
+			t.Errorf("%s: wanted ReadOnlyErr; got %v", name, err)
+		}
+	}
+}
+
+func TestRestrict(t *testing.T) {
+	r := reaperWhich(Harvest{}, nil)
+	inner := &bot{
+		Account:       newAccount(r),
+		Lurker:        newLurker(r),
+		Scanner:       newScanner(r),
+		MediaUploader: newMediaUploader(r),
+		Modmail:       newModmail(r),
+		Live:          newLive(r),
+	}
+
+	for _, p := range []Permission{MayReply, MayMessage, MayPost, MayRevoke} {
+		restricted := inner.Restrict(p)
+		for name, err := range callWrites(restricted, t) {
+			// Some methods reject their zero arguments; only
+			// NotPermittedErr means the restriction stopped them.
+			if writePermissions[name] == p && err == NotPermittedErr {
+				t.Errorf("%s: wanted permitted; got %v", name, err)
+			} else if writePermissions[name] != p && err != NotPermittedErr {
+				t.Errorf("%s: wanted NotPermittedErr; got %v", name, err)
+			}
+		}
+	}
+
+	if err := inner.Restrict(MayReply).Restrict(MayPost).Reply("", ""); err != NotPermittedErr {
+		t.Errorf("wanted restriction to only remove permissions; got %v", err)
+	}
+}
+
+func TestGate(t *testing.T) {
+	haltedErr := fmt.Errorf("halted")
+	halted := true
+	gated := Gate(&bot{r: reaperWhich(Harvest{}, nil)}, func() error {
+		if halted {
+			return haltedErr
+		}
+		return nil
+	})
+
+	// The wrapped bot has no components, so any write method which is not
+	// gated will panic and fail the test. Revoking is never gated.
+	for name, err := range callWrites(gated, t) {
+		if writePermissions[name] == MayRevoke {
+			if err != nil {
+				t.Errorf("%s: wanted revoking to pass the gate; got %v", name, err)
+			}
+		} else if err != haltedErr {
+			t.Errorf("%s: wanted the gate's error; got %v", name, err)
+		}
+	}
+
+	for name, err := range callWrites(gated.Restrict(MayReply).WithContext(context.Background()), t) {
+		if writePermissions[name] == MayReply && err != haltedErr {
+			t.Errorf("%s: wanted the gate's error; got %v", name, err)
+		} else if writePermissions[name] != MayReply && err != NotPermittedErr {
+			t.Errorf("%s: wanted NotPermittedErr; got %v", name, err)
+		}
+	}
+}
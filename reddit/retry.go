@@ -0,0 +1,143 @@
+package reddit
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+)
+
+const (
+	// defaultBackoff is the wait before the first retry if the config does
+	// not say.
+	defaultBackoff = 2 * time.Second
+	// defaultMaxBackoff is the longest wait between retries if the config
+	// does not say.
+	defaultMaxBackoff = time.Minute
+)
+
+// RetryConfig configures how requests which fail transiently, with a 429, 500,
+// 502, 503, or 504 response or a network timeout, are retried. Each retry waits
+// a random time between half of and the full backoff, which doubles with every
+// attempt, and no less than a Retry-After header asked.
+//
+// Writes are retried too; one which failed with a gateway error may have been
+// made anyway, so guard writes which must not be repeated with the once
+// package.
+type RetryConfig struct {
+	// MaxAttempts is how many times a request is made before its error is
+	// returned. If it is less than 2, requests are not retried.
+	MaxAttempts int
+	// Backoff is the wait before the first retry. If zero, it is 2 seconds.
+	Backoff time.Duration
+	// MaxBackoff caps the wait between retries. If zero, it is a minute.
+	MaxBackoff time.Duration
+}
+
+// jitter randomizes backoffs, so bots which failed together don't retry
+// together.
+var jitter = struct {
+	sync.Mutex
+	*rand.Rand
+}{Rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// backoff returns the wait before the retry following the given number of
+// attempts.
+func (c RetryConfig) backoff(attempts int) time.Duration {
+	base, max := c.Backoff, c.MaxBackoff
+	if base <= 0 {
+		base = defaultBackoff
+	}
+	if max <= 0 {
+		max = defaultMaxBackoff
+	}
+
+	wait := base
+	for i := 1; i < attempts && wait < max; i++ {
+		wait *= 2
+	}
+	if wait > max {
+		wait = max
+	}
+
+	jitter.Lock()
+	defer jitter.Unlock()
+	return wait/2 + time.Duration(jitter.Int63n(int64(wait/2)+1))
+}
+
+// transient reports whether a request which failed with err may succeed if it
+// is made again.
+func transient(err error) bool {
+	switch err {
+	case RateLimitErr, BusyErr, GatewayErr, GatewayTimeoutErr:
+		return true
+	}
+
+	switch e := err.(type) {
+	case *StatusError:
+		return e.Code == http.StatusInternalServerError
+	case net.Error:
+		return e.Timeout()
+	}
+	return false
+}
+
+// retry makes the request until it succeeds, fails for good, or runs out of
+// attempts.
+func (r *reaperImpl) retry(req *http.Request) ([]byte, error) {
+	var body []byte
+	hasBody := req.Body != nil
+	if hasBody {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+
+	for attempts := 1; ; attempts++ {
+		if hasBody {
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		resp, err := r.attempt(req)
+		if err == nil || !transient(err) || attempts >= r.retries.MaxAttempts {
+			return resp, err
+		}
+
+		if err := r.sleep(r.context(), r.retries.backoff(attempts)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sleep waits for d, unless ctx is done first.
+func (r *reaperImpl) sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	// A simulated clock only moves when told to, and nothing else moves it
+	// while a handler waits on a request, so the wait is skipped by
+	// sleeping on it.
+	if _, simulated := r.clock.(clock.Simulation); simulated || ctx.Done() == nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		r.clock.Sleep(d)
+		return nil
+	}
+
+	select {
+	case <-r.clock.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,125 @@
+package reddit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+)
+
+// flakyClient fails with each of its errors in turn before succeeding.
+type flakyClient struct {
+	errs   []error
+	bodies []string
+}
+
+func (f *flakyClient) Do(r *http.Request) ([]byte, error) {
+	if r.Body != nil {
+		buf, _ := ioutil.ReadAll(r.Body)
+		f.bodies = append(f.bodies, string(buf))
+	}
+
+	if len(f.errs) == 0 {
+		return []byte("ok"), nil
+	}
+
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return nil, err
+}
+
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string   { return "timed out" }
+func (timeoutErr) Timeout() bool   { return true }
+func (timeoutErr) Temporary() bool { return true }
+
+func TestTransient(t *testing.T) {
+	for i, test := range []struct {
+		err       error
+		transient bool
+	}{
+		{RateLimitErr, true},
+		{BusyErr, true},
+		{GatewayErr, true},
+		{GatewayTimeoutErr, true},
+		{&StatusError{Code: http.StatusInternalServerError}, true},
+		{timeoutErr{}, true},
+		{&StatusError{Code: http.StatusBadRequest}, false},
+		{PermissionDeniedErr, false},
+		{NotFoundErr, false},
+		{fmt.Errorf("malformed"), false},
+	} {
+		if got := transient(test.err); got != test.transient {
+			t.Errorf("%d: transient(%v) = %v; wanted %v", i, test.err, got, test.transient)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	c := RetryConfig{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	for attempts, max := range []time.Duration{
+		1: time.Second,
+		2: 2 * time.Second,
+		3: 4 * time.Second,
+		4: 5 * time.Second,
+		9: 5 * time.Second,
+	} {
+		if max == 0 {
+			continue
+		}
+
+		for i := 0; i < 10; i++ {
+			if wait := c.backoff(attempts); wait < max/2 || wait > max {
+				t.Errorf("backoff after %d attempts is %v; wanted %v to %v", attempts, wait, max/2, max)
+			}
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	for i, test := range []struct {
+		errs     []error
+		attempts int
+		err      error
+	}{
+		{nil, 1, nil},
+		{[]error{BusyErr, GatewayErr}, 3, nil},
+		{[]error{RateLimitErr, RateLimitErr, RateLimitErr, RateLimitErr}, 3, RateLimitErr},
+		{[]error{PermissionDeniedErr}, 1, PermissionDeniedErr},
+	} {
+		start := time.Unix(0, 0)
+		sim := clock.NewSimulation(start)
+		cli := &flakyClient{errs: test.errs}
+		r := &reaperImpl{
+			cli:     cli,
+			parser:  &mockParser{},
+			clock:   sim,
+			limit:   newRateLimit(),
+			retries: RetryConfig{MaxAttempts: 3, Backoff: time.Second},
+		}
+
+		err := r.sow("/api/path", map[string]string{"key": "value"})
+		if err != test.err {
+			t.Errorf("%d: got error %v; wanted %v", i, err, test.err)
+		}
+
+		if len(cli.bodies) != test.attempts {
+			t.Errorf("%d: made %d attempts; wanted %d", i, len(cli.bodies), test.attempts)
+		}
+
+		for _, body := range cli.bodies {
+			if !strings.Contains(body, "key=value") {
+				t.Errorf("%d: retried with body %q", i, body)
+			}
+		}
+
+		if waited := sim.Now().Sub(start); test.attempts > 1 && waited < time.Second/2 {
+			t.Errorf("%d: retried after %v; wanted a backoff", i, waited)
+		}
+	}
+}
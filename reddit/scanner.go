@@ -28,6 +28,18 @@ type Scanner interface {
 	// or graw/streams.
 	Listing(path, after string) (Harvest, error)
 	ListingWithParams(path string, params map[string]string) (Harvest, error)
+	// ListingPage is ListingWithParams, which also returns the page's
+	// metadata, such as the after token to request the next page with.
+	// NewListingIterator follows the tokens through a whole listing.
+	ListingPage(path string, params map[string]string) (
+		Harvest,
+		ListingInfo,
+		error,
+	)
+	// Search searches a subreddit, or a combination of them such as
+	// "golang+rust", for the query in Reddit's search syntax. If the
+	// subreddit is empty, all of Reddit is searched.
+	Search(subreddit, query string, opts SearchOptions) (Harvest, error)
 }
 
 type scanner struct {
@@ -52,6 +64,20 @@ func (s *scanner) ListingWithParams(path string, params map[string]string) (
 	Harvest,
 	error,
 ) {
+	return s.r.reap(path, listingParams(params))
+}
+
+func (s *scanner) ListingPage(path string, params map[string]string) (
+	Harvest,
+	ListingInfo,
+	error,
+) {
+	return s.r.reapPage(path, listingParams(params))
+}
+
+// listingParams returns the params with the defaults for listing requests
+// filled in.
+func listingParams(params map[string]string) map[string]string {
 	reaperParams := map[string]string{
 		"raw_json": "1",
 		"limit":    "100",
@@ -59,5 +85,5 @@ func (s *scanner) ListingWithParams(path string, params map[string]string) (
 	for key, value := range params {
 		reaperParams[key] = value
 	}
-	return s.r.reap(path, reaperParams)
-}
\ No newline at end of file
+	return reaperParams
+}
@@ -1,19 +1,37 @@
 package reddit
 
 import (
+	"context"
 	"net/http"
 	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/logging"
 )
 
 // Script defines the behaviors of a logged out Reddit script.
 type Script interface {
 	Lurker
+	UserLurker
 	Scanner
+
+	// WithContext returns a view of the script whose requests are made
+	// with ctx, so they fail with its error once it is cancelled or its
+	// deadline passes, including while they wait their turn under the
+	// rate limit. The view shares the script's rate limit.
+	WithContext(ctx context.Context) Script
+	// Metrics returns the collector the script's config set, or nil, so
+	// the streams reading through the script can report to it too.
+	Metrics() MetricsCollector
 }
 
 type script struct {
 	Lurker
+	UserLurker
 	Scanner
+
+	r       reaper
+	metrics MetricsCollector
 }
 
 type ScriptConfig struct {
@@ -22,8 +40,31 @@ type ScriptConfig struct {
 	Agent string
 	// Rate is the minimum amount of time between requests.
 	Rate time.Duration
-	// Custom HTTP client
+	// Client, if set, is the HTTP client the script's requests are sent
+	// through. It is copied, not modified, so it can be shared.
 	Client *http.Client
+	// Transport, if set and Client is not, is the transport the script's
+	// requests are sent over, e.g. through a proxy. See BotConfig.
+	Transport http.RoundTripper
+	// Clock times the rate limit between requests. If nil, the wall clock
+	// is used.
+	Clock clock.Clock
+	// WrapClient, if set, wraps the client the script sends its requests
+	// through. See Doer.
+	WrapClient func(Doer) Doer
+	// Middleware wraps the client the script sends its requests through,
+	// in order, inside WrapClient. See Chain.
+	Middleware []Middleware
+	// Retry configures how requests which fail transiently are retried.
+	// If it is zero, they are not.
+	Retry RetryConfig
+	// Trace, if set, is sent a Debug entry for every request the script
+	// makes, with its method, path, status, rate limit headers, and
+	// latency.
+	Trace logging.Logger
+	// Metrics, if set, measures the requests the script makes, and the
+	// streams reading through it report the events they drop to it.
+	Metrics MetricsCollector
 }
 
 // NewScript returns a Script handle to Reddit's API which always sends the
@@ -41,19 +82,50 @@ func NewScript(agent string, rate time.Duration) (Script, error) {
 
 // NewScriptFromConfig returns a Script handle to Reddit's API from ScriptConfig
 func NewScriptFromConfig(config ScriptConfig) (Script, error) {
-	c, err := newClient(clientConfig{agent: config.Agent, client: config.Client})
+	q := newQuota(config.Clock)
+	c, err := newClient(
+		clientConfig{
+			agent:     config.Agent,
+			client:    config.Client,
+			transport: config.Transport,
+			quota:     q,
+			trace:     config.Trace,
+			metrics:   config.Metrics,
+		},
+	)
 	r := newReaper(
 		reaperConfig{
-			client:     c,
+			client:     wrapClient(c, config.WrapClient, config.Middleware),
 			parser:     newParser(),
 			hostname:   "reddit.com",
 			reapSuffix: ".json",
 			tls:        true,
 			rate:       maxOf(config.Rate, 2*time.Second),
+			clock:      config.Clock,
+			quota:      q,
+			retries:    config.Retry,
 		},
 	)
 	return &script{
-		Lurker:  newLurker(r),
-		Scanner: newScanner(r),
+		Lurker:     newLurker(r),
+		UserLurker: newUserLurker(r),
+		Scanner:    newScanner(r),
+		r:          r,
+		metrics:    config.Metrics,
 	}, err
 }
+
+func (s *script) WithContext(ctx context.Context) Script {
+	r := s.r.withContext(ctx)
+	return &script{
+		Lurker:     newLurker(r),
+		UserLurker: newUserLurker(r),
+		Scanner:    newScanner(r),
+		r:          r,
+		metrics:    s.metrics,
+	}
+}
+
+func (s *script) Metrics() MetricsCollector {
+	return s.metrics
+}
@@ -0,0 +1,84 @@
+package reddit
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Search result types, which SearchOptions.Types filters by. Harvests hold
+// only posts and comments, so subreddits and users found are not returned.
+const (
+	SearchPosts    = "link"
+	SearchComments = "comment"
+)
+
+// SearchOptions configure a search of Reddit. The zero value searches posts,
+// by relevance, among the newest page of results Reddit serves.
+type SearchOptions struct {
+	// Sort is the order of results: "relevance", "hot", "top", "new", or
+	// "comments". If empty, Reddit sorts by relevance.
+	Sort string
+	// Time is the window of results sorted by relevance, top, or comments:
+	// "hour", "day", "week", "month", "year", or "all".
+	Time string
+	// RestrictSubreddit limits a search in a subreddit to that subreddit.
+	// Otherwise Reddit only favors its results.
+	RestrictSubreddit bool
+	// Types are the types of results, e.g. SearchPosts. If empty, posts
+	// are searched.
+	Types []string
+	// Limit is the most results returned, up to 100. If zero, 100 are.
+	Limit int
+	// After and Before are the fullnames of results to page from, e.g.
+	// the last result of the previous page, and Count is how many results
+	// were already seen, which Reddit numbers the page from.
+	After  string
+	Before string
+	Count  int
+	// IncludeNSFW includes results marked over 18.
+	IncludeNSFW bool
+}
+
+// params returns the request parameters of a search for the query.
+func (o SearchOptions) params(query string) map[string]string {
+	params := map[string]string{
+		"q":        query,
+		"raw_json": "1",
+		"limit":    "100",
+	}
+	set := func(key, value string) {
+		if value != "" {
+			params[key] = value
+		}
+	}
+
+	set("sort", o.Sort)
+	set("t", o.Time)
+	set("type", strings.Join(o.Types, ","))
+	set("after", o.After)
+	set("before", o.Before)
+	if o.RestrictSubreddit {
+		params["restrict_sr"] = "on"
+	}
+	if o.Limit > 0 {
+		params["limit"] = strconv.Itoa(o.Limit)
+	}
+	if o.Count > 0 {
+		params["count"] = strconv.Itoa(o.Count)
+	}
+	if o.IncludeNSFW {
+		params["include_over_18"] = "on"
+	}
+	return params
+}
+
+func (s *scanner) Search(
+	subreddit, query string,
+	opts SearchOptions,
+) (Harvest, error) {
+	path := "/search"
+	if subreddit != "" {
+		path = "/r/" + subreddit + "/search"
+	}
+	return s.r.reap(path, opts.params(query))
+}
@@ -0,0 +1,377 @@
+package reddit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// WouldWrite is a write a shadowed Bot did not make.
+type WouldWrite struct {
+	// Method is the name of the Bot method called, e.g. "Reply".
+	Method string
+	// Args are the arguments it was called with.
+	Args []interface{}
+}
+
+func (w WouldWrite) String() string {
+	args := make([]string, len(w.Args))
+	for i, arg := range w.Args {
+		args[i] = fmt.Sprintf("%#v", arg)
+	}
+	return w.Method + "(" + strings.Join(args, ", ") + ")"
+}
+
+// Shadow returns a view of the bot whose writes are passed to record instead
+// of being made, and succeed with empty results, such as an empty Submission.
+// Reads are made as usual. Give a handler on trial, such as a new moderation
+// rule, a shadowed view to see what it would do to live traffic while the rest
+// of the bot acts normally:
+//
+//	rule := newRule(reddit.Shadow(bot, func(w reddit.WouldWrite) {
+//		log.Printf("[shadow] new rule would call %s", w)
+//	}))
+//
+// Restricted views of a shadowed bot are still shadowed, and still refuse the
+// writes they are not permitted.
+func Shadow(b Bot, record func(WouldWrite)) Bot {
+	return &shadowBot{Bot: b, recorder: record}
+}
+
+// shadowBot is a Bot whose writes are recorded instead of made. Every method of
+// Bot which changes anything on Reddit must be overridden here.
+type shadowBot struct {
+	Bot
+	recorder func(WouldWrite)
+}
+
+func (s *shadowBot) record(method string, args ...interface{}) {
+	if s.recorder != nil {
+		s.recorder(WouldWrite{Method: method, Args: args})
+	}
+}
+
+func (s *shadowBot) Restrict(p Permission) Bot {
+	return &restrictedBot{Bot: s, allowed: p, err: NotPermittedErr}
+}
+
+func (s *shadowBot) WithContext(ctx context.Context) Bot {
+	return &shadowBot{Bot: s.Bot.WithContext(ctx), recorder: s.recorder}
+}
+
+func (s *shadowBot) Reply(parentName, text string) error {
+	s.record("Reply", parentName, text)
+	return nil
+}
+
+func (s *shadowBot) GetReply(parentName, text string) (Submission, error) {
+	s.record("GetReply", parentName, text)
+	return Submission{}, nil
+}
+
+func (s *shadowBot) SendMessage(user, subject, text string) error {
+	s.record("SendMessage", user, subject, text)
+	return nil
+}
+
+func (s *shadowBot) PostSelf(subreddit, title, text string) error {
+	s.record("PostSelf", subreddit, title, text)
+	return nil
+}
+
+func (s *shadowBot) GetPostSelf(subreddit, title, text string) (Submission, error) {
+	s.record("GetPostSelf", subreddit, title, text)
+	return Submission{}, nil
+}
+
+func (s *shadowBot) GetPostSelfWithFlair(
+	subreddit, title, text string,
+	flair PostFlair,
+) (Submission, error) {
+	s.record("GetPostSelfWithFlair", subreddit, title, text, flair)
+	return Submission{}, nil
+}
+
+func (s *shadowBot) PostLink(subreddit, title, url string) error {
+	s.record("PostLink", subreddit, title, url)
+	return nil
+}
+
+func (s *shadowBot) GetPostLink(subreddit, title, url string) (Submission, error) {
+	s.record("GetPostLink", subreddit, title, url)
+	return Submission{}, nil
+}
+
+func (s *shadowBot) SubmitGallery(
+	subreddit, title string,
+	images []GalleryImage,
+) (Submission, error) {
+	s.record("SubmitGallery", subreddit, title, images)
+	return Submission{}, nil
+}
+
+func (s *shadowBot) SubmitPoll(subreddit, title string, poll Poll) (Submission, error) {
+	s.record("SubmitPoll", subreddit, title, poll)
+	return Submission{}, nil
+}
+
+func (s *shadowBot) SubmitVideo(subreddit, title string, video Video) (Submission, error) {
+	s.record("SubmitVideo", subreddit, title, video)
+	return Submission{}, nil
+}
+
+func (s *shadowBot) UploadMedia(file MediaFile) (Upload, error) {
+	s.record("UploadMedia", file)
+	return Upload{}, nil
+}
+
+func (s *shadowBot) UploadStyleImage(
+	subreddit, image string,
+	file MediaFile,
+) (string, error) {
+	s.record("UploadStyleImage", subreddit, image, file)
+	return "", nil
+}
+
+func (s *shadowBot) RevokeToken() error {
+	s.record("RevokeToken")
+	return nil
+}
+
+func (s *shadowBot) RevokeAll() error {
+	s.record("RevokeAll")
+	return nil
+}
+
+func (s *shadowBot) EditWikiPage(subreddit, page string, edit WikiEdit) error {
+	s.record("EditWikiPage", subreddit, page, edit)
+	return nil
+}
+
+func (s *shadowBot) BanUser(subreddit, user string, ban Ban) error {
+	s.record("BanUser", subreddit, user, ban)
+	return nil
+}
+
+func (s *shadowBot) Unban(subreddit, user string) error {
+	s.record("Unban", subreddit, user)
+	return nil
+}
+
+func (s *shadowBot) Remove(name string, spam bool) error {
+	s.record("Remove", name, spam)
+	return nil
+}
+
+func (s *shadowBot) Approve(name string) error {
+	s.record("Approve", name)
+	return nil
+}
+
+func (s *shadowBot) Lock(name string) error {
+	s.record("Lock", name)
+	return nil
+}
+
+func (s *shadowBot) Unlock(name string) error {
+	s.record("Unlock", name)
+	return nil
+}
+
+func (s *shadowBot) Sticky(post string, on bool) error {
+	s.record("Sticky", post, on)
+	return nil
+}
+
+func (s *shadowBot) Distinguish(name, how string) error {
+	s.record("Distinguish", name, how)
+	return nil
+}
+
+func (s *shadowBot) IgnoreReports(name string, on bool) error {
+	s.record("IgnoreReports", name, on)
+	return nil
+}
+
+func (s *shadowBot) SetContestMode(post string, on bool) error {
+	s.record("SetContestMode", post, on)
+	return nil
+}
+
+func (s *shadowBot) SetSuggestedSort(post string, sort CommentSort) error {
+	s.record("SetSuggestedSort", post, sort)
+	return nil
+}
+
+func (s *shadowBot) SetDefaultSort(subreddit string, sort CommentSort) error {
+	s.record("SetDefaultSort", subreddit, sort)
+	return nil
+}
+
+func (s *shadowBot) Block(user string) error {
+	s.record("Block", user)
+	return nil
+}
+
+func (s *shadowBot) Unblock(user string) error {
+	s.record("Unblock", user)
+	return nil
+}
+
+func (s *shadowBot) ReplyModmail(id, text string, internal bool) error {
+	s.record("ReplyModmail", id, text, internal)
+	return nil
+}
+
+func (s *shadowBot) ArchiveModmail(id string) error {
+	s.record("ArchiveModmail", id)
+	return nil
+}
+
+func (s *shadowBot) UnarchiveModmail(id string) error {
+	s.record("UnarchiveModmail", id)
+	return nil
+}
+
+func (s *shadowBot) HighlightModmail(id string, on bool) error {
+	s.record("HighlightModmail", id, on)
+	return nil
+}
+
+func (s *shadowBot) MuteModmail(id string, days int) error {
+	s.record("MuteModmail", id, days)
+	return nil
+}
+
+func (s *shadowBot) UnmuteModmail(id string) error {
+	s.record("UnmuteModmail", id)
+	return nil
+}
+
+func (s *shadowBot) SetLinkFlair(subreddit, post string, flair FlairChoice) error {
+	s.record("SetLinkFlair", subreddit, post, flair)
+	return nil
+}
+
+func (s *shadowBot) SetUserFlair(subreddit, user string, flair FlairChoice) error {
+	s.record("SetUserFlair", subreddit, user, flair)
+	return nil
+}
+
+func (s *shadowBot) SetFlairCSV(
+	subreddit string,
+	flairs []Flair,
+) ([]FlairResult, error) {
+	s.record("SetFlairCSV", subreddit, flairs)
+	return nil, nil
+}
+
+func (s *shadowBot) Upvote(name string) error {
+	s.record("Upvote", name)
+	return nil
+}
+
+func (s *shadowBot) Downvote(name string) error {
+	s.record("Downvote", name)
+	return nil
+}
+
+func (s *shadowBot) ClearVote(name string) error {
+	s.record("ClearVote", name)
+	return nil
+}
+
+func (s *shadowBot) Save(name string) error {
+	s.record("Save", name)
+	return nil
+}
+
+func (s *shadowBot) Unsave(name string) error {
+	s.record("Unsave", name)
+	return nil
+}
+
+func (s *shadowBot) Hide(post string) error {
+	s.record("Hide", post)
+	return nil
+}
+
+func (s *shadowBot) Unhide(post string) error {
+	s.record("Unhide", post)
+	return nil
+}
+
+func (s *shadowBot) Report(name, reason string) error {
+	s.record("Report", name, reason)
+	return nil
+}
+
+func (s *shadowBot) EditText(name, text string) error {
+	s.record("EditText", name, text)
+	return nil
+}
+
+func (s *shadowBot) Delete(name string) error {
+	s.record("Delete", name)
+	return nil
+}
+
+func (s *shadowBot) MarkRead(names ...string) error {
+	s.record("MarkRead", names)
+	return nil
+}
+
+func (s *shadowBot) MarkUnread(names ...string) error {
+	s.record("MarkUnread", names)
+	return nil
+}
+
+func (s *shadowBot) MarkAllRead() error {
+	s.record("MarkAllRead")
+	return nil
+}
+
+func (s *shadowBot) CollapseMessages(names ...string) error {
+	s.record("CollapseMessages", names)
+	return nil
+}
+
+func (s *shadowBot) UncollapseMessages(names ...string) error {
+	s.record("UncollapseMessages", names)
+	return nil
+}
+
+func (s *shadowBot) BlockSender(name string) error {
+	s.record("BlockSender", name)
+	return nil
+}
+
+func (s *shadowBot) CreateLiveThread(settings LiveThreadSettings) (string, error) {
+	s.record("CreateLiveThread", settings)
+	return "", nil
+}
+
+func (s *shadowBot) EditLiveThread(thread string, settings LiveThreadSettings) error {
+	s.record("EditLiveThread", thread, settings)
+	return nil
+}
+
+func (s *shadowBot) PostLiveUpdate(thread, text string) error {
+	s.record("PostLiveUpdate", thread, text)
+	return nil
+}
+
+func (s *shadowBot) StrikeLiveUpdate(thread, update string) error {
+	s.record("StrikeLiveUpdate", thread, update)
+	return nil
+}
+
+func (s *shadowBot) DeleteLiveUpdate(thread, update string) error {
+	s.record("DeleteLiveUpdate", thread, update)
+	return nil
+}
+
+func (s *shadowBot) CloseLiveThread(thread string) error {
+	s.record("CloseLiveThread", thread)
+	return nil
+}
@@ -0,0 +1,33 @@
+package reddit
+
+import "testing"
+
+func TestShadow(t *testing.T) {
+	var recorded []WouldWrite
+	// The wrapped bot has no components, so any write method which is not
+	// shadowed will panic and fail the test.
+	shadowed := Shadow(&bot{}, func(w WouldWrite) {
+		recorded = append(recorded, w)
+	})
+
+	errs := callWrites(shadowed, t)
+	for name, err := range errs {
+		if err != nil {
+			t.Errorf("%s: wanted success; got %v", name, err)
+		}
+	}
+	if len(recorded) != len(errs) {
+		t.Errorf("recorded %d writes; wanted %d", len(recorded), len(errs))
+	}
+
+	recorded = nil
+	if err := shadowed.Restrict(MayReply).SendMessage("a", "b", "c"); err != NotPermittedErr {
+		t.Errorf("wanted NotPermittedErr; got %v", err)
+	}
+	if err := shadowed.Restrict(MayReply).Reply("t1_a", "hi"); err != nil {
+		t.Errorf("wanted success; got %v", err)
+	}
+	if len(recorded) != 1 || recorded[0].String() != `Reply("t1_a", "hi")` {
+		t.Errorf("got writes %v; wanted the reply", recorded)
+	}
+}
@@ -0,0 +1,168 @@
+package reddit
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// siteAdminSettings are the settings of a subreddit's about/edit listing which
+// /api/site_admin takes. The listing reports others which it does not, such
+// as subreddit_id and objects like comment_contribution_settings.
+var siteAdminSettings = map[string]bool{
+	"accept_followers":                   true,
+	"admin_override_spam_comments":       true,
+	"admin_override_spam_links":          true,
+	"admin_override_spam_selfposts":      true,
+	"all_original_content":               true,
+	"allow_chat_post_creation":           true,
+	"allow_discovery":                    true,
+	"allow_galleries":                    true,
+	"allow_images":                       true,
+	"allow_polls":                        true,
+	"allow_post_crossposts":              true,
+	"allow_predictions":                  true,
+	"allow_predictions_tournament":       true,
+	"allow_talks":                        true,
+	"allow_videos":                       true,
+	"collapse_deleted_comments":          true,
+	"comment_score_hide_mins":            true,
+	"content_options":                    true,
+	"crowd_control_chat_level":           true,
+	"crowd_control_filter":               true,
+	"crowd_control_level":                true,
+	"crowd_control_mode":                 true,
+	"crowd_control_post_level":           true,
+	"default_set":                        true,
+	"description":                        true,
+	"disable_contributor_requests":       true,
+	"domain":                             true,
+	"exclude_banned_modqueue":            true,
+	"free_form_reports":                  true,
+	"hateful_content_threshold_abuse":    true,
+	"hateful_content_threshold_identity": true,
+	"header_hover_text":                  true,
+	"hide_ads":                           true,
+	"key_color":                          true,
+	"language":                           true,
+	"modmail_harassment_filter_enabled":  true,
+	"new_pinned_post_pns_enabled":        true,
+	"original_content_tag_enabled":       true,
+	"over_18":                            true,
+	"prediction_leaderboard_entry_type":  true,
+	"public_description":                 true,
+	"public_traffic":                     true,
+	"restrict_commenting":                true,
+	"restrict_posting":                   true,
+	"should_archive_posts":               true,
+	"show_media":                         true,
+	"show_media_preview":                 true,
+	"spam_comments":                      true,
+	"spam_links":                         true,
+	"spam_selfposts":                     true,
+	"spoilers_enabled":                   true,
+	"submit_link_label":                  true,
+	"submit_text":                        true,
+	"submit_text_label":                  true,
+	"subreddit_type":                     true,
+	"suggested_comment_sort":             true,
+	"title":                              true,
+	"toxicity_threshold_chat_level":      true,
+	"user_flair_pn_enabled":              true,
+	"welcome_message_enabled":            true,
+	"welcome_message_text":               true,
+	"wiki_edit_age":                      true,
+	"wiki_edit_karma":                    true,
+	"wikimode":                           true,
+}
+
+// siteAdminFields renames the settings of a subreddit's about/edit listing to
+// the parameters /api/site_admin takes for them.
+var siteAdminFields = map[string]string{
+	"content_options":   "link_type",
+	"default_set":       "allow_top",
+	"header_hover_text": "header-title",
+	"language":          "lang",
+	"subreddit_type":    "type",
+}
+
+// noSubredditIDErr is returned when a subreddit's settings do not say which
+// subreddit they are. Sent without it, site_admin creates a subreddit.
+var noSubredditIDErr = fmt.Errorf("subreddit settings have no subreddit_id")
+
+// subredditSettings is the shape of Reddit's about/edit listing.
+type subredditSettings struct {
+	Data map[string]interface{} `mapstructure:"data"`
+}
+
+func (m *moderator) SetSuggestedSort(post string, sort CommentSort) error {
+	if sort == "" {
+		sort = "blank"
+	}
+
+	return m.r.sow(
+		"/api/set_suggested_sort", map[string]string{
+			"id":       post,
+			"sort":     string(sort),
+			"api_type": "json",
+		},
+	)
+}
+
+func (m *moderator) SetDefaultSort(subreddit string, sort CommentSort) error {
+	settings := &subredditSettings{}
+	if err := m.r.reapInto(
+		"/r/"+subreddit+"/about/edit",
+		map[string]string{"raw_json": "1"},
+		settings,
+	); err != nil {
+		return err
+	}
+
+	// site_admin resets every setting it is not sent, so the subreddit's
+	// current settings are sent back with only the sort changed.
+	values, err := siteAdminValues(settings.Data)
+	if err != nil {
+		return err
+	}
+	values["suggested_comment_sort"] = string(sort)
+	return m.r.sow("/api/site_admin", values)
+}
+
+// siteAdminValues returns the parameters which set a subreddit's settings, as
+// its about/edit listing reports them, through /api/site_admin. Settings
+// site_admin does not take are left out; it fails on any it takes but cannot
+// send back, rather than let site_admin reset it.
+func siteAdminValues(settings map[string]interface{}) (map[string]string, error) {
+	id, ok := settings["subreddit_id"].(string)
+	if !ok || id == "" {
+		return nil, noSubredditIDErr
+	}
+
+	values := map[string]string{"api_type": "json", "sr": id}
+	for key, value := range settings {
+		if !siteAdminSettings[key] {
+			continue
+		}
+		name := key
+		if renamed, ok := siteAdminFields[key]; ok {
+			name = renamed
+		}
+
+		switch v := value.(type) {
+		case nil:
+			values[name] = ""
+		case string:
+			values[name] = v
+		case bool:
+			values[name] = strconv.FormatBool(v)
+		case float64:
+			values[name] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			return nil, fmt.Errorf(
+				"subreddit setting %s is a %T, which cannot be sent back to site_admin",
+				key, value,
+			)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,109 @@
+package reddit
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// settingsFixture returns a subreddit's settings in the shape of Reddit's
+// about/edit response.
+func settingsFixture(t *testing.T) map[string]interface{} {
+	blob, err := ioutil.ReadFile(filepath.Join("testdata", "settings", "about_edit.json"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(blob, &raw); err != nil {
+		t.Fatalf("failed to decode fixture: %v", err)
+	}
+	return raw
+}
+
+func TestSetDefaultSort(t *testing.T) {
+	raw := settingsFixture(t)
+	settings := raw["data"].(map[string]interface{})
+	r := &mockReaper{raw: raw}
+
+	if err := newModerator(r).SetDefaultSort("sub", SortNew); err != nil {
+		t.Fatalf("error setting the default sort: %v", err)
+	}
+
+	if r.path != "/api/site_admin" {
+		t.Errorf("got path %s; wanted /api/site_admin", r.path)
+	}
+
+	// Every setting site_admin takes is sent back, along with api_type and
+	// sr; subreddit_id and the nested comment_contribution_settings are not.
+	if len(r.values) != len(settings) {
+		t.Errorf("sent %d values for %d settings", len(r.values), len(settings))
+	}
+	for _, key := range []string{"subreddit_id", "comment_contribution_settings"} {
+		if _, ok := r.values[key]; ok {
+			t.Errorf("sent %s, which site_admin does not take", key)
+		}
+	}
+	for key, expected := range map[string]string{
+		"api_type":                "json",
+		"sr":                      "t5_2qh1i",
+		"suggested_comment_sort":  "new",
+		"type":                    "restricted",
+		"link_type":               "any",
+		"allow_top":               "true",
+		"header-title":            "graw",
+		"lang":                    "en",
+		"wiki_edit_karma":         "100",
+		"comment_score_hide_mins": "60",
+		"over_18":                 "false",
+		"domain":                  "",
+		"spam_comments":           "low",
+	} {
+		if value, ok := r.values[key]; !ok || value != expected {
+			t.Errorf("sent %s=%q; wanted %q", key, value, expected)
+		}
+	}
+}
+
+func TestSetDefaultSortUnknownSetting(t *testing.T) {
+	raw := settingsFixture(t)
+	raw["data"].(map[string]interface{})["new_setting"] = []interface{}{"giphy"}
+	r := &mockReaper{raw: raw}
+
+	// site_admin does not take the setting, so it is left out.
+	if err := newModerator(r).SetDefaultSort("sub", SortNew); err != nil {
+		t.Fatalf("error setting the default sort: %v", err)
+	}
+	if _, ok := r.values["new_setting"]; ok {
+		t.Errorf("sent a setting site_admin does not take")
+	}
+}
+
+func TestSetDefaultSortUnsendableSetting(t *testing.T) {
+	for name, value := range map[string]interface{}{
+		"array":  []interface{}{"giphy", "static"},
+		"object": map[string]interface{}{"allowed_media_types": nil},
+	} {
+		raw := settingsFixture(t)
+		raw["data"].(map[string]interface{})["submit_text"] = value
+		r := &mockReaper{raw: raw}
+
+		if err := newModerator(r).SetDefaultSort("sub", SortNew); err == nil {
+			t.Errorf("%s: wanted an error for a setting that can't be sent back", name)
+		}
+		if r.path == "/api/site_admin" {
+			t.Errorf("%s: sent the settings to site_admin anyway", name)
+		}
+	}
+}
+
+func TestSetDefaultSortNoSubredditID(t *testing.T) {
+	raw := settingsFixture(t)
+	delete(raw["data"].(map[string]interface{}), "subreddit_id")
+	r := &mockReaper{raw: raw}
+
+	if err := newModerator(r).SetDefaultSort("sub", SortNew); err != noSubredditIDErr {
+		t.Errorf("got %v; wanted noSubredditIDErr", err)
+	}
+}
@@ -0,0 +1,91 @@
+package reddit
+
+import "context"
+
+// Actor defines the behaviors of a logged in Reddit bot which only acts, with
+// none of the streams and listings of a Bot. Deployments which scale the
+// bots reading from Reddit apart from those writing to it give the writers an
+// Actor, and the readers a collector from NewCollector.
+type Actor interface {
+	Account
+	Wiki
+	Moderator
+	MediaUploader
+	Modmail
+	Live
+
+	// WithContext returns a view of the actor whose requests are made
+	// with ctx, as Bot's WithContext does.
+	WithContext(ctx context.Context) Actor
+}
+
+type actor struct {
+	Account
+	Wiki
+	Moderator
+	MediaUploader
+	Modmail
+	Live
+
+	b Bot
+}
+
+type collector struct {
+	Lurker
+	UserLurker
+	Scanner
+
+	b Bot
+}
+
+// Split returns an Actor of the bot's writes and a Script of its reads, which
+// share its token and rate limit, e.g. to hand its streams to one part of a
+// program and its actions to another. The views keep the bot's restrictions
+// and reply guard.
+func Split(b Bot) (Actor, Script) {
+	return newActor(b), newCollector(b)
+}
+
+// NewActor returns an Actor logged in with the config's credentials. Actors
+// and collectors created apart, e.g. in separate processes, claim tokens of
+// their own and keep rate limits of their own.
+func NewActor(c BotConfig) (Actor, error) {
+	b, err := NewBot(c)
+	return newActor(b), err
+}
+
+// NewCollector returns a Script logged in with the config's credentials, which
+// reads with the bot's OAuth2 rate limit and sees what its account sees, but
+// has none of its write methods. Collectors can drive graw's Scan.
+func NewCollector(c BotConfig) (Script, error) {
+	b, err := NewBot(c)
+	return newCollector(b), err
+}
+
+func newActor(b Bot) Actor {
+	return &actor{
+		Account:       b,
+		Wiki:          b,
+		Moderator:     b,
+		MediaUploader: b,
+		Modmail:       b,
+		Live:          b,
+		b:             b,
+	}
+}
+
+func newCollector(b Bot) Script {
+	return &collector{Lurker: b, UserLurker: b, Scanner: b, b: b}
+}
+
+func (a *actor) WithContext(ctx context.Context) Actor {
+	return newActor(a.b.WithContext(ctx))
+}
+
+func (c *collector) WithContext(ctx context.Context) Script {
+	return newCollector(c.b.WithContext(ctx))
+}
+
+func (c *collector) Metrics() MetricsCollector {
+	return c.b.Metrics()
+}
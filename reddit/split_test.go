@@ -0,0 +1,33 @@
+package reddit
+
+import "testing"
+
+func TestSplit(t *testing.T) {
+	r := reaperWhich(Harvest{Posts: []*Post{{Name: "t3_a"}}}, nil)
+	b := &bot{
+		Account: newAccount(r),
+		Lurker:  newLurker(r),
+		Scanner: newScanner(r),
+		r:       r,
+	}
+	a, s := Split(b.Restrict(MayReply))
+
+	if _, ok := a.(Scanner); ok {
+		t.Errorf("actor can stream")
+	}
+	if _, ok := s.(Account); ok {
+		t.Errorf("collector can write")
+	}
+
+	if err := a.Reply("t3_a", "hi"); err != nil || r.path != "/api/comment" {
+		t.Errorf("actor replied to %s: %v", r.path, err)
+	}
+	if err := a.SendMessage("user", "subject", "hi"); err != NotPermittedErr {
+		t.Errorf("actor lost the bot's restriction: %v", err)
+	}
+
+	h, err := s.Listing("/r/golang", "")
+	if err != nil || len(h.Posts) != 1 || r.path != "/r/golang" {
+		t.Errorf("collector read %s: %v, %v", r.path, h, err)
+	}
+}
@@ -0,0 +1,188 @@
+package reddit
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// minGallery is the fewest images a gallery post holds.
+	minGallery = 2
+	// minPollOptions and maxPollOptions bound the options of a poll.
+	minPollOptions = 2
+	maxPollOptions = 6
+	// defaultPollDays is how long a poll runs if it gives no duration.
+	defaultPollDays = 3
+)
+
+var (
+	galleryErr     = fmt.Errorf("a gallery post needs at least %d images", minGallery)
+	pollOptionsErr = fmt.Errorf("a poll needs %d to %d options", minPollOptions, maxPollOptions)
+	pollDaysErr    = fmt.Errorf("a poll runs for 1 to 7 days")
+)
+
+// GalleryImage is an image of a gallery post.
+type GalleryImage struct {
+	Image MediaFile
+	// MediaID, if set, is the AssetID of an image uploaded earlier with
+	// UploadMedia, which is posted instead of uploading Image.
+	MediaID string
+	Caption string
+	// OutboundURL, if set, is a link shown with the image.
+	OutboundURL string
+}
+
+// Poll is the question and options of a poll post.
+type Poll struct {
+	// Text is the body of the post, above the options.
+	Text    string
+	Options []string
+	// Days is how long the poll runs, from 1 to 7. If zero, it runs for
+	// three days.
+	Days int
+}
+
+// Video is the video of a video post.
+type Video struct {
+	Video MediaFile
+	// Thumbnail is the image shown before the video plays.
+	Thumbnail MediaFile
+	// If GIF is set, the video is posted as a looping, silent video.
+	GIF bool
+}
+
+func (a *account) SubmitGallery(
+	subreddit, title string,
+	images []GalleryImage,
+) (Submission, error) {
+	if len(images) < minGallery {
+		return Submission{}, galleryErr
+	}
+
+	items := make([]map[string]string, 0, len(images))
+	for _, image := range images {
+		id := image.MediaID
+		if id == "" {
+			lease, _, err := uploadMedia(a.r, image.Image)
+			if err != nil {
+				return Submission{}, err
+			}
+			id = lease.Asset.ID
+		}
+
+		items = append(items, map[string]string{
+			"media_id":     id,
+			"caption":      image.Caption,
+			"outbound_url": image.OutboundURL,
+		})
+	}
+
+	s, err := a.r.sowJSON(
+		"/api/submit_gallery_post.json", map[string]interface{}{
+			"api_type":        "json",
+			"sr":              subreddit,
+			"title":           title,
+			"items":           items,
+			"show_error_list": true,
+		},
+	)
+	return named(s), err
+}
+
+func (a *account) SubmitPoll(subreddit, title string, poll Poll) (Submission, error) {
+	if len(poll.Options) < minPollOptions || len(poll.Options) > maxPollOptions {
+		return Submission{}, pollOptionsErr
+	}
+
+	days := poll.Days
+	if days == 0 {
+		days = defaultPollDays
+	}
+	if days < 1 || days > 7 {
+		return Submission{}, pollDaysErr
+	}
+
+	s, err := a.r.sowJSON(
+		"/api/submit_poll_post.json", map[string]interface{}{
+			"api_type": "json",
+			"sr":       subreddit,
+			"title":    title,
+			"text":     poll.Text,
+			"options":  poll.Options,
+			"duration": days,
+		},
+	)
+	return named(s), err
+}
+
+// videoResponse is the shape of Reddit's response to a video submission,
+// which is posted once the video is processed.
+type videoResponse struct {
+	JSON struct {
+		Data struct {
+			WebsocketURL string `mapstructure:"websocket_url"`
+		} `mapstructure:"data"`
+	} `mapstructure:"json"`
+}
+
+func (a *account) SubmitVideo(subreddit, title string, video Video) (Submission, error) {
+	_, videoURL, err := uploadMedia(a.r, video.Video)
+	if err != nil {
+		return Submission{}, err
+	}
+
+	_, thumbnailURL, err := uploadMedia(a.r, video.Thumbnail)
+	if err != nil {
+		return Submission{}, err
+	}
+
+	kind := "video"
+	if video.GIF {
+		kind = "videogif"
+	}
+
+	resp := &videoResponse{}
+	if err := a.r.sowInto(
+		"/api/submit", map[string]string{
+			"api_type":         "json",
+			"sr":               subreddit,
+			"kind":             kind,
+			"title":            title,
+			"url":              videoURL,
+			"video_poster_url": thumbnailURL,
+		},
+		resp,
+	); err != nil {
+		return Submission{}, err
+	}
+
+	url, err := a.r.awaitMedia(resp.JSON.Data.WebsocketURL)
+	if err != nil {
+		return Submission{}, err
+	}
+
+	return submissionAt(url), nil
+}
+
+// named fills in the name of a submission whose ID Reddit gave as a fullname,
+// as it does for gallery and poll posts.
+func named(s Submission) Submission {
+	if strings.HasPrefix(s.ID, "t3_") {
+		s.Name = s.ID
+		s.ID = strings.TrimPrefix(s.ID, "t3_")
+	}
+	return s
+}
+
+// submissionAt returns the submission of the post at a url.
+func submissionAt(url string) Submission {
+	s := Submission{URL: url}
+	parts := strings.Split(strings.Trim(url, "/"), "/")
+	for i, part := range parts {
+		if part == "comments" && i+1 < len(parts) {
+			s.ID = parts[i+1]
+			s.Name = "t3_" + s.ID
+		}
+	}
+	return s
+}
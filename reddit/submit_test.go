@@ -0,0 +1,171 @@
+package reddit
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aldarisbm/graw/clock"
+	"golang.org/x/net/websocket"
+)
+
+// routeClient answers requests with canned responses by path, and records the
+// bodies it is sent.
+type routeClient struct {
+	resps  map[string]string
+	bodies map[string][]string
+}
+
+func (c *routeClient) Do(r *http.Request) ([]byte, error) {
+	if r.Body != nil {
+		buf, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		c.bodies[r.URL.Path] = append(c.bodies[r.URL.Path], string(buf))
+	}
+	return []byte(c.resps[r.URL.Path]), nil
+}
+
+func mediaServers(t *testing.T) (*httptest.Server, *httptest.Server) {
+	storage := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Errorf("bad upload: %v", err)
+			}
+			if r.FormValue("key") == "" {
+				t.Errorf("upload lacks the lease's fields")
+			}
+			w.WriteHeader(http.StatusCreated)
+		}),
+	)
+
+	announcer := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		websocket.JSON.Send(ws, map[string]interface{}{
+			"type":    "success",
+			"payload": map[string]string{"redirect": "https://www.reddit.com/r/sub/comments/vid/title/"},
+		})
+	}))
+
+	return storage, announcer
+}
+
+func mediaReaper(storage, announcer *httptest.Server) (reaper, *routeClient) {
+	lease := `{
+		"args": {"action": "` + storage.URL + `", "fields": [{"name": "key", "value": "media/abc"}]},
+		"asset": {"asset_id": "abc", "websocket_url": "ws` + strings.TrimPrefix(announcer.URL, "http") + `"}
+	}`
+	c := &routeClient{
+		resps: map[string]string{
+			"/api/media/asset.json":             lease,
+			"/api/submit_gallery_post.json":     `{"json": {"errors": [], "data": {"id": "t3_gal", "url": "https://www.reddit.com/gallery/gal"}}}`,
+			"/api/submit_poll_post.json":        `{"json": {"errors": [], "data": {"id": "t3_poll", "url": "https://www.reddit.com/poll"}}}`,
+			"/api/submit":                       `{"json": {"errors": [], "data": {"websocket_url": "ws` + strings.TrimPrefix(announcer.URL, "http") + `"}}}`,
+			"/api/v1/style_asset_upload_s3/sub": `{"s3UploadLease": {"action": "` + storage.URL + `", "fields": [{"name": "key", "value": "style/banner"}]}}`,
+		},
+		bodies: make(map[string][]string),
+	}
+	r := &reaperImpl{
+		cli:      c,
+		parser:   newParser(),
+		hostname: "oauth.reddit.com",
+		scheme:   "https",
+		clock:    clock.Real(),
+		limit:    newRateLimit(),
+		uploader: storage.Client(),
+	}
+	return r, c
+}
+
+func mediaAccount(storage, announcer *httptest.Server) (Account, *routeClient) {
+	r, c := mediaReaper(storage, announcer)
+	return newAccount(r), c
+}
+
+func TestSubmitGallery(t *testing.T) {
+	storage, announcer := mediaServers(t)
+	defer storage.Close()
+	defer announcer.Close()
+	a, c := mediaAccount(storage, announcer)
+
+	s, err := a.SubmitGallery("sub", "title", []GalleryImage{
+		{Image: MediaFile{Filename: "a.png", Content: strings.NewReader("png")}, Caption: "first"},
+		{Image: MediaFile{Filename: "b.jpg", Content: strings.NewReader("jpg")}},
+	})
+	if err != nil {
+		t.Fatalf("failed to submit gallery: %v", err)
+	}
+
+	if s.Name != "t3_gal" || s.ID != "gal" {
+		t.Errorf("got submission %+v", s)
+	}
+
+	if leases := c.bodies["/api/media/asset.json"]; len(leases) != 2 ||
+		leases[0] != "filepath=a.png&mimetype=image%2Fpng" {
+		t.Errorf("leased %v", leases)
+	}
+
+	var gallery struct {
+		SR    string              `json:"sr"`
+		Items []map[string]string `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(c.bodies["/api/submit_gallery_post.json"][0]), &gallery); err != nil {
+		t.Fatal(err)
+	}
+	if gallery.SR != "sub" || len(gallery.Items) != 2 ||
+		gallery.Items[0]["media_id"] != "abc" || gallery.Items[0]["caption"] != "first" {
+		t.Errorf("submitted gallery %+v", gallery)
+	}
+}
+
+func TestSubmitPoll(t *testing.T) {
+	storage, announcer := mediaServers(t)
+	defer storage.Close()
+	defer announcer.Close()
+	a, c := mediaAccount(storage, announcer)
+
+	if _, err := a.SubmitPoll("sub", "title", Poll{Options: []string{"one"}}); err != pollOptionsErr {
+		t.Errorf("wanted a poll of one option rejected; got %v", err)
+	}
+
+	s, err := a.SubmitPoll("sub", "title", Poll{Text: "Which?", Options: []string{"a", "b"}})
+	if err != nil {
+		t.Fatalf("failed to submit poll: %v", err)
+	}
+	if s.Name != "t3_poll" {
+		t.Errorf("got submission %+v", s)
+	}
+
+	if body := c.bodies["/api/submit_poll_post.json"][0]; !strings.Contains(body, `"duration":3`) ||
+		!strings.Contains(body, `"options":["a","b"]`) {
+		t.Errorf("submitted poll %s", body)
+	}
+}
+
+func TestSubmitVideo(t *testing.T) {
+	storage, announcer := mediaServers(t)
+	defer storage.Close()
+	defer announcer.Close()
+	a, c := mediaAccount(storage, announcer)
+
+	s, err := a.SubmitVideo("sub", "title", Video{
+		Video:     MediaFile{Filename: "clip.mp4", Content: strings.NewReader("mp4")},
+		Thumbnail: MediaFile{Filename: "thumb.png", Content: strings.NewReader("png")},
+	})
+	if err != nil {
+		t.Fatalf("failed to submit video: %v", err)
+	}
+
+	if s.Name != "t3_vid" {
+		t.Errorf("got submission %+v", s)
+	}
+
+	body := c.bodies["/api/submit"][0]
+	if !strings.Contains(body, "kind=video&") || !strings.Contains(body, "url="+url.QueryEscape(storage.URL+"/media/abc")) {
+		t.Errorf("submitted video %s", body)
+	}
+}
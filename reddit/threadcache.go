@@ -0,0 +1,88 @@
+package reddit
+
+import (
+	"strings"
+	"sync"
+)
+
+type threadKey struct {
+	permalink string
+	opts      ThreadOptions
+}
+
+// threadFetch is a thread fetched, or being fetched, by a threadCache.
+type threadFetch struct {
+	done chan struct{}
+	post *Post
+	err  error
+}
+
+// threadCache is a Lurker which fetches each thread once.
+type threadCache struct {
+	l    Lurker
+	opts ThreadOptions
+
+	mu      sync.Mutex
+	threads map[threadKey]*threadFetch
+}
+
+// NewThreadCache returns a Lurker which fetches threads through l with the
+// given options, and fetches each thread only once however many times it is
+// asked for, even by concurrent callers. Context calls use the options with
+// their own context. Failed fetches are remembered too.
+//
+// Entries never expire, so a cache should live no longer than the handling
+// of one event, e.g. shared by everything that enriches a mention before it
+// is answered. The posts it returns are shared, and must not be modified.
+func NewThreadCache(l Lurker, opts ThreadOptions) Lurker {
+	return &threadCache{
+		l:       l,
+		opts:    opts,
+		threads: make(map[threadKey]*threadFetch),
+	}
+}
+
+func (c *threadCache) Thread(permalink string) (*Post, error) {
+	return c.ThreadWithOptions(permalink, c.opts)
+}
+
+func (c *threadCache) Context(permalink string, context int) (*Post, error) {
+	opts := c.opts
+	opts.Context = context
+	return c.ThreadWithOptions(permalink, opts)
+}
+
+func (c *threadCache) ThreadWithOptions(
+	permalink string,
+	opts ThreadOptions,
+) (*Post, error) {
+	key := threadKey{permalink: strings.TrimSuffix(permalink, "/"), opts: opts}
+
+	c.mu.Lock()
+	f, ok := c.threads[key]
+	if !ok {
+		f = &threadFetch{done: make(chan struct{})}
+		c.threads[key] = f
+	}
+	c.mu.Unlock()
+
+	if ok {
+		<-f.done
+		return f.post, f.err
+	}
+
+	f.post, f.err = c.l.ThreadWithOptions(permalink, opts)
+	close(f.done)
+	return f.post, f.err
+}
+
+// ExpandMore is passed through to the underlying Lurker. It modifies the
+// thread, so it must not be given threads the cache returned.
+func (c *threadCache) ExpandMore(thread *Post, more *More) ([]*Comment, error) {
+	return c.l.ExpandMore(thread, more)
+}
+
+// ExpandThread is passed through to the underlying Lurker, like ExpandMore.
+func (c *threadCache) ExpandThread(thread *Post, maxRequests int) error {
+	return c.l.ExpandThread(thread, maxRequests)
+}
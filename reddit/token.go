@@ -0,0 +1,71 @@
+package reddit
+
+import (
+	"fmt"
+	"time"
+)
+
+var noRefreshTokenErr = fmt.Errorf("a token bot needs a refresh token")
+
+// NewTokenBot returns a Bot authorized with a refresh token, such as one
+// Authorize or the authorize command obtained, instead of the account's
+// password. The bot claims an access token with it, and claims a new one ahead
+// of each one's expiry; see BotConfig.TokenRefreshMargin. The config's
+// username and password are ignored.
+func NewTokenBot(c BotConfig, refreshToken string) (Bot, error) {
+	if refreshToken == "" {
+		return nil, noRefreshTokenErr
+	}
+
+	c.App.RefreshToken = refreshToken
+	c.App.Username = ""
+	c.App.Password = ""
+	return NewBot(c)
+}
+
+// NewReadOnlyClient returns a Script authorized as the app itself, with no
+// user account, using only the app's ID and secret. Such application only
+// grants read Reddit through its OAuth2 API, with the OAuth2 rate limit of a
+// request a second, where a logged out Script is held to one every two
+// seconds. The access token is renewed ahead of its expiry like a Bot's. The
+// config's username, password, and refresh token are ignored.
+func NewReadOnlyClient(c BotConfig) (Script, error) {
+	c.App.Username = ""
+	c.App.Password = ""
+	c.App.RefreshToken = ""
+	if c.App.unauthenticated() {
+		return nil, errMissingOauthCredentials
+	}
+
+	q := newQuota(c.Clock)
+	cli, err := newClient(
+		clientConfig{
+			agent:         c.Agent,
+			app:           c.App,
+			client:        c.Client,
+			transport:     c.Transport,
+			refreshMargin: c.TokenRefreshMargin,
+			quota:         q,
+			trace:         c.Trace,
+			metrics:       c.Metrics,
+		},
+	)
+	r := newReaper(
+		reaperConfig{
+			client:   wrapClient(cli, c.WrapClient, c.Middleware),
+			parser:   newParser(),
+			hostname: "oauth.reddit.com",
+			tls:      true,
+			rate:     maxOf(c.Rate, time.Second),
+			clock:    c.Clock,
+			quota:    q,
+			retries:  c.Retry,
+		},
+	)
+	return &script{
+		Lurker:  newLurker(r),
+		Scanner: newScanner(r),
+		r:       r,
+		metrics: c.Metrics,
+	}, err
+}
@@ -0,0 +1,81 @@
+package reddit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// grantServer serves access tokens, recording the grant type of each claim.
+func grantServer(grants *[]string) *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+				*grants = append(*grants, r.Form.Get("grant_type"))
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{
+					"access_token": "access",
+					"token_type": "bearer",
+					"expires_in": 3600
+				}`))
+			},
+		),
+	)
+}
+
+func TestNewTokenBot(t *testing.T) {
+	var grants []string
+	tokens := grantServer(&grants)
+	defer tokens.Close()
+
+	c := BotConfig{
+		Agent: "agent",
+		App: App{
+			ID:       "id",
+			Secret:   "secret",
+			Username: "user",
+			tokenURL: tokens.URL,
+		},
+	}
+	if _, err := NewTokenBot(c, "refresh"); err != nil {
+		t.Fatalf("failed to make token bot: %v", err)
+	}
+
+	if len(grants) != 1 || grants[0] != "refresh_token" {
+		t.Errorf("claimed tokens with grants %v; wanted a refresh_token grant", grants)
+	}
+
+	if _, err := NewTokenBot(c, ""); err != noRefreshTokenErr {
+		t.Errorf("got error %v without a refresh token; wanted %v", err, noRefreshTokenErr)
+	}
+}
+
+func TestNewReadOnlyClient(t *testing.T) {
+	var grants []string
+	tokens := grantServer(&grants)
+	defer tokens.Close()
+
+	_, err := NewReadOnlyClient(BotConfig{
+		Agent: "agent",
+		App: App{
+			ID:           "id",
+			Secret:       "secret",
+			Username:     "user",
+			Password:     "password",
+			RefreshToken: "refresh",
+			tokenURL:     tokens.URL,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to make read only client: %v", err)
+	}
+
+	if len(grants) != 1 || grants[0] != "client_credentials" {
+		t.Errorf("claimed tokens with grants %v; wanted a client_credentials grant", grants)
+	}
+
+	if _, err := NewReadOnlyClient(BotConfig{App: App{ID: "id"}}); err != errMissingOauthCredentials {
+		t.Errorf("got error %v without a secret; wanted %v", err, errMissingOauthCredentials)
+	}
+}
@@ -0,0 +1,195 @@
+package reddit
+
+import "strconv"
+
+// HistoryOptions select a page of a user's history. The zero value selects
+// the newest hundred elements.
+type HistoryOptions struct {
+	// Sort is the order of the history: "new", "hot", "top", or
+	// "controversial". If empty, the newest elements come first.
+	Sort string
+	// Time is the window of a history sorted by top or controversial:
+	// "hour", "day", "week", "month", "year", or "all".
+	Time string
+	// Limit is the most elements returned, up to 100. If zero, 100 are.
+	Limit int
+	// After and Before are the fullnames of elements to page from, e.g.
+	// the After of the previous page's ListingInfo, and Count is how many
+	// elements were already seen.
+	After  string
+	Before string
+	Count  int
+}
+
+// params returns the request parameters of the options.
+func (o HistoryOptions) params() map[string]string {
+	params := map[string]string{"raw_json": "1", "limit": "100"}
+	set := func(key, value string) {
+		if value != "" {
+			params[key] = value
+		}
+	}
+
+	set("sort", o.Sort)
+	set("t", o.Time)
+	set("after", o.After)
+	set("before", o.Before)
+	if o.Limit > 0 {
+		params["limit"] = strconv.Itoa(o.Limit)
+	}
+	if o.Count > 0 {
+		params["count"] = strconv.Itoa(o.Count)
+	}
+	return params
+}
+
+// Trophy is a trophy shown on a user's profile, such as for their account's
+// age or a Reddit award.
+type Trophy struct {
+	ID          string `mapstructure:"id"`
+	Name        string `mapstructure:"name"`
+	Description string `mapstructure:"description"`
+	AwardID     string `mapstructure:"award_id"`
+	URL         string `mapstructure:"url"`
+	Icon        string `mapstructure:"icon_70"`
+	// GrantedAt is when the trophy was granted, in unix time. It is zero
+	// for trophies Reddit does not date.
+	GrantedAt uint64 `mapstructure:"granted_at"`
+}
+
+// AuthorState is the state of a Reddit account, as AuthorState finds it.
+type AuthorState int
+
+const (
+	// AuthorActive accounts exist and may post.
+	AuthorActive AuthorState = iota
+	// AuthorDeleted accounts were deleted by their owners, or are authors
+	// of content shown as "[deleted]". Reddit hides shadowbanned accounts
+	// as if they were deleted, so they are found deleted too.
+	AuthorDeleted
+	// AuthorSuspended accounts were suspended by Reddit's admins. While
+	// suspended they can't post, and their profiles are hidden.
+	AuthorSuspended
+)
+
+func (s AuthorState) String() string {
+	switch s {
+	case AuthorActive:
+		return "active"
+	case AuthorDeleted:
+		return "deleted"
+	case AuthorSuspended:
+		return "suspended"
+	}
+	return "AuthorState(" + strconv.Itoa(int(s)) + ")"
+}
+
+// UserLurker defines behaviors for looking up Reddit accounts.
+type UserLurker interface {
+	// AboutUser returns a user's account, e.g. to check whether it has a
+	// verified email before trusting it.
+	AboutUser(user string) (*Redditor, error)
+	// AuthorState returns the state of a user's account, and the account
+	// if it was found, so handlers can branch on authors who are gone
+	// instead of on the error of looking them up. Deleted accounts, which
+	// Reddit answers NotFoundErr for, are AuthorDeleted with no error.
+	AuthorState(user string) (AuthorState, *Redditor, error)
+	// UserOverview returns a page of a user's posts and comments,
+	// UserPosts a page of their posts, and UserComments a page of their
+	// comments. The ListingInfo holds the After to page back with.
+	UserOverview(user string, opts HistoryOptions) (Harvest, ListingInfo, error)
+	UserPosts(user string, opts HistoryOptions) (Harvest, ListingInfo, error)
+	UserComments(user string, opts HistoryOptions) (Harvest, ListingInfo, error)
+	// UserTrophies returns the trophies on a user's profile.
+	UserTrophies(user string) ([]*Trophy, error)
+}
+
+type userLurker struct {
+	r reaper
+}
+
+func newUserLurker(r reaper) UserLurker {
+	return &userLurker{r: r}
+}
+
+// aboutResponse is the shape of Reddit's response about an account.
+type aboutResponse struct {
+	Data Redditor `mapstructure:"data"`
+}
+
+func (u *userLurker) AboutUser(user string) (*Redditor, error) {
+	resp := &aboutResponse{}
+	if err := u.r.reapInto(
+		"/user/"+user+"/about",
+		map[string]string{"raw_json": "1"},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+func (u *userLurker) AuthorState(user string) (AuthorState, *Redditor, error) {
+	if user == "" || user == deletedKey {
+		return AuthorDeleted, nil, nil
+	}
+
+	r, err := u.AboutUser(user)
+	switch {
+	case err == NotFoundErr:
+		return AuthorDeleted, nil, nil
+	case err != nil:
+		return AuthorActive, nil, err
+	case r.IsSuspended:
+		return AuthorSuspended, r, nil
+	}
+	return AuthorActive, r, nil
+}
+
+func (u *userLurker) UserOverview(
+	user string,
+	opts HistoryOptions,
+) (Harvest, ListingInfo, error) {
+	return u.r.reapPage("/user/"+user+"/overview", opts.params())
+}
+
+func (u *userLurker) UserPosts(
+	user string,
+	opts HistoryOptions,
+) (Harvest, ListingInfo, error) {
+	return u.r.reapPage("/user/"+user+"/submitted", opts.params())
+}
+
+func (u *userLurker) UserComments(
+	user string,
+	opts HistoryOptions,
+) (Harvest, ListingInfo, error) {
+	return u.r.reapPage("/user/"+user+"/comments", opts.params())
+}
+
+// trophiesResponse is the shape of Reddit's lists of trophies.
+type trophiesResponse struct {
+	Data struct {
+		Trophies []struct {
+			Data Trophy `mapstructure:"data"`
+		} `mapstructure:"trophies"`
+	} `mapstructure:"data"`
+}
+
+func (u *userLurker) UserTrophies(user string) ([]*Trophy, error) {
+	resp := &trophiesResponse{}
+	if err := u.r.reapInto(
+		"/api/v1/user/"+user+"/trophies",
+		map[string]string{"raw_json": "1"},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+
+	trophies := make([]*Trophy, 0, len(resp.Data.Trophies))
+	for _, t := range resp.Data.Trophies {
+		trophy := t.Data
+		trophies = append(trophies, &trophy)
+	}
+	return trophies, nil
+}
@@ -0,0 +1,90 @@
+package reddit
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestUserTrophies(t *testing.T) {
+	r := &mockReaper{
+		raw: map[string]interface{}{
+			"kind": "TrophyList",
+			"data": map[string]interface{}{
+				"trophies": []interface{}{
+					map[string]interface{}{
+						"kind": "t6",
+						"data": map[string]interface{}{
+							"id":          "1a",
+							"name":        "Five-Year Club",
+							"description": nil,
+							"icon_70":     "https://example.com/5.png",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	trophies, err := newUserLurker(r).UserTrophies("user")
+	if err != nil {
+		t.Fatalf("error reading trophies: %v", err)
+	}
+
+	expected := []*Trophy{
+		{ID: "1a", Name: "Five-Year Club", Icon: "https://example.com/5.png"},
+	}
+	if diff := pretty.Compare(trophies, expected); diff != "" {
+		t.Errorf("trophies incorrect; diff: %s", diff)
+	}
+
+	if r.path != "/api/v1/user/user/trophies" {
+		t.Errorf("got path %s", r.path)
+	}
+}
+
+func TestAuthorState(t *testing.T) {
+	for _, test := range []struct {
+		user     string
+		raw      interface{}
+		err      error
+		expected AuthorState
+		found    bool
+	}{
+		{
+			user: "gopher",
+			raw: map[string]interface{}{
+				"kind": "t2",
+				"data": map[string]interface{}{"name": "gopher"},
+			},
+			expected: AuthorActive,
+			found:    true,
+		},
+		{
+			user: "spammer",
+			raw: map[string]interface{}{
+				"kind": "t2",
+				"data": map[string]interface{}{"name": "spammer", "is_suspended": true},
+			},
+			expected: AuthorSuspended,
+			found:    true,
+		},
+		{user: "gone", err: NotFoundErr, expected: AuthorDeleted},
+		{user: "[deleted]", expected: AuthorDeleted},
+	} {
+		r := &mockReaper{raw: test.raw, err: test.err}
+		state, redditor, err := newUserLurker(r).AuthorState(test.user)
+		if err != nil {
+			t.Errorf("%s: error reading state: %v", test.user, err)
+			continue
+		}
+		if state != test.expected || (redditor != nil) != test.found {
+			t.Errorf("%s: got %v with account %v; wanted %v", test.user, state, redditor, test.expected)
+		}
+	}
+
+	r := &mockReaper{err: BusyErr}
+	if _, _, err := newUserLurker(r).AuthorState("gopher"); err != BusyErr {
+		t.Errorf("got %v; wanted BusyErr", err)
+	}
+}
@@ -0,0 +1,162 @@
+package reddit
+
+import (
+	"strconv"
+	"time"
+)
+
+// WikiPage is a revision of a page in a subreddit's wiki.
+type WikiPage struct {
+	Content string
+	// RevisionID identifies this revision of the page. Pass it as the
+	// Previous revision of an edit to make the edit conflict safe.
+	RevisionID   string
+	RevisionDate time.Time
+	// RevisionBy is the name of the user who made this revision.
+	RevisionBy string
+	// MayRevise reports whether the bot may edit the page.
+	MayRevise bool
+}
+
+// WikiEdit is an edit to a wiki page.
+type WikiEdit struct {
+	// Content replaces the content of the page.
+	Content string
+	// Reason is shown in the page's revision history.
+	Reason string
+	// Previous, if set, is the revision ID the edit was based on. If the
+	// page has been revised since, the edit fails with ConflictErr instead
+	// of overwriting the newer revision.
+	Previous string
+}
+
+// WikiRevision is an entry in a wiki page's revision history.
+type WikiRevision struct {
+	// ID identifies the revision, as the RevisionID of the page it made.
+	ID     string
+	Page   string
+	Author string
+	Reason string
+	Date   time.Time
+	// Hidden is set on revisions a moderator hid from the history.
+	Hidden bool
+}
+
+// Wiki defines behaviors for reading and editing subreddit wikis.
+type Wiki interface {
+	// WikiPage returns the current revision of a page in the subreddit's
+	// wiki.
+	WikiPage(subreddit, page string) (*WikiPage, error)
+	// EditWikiPage edits a page in the subreddit's wiki, creating it if it
+	// does not exist.
+	EditWikiPage(subreddit, page string, edit WikiEdit) error
+	// WikiRevisions returns up to limit of the latest revisions of a page
+	// in the subreddit's wiki, newest first. If limit is zero, Reddit's
+	// default of 25 is used; it can be at most 100.
+	WikiRevisions(subreddit, page string, limit int) ([]*WikiRevision, error)
+}
+
+type wiki struct {
+	r reaper
+}
+
+func newWiki(r reaper) Wiki {
+	return &wiki{r: r}
+}
+
+// wikiPageResponse is the shape of Reddit's wiki page responses.
+type wikiPageResponse struct {
+	Data struct {
+		ContentMD    string  `mapstructure:"content_md"`
+		RevisionID   string  `mapstructure:"revision_id"`
+		RevisionDate float64 `mapstructure:"revision_date"`
+		MayRevise    bool    `mapstructure:"may_revise"`
+		RevisionBy   struct {
+			Data struct {
+				Name string `mapstructure:"name"`
+			} `mapstructure:"data"`
+		} `mapstructure:"revision_by"`
+	} `mapstructure:"data"`
+}
+
+func (w *wiki) WikiPage(subreddit, page string) (*WikiPage, error) {
+	resp := &wikiPageResponse{}
+	if err := w.r.reapInto(
+		"/r/"+subreddit+"/wiki/"+page,
+		map[string]string{"raw_json": "1"},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+
+	return &WikiPage{
+		Content:      resp.Data.ContentMD,
+		RevisionID:   resp.Data.RevisionID,
+		RevisionDate: time.Unix(int64(resp.Data.RevisionDate), 0),
+		RevisionBy:   resp.Data.RevisionBy.Data.Name,
+		MayRevise:    resp.Data.MayRevise,
+	}, nil
+}
+
+func (w *wiki) EditWikiPage(subreddit, page string, edit WikiEdit) error {
+	values := map[string]string{
+		"page":    page,
+		"content": edit.Content,
+		"reason":  edit.Reason,
+	}
+	if edit.Previous != "" {
+		values["previous"] = edit.Previous
+	}
+	return w.r.sow("/r/"+subreddit+"/api/wiki/edit", values)
+}
+
+// wikiRevisionsResponse is the shape of Reddit's wiki revision listings, whose
+// children are bare revisions rather than things.
+type wikiRevisionsResponse struct {
+	Data struct {
+		Children []struct {
+			ID             string  `mapstructure:"id"`
+			Page           string  `mapstructure:"page"`
+			Reason         string  `mapstructure:"reason"`
+			Timestamp      float64 `mapstructure:"timestamp"`
+			RevisionHidden bool    `mapstructure:"revision_hidden"`
+			Author         struct {
+				Data struct {
+					Name string `mapstructure:"name"`
+				} `mapstructure:"data"`
+			} `mapstructure:"author"`
+		} `mapstructure:"children"`
+	} `mapstructure:"data"`
+}
+
+func (w *wiki) WikiRevisions(
+	subreddit, page string,
+	limit int,
+) ([]*WikiRevision, error) {
+	values := map[string]string{"raw_json": "1"}
+	if limit > 0 {
+		values["limit"] = strconv.Itoa(limit)
+	}
+
+	resp := &wikiRevisionsResponse{}
+	if err := w.r.reapInto(
+		"/r/"+subreddit+"/wiki/revisions/"+page,
+		values,
+		resp,
+	); err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*WikiRevision, 0, len(resp.Data.Children))
+	for _, c := range resp.Data.Children {
+		revisions = append(revisions, &WikiRevision{
+			ID:     c.ID,
+			Page:   c.Page,
+			Author: c.Author.Data.Name,
+			Reason: c.Reason,
+			Date:   time.Unix(int64(c.Timestamp), 0),
+			Hidden: c.RevisionHidden,
+		})
+	}
+	return revisions, nil
+}
@@ -0,0 +1,92 @@
+package reddit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestWikiPage(t *testing.T) {
+	r := &mockReaper{
+		raw: map[string]interface{}{
+			"kind": "wikipage",
+			"data": map[string]interface{}{
+				"content_md":    "hello",
+				"revision_id":   "abc",
+				"revision_date": float64(1500000000),
+				"may_revise":    true,
+				"revision_by": map[string]interface{}{
+					"kind": "t2",
+					"data": map[string]interface{}{"name": "mod"},
+				},
+			},
+		},
+	}
+
+	page, err := newWiki(r).WikiPage("sub", "index")
+	if err != nil {
+		t.Fatalf("error reading wiki page: %v", err)
+	}
+
+	expected := &WikiPage{
+		Content:      "hello",
+		RevisionID:   "abc",
+		RevisionDate: time.Unix(1500000000, 0),
+		RevisionBy:   "mod",
+		MayRevise:    true,
+	}
+	if diff := pretty.Compare(page, expected); diff != "" {
+		t.Errorf("page incorrect; diff: %s", diff)
+	}
+
+	if r.path != "/r/sub/wiki/index" {
+		t.Errorf("got path %s", r.path)
+	}
+}
+
+func TestWikiRevisions(t *testing.T) {
+	r := &mockReaper{
+		raw: map[string]interface{}{
+			"kind": "Listing",
+			"data": map[string]interface{}{
+				"children": []interface{}{
+					map[string]interface{}{
+						"id":              "abc",
+						"page":            "index",
+						"reason":          "why",
+						"timestamp":       float64(1500000000),
+						"revision_hidden": true,
+						"author": map[string]interface{}{
+							"kind": "t2",
+							"data": map[string]interface{}{"name": "mod"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	revisions, err := newWiki(r).WikiRevisions("sub", "index", 0)
+	if err != nil {
+		t.Fatalf("error reading wiki revisions: %v", err)
+	}
+
+	expected := []*WikiRevision{
+		{
+			ID:     "abc",
+			Page:   "index",
+			Author: "mod",
+			Reason: "why",
+			Date:   time.Unix(1500000000, 0),
+			Hidden: true,
+		},
+	}
+	if diff := pretty.Compare(revisions, expected); diff != "" {
+		t.Errorf("revisions incorrect; diff: %s", diff)
+	}
+
+	if r.path != "/r/sub/wiki/revisions/index" {
+		t.Errorf("got path %s", r.path)
+	}
+}
@@ -0,0 +1,191 @@
+package graw
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aldarisbm/graw/archive"
+	"github.com/aldarisbm/graw/botfaces"
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/logging"
+)
+
+// errReplayDone is sent by the replay once the archive is exhausted, to end
+// the run.
+var errReplayDone = fmt.Errorf("replay finished")
+
+// ReplayConfig configures a graw replay.
+type ReplayConfig struct {
+	// Speed is how many times faster than they originally happened the
+	// archived events are replayed; e.g. 60 replays an hour of traffic in
+	// a minute. If zero, events are replayed as fast as the handler takes
+	// them.
+	Speed float64
+	// If set, this clock is advanced to the archived time of each event
+	// before the event is dispatched. Bots which read the time from the
+	// same clock, and Reddit handles built with it, see time pass as it
+	// did in the archive, so schedules, cooldowns, and rate limits can be
+	// tested over a week of traffic in a minute.
+	Clock clock.Simulation
+	// If set, internal messages will be logged here, such as the errors
+	// the run stays up through and the one it stops with. See
+	// logging.Std to log to a *log.Logger.
+	Logger logging.Logger
+}
+
+// Replay feeds the events in an archive to the handler, as Run would have
+// when they happened, so new handler logic can be tried against historical
+// traffic; see Config.Archive for which events are archived. Events are
+// dispatched one at a time in archive order. It returns the same stop() and
+// wait() functions as Run; wait() returns nil once the archive is exhausted.
+//
+// Unlike Run, Replay calls the handler directly rather than through a run's
+// dispatch pipeline: events are not translated, ordering is archive order
+// whatever the original run's Ordering, and the Own field of an event is
+// left as it was archived rather than tagged again.
+func Replay(handler interface{}, source archive.Reader, cfg ReplayConfig) (
+	func(),
+	func() error,
+	error,
+) {
+	kill := make(chan bool)
+	errs := make(chan error)
+
+	stop, wait, err := launch(handler, kill, errs, logger(cfg.Logger), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	go replay(handler, source, cfg.Speed, cfg.Clock, kill, errs)
+
+	return stop, func() error {
+		if err := wait(); err != errReplayDone {
+			return err
+		}
+		return nil
+	}, nil
+}
+
+func replay(
+	handler interface{},
+	source archive.Reader,
+	speed float64,
+	sim clock.Simulation,
+	kill <-chan bool,
+	errs chan<- error,
+) {
+	var last time.Time
+	for {
+		e, err := source.Read()
+		if err == io.EOF {
+			err = errReplayDone
+		}
+		if err != nil {
+			select {
+			case errs <- err:
+			case <-kill:
+			}
+			return
+		}
+
+		when := e.When()
+		if speed > 0 && !last.IsZero() && when.After(last) {
+			select {
+			case <-time.After(time.Duration(float64(when.Sub(last)) / speed)):
+			case <-kill:
+				return
+			}
+		}
+		last = when
+
+		if sim != nil {
+			sim.AdvanceTo(when)
+		}
+
+		select {
+		case errs <- dispatch(handler, e):
+		case <-kill:
+			return
+		}
+	}
+}
+
+// dispatch calls the handler method an archived event was originally
+// dispatched to.
+func dispatch(handler interface{}, e archive.Event) error {
+	switch e.Kind {
+	case archive.PostKind, archive.UserPostKind:
+		if e.Post == nil {
+			return missingPayloadErr(e)
+		}
+	case archive.CommentKind, archive.UserCommentKind:
+		if e.Comment == nil {
+			return missingPayloadErr(e)
+		}
+	case archive.PostReplyKind, archive.CommentReplyKind,
+		archive.MentionKind, archive.MessageKind:
+		if e.Message == nil {
+			return missingPayloadErr(e)
+		}
+	}
+
+	switch e.Kind {
+	case archive.PostKind:
+		if ph, ok := handler.(botfaces.PostHandler); ok {
+			return ph.Post(e.Post)
+		}
+		return postHandlerErr
+	case archive.CommentKind:
+		if ch, ok := handler.(botfaces.CommentHandler); ok {
+			return ch.Comment(e.Comment)
+		}
+		return commentHandlerErr
+	case archive.UserPostKind:
+		if uh, ok := handler.(botfaces.UserHandler); ok {
+			return uh.UserPost(e.Post)
+		}
+		return userHandlerErr
+	case archive.UserCommentKind:
+		if uh, ok := handler.(botfaces.UserHandler); ok {
+			return uh.UserComment(e.Comment)
+		}
+		return userHandlerErr
+	case archive.PostReplyKind:
+		if prh, ok := handler.(botfaces.PostReplyHandler); ok {
+			return prh.PostReply(e.Message)
+		}
+		return postReplyHandlerErr
+	case archive.CommentReplyKind:
+		if crh, ok := handler.(botfaces.CommentReplyHandler); ok {
+			return crh.CommentReply(e.Message)
+		}
+		return commentReplyHandlerErr
+	case archive.MentionKind:
+		if mh, ok := handler.(botfaces.MentionHandler); ok {
+			return mh.Mention(e.Message)
+		}
+		return mentionHandlerErr
+	case archive.MessageKind:
+		if mh, ok := handler.(botfaces.MessageHandler); ok {
+			return mh.Message(e.Message)
+		}
+		return messageHandlerErr
+	}
+
+	return fmt.Errorf("unknown archived event kind %q", e.Kind)
+}
+
+func missingPayloadErr(e archive.Event) error {
+	return fmt.Errorf("archived %s event has no content", e.Kind)
+}
+
+// archived writes an event to the archive, if there is one.
+func archived(w archive.Writer, e archive.Event) error {
+	if w == nil {
+		return nil
+	}
+
+	e.Time = time.Now()
+	return w.Write(e)
+}
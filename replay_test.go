@@ -0,0 +1,217 @@
+package graw
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/archive"
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+)
+
+type replayBot struct {
+	posts    []string
+	mentions []string
+}
+
+func (r *replayBot) Post(p *reddit.Post) error {
+	r.posts = append(r.posts, p.Name)
+	return nil
+}
+
+func (r *replayBot) Mention(m *reddit.Message) error {
+	r.mentions = append(r.mentions, m.Name)
+	return nil
+}
+
+func archiveOf(t *testing.T, events ...archive.Event) archive.Reader {
+	var buf bytes.Buffer
+	w := archive.NewWriter(&buf)
+	for _, e := range events {
+		if err := w.Write(e); err != nil {
+			t.Fatalf("failed to write archive: %v", err)
+		}
+	}
+	return archive.NewReader(&buf)
+}
+
+func TestReplay(t *testing.T) {
+	source := archiveOf(
+		t,
+		archive.Event{
+			Kind: archive.PostKind,
+			Time: time.Unix(0, 0),
+			Post: &reddit.Post{Name: "t3_a"},
+		},
+		archive.Event{
+			Kind:    archive.MentionKind,
+			Time:    time.Unix(60, 0),
+			Message: &reddit.Message{Name: "t1_b"},
+		},
+		archive.Event{
+			Kind: archive.PostKind,
+			Time: time.Unix(120, 0),
+			Post: &reddit.Post{Name: "t3_c"},
+		},
+	)
+
+	bot := &replayBot{}
+	began := time.Now()
+	_, wait, err := Replay(bot, source, ReplayConfig{Speed: 6000})
+	if err != nil {
+		t.Fatalf("failed to start replay: %v", err)
+	}
+
+	if err := wait(); err != nil {
+		t.Errorf("replay failed: %v", err)
+	}
+
+	if elapsed := time.Since(began); elapsed < 20*time.Millisecond {
+		t.Errorf("two minutes at 6000x took %v; wanted at least 20ms", elapsed)
+	}
+
+	if len(bot.posts) != 2 || bot.posts[0] != "t3_a" || bot.posts[1] != "t3_c" {
+		t.Errorf("got posts %v", bot.posts)
+	}
+
+	if len(bot.mentions) != 1 || bot.mentions[0] != "t1_b" {
+		t.Errorf("got mentions %v", bot.mentions)
+	}
+}
+
+func TestReplayUnhandled(t *testing.T) {
+	source := archiveOf(t, archive.Event{
+		Kind:    archive.CommentKind,
+		Comment: &reddit.Comment{Name: "t1_a"},
+	})
+
+	_, wait, err := Replay(&replayBot{}, source, ReplayConfig{})
+	if err != nil {
+		t.Fatalf("failed to start replay: %v", err)
+	}
+
+	if err := wait(); err != commentHandlerErr {
+		t.Errorf("got %v; wanted %v", err, commentHandlerErr)
+	}
+}
+
+// cooldownBot only handles posts once a minute has passed since the last one
+// it handled.
+type cooldownBot struct {
+	clock   clock.Clock
+	last    time.Time
+	handled []string
+}
+
+func (c *cooldownBot) Post(p *reddit.Post) error {
+	if now := c.clock.Now(); now.Sub(c.last) >= time.Minute {
+		c.last = now
+		c.handled = append(c.handled, p.Name)
+	}
+	return nil
+}
+
+func TestReplayClock(t *testing.T) {
+	var events []archive.Event
+	for i, name := range []string{"t3_a", "t3_b", "t3_c", "t3_d"} {
+		events = append(events, archive.Event{
+			Kind: archive.PostKind,
+			Time: time.Unix(int64(i*40), 0),
+			Post: &reddit.Post{Name: name},
+		})
+	}
+
+	sim := clock.NewSimulation(time.Unix(-3600, 0))
+	bot := &cooldownBot{clock: sim}
+	_, wait, err := Replay(bot, archiveOf(t, events...), ReplayConfig{Clock: sim})
+	if err != nil {
+		t.Fatalf("failed to start replay: %v", err)
+	}
+
+	if err := wait(); err != nil {
+		t.Errorf("replay failed: %v", err)
+	}
+
+	if len(bot.handled) != 2 || bot.handled[0] != "t3_a" || bot.handled[1] != "t3_c" {
+		t.Errorf("got handled posts %v; wanted [t3_a t3_c]", bot.handled)
+	}
+
+	if now := sim.Now(); !now.Equal(time.Unix(120, 0)) {
+		t.Errorf("clock reads %v after replay; wanted %v", now, time.Unix(120, 0))
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// lookupBot reads two accounts from Reddit for every post it handles.
+type lookupBot struct {
+	bot reddit.Bot
+}
+
+func (l *lookupBot) Post(p *reddit.Post) error {
+	for _, user := range []string{"alice", "bob"} {
+		if _, err := l.bot.AboutUser(user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestReplayRateLimit(t *testing.T) {
+	sim := clock.NewSimulation(time.Unix(0, 0))
+	requests := 0
+	bot, err := reddit.NewBot(reddit.BotConfig{
+		Agent: "test",
+		Clock: sim,
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       ioutil.NopCloser(strings.NewReader(`{"kind": "t2", "data": {}}`)),
+				Request:    req,
+			}, nil
+		}),
+	})
+	if err != nil {
+		t.Fatalf("failed to make bot: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, b := range []reddit.Bot{bot, bot.WithContext(ctx)} {
+		source := archiveOf(t, archive.Event{
+			Kind: archive.PostKind,
+			Time: sim.Now(),
+			Post: &reddit.Post{Name: "t3_a"},
+		})
+		_, wait, err := Replay(&lookupBot{bot: b}, source, ReplayConfig{Clock: sim})
+		if err != nil {
+			t.Fatalf("failed to start replay: %v", err)
+		}
+
+		result := make(chan error)
+		go func() { result <- wait() }()
+		select {
+		case err := <-result:
+			if err != nil {
+				t.Errorf("replay failed: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("replay blocked on the rate limit")
+		}
+	}
+
+	if requests != 4 {
+		t.Errorf("made %d requests; wanted 4", requests)
+	}
+}
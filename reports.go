@@ -0,0 +1,69 @@
+package graw
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aldarisbm/graw/botfaces"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/streams"
+)
+
+// defaultReportInterval is how often report queues are read if the Config
+// gives no interval.
+const defaultReportInterval = time.Minute
+
+var reportHandlerErr = fmt.Errorf(
+	"You must implement ReportHandler to take report feeds.",
+)
+
+// connectReports connects the report queues of the config's subreddits to
+// the handler, if it names any.
+func connectReports(
+	handler interface{},
+	bot reddit.Bot,
+	c Config,
+	kill <-chan bool,
+	errs chan<- error,
+) error {
+	if len(c.Reports) == 0 {
+		return nil
+	}
+
+	rh, ok := handler.(botfaces.ReportHandler)
+	if !ok {
+		return reportHandlerErr
+	}
+
+	cfg := streams.ReportConfig{
+		Interval:   c.ReportInterval,
+		MinReports: c.MinReports,
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = defaultReportInterval
+	}
+
+	reported, err := streams.Reports(bot, kill, errs, cfg, c.Reports...)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for r := range reported {
+			if c.Authors != nil {
+				c.Authors.Report(reportedAuthor(r), r.Total)
+			}
+			errs <- rh.Reported(r)
+		}
+	}()
+
+	return nil
+}
+
+// reportedAuthor returns the author of a reported post or comment.
+func reportedAuthor(r *streams.Reported) string {
+	if r.Post != nil {
+		return r.Post.Author
+	}
+	return r.Comment.Author
+}
@@ -0,0 +1,40 @@
+package graw
+
+import (
+	"fmt"
+
+	"github.com/aldarisbm/graw/botfaces"
+	"github.com/aldarisbm/graw/reputation"
+)
+
+var linkPostHandlerErr = fmt.Errorf(
+	"You must implement LinkPostHandler to take domain reputations.",
+)
+
+// connectReputation connects the handler to link posts enriched with their
+// domain's reputation if the config has a reputation ledger, and returns the
+// channel to dispatch them over. The channel is nil if there is no ledger.
+func connectReputation(
+	handler interface{},
+	c Config,
+	kill <-chan bool,
+	errs chan<- error,
+) (chan<- *reputation.LinkPost, error) {
+	if c.Reputation == nil {
+		return nil, nil
+	}
+
+	lh, ok := handler.(botfaces.LinkPostHandler)
+	if !ok {
+		return nil, linkPostHandlerErr
+	}
+
+	links := make(chan *reputation.LinkPost)
+	go func() {
+		for lp := range links {
+			errs <- lh.LinkPost(lp)
+		}
+	}()
+
+	return links, nil
+}
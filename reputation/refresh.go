@@ -0,0 +1,37 @@
+package reputation
+
+import (
+	"strings"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// infoBatch is the most fullnames Reddit's /api/info accepts in one request.
+const infoBatch = 100
+
+// Refresh fetches the posts the ledger remembers anew and observes them, so
+// removals and reports since they were first seen are counted. It consumes one
+// interval of the handle per hundred posts.
+func Refresh(sc reddit.Scanner, l Ledger) error {
+	names := l.Recent()
+	for i := 0; i < len(names); i += infoBatch {
+		end := i + infoBatch
+		if end > len(names) {
+			end = len(names)
+		}
+
+		h, err := sc.ListingWithParams(
+			"/api/info",
+			map[string]string{"id": strings.Join(names[i:end], ",")},
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range h.Posts {
+			l.Observe(p)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+// Package reputation keeps per-domain statistics of the link posts a bot
+// sees, to help moderators police spam domains.
+package reputation
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aldarisbm/graw/archive"
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// maxPosts is the most posts a ledger remembers individually. Forgotten posts
+// still count toward their domain's statistics, but updates to them are no
+// longer seen.
+const maxPosts = 10000
+
+// Reputation is what a bot has seen of a domain.
+type Reputation struct {
+	Domain string
+	// Submissions is the number of link posts to the domain.
+	Submissions int
+	// Removed is the number of those posts which were removed.
+	Removed int
+	// Reports is the number of reports on those posts. Reports are only
+	// visible to moderators.
+	Reports int
+}
+
+// RemovalRate is the fraction of the domain's submissions which were removed.
+func (r Reputation) RemovalRate() float64 {
+	if r.Submissions == 0 {
+		return 0
+	}
+
+	return float64(r.Removed) / float64(r.Submissions)
+}
+
+// LinkPost is a link post with the reputation of its domain attached.
+type LinkPost struct {
+	Post       *reddit.Post
+	Reputation Reputation
+}
+
+// Ledger keeps the reputations of domains. It is safe for concurrent use.
+type Ledger interface {
+	// Observe counts a link post toward its domain. Observing a post again,
+	// e.g. after fetching it anew, updates whether it was removed and how
+	// many reports it has. Self posts are ignored.
+	Observe(p *reddit.Post)
+	// Reputation returns the reputation of a domain.
+	Reputation(domain string) Reputation
+	// Domains returns the reputations of every domain seen, the most
+	// submitted first.
+	Domains() []Reputation
+	// Recent returns the fullnames of the posts the ledger remembers,
+	// oldest first, for fetching anew.
+	Recent() []string
+}
+
+// post is the state of a post last observed.
+type post struct {
+	domain  string
+	removed bool
+	reports int
+}
+
+type ledger struct {
+	mu      sync.RWMutex
+	domains map[string]*Reputation
+	posts   map[string]post
+	order   []string
+}
+
+// NewLedger returns an empty ledger.
+func NewLedger() Ledger {
+	return &ledger{
+		domains: make(map[string]*Reputation),
+		posts:   make(map[string]post),
+	}
+}
+
+// FromArchive returns a ledger of every link post in the archive.
+func FromArchive(r archive.Reader) (Ledger, error) {
+	l := NewLedger()
+	for {
+		e, err := r.Read()
+		if err == io.EOF {
+			return l, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		if e.Post != nil {
+			l.Observe(e.Post)
+		}
+	}
+}
+
+// Enrich attaches the reputation of a post's domain to it, first counting the
+// post toward its domain. It returns nil for self posts.
+func Enrich(l Ledger, p *reddit.Post) *LinkPost {
+	if p.IsSelf {
+		return nil
+	}
+
+	l.Observe(p)
+	return &LinkPost{Post: p, Reputation: l.Reputation(p.Domain)}
+}
+
+func (l *ledger) Observe(p *reddit.Post) {
+	if p.IsSelf || p.Domain == "" {
+		return
+	}
+
+	now := post{
+		domain:  strings.ToLower(p.Domain),
+		removed: p.RemovedByCategory != "",
+		reports: int(p.NumReports),
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rep, ok := l.domains[now.domain]
+	if !ok {
+		rep = &Reputation{Domain: now.domain}
+		l.domains[now.domain] = rep
+	}
+
+	last, seen := l.posts[p.Name]
+	if !seen {
+		rep.Submissions++
+		l.order = append(l.order, p.Name)
+		if len(l.order) > maxPosts {
+			delete(l.posts, l.order[0])
+			l.order = l.order[1:]
+		}
+	}
+
+	if now.removed && !last.removed {
+		rep.Removed++
+	} else if !now.removed && last.removed {
+		rep.Removed--
+	}
+	rep.Reports += now.reports - last.reports
+
+	l.posts[p.Name] = now
+}
+
+func (l *ledger) Reputation(domain string) Reputation {
+	domain = strings.ToLower(domain)
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if rep, ok := l.domains[domain]; ok {
+		return *rep
+	}
+	return Reputation{Domain: domain}
+}
+
+func (l *ledger) Domains() []Reputation {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	reps := make([]Reputation, 0, len(l.domains))
+	for _, rep := range l.domains {
+		reps = append(reps, *rep)
+	}
+	sort.Slice(reps, func(i, j int) bool {
+		if reps[i].Submissions != reps[j].Submissions {
+			return reps[i].Submissions > reps[j].Submissions
+		}
+		return reps[i].Domain < reps[j].Domain
+	})
+	return reps
+}
+
+func (l *ledger) Recent() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]string(nil), l.order...)
+}
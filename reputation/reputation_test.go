@@ -0,0 +1,123 @@
+package reputation
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/aldarisbm/graw/archive"
+	"github.com/aldarisbm/graw/reddit"
+)
+
+func TestObserve(t *testing.T) {
+	l := NewLedger()
+	l.Observe(&reddit.Post{Name: "t3_a", Domain: "Spam.example"})
+	l.Observe(&reddit.Post{Name: "t3_b", Domain: "spam.example"})
+	l.Observe(&reddit.Post{Name: "t3_c", Domain: "good.example"})
+	l.Observe(&reddit.Post{Name: "t3_d", Domain: "self.golang", IsSelf: true})
+
+	// Observing a post again updates it without counting it twice.
+	l.Observe(&reddit.Post{
+		Name:              "t3_a",
+		Domain:            "spam.example",
+		RemovedByCategory: "moderator",
+		NumReports:        3,
+	})
+
+	rep := l.Reputation("SPAM.example")
+	if rep.Submissions != 2 || rep.Removed != 1 || rep.Reports != 3 {
+		t.Errorf("wrong reputation: %+v", rep)
+	}
+
+	if rate := rep.RemovalRate(); rate != 0.5 {
+		t.Errorf("wanted removal rate 0.5; got %f", rate)
+	}
+
+	// Reinstated posts no longer count as removed.
+	l.Observe(&reddit.Post{Name: "t3_a", Domain: "spam.example", NumReports: 1})
+	if rep := l.Reputation("spam.example"); rep.Removed != 0 || rep.Reports != 1 {
+		t.Errorf("wrong reputation after reinstatement: %+v", rep)
+	}
+
+	domains := l.Domains()
+	if len(domains) != 2 || domains[0].Domain != "spam.example" {
+		t.Errorf("wrong domains: %+v", domains)
+	}
+
+	if recent := l.Recent(); len(recent) != 3 || recent[0] != "t3_a" {
+		t.Errorf("wrong recent posts: %v", recent)
+	}
+}
+
+func TestEnrich(t *testing.T) {
+	l := NewLedger()
+	l.Observe(&reddit.Post{
+		Name:              "t3_a",
+		Domain:            "spam.example",
+		RemovedByCategory: "reddit",
+	})
+
+	lp := Enrich(l, &reddit.Post{Name: "t3_b", Domain: "spam.example"})
+	if lp == nil || lp.Reputation.Submissions != 2 || lp.Reputation.Removed != 1 {
+		t.Errorf("wrong enrichment: %+v", lp)
+	}
+
+	if lp := Enrich(l, &reddit.Post{Name: "t3_c", IsSelf: true}); lp != nil {
+		t.Errorf("wanted self posts left alone; got %+v", lp)
+	}
+}
+
+type infoScanner struct {
+	reddit.Scanner
+	posts map[string]*reddit.Post
+	calls int
+}
+
+func (i *infoScanner) ListingWithParams(path string, params map[string]string) (reddit.Harvest, error) {
+	i.calls++
+	var h reddit.Harvest
+	for _, name := range strings.Split(params["id"], ",") {
+		if p, ok := i.posts[name]; ok {
+			h.Posts = append(h.Posts, p)
+		}
+	}
+	return h, nil
+}
+
+func TestRefresh(t *testing.T) {
+	l := NewLedger()
+	l.Observe(&reddit.Post{Name: "t3_a", Domain: "spam.example"})
+
+	sc := &infoScanner{posts: map[string]*reddit.Post{
+		"t3_a": {Name: "t3_a", Domain: "spam.example", RemovedByCategory: "automod_filtered"},
+	}}
+	if err := Refresh(sc, l); err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+
+	if rep := l.Reputation("spam.example"); rep.Removed != 1 || sc.calls != 1 {
+		t.Errorf("refresh did not count removal: %+v after %d calls", rep, sc.calls)
+	}
+}
+
+func TestFromArchive(t *testing.T) {
+	var buf bytes.Buffer
+	w := archive.NewWriter(&buf)
+	w.Write(archive.Event{
+		Kind: archive.PostKind,
+		Post: &reddit.Post{Name: "t3_a", Domain: "spam.example"},
+	})
+	w.Write(archive.Event{
+		Kind:    archive.CommentKind,
+		Comment: &reddit.Comment{Name: "t1_b"},
+	})
+
+	l, err := FromArchive(archive.NewReader(&buf))
+	if err != nil {
+		t.Fatalf("failed to read archive: %v", err)
+	}
+
+	if rep := l.Reputation("spam.example"); rep.Submissions != 1 {
+		t.Errorf("wrong reputation from archive: %+v", rep)
+	}
+}
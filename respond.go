@@ -0,0 +1,39 @@
+package graw
+
+import (
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/streams"
+)
+
+// connectResponder answers modmail from the bot's messages with the config's
+// responder, if it has one.
+func connectResponder(
+	bot reddit.Bot,
+	c Config,
+	from streams.Start,
+	kill <-chan bool,
+	errs chan<- error,
+) error {
+	if c.Responder == nil {
+		return nil
+	}
+
+	if c.Ledger != nil {
+		bot = c.Ledger.Bot(bot)
+	}
+
+	messages, err := streams.MessagesFrom(bot, kill, errs, from)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for m := range messages {
+			if _, err := c.Responder.Respond(bot, m); err != nil {
+				errs <- err
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,356 @@
+// Package responder answers modmail, the messages users send to the
+// moderators of a subreddit, by rules: each rule matches messages by their
+// subject, body, and author, and replies from a template, archives, or assigns
+// them. Every message considered is written to an audit log, with what was
+// done about it, and a dry run logs what would have been done without doing
+// it.
+//
+// A rule answering ban appeals from users who are no longer banned might be:
+//
+//	responder.Rule{
+//	  Name:    "lifted",
+//	  Subject: `(?i)appeal`,
+//	  Banned:  responder.IsNot,
+//	  Reply:   `Hi /u/{{.Message.Author}}, you are not banned from /r/{{.Message.Subreddit}}.`,
+//	  Archive: true,
+//	}
+package responder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/compose"
+	"github.com/aldarisbm/graw/reddit"
+)
+
+var (
+	noNameErr      = fmt.Errorf("every rule needs a name")
+	noOrganizerErr = fmt.Errorf("rules which archive or assign modmail need an Organizer")
+)
+
+// Condition is a requirement on a yes or no property of a message's author.
+type Condition int
+
+const (
+	// Any matches authors whether or not they have the property.
+	Any Condition = iota
+	// Is matches authors who have the property.
+	Is
+	// IsNot matches authors who do not have the property.
+	IsNot
+)
+
+func (c Condition) matches(has bool) bool {
+	return c == Any || (c == Is) == has
+}
+
+// Rule matches modmail and says how to respond to it.
+type Rule struct {
+	// Name identifies the rule in the audit log.
+	Name string
+	// Subreddits, if set, are the only subreddits whose modmail the rule
+	// matches.
+	Subreddits []string
+	// Subject, Body, and Author, if set, are regular expressions the
+	// message's subject, body, and author's name must match.
+	Subject string
+	Body    string
+	Author  string
+	// Banned and Muted require the author to be banned from the subreddit,
+	// or muted from its modmail, or not. Checking them costs a request.
+	Banned Condition
+	Muted  Condition
+
+	// Reply, if set, is the template of the reply to the message, in the
+	// syntax of the compose package. It is executed with a Match.
+	Reply string
+	// Archive archives the message, and Assign, if set, assigns it to the
+	// named moderator. They need the Config's Organizer.
+	Archive bool
+	Assign  string
+}
+
+// Match is a message a rule matched, which its reply template is executed
+// with.
+type Match struct {
+	Message *reddit.Message
+	Rule    string
+	// Banned and Muted are set when the rule checked them and they hold.
+	Banned bool
+	Muted  bool
+}
+
+// Organizer files modmail. The inbox messages Reddit delivers modmail as
+// can't be archived or assigned; Organizers do so in whatever tracks the
+// conversations, such as Reddit's modmail or a ticket system.
+type Organizer interface {
+	Archive(m *reddit.Message) error
+	Assign(m *reddit.Message, moderator string) error
+}
+
+// Config configures a Responder.
+type Config struct {
+	// Rules are tried in order; only the first which matches a message
+	// is applied.
+	Rules []Rule
+	// DryRun, when true, logs what the rules would do without replying,
+	// archiving, or assigning.
+	DryRun bool
+	// Organizer archives and assigns modmail for rules which do.
+	Organizer Organizer
+	// Audit, if set, receives a JSON line per message considered; see
+	// Decision.
+	Audit io.Writer
+	// Compose configures the links of the reply templates.
+	Compose compose.Config
+	// Clock times the audit log. If nil, the wall clock is used.
+	Clock clock.Clock
+}
+
+// Decision records what a Responder did about a message.
+type Decision struct {
+	Time      time.Time `json:"time"`
+	Message   string    `json:"message"`
+	Subreddit string    `json:"subreddit"`
+	Author    string    `json:"author"`
+	// Rule is the name of the rule which matched, if one did.
+	Rule     string `json:"rule,omitempty"`
+	Reply    string `json:"reply,omitempty"`
+	Archived bool   `json:"archived,omitempty"`
+	Assigned string `json:"assigned,omitempty"`
+	// DryRun is set when nothing was done.
+	DryRun bool `json:"dry_run,omitempty"`
+	// Error is the error which stopped the response, if one did.
+	Error string `json:"error,omitempty"`
+}
+
+// Responder responds to modmail by its rules.
+type Responder interface {
+	// Respond applies the first rule matching the message, if it is
+	// modmail, and returns what was done about it. Messages which are not
+	// modmail are ignored, and nil is returned for them.
+	Respond(bot reddit.Bot, m *reddit.Message) (*Decision, error)
+}
+
+type rule struct {
+	Rule
+	subject, body, author *regexp.Regexp
+	subreddits            map[string]bool
+}
+
+type responder struct {
+	cfg      Config
+	rules    []*rule
+	composer compose.Composer
+	// mu keeps the lines of the audit log whole.
+	mu sync.Mutex
+}
+
+// New returns a Responder applying the config's rules. It fails if a rule is
+// malformed.
+func New(c Config) (Responder, error) {
+	if c.Clock == nil {
+		c.Clock = clock.Real()
+	}
+
+	r := &responder{cfg: c, composer: compose.New(c.Compose)}
+	for _, cfg := range c.Rules {
+		rl, err := r.compile(cfg)
+		if err != nil {
+			return nil, err
+		}
+		r.rules = append(r.rules, rl)
+	}
+
+	return r, nil
+}
+
+func (r *responder) compile(c Rule) (*rule, error) {
+	if c.Name == "" {
+		return nil, noNameErr
+	}
+
+	if (c.Archive || c.Assign != "") && r.cfg.Organizer == nil {
+		return nil, noOrganizerErr
+	}
+
+	rl := &rule{Rule: c, subreddits: make(map[string]bool)}
+	for _, field := range []struct {
+		pattern string
+		re      **regexp.Regexp
+	}{
+		{c.Subject, &rl.subject},
+		{c.Body, &rl.body},
+		{c.Author, &rl.author},
+	} {
+		if field.pattern == "" {
+			continue
+		}
+
+		re, err := regexp.Compile(field.pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %v", c.Name, err)
+		}
+		*field.re = re
+	}
+
+	for _, sub := range c.Subreddits {
+		rl.subreddits[strings.ToLower(sub)] = true
+	}
+
+	if c.Reply != "" {
+		if err := r.composer.Add(c.Name, c.Reply); err != nil {
+			return nil, fmt.Errorf("rule %s: %v", c.Name, err)
+		}
+	}
+
+	return rl, nil
+}
+
+func (r *responder) Respond(bot reddit.Bot, m *reddit.Message) (*Decision, error) {
+	if m.Subreddit == "" {
+		return nil, nil
+	}
+
+	d := &Decision{
+		Time:      r.cfg.Clock.Now(),
+		Message:   m.Name,
+		Subreddit: m.Subreddit,
+		Author:    m.Author,
+		DryRun:    r.cfg.DryRun,
+	}
+
+	err := r.respond(bot, m, d)
+	if err != nil {
+		d.Error = err.Error()
+	}
+
+	if auditErr := r.audit(d); err == nil {
+		err = auditErr
+	}
+	return d, err
+}
+
+func (r *responder) respond(bot reddit.Bot, m *reddit.Message, d *Decision) error {
+	for _, rl := range r.rules {
+		match, err := rl.match(bot, m)
+		if err != nil {
+			return err
+		}
+
+		if match == nil {
+			continue
+		}
+
+		d.Rule = rl.Name
+		return r.apply(bot, rl, match, d)
+	}
+
+	return nil
+}
+
+// apply applies a rule to the message it matched, recording what it does.
+func (r *responder) apply(bot reddit.Bot, rl *rule, match *Match, d *Decision) error {
+	if rl.Reply != "" {
+		reply, err := r.composer.Compose(rl.Name, match)
+		if err != nil {
+			return err
+		}
+
+		d.Reply = reply
+		if !r.cfg.DryRun {
+			if err := bot.Reply(match.Message.Name, reply); err != nil {
+				return err
+			}
+		}
+	}
+
+	if rl.Assign != "" {
+		if !r.cfg.DryRun {
+			if err := r.cfg.Organizer.Assign(match.Message, rl.Assign); err != nil {
+				return err
+			}
+		}
+		d.Assigned = rl.Assign
+	}
+
+	if rl.Archive {
+		if !r.cfg.DryRun {
+			if err := r.cfg.Organizer.Archive(match.Message); err != nil {
+				return err
+			}
+		}
+		d.Archived = true
+	}
+
+	return nil
+}
+
+// match returns the match of the message if the rule matches it, or nil.
+func (rl *rule) match(bot reddit.Bot, m *reddit.Message) (*Match, error) {
+	if len(rl.subreddits) > 0 && !rl.subreddits[strings.ToLower(m.Subreddit)] {
+		return nil, nil
+	}
+
+	for _, field := range []struct {
+		re   *regexp.Regexp
+		text string
+	}{
+		{rl.subject, m.Subject},
+		{rl.body, m.Body},
+		{rl.author, m.Author},
+	} {
+		if field.re != nil && !field.re.MatchString(field.text) {
+			return nil, nil
+		}
+	}
+
+	match := &Match{Message: m, Rule: rl.Name}
+	for _, state := range []struct {
+		cond  Condition
+		check func(subreddit, user string) (bool, error)
+		has   *bool
+	}{
+		{rl.Banned, bot.Banned, &match.Banned},
+		{rl.Muted, bot.Muted, &match.Muted},
+	} {
+		if state.cond == Any {
+			continue
+		}
+
+		has, err := state.check(m.Subreddit, m.Author)
+		if err != nil {
+			return nil, err
+		}
+
+		if !state.cond.matches(has) {
+			return nil, nil
+		}
+		*state.has = has
+	}
+
+	return match, nil
+}
+
+func (r *responder) audit(d *Decision) error {
+	if r.cfg.Audit == nil {
+		return nil
+	}
+
+	line, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err = r.cfg.Audit.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,207 @@
+package responder
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+)
+
+type mockBot struct {
+	reddit.Bot
+	banned  map[string]bool
+	checks  int
+	replies []string
+}
+
+func (m *mockBot) Banned(subreddit, user string) (bool, error) {
+	m.checks++
+	return m.banned[user], nil
+}
+
+func (m *mockBot) Muted(subreddit, user string) (bool, error) {
+	m.checks++
+	return false, nil
+}
+
+func (m *mockBot) Reply(parent, text string) error {
+	m.replies = append(m.replies, parent+": "+text)
+	return nil
+}
+
+type mockOrganizer struct {
+	archived []string
+	assigned []string
+}
+
+func (m *mockOrganizer) Archive(msg *reddit.Message) error {
+	m.archived = append(m.archived, msg.Name)
+	return nil
+}
+
+func (m *mockOrganizer) Assign(msg *reddit.Message, moderator string) error {
+	m.assigned = append(m.assigned, msg.Name+" "+moderator)
+	return nil
+}
+
+var rules = []Rule{
+	{
+		Name:    "lifted",
+		Subject: `(?i)appeal`,
+		Banned:  IsNot,
+		Reply:   `You are not banned from /r/{{.Message.Subreddit}}.`,
+		Archive: true,
+	},
+	{
+		Name:    "appeal",
+		Subject: `(?i)appeal`,
+		Assign:  "spez",
+	},
+}
+
+func TestRespond(t *testing.T) {
+	start := time.Unix(1700000000, 0).UTC()
+	for i, test := range []struct {
+		dryRun   bool
+		m        reddit.Message
+		decision *Decision
+		replies  []string
+		archived []string
+		assigned []string
+		checks   int
+	}{
+		{
+			m: reddit.Message{Name: "t4_a", Author: "free", Subreddit: "golang", Subject: "Ban appeal"},
+			decision: &Decision{
+				Time:      start,
+				Message:   "t4_a",
+				Subreddit: "golang",
+				Author:    "free",
+				Rule:      "lifted",
+				Reply:     "You are not banned from /r/golang.",
+				Archived:  true,
+			},
+			replies:  []string{"t4_a: You are not banned from /r/golang."},
+			archived: []string{"t4_a"},
+			checks:   1,
+		},
+		{
+			m: reddit.Message{Name: "t4_b", Author: "troll", Subreddit: "golang", Subject: "appeal"},
+			decision: &Decision{
+				Time:      start,
+				Message:   "t4_b",
+				Subreddit: "golang",
+				Author:    "troll",
+				Rule:      "appeal",
+				Assigned:  "spez",
+			},
+			assigned: []string{"t4_b spez"},
+			checks:   1,
+		},
+		{
+			dryRun: true,
+			m:      reddit.Message{Name: "t4_c", Author: "free", Subreddit: "golang", Subject: "appeal"},
+			decision: &Decision{
+				Time:      start,
+				Message:   "t4_c",
+				Subreddit: "golang",
+				Author:    "free",
+				Rule:      "lifted",
+				Reply:     "You are not banned from /r/golang.",
+				Archived:  true,
+				DryRun:    true,
+			},
+			checks: 1,
+		},
+		{
+			m: reddit.Message{Name: "t4_d", Author: "free", Subreddit: "golang", Subject: "hello"},
+			decision: &Decision{
+				Time:      start,
+				Message:   "t4_d",
+				Subreddit: "golang",
+				Author:    "free",
+			},
+		},
+		{
+			m: reddit.Message{Name: "t4_e", Author: "free", Subject: "appeal"},
+		},
+	} {
+		bot := &mockBot{banned: map[string]bool{"troll": true}}
+		organizer := &mockOrganizer{}
+		audit := &bytes.Buffer{}
+		r, err := New(Config{
+			Rules:     rules,
+			DryRun:    test.dryRun,
+			Organizer: organizer,
+			Audit:     audit,
+			Clock:     clock.NewSimulation(start),
+		})
+		if err != nil {
+			t.Fatalf("%d: failed to build responder: %v", i, err)
+		}
+
+		d, err := r.Respond(bot, &test.m)
+		if err != nil {
+			t.Errorf("%d: failed to respond: %v", i, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(d, test.decision) {
+			t.Errorf("%d: got decision %+v; wanted %+v", i, d, test.decision)
+		}
+
+		if !reflect.DeepEqual(bot.replies, test.replies) {
+			t.Errorf("%d: got replies %v; wanted %v", i, bot.replies, test.replies)
+		}
+
+		if !reflect.DeepEqual(organizer.archived, test.archived) {
+			t.Errorf("%d: archived %v; wanted %v", i, organizer.archived, test.archived)
+		}
+
+		if !reflect.DeepEqual(organizer.assigned, test.assigned) {
+			t.Errorf("%d: assigned %v; wanted %v", i, organizer.assigned, test.assigned)
+		}
+
+		if bot.checks != test.checks {
+			t.Errorf("%d: checked author state %d times; wanted %d", i, bot.checks, test.checks)
+		}
+
+		if test.decision == nil {
+			if audit.Len() != 0 {
+				t.Errorf("%d: audited a message which is not modmail: %s", i, audit)
+			}
+			continue
+		}
+
+		logged := &Decision{}
+		if err := json.Unmarshal(audit.Bytes(), logged); err != nil {
+			t.Errorf("%d: audit log is malformed: %v", i, err)
+		} else if !reflect.DeepEqual(logged, test.decision) {
+			t.Errorf("%d: audited %+v; wanted %+v", i, logged, test.decision)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	for i, test := range []struct {
+		c   Config
+		err error
+	}{
+		{Config{Rules: []Rule{{Subject: "x"}}}, noNameErr},
+		{Config{Rules: []Rule{{Name: "a", Archive: true}}}, noOrganizerErr},
+		{Config{Rules: []Rule{{Name: "a", Assign: "spez"}}}, noOrganizerErr},
+		{Config{Rules: []Rule{{Name: "a", Reply: "hi"}}}, nil},
+	} {
+		if _, err := New(test.c); err != test.err {
+			t.Errorf("%d: got error %v; wanted %v", i, err, test.err)
+		}
+	}
+
+	if _, err := New(Config{Rules: []Rule{{Name: "a", Body: "("}}}); err == nil {
+		t.Errorf("accepted a malformed pattern")
+	}
+}
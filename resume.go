@@ -0,0 +1,31 @@
+package graw
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aldarisbm/graw/streams"
+)
+
+// start returns the point in their listings streams should start from, given
+// the Config's ResumeFrom.
+func start(resumeFrom string) (streams.Start, error) {
+	if resumeFrom == "" {
+		return streams.Start{}, nil
+	}
+
+	if strings.HasPrefix(resumeFrom, "t") && strings.Contains(resumeFrom, "_") {
+		return streams.Start{From: resumeFrom}, nil
+	}
+
+	if unix, err := strconv.ParseInt(resumeFrom, 10, 64); err == nil {
+		return streams.Start{Since: time.Unix(unix, 0)}, nil
+	}
+
+	return streams.Start{}, fmt.Errorf(
+		"ResumeFrom must be a fullname or unix timestamp; got %q",
+		resumeFrom,
+	)
+}
@@ -0,0 +1,30 @@
+package graw
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/streams"
+)
+
+func TestStart(t *testing.T) {
+	for _, test := range []struct {
+		resumeFrom string
+		start      streams.Start
+		err        bool
+	}{
+		{"", streams.Start{}, false},
+		{"t3_abc123", streams.Start{From: "t3_abc123"}, false},
+		{"1500000000", streams.Start{Since: time.Unix(1500000000, 0)}, false},
+		{"yesterday", streams.Start{}, true},
+	} {
+		s, err := start(test.resumeFrom)
+		if (err != nil) != test.err {
+			t.Errorf("%q: unexpected error: %v", test.resumeFrom, err)
+		}
+
+		if s != test.start {
+			t.Errorf("%q: got %+v; wanted %+v", test.resumeFrom, s, test.start)
+		}
+	}
+}
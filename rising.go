@@ -0,0 +1,59 @@
+package graw
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aldarisbm/graw/botfaces"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/streams"
+)
+
+// defaultRisingInterval is how often rising listings are read if the Config
+// gives no interval.
+const defaultRisingInterval = time.Minute
+
+var risingHandlerErr = fmt.Errorf(
+	"You must implement RisingHandler to take rising feeds.",
+)
+
+// connectRising connects the rising listings of the config's subreddits to
+// the handler, if it names any. It returns the set the new post streams
+// should add the posts they dispatch to, which is nil if there are no rising
+// listings.
+func connectRising(
+	handler interface{},
+	sc reddit.Scanner,
+	c Config,
+	kill <-chan bool,
+	errs chan<- error,
+) (*recent, error) {
+	if len(c.Rising) == 0 {
+		return nil, nil
+	}
+
+	rh, ok := handler.(botfaces.RisingHandler)
+	if !ok {
+		return nil, risingHandlerErr
+	}
+
+	cfg := streams.RisingConfig{Interval: c.RisingInterval}
+	if cfg.Interval == 0 {
+		cfg.Interval = defaultRisingInterval
+	}
+
+	rising, err := streams.Rising(sc, kill, errs, cfg, c.Rising...)
+	if err != nil {
+		return nil, err
+	}
+
+	handled := newRecent()
+	go func() {
+		for r := range rising {
+			r.Handled = handled.has(r.Post.Name)
+			errs <- rh.Rising(r)
+		}
+	}()
+
+	return handled, nil
+}
@@ -1,11 +1,16 @@
 package graw
 
 import (
+	"context"
 	"fmt"
+	"sync"
 
-	"github.com/turnage/graw/botfaces"
-	"github.com/turnage/graw/reddit"
-	"github.com/turnage/graw/streams"
+	"github.com/aldarisbm/graw/archive"
+	"github.com/aldarisbm/graw/botfaces"
+	"github.com/aldarisbm/graw/digest"
+	"github.com/aldarisbm/graw/logging"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/streams"
 )
 
 var (
@@ -21,12 +26,17 @@ var (
 	messageHandlerErr = fmt.Errorf(
 		"You must implement MessageHandler to take message feeds.",
 	)
+	accountSnapshotHandlerErr = fmt.Errorf(
+		"You must implement AccountSnapshotHandler to take account " +
+			"snapshots.",
+	)
 )
 
 // Run connects a handler to any requested event sources and makes requests with
 // the given bot api handle. It launches a goroutine for the run. It returns two
 // functions, a stop() function to terminate the graw run at any time, and a
-// wait() function to block until the graw run fails.
+// wait() function to block until the graw run fails. Requests the run's streams
+// have in flight, or waiting under the rate limit, are cancelled when it stops.
 func Run(handler interface{}, bot reddit.Bot, cfg Config) (
 	func(),
 	func() error,
@@ -34,18 +44,61 @@ func Run(handler interface{}, bot reddit.Bot, cfg Config) (
 ) {
 	kill := make(chan bool)
 	errs := make(chan error)
+	ctx, cancel := context.WithCancel(context.Background())
 
 	if err := connectAllStreams(
 		handler,
-		bot,
+		bot.WithContext(ctx),
 		cfg,
 		kill,
 		errs,
 	); err != nil {
+		cancel()
 		return nil, nil, err
 	}
 
-	return launch(handler, kill, errs, logger(cfg.Logger))
+	stop, wait, err := launch(handler, kill, errs, logger(cfg.Logger), cancel)
+	if err != nil || !cfg.RevokeOnShutdown {
+		return stop, wait, err
+	}
+
+	// The token is revoked with the bot itself, since the streams' view of
+	// it is cancelled by then.
+	stop, wait = revokeOnStop(stop, wait, bot, logger(cfg.Logger))
+	return stop, wait, nil
+}
+
+// revokeOnStop returns stop and wait functions which revoke the bot's access
+// token once the run has stopped, whether it was stopped or failed on its
+// own. The token is revoked only once.
+func revokeOnStop(
+	stop func(),
+	wait func() error,
+	bot reddit.Bot,
+	logger logging.Logger,
+) (func(), func() error) {
+	var once sync.Once
+	revoke := func() {
+		once.Do(func() {
+			if err := bot.RevokeToken(); err != nil {
+				logger.Log(
+					logging.Warn, "Failed to revoke access token.",
+					logging.F("err", err),
+				)
+			}
+		})
+	}
+
+	revokingStop := func() {
+		stop()
+		revoke()
+	}
+	revokingWait := func() error {
+		err := wait()
+		revoke()
+		return err
+	}
+	return revokingStop, revokingWait
 }
 
 func connectAllStreams(
@@ -55,9 +108,17 @@ func connectAllStreams(
 	kill <-chan bool,
 	errs chan<- error,
 ) error {
+	o, err := ownContent(bot, c)
+	if err != nil {
+		return err
+	}
+
+	d := newDispatcher(c, kill, errs)
 	if err := connectScanStreams(
 		handler,
 		bot,
+		o,
+		d,
 		c,
 		kill,
 		errs,
@@ -65,21 +126,38 @@ func connectAllStreams(
 		return err
 	}
 
+	from, err := start(c.ResumeFrom)
+	if err != nil {
+		return err
+	}
+	from.Limit = c.ListingLimit
+	sink := c.Archive
+
 	// lol no generics:
 
 	if c.PostReplies {
 		if prh, ok := handler.(botfaces.PostReplyHandler); !ok {
 			return postReplyHandlerErr
-		} else if prs, err := streams.PostReplies(
+		} else if prs, err := streams.PostRepliesFrom(
 			bot,
 			kill,
 			errs,
+			from,
 		); err != nil {
 			return err
 		} else {
 			go func() {
 				for pr := range prs {
-					errs <- prh.PostReply(pr)
+					if o.message(pr) {
+						continue
+					}
+					if err := archived(sink, archive.Event{
+						Kind:    archive.PostReplyKind,
+						Message: pr,
+					}); err != nil && !d.send(err) {
+						return
+					}
+					d.message(pr, prh.PostReply)
 				}
 			}()
 		}
@@ -88,16 +166,26 @@ func connectAllStreams(
 	if c.CommentReplies {
 		if crh, ok := handler.(botfaces.CommentReplyHandler); !ok {
 			return commentReplyHandlerErr
-		} else if crs, err := streams.CommentReplies(
+		} else if crs, err := streams.CommentRepliesFrom(
 			bot,
 			kill,
 			errs,
+			from,
 		); err != nil {
 			return err
 		} else {
 			go func() {
 				for cr := range crs {
-					errs <- crh.CommentReply(cr)
+					if o.message(cr) {
+						continue
+					}
+					if err := archived(sink, archive.Event{
+						Kind:    archive.CommentReplyKind,
+						Message: cr,
+					}); err != nil && !d.send(err) {
+						return
+					}
+					d.message(cr, crh.CommentReply)
 				}
 			}()
 		}
@@ -106,16 +194,26 @@ func connectAllStreams(
 	if c.Mentions {
 		if mh, ok := handler.(botfaces.MentionHandler); !ok {
 			return mentionHandlerErr
-		} else if ms, err := streams.Mentions(
+		} else if ms, err := streams.MentionsFrom(
 			bot,
 			kill,
 			errs,
+			from,
 		); err != nil {
 			return err
 		} else {
 			go func() {
 				for m := range ms {
-					errs <- mh.Mention(m)
+					if o.message(m) {
+						continue
+					}
+					if err := archived(sink, archive.Event{
+						Kind:    archive.MentionKind,
+						Message: m,
+					}); err != nil && !d.send(err) {
+						return
+					}
+					d.message(m, mh.Mention)
 				}
 			}()
 		}
@@ -124,16 +222,80 @@ func connectAllStreams(
 	if c.Messages {
 		if mh, ok := handler.(botfaces.MessageHandler); !ok {
 			return messageHandlerErr
-		} else if ms, err := streams.Messages(
+		} else if ms, err := streams.MessagesFrom(
 			bot,
 			kill,
 			errs,
+			from,
 		); err != nil {
 			return err
 		} else {
 			go func() {
 				for m := range ms {
-					errs <- mh.Message(m)
+					if o.message(m) {
+						continue
+					}
+					if err := archived(sink, archive.Event{
+						Kind:    archive.MessageKind,
+						Message: m,
+					}); err != nil && !d.send(err) {
+						return
+					}
+					d.message(m, mh.Message)
+				}
+			}()
+		}
+	}
+
+	if err := connectSummarizer(bot, c, from, kill, errs); err != nil {
+		return err
+	}
+
+	if err := connectResponder(bot, c, from, kill, errs); err != nil {
+		return err
+	}
+
+	for _, roundup := range c.Roundups {
+		if err := digest.Schedule(bot, kill, errs, roundup); err != nil {
+			return err
+		}
+	}
+
+	if err := connectReports(handler, bot, c, kill, errs); err != nil {
+		return err
+	}
+
+	if err := connectQueues(handler, bot, c, kill, errs); err != nil {
+		return err
+	}
+
+	if err := connectModmail(handler, bot, c, kill, errs); err != nil {
+		return err
+	}
+
+	if err := connectLiveThreads(handler, bot, c, kill, errs); err != nil {
+		return err
+	}
+
+	if c.AccountSnapshots > 0 {
+		ash, ok := handler.(botfaces.AccountSnapshotHandler)
+		if !ok {
+			return accountSnapshotHandlerErr
+		}
+
+		if snapshots, err := streams.AccountSnapshots(
+			bot,
+			kill,
+			errs,
+			c.AccountSnapshots,
+		); err != nil {
+			return err
+		} else {
+			go func() {
+				for s := range snapshots {
+					if !d.send(ash.AccountSnapshot(s)) {
+						return
+					}
 				}
 			}()
 		}
@@ -0,0 +1,37 @@
+package graw
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+type revokeBot struct {
+	reddit.Bot
+	revoked int
+}
+
+func (r *revokeBot) RevokeToken() error {
+	r.revoked++
+	return nil
+}
+
+func TestRevokeOnStop(t *testing.T) {
+	failed := fmt.Errorf("stream failed")
+	bot := &revokeBot{}
+	stop, wait := revokeOnStop(func() {}, func() error { return failed }, bot, logger(nil))
+
+	// A run which fails on its own revokes its token once it is waited on,
+	// and not again when it is stopped afterwards.
+	if err := wait(); err != failed {
+		t.Errorf("got %v from wait; wanted the run's error", err)
+	}
+	if bot.revoked != 1 {
+		t.Errorf("revoked %d times after wait; wanted once", bot.revoked)
+	}
+	stop()
+	if bot.revoked != 1 {
+		t.Errorf("revoked %d times after stop; wanted once", bot.revoked)
+	}
+}
@@ -1,11 +1,13 @@
 package graw
 
 import (
+	"context"
 	"fmt"
 
-	"github.com/turnage/graw/botfaces"
-	"github.com/turnage/graw/reddit"
-	"github.com/turnage/graw/streams"
+	"github.com/aldarisbm/graw/archive"
+	"github.com/aldarisbm/graw/botfaces"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/streams"
 )
 
 var (
@@ -36,49 +38,140 @@ func Scan(handler interface{}, script reddit.Script, cfg Config) (
 	kill := make(chan bool)
 	errs := make(chan error)
 
-	if cfg.PostReplies || cfg.CommentReplies || cfg.Mentions || cfg.Messages {
+	if cfg.PostReplies || cfg.CommentReplies || cfg.Mentions || cfg.Messages ||
+		cfg.AccountSnapshots > 0 || cfg.Summarizer != nil ||
+		len(cfg.Reports) > 0 || len(cfg.Modqueue) > 0 || len(cfg.Spam) > 0 ||
+		len(cfg.Edited) > 0 || len(cfg.Modmail) > 0 || len(cfg.Roundups) > 0 ||
+		len(cfg.LiveThreads) > 0 || cfg.Responder != nil || cfg.SkipOwnContent {
 		return nil, nil, loggedOutErr
 	}
 
+	// The scan's requests are cancelled when it stops, rather than run to
+	// completion.
+	ctx, cancel := context.WithCancel(context.Background())
+	script = script.WithContext(ctx)
+
 	if err := connectScanStreams(
 		handler,
 		script,
+		own{},
+		newDispatcher(cfg, kill, errs),
 		cfg,
 		kill,
 		errs,
 	); err != nil {
+		cancel()
 		return nil, nil, err
 	}
 
-	return launch(handler, kill, errs, logger(cfg.Logger))
+	return launch(handler, kill, errs, logger(cfg.Logger), cancel)
 }
 
 // connectScanStreams connects the streams a scanner can subscribe to to the
-// handler.
+// handler, tagging the content that o recognizes as the bot's own.
 func connectScanStreams(
 	handler interface{},
 	sc reddit.Scanner,
+	o own,
+	d *dispatcher,
 	c Config,
 	kill <-chan bool,
 	errs chan<- error,
 ) error {
+	from, err := start(c.ResumeFrom)
+	if err != nil {
+		return err
+	}
+	from.Limit = c.ListingLimit
+	sink := c.Archive
+
+	track, err := connectThresholds(handler, sc, c, kill, errs)
+	if err != nil {
+		return err
+	}
+
+	texts, err := connectTrends(handler, c, kill, errs)
+	if err != nil {
+		return err
+	}
+
+	posts, err := connectPostVolume(handler, c, kill, errs)
+	if err != nil {
+		return err
+	}
+
+	watched, err := connectWatchlist(handler, c, kill, errs)
+	if err != nil {
+		return err
+	}
+
+	links, err := connectReputation(handler, c, kill, errs)
+	if err != nil {
+		return err
+	}
+
+	handled, err := connectRising(handler, sc, c, kill, errs)
+	if err != nil {
+		return err
+	}
+
+	if err := connectGilded(handler, sc, c, kill, errs); err != nil {
+		return err
+	}
+
+	if err := connectSearches(handler, sc, c, kill, errs); err != nil {
+		return err
+	}
+
+	connectScheduler(handler, c, kill, errs)
+	connectStats(c, kill, errs)
+
+	passesPost := streams.MatchPosts(c.PostFilter)
+	tap := taps{
+		track:     track,
+		texts:     texts,
+		posts:     posts,
+		watched:   watched,
+		watchlist: c.Watchlist,
+		seen:      newRecent(),
+		links:     links,
+		ledger:    c.Reputation,
+		stats:     c.Stats,
+		authors:   c.Authors,
+		overlap:   c.Overlap,
+		handled:   handled,
+		kill:      kill,
+	}
+
 	if len(c.Subreddits) > 0 {
 		ph, ok := handler.(botfaces.PostHandler)
 		if !ok {
 			return postHandlerErr
 		}
 
-		if posts, err := streams.Subreddits(
+		if posts, err := streams.SubredditsFrom(
 			sc,
 			kill,
 			errs,
+			from,
 			c.Subreddits...,
 		); err != nil {
 			return err
 		} else {
 			go func() {
 				for p := range posts {
-					errs <- ph.Post(p)
+					tap.post(p)
+					if !passesPost(p) || o.post(p) {
+						continue
+					}
+					if err := archived(sink, archive.Event{
+						Kind: archive.PostKind,
+						Post: p,
+					}); err != nil && !d.send(err) {
+						return
+					}
+					tap.handle(p)
+					d.post(p, ph.Post)
 				}
 			}()
 		}
@@ -91,10 +184,11 @@ func connectScanStreams(
 		}
 
 		for user, feeds := range c.CustomFeeds {
-			if posts, err := streams.CustomFeeds(
+			if posts, err := streams.CustomFeedsFrom(
 				sc,
 				kill,
 				errs,
+				from,
 				user,
 				feeds...,
 			); err != nil {
@@ -102,7 +196,18 @@ func connectScanStreams(
 			} else {
 				go func() {
 					for p := range posts {
-						errs <- ph.Post(p)
+						tap.post(p)
+						if !passesPost(p) || o.post(p) {
+							continue
+						}
+						if err := archived(sink, archive.Event{
+							Kind: archive.PostKind,
+							Post: p,
+						}); err != nil && !d.send(err) {
+							return
+						}
+						tap.handle(p)
+						d.post(p, ph.Post)
 					}
 				}()
 			}
@@ -115,17 +220,29 @@ func connectScanStreams(
 			return commentHandlerErr
 		}
 
-		if comments, err := streams.SubredditComments(
+		if comments, err := streams.SubredditCommentsFrom(
 			sc,
 			kill,
 			errs,
+			from,
 			c.SubredditComments...,
 		); err != nil {
 			return err
 		} else {
+			passes := streams.MatchComments(c.CommentFilter)
 			go func() {
 				for c := range comments {
-					errs <- ch.Comment(c)
+					tap.comment(c)
+					if !passes(c) || o.comment(c) {
+						continue
+					}
+					if err := archived(sink, archive.Event{
+						Kind:    archive.CommentKind,
+						Comment: c,
+					}); err != nil && !d.send(err) {
+						return
+					}
+					d.comment(c, ch.Comment)
 				}
 			}()
 		}
@@ -138,27 +255,48 @@ func connectScanStreams(
 		}
 
 		for _, user := range c.Users {
-			if posts, comments, err := streams.User(
+			if posts, comments, err := streams.UserFrom(
 				sc,
 				kill,
 				errs,
+				from,
 				user,
 			); err != nil {
 				return err
 			} else {
 				go func() {
 					for p := range posts {
-						errs <- uh.UserPost(p)
+						tap.watchPost(p)
+						if o.post(p) {
+							continue
+						}
+						if err := archived(sink, archive.Event{
+							Kind: archive.UserPostKind,
+							Post: p,
+						}); err != nil && !d.send(err) {
+							return
+						}
+						d.post(p, uh.UserPost)
 					}
 				}()
 				go func() {
 					for c := range comments {
-						errs <- uh.UserComment(c)
+						tap.watchComment(c)
+						if o.comment(c) {
+							continue
+						}
+						if err := archived(sink, archive.Event{
+							Kind:    archive.UserCommentKind,
+							Comment: c,
+						}); err != nil && !d.send(err) {
+							return
+						}
+						d.comment(c, uh.UserComment)
 					}
 				}()
 			}
 		}
 	}
 
-	return nil
+	return watchAnywhere(tap, sc, c, from, kill, errs)
 }
@@ -0,0 +1,23 @@
+package graw
+
+import (
+	"testing"
+
+	"github.com/aldarisbm/graw/responder"
+)
+
+func TestScanLoggedOut(t *testing.T) {
+	r, err := responder.New(responder.Config{})
+	if err != nil {
+		t.Fatalf("failed to make responder: %v", err)
+	}
+
+	for name, cfg := range map[string]Config{
+		"messages":  {Messages: true},
+		"responder": {Responder: r},
+	} {
+		if _, _, err := Scan(nil, nil, cfg); err != loggedOutErr {
+			t.Errorf("%s: got %v; wanted loggedOutErr", name, err)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// Package schedule defers graw events to be dispatched again later, which is
+// the core of reminder bots: a handler defers the mention asking for a
+// reminder, and it comes back to the handler when the reminder is due.
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/archive"
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/store"
+)
+
+// stateKey is the key deferred events are stored under.
+const stateKey = "deferred-events"
+
+var emptyEventErr = fmt.Errorf("deferred event has no post, comment, or message")
+
+// Deferred is an event deferred to a later time.
+type Deferred struct {
+	Event archive.Event `json:"event"`
+	// At is when the event is due.
+	At time.Time `json:"at"`
+}
+
+// Config configures a Scheduler.
+type Config struct {
+	// If set, deferred events are saved here, and loaded again when a
+	// scheduler is made with the same store, so they survive restarts.
+	Store store.Store
+	// Clock times when deferred events are due. If nil, the wall clock is
+	// used.
+	Clock clock.Clock
+}
+
+// Scheduler holds deferred events until they are due.
+type Scheduler interface {
+	// Defer schedules the event to be dispatched again at the given time.
+	// Events due in the past are dispatched as soon as possible.
+	Defer(e archive.Event, at time.Time) error
+	// Pending returns the events which have not yet been received from Due,
+	// soonest first, including any which are already due.
+	Pending() []Deferred
+	// Due returns a stream of deferred events as they come due, until
+	// kill is closed. An event is only forgotten once it was received
+	// from the stream. A scheduler should be streamed from once at a time.
+	Due(kill <-chan bool) <-chan Deferred
+}
+
+type scheduler struct {
+	store store.Store
+	clock clock.Clock
+	// wake is signalled when an event is deferred, so Due can recompute
+	// when the next one is due.
+	wake chan struct{}
+
+	mu      sync.Mutex
+	pending []Deferred
+}
+
+// New returns a Scheduler, holding the events saved in the config's store.
+func New(c Config) (Scheduler, error) {
+	s := &scheduler{
+		store: c.Store,
+		clock: c.Clock,
+		wake:  make(chan struct{}, 1),
+	}
+	if s.clock == nil {
+		s.clock = clock.Real()
+	}
+
+	if s.store != nil {
+		err := s.store.Load(stateKey, &s.pending)
+		if err != nil && err != store.NotFoundErr {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func (s *scheduler) Defer(e archive.Event, at time.Time) error {
+	if e.Post == nil && e.Comment == nil && e.Message == nil {
+		return emptyEventErr
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := sort.Search(len(s.pending), func(i int) bool {
+		return s.pending[i].At.After(at)
+	})
+	s.pending = append(s.pending, Deferred{})
+	copy(s.pending[i+1:], s.pending[i:])
+	s.pending[i] = Deferred{Event: e, At: at}
+
+	if err := s.save(); err != nil {
+		s.pending = append(s.pending[:i], s.pending[i+1:]...)
+		return err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (s *scheduler) Pending() []Deferred {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Deferred(nil), s.pending...)
+}
+
+func (s *scheduler) Due(kill <-chan bool) <-chan Deferred {
+	due := make(chan Deferred)
+	go func() {
+		defer close(due)
+
+		// One timer is reset on every pass, rather than a new one made,
+		// so those of events deferred earlier are not left waiting.
+		var timer clock.Timer
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+
+		for {
+			next, ok := s.next()
+			var fire <-chan time.Time
+			if ok {
+				wait := next.At.Sub(s.clock.Now())
+				if wait <= 0 {
+					select {
+					case due <- next:
+						s.remove(next)
+					case <-kill:
+						return
+					}
+					continue
+				}
+
+				if timer == nil {
+					timer = s.clock.NewTimer(wait)
+				} else {
+					timer.Reset(wait)
+				}
+				fire = timer.C()
+			} else if timer != nil {
+				timer.Stop()
+			}
+
+			select {
+			case <-kill:
+				return
+			case <-s.wake:
+			case <-fire:
+			}
+		}
+	}()
+	return due
+}
+
+// next returns the soonest pending event.
+func (s *scheduler) next() (Deferred, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.pending) == 0 {
+		return Deferred{}, false
+	}
+	return s.pending[0], true
+}
+
+// remove forgets a dispatched event. Save failures are not reported; at
+// worst the event is dispatched again after a restart.
+func (s *scheduler) remove(d Deferred) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, p := range s.pending {
+		if p.At.Equal(d.At) && p.Event.Kind == d.Event.Kind &&
+			name(p.Event) == name(d.Event) {
+			s.pending = append(s.pending[:i], s.pending[i+1:]...)
+			s.save()
+			return
+		}
+	}
+}
+
+// name returns the fullname of the element of an event.
+func name(e archive.Event) string {
+	switch {
+	case e.Post != nil:
+		return e.Post.Name
+	case e.Comment != nil:
+		return e.Comment.Name
+	case e.Message != nil:
+		return e.Message.Name
+	}
+	return ""
+}
+
+func (s *scheduler) save() error {
+	if s.store == nil {
+		return nil
+	}
+	return s.store.Save(stateKey, s.pending)
+}
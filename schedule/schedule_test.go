@@ -0,0 +1,130 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/archive"
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/store"
+)
+
+func mention(name string) archive.Event {
+	return archive.Event{
+		Kind:    archive.MentionKind,
+		Message: &reddit.Message{Name: name},
+	}
+}
+
+func TestDue(t *testing.T) {
+	start := time.Unix(1000, 0)
+	sim := clock.NewSimulation(start)
+	s, err := New(Config{Clock: sim})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kill := make(chan bool)
+	defer close(kill)
+	due := s.Due(kill)
+
+	for i, name := range []string{"t4_late", "t4_early"} {
+		at := start.Add(time.Duration(2-i) * time.Hour)
+		if err := s.Defer(mention(name), at); err != nil {
+			t.Fatalf("failed to defer: %v", err)
+		}
+	}
+
+	if p := s.Pending(); len(p) != 2 || p[0].Event.Message.Name != "t4_early" {
+		t.Fatalf("pending events out of order: %v", p)
+	}
+
+	select {
+	case d := <-due:
+		t.Fatalf("%s dispatched before it was due", d.Event.Message.Name)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	sim.AdvanceTo(start.Add(3 * time.Hour))
+	for _, expected := range []string{"t4_early", "t4_late"} {
+		select {
+		case d := <-due:
+			if d.Event.Message.Name != expected {
+				t.Errorf("got %s; wanted %s", d.Event.Message.Name, expected)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s was not dispatched", expected)
+		}
+	}
+}
+
+func TestPersisted(t *testing.T) {
+	st := store.NewMemoryStore()
+	s, err := New(Config{Store: st})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := time.Now().Add(time.Hour).Round(time.Second)
+	if err := s.Defer(mention("t4_a"), at); err != nil {
+		t.Fatalf("failed to defer: %v", err)
+	}
+
+	restarted, err := New(Config{Store: st})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := restarted.Pending()
+	if len(p) != 1 || p[0].Event.Message.Name != "t4_a" || !p[0].At.Equal(at) {
+		t.Errorf("deferred event was not restored: %v", p)
+	}
+}
+
+func TestDeferEmpty(t *testing.T) {
+	s, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Defer(archive.Event{Kind: archive.PostKind}, time.Now()); err != emptyEventErr {
+		t.Errorf("got %v; wanted emptyEventErr", err)
+	}
+}
+
+// timerClock counts the timers made on a simulated clock.
+type timerClock struct {
+	clock.Simulation
+	timers chan bool
+}
+
+func (c *timerClock) NewTimer(d time.Duration) clock.Timer {
+	c.timers <- true
+	return c.Simulation.NewTimer(d)
+}
+
+func TestDueReusesTimer(t *testing.T) {
+	start := time.Unix(1000, 0)
+	c := &timerClock{clock.NewSimulation(start), make(chan bool, 10)}
+	s, err := New(Config{Clock: c})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kill := make(chan bool)
+	defer close(kill)
+	s.Due(kill)
+
+	// Each event wakes Due to recompute when the next is due, but it waits
+	// on the same timer every time.
+	for i := 0; i < 3; i++ {
+		if err := s.Defer(mention("t4_a"), start.Add(time.Hour)); err != nil {
+			t.Fatalf("failed to defer: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if made := len(c.timers); made != 1 {
+		t.Errorf("made %d timers; wanted 1", made)
+	}
+}
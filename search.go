@@ -0,0 +1,114 @@
+package graw
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aldarisbm/graw/botfaces"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/streams"
+)
+
+// defaultSearchInterval is how often saved searches are run if the Config
+// gives no interval.
+const defaultSearchInterval = 5 * time.Minute
+
+var (
+	searchResultHandlerErr = fmt.Errorf(
+		"You must implement SearchResultHandler to take saved search feeds.",
+	)
+	searchDigestHandlerErr = fmt.Errorf(
+		"You must implement SearchDigestHandler or set a DigestSink to " +
+			"take saved search digests.",
+	)
+)
+
+// connectSearches connects the config's saved searches to the handler, or to
+// its digests, if it has any.
+func connectSearches(
+	handler interface{},
+	sc reddit.Scanner,
+	c Config,
+	kill <-chan bool,
+	errs chan<- error,
+) error {
+	if c.Searches == nil {
+		return nil
+	}
+
+	if c.SearchDigest != nil {
+		return connectSearchDigests(handler, sc, c, kill, errs)
+	}
+
+	sh, ok := handler.(botfaces.SearchResultHandler)
+	if !ok {
+		return searchResultHandlerErr
+	}
+
+	results, err := searches(sc, c, kill, errs)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for r := range results {
+			errs <- sh.SearchResult(r)
+		}
+	}()
+
+	return nil
+}
+
+// connectSearchDigests delivers digests of the config's saved search results
+// to its digest sink, or to the handler.
+func connectSearchDigests(
+	handler interface{},
+	sc reddit.Scanner,
+	c Config,
+	kill <-chan bool,
+	errs chan<- error,
+) error {
+	deliver := func(d *streams.SearchDigest) error {
+		return c.DigestSink.Deliver(d)
+	}
+	if c.DigestSink == nil {
+		dh, ok := handler.(botfaces.SearchDigestHandler)
+		if !ok {
+			return searchDigestHandlerErr
+		}
+		deliver = dh.SearchDigest
+	}
+
+	results, err := searches(sc, c, kill, errs)
+	if err != nil {
+		return err
+	}
+
+	digests, err := streams.SearchDigests(kill, errs, results, *c.SearchDigest)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for d := range digests {
+			errs <- deliver(d)
+		}
+	}()
+
+	return nil
+}
+
+// searches opens the stream of the config's saved searches.
+func searches(
+	sc reddit.Scanner,
+	c Config,
+	kill <-chan bool,
+	errs chan<- error,
+) (<-chan *streams.SearchResult, error) {
+	cfg := *c.Searches
+	if cfg.Interval == 0 {
+		cfg.Interval = defaultSearchInterval
+	}
+
+	return streams.Searches(sc, kill, errs, cfg)
+}
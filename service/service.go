@@ -0,0 +1,119 @@
+// Package service runs graw bots as services of a service manager, such as a
+// systemd unit or a Windows service, stopping them gracefully when the manager
+// asks and keeping it informed of their state.
+//
+//	stop, wait, err := graw.Run(bot, handle, cfg)
+//	if err != nil {
+//	  ...
+//	}
+//	return service.Run(stop, wait, service.Config{})
+//
+// Under systemd, use Type=notify so the unit is only active once the bot is
+// running, and set WatchdogSec to have systemd restart a bot which hangs.
+//
+// On Windows, install the bot under a service wrapper such as WinSW or NSSM;
+// they stop services with a console control event, which Run handles like a
+// stop request from systemd.
+package service
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// defaultStatusInterval is how often the status is reported if the config does
+// not say.
+const defaultStatusInterval = time.Minute
+
+// stopSignals are the signals which stop the bot. SIGTERM is also delivered
+// for the console close, logoff, and shutdown events on Windows.
+var stopSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// Config configures a service.
+type Config struct {
+	// Status, if set, is called every StatusInterval, or every minute if
+	// that is zero, and what it returns is shown in the service manager,
+	// e.g. in `systemctl status`.
+	Status         func() string
+	StatusInterval time.Duration
+	// If set, failures to reach the service manager are logged here.
+	Logger *log.Logger
+}
+
+// Run blocks until the bot started by graw.Run or graw.Scan, whose stop and wait
+// functions are given, finishes. The service manager is told the bot is ready,
+// is sent watchdog pings while the bot runs, and is told it is stopping when it
+// stops. Interrupts and SIGTERM stop the bot gracefully; Run then returns nil.
+// Otherwise it returns the error the bot stopped with.
+func Run(stop func(), wait func() error, c Config) error {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, stopSignals...)
+	defer signal.Stop(sigs)
+
+	return run(stop, wait, c, sigs)
+}
+
+func run(stop func(), wait func() error, c Config, sigs <-chan os.Signal) error {
+	if c.StatusInterval <= 0 {
+		c.StatusInterval = defaultStatusInterval
+	}
+
+	if c.Logger == nil {
+		c.Logger = log.New(ioutil.Discard, "", 0)
+	}
+
+	notify := func(state string) {
+		if err := Notify(state); err != nil {
+			c.Logger.Printf("Failed to notify the service manager: %v", err)
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- wait()
+	}()
+
+	ready := "READY=1"
+	if c.Status != nil {
+		ready += "\nSTATUS=" + c.Status()
+	}
+	notify(ready)
+
+	var watchdog <-chan time.Time
+	if interval := watchdogInterval(); interval > 0 {
+		ticker := time.NewTicker(interval / 2)
+		defer ticker.Stop()
+		watchdog = ticker.C
+	}
+
+	var status <-chan time.Time
+	if c.Status != nil {
+		ticker := time.NewTicker(c.StatusInterval)
+		defer ticker.Stop()
+		status = ticker.C
+	}
+
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				notify("STOPPING=1\nSTATUS=" + err.Error())
+				return err
+			}
+			notify("STOPPING=1")
+			return nil
+		case sig := <-sigs:
+			notify("STOPPING=1\nSTATUS=Stopping on " + sig.String())
+			stop()
+			return <-done
+		case <-watchdog:
+			notify("WATCHDOG=1")
+		case <-status:
+			notify("STATUS=" + c.Status())
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// listen points NOTIFY_SOCKET at a socket of its own and returns the updates
+// sent to it.
+func listen(t *testing.T) (<-chan string, func()) {
+	dir, err := ioutil.TempDir("", "service")
+	if err != nil {
+		t.Fatalf("failed to make temp dir: %v", err)
+	}
+
+	path := filepath.Join(dir, "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	os.Setenv("NOTIFY_SOCKET", path)
+
+	updates := make(chan string, 10)
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				return
+			}
+			updates <- string(buf[:n])
+		}
+	}()
+
+	return updates, func() {
+		os.Unsetenv("NOTIFY_SOCKET")
+		conn.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func next(t *testing.T, updates <-chan string) string {
+	select {
+	case u := <-updates:
+		return u
+	case <-time.After(time.Second):
+		t.Fatalf("no update was sent")
+		return ""
+	}
+}
+
+func TestRunStopsOnSignal(t *testing.T) {
+	updates, cleanup := listen(t)
+	defer cleanup()
+
+	stopped := make(chan error)
+	stop := func() { close(stopped) }
+	wait := func() error {
+		<-stopped
+		return nil
+	}
+
+	sigs := make(chan os.Signal, 1)
+	result := make(chan error)
+	go func() {
+		result <- run(stop, wait, Config{Status: func() string { return "up" }}, sigs)
+	}()
+
+	if u := next(t, updates); u != "READY=1\nSTATUS=up" {
+		t.Errorf("got %q; wanted ready", u)
+	}
+
+	sigs <- syscall.SIGTERM
+	if err := <-result; err != nil {
+		t.Errorf("got error %v; wanted a graceful stop", err)
+	}
+
+	if u := next(t, updates); u != "STOPPING=1\nSTATUS=Stopping on terminated" {
+		t.Errorf("got %q; wanted stopping", u)
+	}
+}
+
+func TestRunReportsFailure(t *testing.T) {
+	updates, cleanup := listen(t)
+	defer cleanup()
+
+	failure := fmt.Errorf("reddit is down")
+	err := run(func() {}, func() error { return failure }, Config{}, nil)
+	if err != failure {
+		t.Errorf("got error %v; wanted %v", err, failure)
+	}
+
+	for _, wanted := range []string{"READY=1", "STOPPING=1\nSTATUS=reddit is down"} {
+		if u := next(t, updates); u != wanted {
+			t.Errorf("got %q; wanted %q", u, wanted)
+		}
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	defer os.Unsetenv("WATCHDOG_USEC")
+	defer os.Unsetenv("WATCHDOG_PID")
+
+	os.Setenv("WATCHDOG_USEC", "3000000")
+	if got := watchdogInterval(); got != 3*time.Second {
+		t.Errorf("got %v; wanted 3s", got)
+	}
+
+	os.Setenv("WATCHDOG_PID", "1")
+	if got := watchdogInterval(); got != 0 {
+		t.Errorf("got %v; wanted no watchdog for another process", got)
+	}
+}
+
+func TestNotifyWithoutManager(t *testing.T) {
+	os.Unsetenv("NOTIFY_SOCKET")
+	if err := Notify("READY=1"); err != nil {
+		t.Errorf("wanted no error outside a service manager; got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package graw
+
+import (
+	"time"
+)
+
+// defaultStatsInterval is how often statistics are published if the Config
+// gives no interval.
+const defaultStatsInterval = time.Minute
+
+// authorsSaveInterval is how often author counts and overlap graphs are saved.
+const authorsSaveInterval = time.Minute
+
+// connectStats publishes the config's statistics to its sink, if it has both,
+// and saves its author counts and overlap graph, if it has them.
+func connectStats(c Config, kill <-chan bool, errs chan<- error) {
+	connectAuthors(c, kill, errs)
+	connectOverlap(c, kill, errs)
+	connectStatsSink(c, kill, errs)
+}
+
+// connectStatsSink publishes the config's statistics to its sink, if it has
+// both.
+func connectStatsSink(c Config, kill <-chan bool, errs chan<- error) {
+	if c.Stats == nil || c.StatsSink == nil {
+		return
+	}
+
+	interval := c.StatsInterval
+	if interval == 0 {
+		interval = defaultStatsInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-kill:
+				return
+			case now := <-ticker.C:
+				errs <- c.StatsSink.Publish(now, c.Stats.All())
+			}
+		}
+	}()
+}
+
+// connectAuthors saves the config's author counts periodically, if it has
+// any.
+func connectAuthors(c Config, kill <-chan bool, errs chan<- error) {
+	if c.Authors == nil {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(authorsSaveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-kill:
+				return
+			case <-ticker.C:
+				errs <- c.Authors.Save()
+			}
+		}
+	}()
+}
+
+// connectOverlap saves the config's overlap graph periodically, if it has one.
+func connectOverlap(c Config, kill <-chan bool, errs chan<- error) {
+	if c.Overlap == nil {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(authorsSaveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-kill:
+				return
+			case <-ticker.C:
+				errs <- c.Overlap.Save()
+			}
+		}
+	}()
+}
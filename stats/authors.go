@@ -0,0 +1,191 @@
+package stats
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/store"
+)
+
+const (
+	// authorsKey is the key author counts are stored under.
+	authorsKey = "author-counts"
+	// authorBuckets is how many buckets an author's window is split into;
+	// counts leave the window a bucket at a time.
+	authorBuckets = 24
+)
+
+// AuthorConfig configures an Authors counter.
+type AuthorConfig struct {
+	// Window is the span of time activity is counted over. If zero, the
+	// last 24 hours.
+	Window time.Duration
+	// Store, if set, is where Save keeps the counts, and they are loaded
+	// from it again when a counter is made with the same store.
+	Store store.Store
+	// Clock times activity. If nil, the wall clock is used.
+	Clock clock.Clock
+}
+
+// AuthorCounts are an author's activity over the window.
+type AuthorCounts struct {
+	Posts    int `json:"posts"`
+	Comments int `json:"comments"`
+	Removals int `json:"removals"`
+	Reports  int `json:"reports"`
+}
+
+func (a *AuthorCounts) add(b AuthorCounts) {
+	a.Posts += b.Posts
+	a.Comments += b.Comments
+	a.Removals += b.Removals
+	a.Reports += b.Reports
+}
+
+// Authors counts each author's activity over a sliding window, as the basis
+// for per-user rate limits and spam heuristics. It is safe for concurrent
+// use.
+type Authors interface {
+	// Post counts a post by its author.
+	Post(p *reddit.Post)
+	// Comment counts a comment by its author.
+	Comment(c *reddit.Comment)
+	// Removal counts a removal of the author's post or comment.
+	Removal(author string)
+	// Report counts reports against the author's post or comment.
+	Report(author string, reports int)
+	// Counts returns the author's activity in the window.
+	Counts(author string) AuthorCounts
+	// Save saves the counts to the config's store, if it has one,
+	// forgetting authors without activity in the window.
+	Save() error
+}
+
+// bucket is an author's activity over one slice of the window.
+type bucket struct {
+	// Start is the unix time the bucket starts at.
+	Start  int64        `json:"start"`
+	Counts AuthorCounts `json:"counts"`
+}
+
+type authors struct {
+	window time.Duration
+	width  time.Duration
+	store  store.Store
+	clock  clock.Clock
+
+	mu      sync.Mutex
+	buckets map[string][]bucket
+}
+
+// NewAuthors returns an Authors counter, holding the counts saved in the
+// config's store.
+func NewAuthors(c AuthorConfig) (Authors, error) {
+	a := &authors{
+		window:  c.Window,
+		store:   c.Store,
+		clock:   c.Clock,
+		buckets: make(map[string][]bucket),
+	}
+	if a.window <= 0 {
+		a.window = defaultWindow
+	}
+	a.width = a.window / authorBuckets
+	if a.width <= 0 {
+		a.width = a.window
+	}
+	if a.clock == nil {
+		a.clock = clock.Real()
+	}
+
+	if a.store != nil {
+		err := a.store.Load(authorsKey, &a.buckets)
+		if err != nil && err != store.NotFoundErr {
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+func (a *authors) Post(p *reddit.Post) {
+	a.count(p.Author, AuthorCounts{Posts: 1})
+}
+
+func (a *authors) Comment(c *reddit.Comment) {
+	a.count(c.Author, AuthorCounts{Comments: 1})
+}
+
+func (a *authors) Removal(author string) {
+	a.count(author, AuthorCounts{Removals: 1})
+}
+
+func (a *authors) Report(author string, reports int) {
+	a.count(author, AuthorCounts{Reports: reports})
+}
+
+func (a *authors) count(author string, c AuthorCounts) {
+	if author == "" || author == "[deleted]" {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := strings.ToLower(author)
+	start := a.clock.Now().Truncate(a.width).Unix()
+	bs := a.prune(key)
+	if n := len(bs); n > 0 && bs[n-1].Start == start {
+		bs[n-1].Counts.add(c)
+	} else {
+		bs = append(bs, bucket{Start: start, Counts: c})
+	}
+	a.buckets[key] = bs
+}
+
+func (a *authors) Counts(author string) AuthorCounts {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var total AuthorCounts
+	for _, b := range a.prune(strings.ToLower(author)) {
+		total.add(b.Counts)
+	}
+	return total
+}
+
+func (a *authors) Save() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for key := range a.buckets {
+		a.prune(key)
+	}
+
+	if a.store == nil {
+		return nil
+	}
+	return a.store.Save(authorsKey, a.buckets)
+}
+
+// prune drops the author's buckets which have left the window, and returns
+// the rest.
+func (a *authors) prune(key string) []bucket {
+	bs := a.buckets[key]
+	cutoff := a.clock.Now().Add(-a.window).Unix()
+	stale := 0
+	for stale < len(bs) && bs[stale].Start+int64(a.width/time.Second) <= cutoff {
+		stale++
+	}
+
+	bs = bs[stale:]
+	if len(bs) == 0 {
+		delete(a.buckets, key)
+		return nil
+	}
+	a.buckets[key] = bs
+	return bs
+}
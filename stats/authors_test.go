@@ -0,0 +1,53 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/store"
+)
+
+func TestAuthors(t *testing.T) {
+	sim := clock.NewSimulation(time.Unix(0, 0))
+	s := store.NewMemoryStore()
+	a, err := NewAuthors(AuthorConfig{Window: 24 * time.Hour, Store: s, Clock: sim})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.Post(&reddit.Post{Author: "spammer"})
+	a.Comment(&reddit.Comment{Author: "Spammer"})
+	a.Report("spammer", 3)
+
+	sim.AdvanceTo(time.Unix(12*3600, 0))
+	a.Post(&reddit.Post{Author: "spammer"})
+	a.Removal("spammer")
+	a.Post(&reddit.Post{Author: "[deleted]"})
+
+	expected := AuthorCounts{Posts: 2, Comments: 1, Removals: 1, Reports: 3}
+	if c := a.Counts("SPAMMER"); c != expected {
+		t.Errorf("got %+v; wanted %+v", c, expected)
+	}
+
+	if err := a.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first hour's activity leaves the window a day later, and the
+	// counts survive a restart.
+	sim.AdvanceTo(time.Unix(25*3600, 0))
+	a, err = NewAuthors(AuthorConfig{Window: 24 * time.Hour, Store: s, Clock: sim})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = AuthorCounts{Posts: 1, Removals: 1}
+	if c := a.Counts("spammer"); c != expected {
+		t.Errorf("got %+v; wanted %+v", c, expected)
+	}
+	if c := a.Counts("[deleted]"); c != (AuthorCounts{}) {
+		t.Errorf("got %+v for deleted authors; wanted nothing", c)
+	}
+}
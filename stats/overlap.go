@@ -0,0 +1,263 @@
+package stats
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aldarisbm/graw/archive"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/store"
+)
+
+// overlapKey is the key the authors of an Overlap are stored under.
+const overlapKey = "subreddit-overlap"
+
+// OverlapConfig configures an Overlap graph.
+type OverlapConfig struct {
+	// MinShared is the fewest authors two subreddits must share to be
+	// linked in the graph. If zero, one is enough.
+	MinShared int
+	// Store, if set, is where Save keeps the authors seen, and they are
+	// loaded from it again when a graph is made with the same store.
+	Store store.Store
+}
+
+// Node is a subreddit in an overlap graph.
+type Node struct {
+	Subreddit string `json:"subreddit"`
+	// Authors counts the distinct authors seen in the subreddit.
+	Authors int `json:"authors"`
+}
+
+// Edge links two subreddits which share authors.
+type Edge struct {
+	A      string `json:"a"`
+	B      string `json:"b"`
+	Shared int    `json:"shared"`
+	// Jaccard is Shared over the authors of either subreddit, from 0 to
+	// 1, so edges between big and small subreddits compare fairly.
+	Jaccard float64 `json:"jaccard"`
+}
+
+// Graph is the co-activity graph of the subreddits seen: subreddits are linked
+// by the authors active in both. Nodes are ordered by name, and edges by
+// Shared, highest first.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// Overlap builds a graph of the author overlap between subreddits from the
+// posts and comments it is given, for studies of how communities relate. It
+// is safe for concurrent use.
+type Overlap interface {
+	// Post records the post's author as active in its subreddit.
+	Post(p *reddit.Post)
+	// Comment records the comment's author as active in its subreddit.
+	Comment(c *reddit.Comment)
+	// Graph computes the graph of every subreddit seen.
+	Graph() Graph
+	// Neighbors returns the edges of a subreddit, strongest first.
+	Neighbors(subreddit string) []Edge
+	// Save saves the authors seen to the config's store, if it has one.
+	Save() error
+}
+
+type overlap struct {
+	minShared int
+	store     store.Store
+
+	mu sync.Mutex
+	// authors are the authors seen in each subreddit, by lower cased
+	// name.
+	authors map[string]map[string]bool
+	// names are the subreddits' names as they were first seen.
+	names map[string]string
+}
+
+// NewOverlap returns an Overlap graph, holding the authors saved in the
+// config's store.
+func NewOverlap(c OverlapConfig) (Overlap, error) {
+	o := &overlap{
+		minShared: c.MinShared,
+		store:     c.Store,
+		authors:   make(map[string]map[string]bool),
+		names:     make(map[string]string),
+	}
+	if o.minShared <= 0 {
+		o.minShared = 1
+	}
+
+	if o.store != nil {
+		var saved map[string][]string
+		err := o.store.Load(overlapKey, &saved)
+		if err != nil && err != store.NotFoundErr {
+			return nil, err
+		}
+		for sub, authors := range saved {
+			for _, author := range authors {
+				o.add(sub, author)
+			}
+		}
+	}
+
+	return o, nil
+}
+
+func (o *overlap) Post(p *reddit.Post) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.add(p.Subreddit, p.Author)
+}
+
+func (o *overlap) Comment(c *reddit.Comment) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.add(c.Subreddit, c.Author)
+}
+
+// add records an author as active in a subreddit. The caller must hold the
+// lock.
+func (o *overlap) add(subreddit, author string) {
+	if subreddit == "" || author == "" || author == "[deleted]" {
+		return
+	}
+
+	key := strings.ToLower(subreddit)
+	if o.authors[key] == nil {
+		o.authors[key] = make(map[string]bool)
+		o.names[key] = subreddit
+	}
+	o.authors[key][strings.ToLower(author)] = true
+}
+
+func (o *overlap) Graph() Graph {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	g := Graph{Nodes: make([]Node, 0, len(o.authors)), Edges: o.edges("")}
+	for key, authors := range o.authors {
+		g.Nodes = append(g.Nodes, Node{Subreddit: o.names[key], Authors: len(authors)})
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].Subreddit < g.Nodes[j].Subreddit
+	})
+	return g
+}
+
+func (o *overlap) Neighbors(subreddit string) []Edge {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.edges(strings.ToLower(subreddit))
+}
+
+// edges computes the edges of the graph, or only those of the subreddit if one
+// is given, strongest first. The caller must hold the lock.
+func (o *overlap) edges(only string) []Edge {
+	// Counting the pairs of each author's subreddits visits only pairs
+	// which share someone.
+	subs := make(map[string][]string)
+	for key, authors := range o.authors {
+		for author := range authors {
+			subs[author] = append(subs[author], key)
+		}
+	}
+
+	type pair struct{ a, b string }
+	shared := make(map[pair]int)
+	for _, keys := range subs {
+		sort.Strings(keys)
+		for i := range keys {
+			for j := i + 1; j < len(keys); j++ {
+				if only == "" || keys[i] == only || keys[j] == only {
+					shared[pair{keys[i], keys[j]}]++
+				}
+			}
+		}
+	}
+
+	edges := []Edge{}
+	for p, n := range shared {
+		if n < o.minShared {
+			continue
+		}
+
+		union := len(o.authors[p.a]) + len(o.authors[p.b]) - n
+		edges = append(edges, Edge{
+			A:       o.names[p.a],
+			B:       o.names[p.b],
+			Shared:  n,
+			Jaccard: float64(n) / float64(union),
+		})
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Shared != edges[j].Shared {
+			return edges[i].Shared > edges[j].Shared
+		}
+		if edges[i].A != edges[j].A {
+			return edges[i].A < edges[j].A
+		}
+		return edges[i].B < edges[j].B
+	})
+	return edges
+}
+
+func (o *overlap) Save() error {
+	if o.store == nil {
+		return nil
+	}
+
+	o.mu.Lock()
+	saved := make(map[string][]string, len(o.authors))
+	for key, authors := range o.authors {
+		for author := range authors {
+			saved[o.names[key]] = append(saved[o.names[key]], author)
+		}
+	}
+	o.mu.Unlock()
+
+	return o.store.Save(overlapKey, saved)
+}
+
+// LoadArchive feeds the posts and comments of an archive to an Overlap graph,
+// so it can be built from traffic collected earlier.
+func LoadArchive(r archive.Reader, o Overlap) error {
+	for {
+		e, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case e.Post != nil:
+			o.Post(e.Post)
+		case e.Comment != nil:
+			o.Comment(e.Comment)
+		}
+	}
+}
+
+// GraphHandler serves an Overlap graph as JSON. A request for the handler's
+// path returns the whole Graph; with a "subreddit" query parameter, it
+// returns only that subreddit's edges.
+func GraphHandler(o Overlap) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var v interface{}
+		if sub := r.URL.Query().Get("subreddit"); sub != "" {
+			v = o.Neighbors(sub)
+		} else {
+			v = o.Graph()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(v)
+	})
+}
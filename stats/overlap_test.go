@@ -0,0 +1,82 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/aldarisbm/graw/archive"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/store"
+)
+
+func TestOverlap(t *testing.T) {
+	s := store.NewMemoryStore()
+	o, err := NewOverlap(OverlapConfig{Store: s})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []struct{ sub, author string }{
+		{"golang", "x"}, {"golang", "y"}, {"golang", "z"},
+		{"rust", "X"}, {"rust", "y"},
+		{"python", "z"},
+		{"python", "[deleted]"}, {"rust", "[deleted]"},
+	} {
+		o.Post(&reddit.Post{Subreddit: p.sub, Author: p.author})
+	}
+
+	g := o.Graph()
+	if want := []Node{{"golang", 3}, {"python", 1}, {"rust", 2}}; !reflect.DeepEqual(g.Nodes, want) {
+		t.Errorf("got nodes %v; wanted %v", g.Nodes, want)
+	}
+	if want := []Edge{
+		{A: "golang", B: "rust", Shared: 2, Jaccard: 2.0 / 3},
+		{A: "golang", B: "python", Shared: 1, Jaccard: 1.0 / 3},
+	}; !reflect.DeepEqual(g.Edges, want) {
+		t.Errorf("got edges %v; wanted %v", g.Edges, want)
+	}
+
+	if edges := o.Neighbors("Python"); len(edges) != 1 || edges[0].B != "python" {
+		t.Errorf("got neighbors %v", edges)
+	}
+
+	if err := o.Save(); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := NewOverlap(OverlapConfig{Store: s, MinShared: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edges := restored.Graph().Edges; len(edges) != 1 || edges[0].Shared != 2 {
+		t.Errorf("got edges %v after a restart", edges)
+	}
+}
+
+func TestOverlapFromArchive(t *testing.T) {
+	var buf bytes.Buffer
+	w := archive.NewWriter(&buf)
+	w.Write(archive.Event{Kind: archive.PostKind, Post: &reddit.Post{Subreddit: "golang", Author: "x"}})
+	w.Write(archive.Event{Kind: archive.CommentKind, Comment: &reddit.Comment{Subreddit: "rust", Author: "x"}})
+
+	o, err := NewOverlap(OverlapConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadArchive(archive.NewReader(&buf), o); err != nil {
+		t.Fatalf("failed to load archive: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	GraphHandler(o).ServeHTTP(rec, httptest.NewRequest("GET", "/graph", nil))
+
+	var g Graph
+	if err := json.NewDecoder(rec.Body).Decode(&g); err != nil {
+		t.Fatalf("bad graph response: %v", err)
+	}
+	if len(g.Edges) != 1 || g.Edges[0].Shared != 1 {
+		t.Errorf("served graph %+v", g)
+	}
+}
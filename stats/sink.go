@@ -0,0 +1,34 @@
+package stats
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Sink receives snapshots of the statistics of every subreddit, e.g. to feed a
+// metrics system.
+type Sink interface {
+	Publish(at time.Time, all map[string]Stats) error
+}
+
+type jsonSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// JSONLines returns a sink which writes each snapshot to w as a line of JSON.
+func JSONLines(w io.Writer) Sink {
+	return &jsonSink{enc: json.NewEncoder(w)}
+}
+
+func (j *jsonSink) Publish(at time.Time, all map[string]Stats) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.enc.Encode(struct {
+		At         int64            `json:"at"`
+		Subreddits map[string]Stats `json:"subreddits"`
+	}{at.Unix(), all})
+}
@@ -0,0 +1,243 @@
+// Package stats aggregates rolling activity statistics per subreddit from the
+// posts and comments a bot streams, for community health dashboards.
+package stats
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+)
+
+const (
+	// defaultWindow is the span of time statistics are computed over if
+	// the config does not say.
+	defaultWindow = 24 * time.Hour
+	// maxObservations is the most posts and comments remembered per
+	// subreddit; the oldest are forgotten first, so statistics of very
+	// busy subreddits cover less than the window.
+	maxObservations = 50000
+)
+
+// Config configures an Aggregator.
+type Config struct {
+	// Window is the span of time statistics are computed over. If zero,
+	// the last 24 hours.
+	Window time.Duration
+	// Clock times observations. If nil, the wall clock is used.
+	Clock clock.Clock
+}
+
+// Stats are a subreddit's activity over the aggregator's window.
+type Stats struct {
+	Posts    int `json:"posts"`
+	Comments int `json:"comments"`
+	// PostsPerHour is Posts spread over the window, or over the time since
+	// the first observation if that is shorter.
+	PostsPerHour float64 `json:"posts_per_hour"`
+	// CommentsPerPost is zero if there were no posts.
+	CommentsPerPost float64 `json:"comments_per_post"`
+	// UniqueAuthors counts the distinct authors of the posts and comments.
+	UniqueAuthors int `json:"unique_authors"`
+	// MedianScore is the median of the posts' last observed scores.
+	MedianScore float64 `json:"median_score"`
+}
+
+// Aggregator computes rolling statistics from the posts and comments it is
+// given. It is safe for concurrent use.
+type Aggregator interface {
+	// Post observes a post. Observing a post again, e.g. with a newer
+	// score, updates it rather than counting it twice.
+	Post(p *reddit.Post)
+	// Comment observes a comment.
+	Comment(c *reddit.Comment)
+	// Stats returns the statistics of the subreddit.
+	Stats(subreddit string) Stats
+	// All returns the statistics of every subreddit observed in the
+	// window, by lower cased name.
+	All() map[string]Stats
+}
+
+// observation is a post or comment in a subreddit's window.
+type observation struct {
+	at     time.Time
+	post   bool
+	author string
+	score  int32
+}
+
+type aggregator struct {
+	window time.Duration
+	clock  clock.Clock
+	// started is when the first observation was made.
+	started time.Time
+
+	mu   sync.Mutex
+	subs map[string]*subreddit
+}
+
+// subreddit holds the observations of a subreddit, oldest first, and the
+// index of each by fullname.
+type subreddit struct {
+	names []string
+	obs   map[string]*observation
+}
+
+// New returns an Aggregator.
+func New(c Config) Aggregator {
+	a := &aggregator{
+		window: c.Window,
+		clock:  c.Clock,
+		subs:   make(map[string]*subreddit),
+	}
+	if a.window <= 0 {
+		a.window = defaultWindow
+	}
+	if a.clock == nil {
+		a.clock = clock.Real()
+	}
+	return a
+}
+
+func (a *aggregator) Post(p *reddit.Post) {
+	a.observe(p.Subreddit, p.Name, true, p.Author, p.Score)
+}
+
+func (a *aggregator) Comment(c *reddit.Comment) {
+	a.observe(c.Subreddit, c.Name, false, c.Author, c.Score)
+}
+
+func (a *aggregator) observe(
+	sub, name string,
+	post bool,
+	author string,
+	score int32,
+) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.clock.Now()
+	if a.started.IsZero() {
+		a.started = now
+	}
+
+	key := strings.ToLower(sub)
+	s, ok := a.subs[key]
+	if !ok {
+		s = &subreddit{obs: make(map[string]*observation)}
+		a.subs[key] = s
+	}
+
+	if o, ok := s.obs[name]; ok {
+		o.score = score
+		return
+	}
+
+	s.names = append(s.names, name)
+	s.obs[name] = &observation{
+		at:     now,
+		post:   post,
+		author: strings.ToLower(author),
+		score:  score,
+	}
+	if len(s.names) > maxObservations {
+		s.forget(1)
+	}
+}
+
+func (a *aggregator) Stats(sub string) Stats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, ok := a.subs[strings.ToLower(sub)]
+	if !ok {
+		return Stats{}
+	}
+	return a.stats(s, a.clock.Now())
+}
+
+func (a *aggregator) All() map[string]Stats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.clock.Now()
+	all := make(map[string]Stats)
+	for key, s := range a.subs {
+		if st := a.stats(s, now); st.Posts+st.Comments > 0 {
+			all[key] = st
+		} else {
+			delete(a.subs, key)
+		}
+	}
+	return all
+}
+
+// stats prunes the subreddit's observations older than the window and
+// computes its statistics from the rest.
+func (a *aggregator) stats(s *subreddit, now time.Time) Stats {
+	cutoff := now.Add(-a.window)
+	stale := 0
+	for _, name := range s.names {
+		if s.obs[name].at.After(cutoff) {
+			break
+		}
+		stale++
+	}
+	s.forget(stale)
+
+	var st Stats
+	authors := make(map[string]bool)
+	var scores []int
+	for _, name := range s.names {
+		o := s.obs[name]
+		if o.post {
+			st.Posts++
+			scores = append(scores, int(o.score))
+		} else {
+			st.Comments++
+		}
+		if o.author != "" && o.author != "[deleted]" {
+			authors[o.author] = true
+		}
+	}
+	st.UniqueAuthors = len(authors)
+
+	span := a.window
+	if since := now.Sub(a.started); since < span {
+		span = since
+	}
+	if hours := span.Hours(); hours > 0 {
+		st.PostsPerHour = float64(st.Posts) / hours
+	}
+
+	if st.Posts > 0 {
+		st.CommentsPerPost = float64(st.Comments) / float64(st.Posts)
+	}
+	st.MedianScore = median(scores)
+
+	return st
+}
+
+// forget drops the n oldest observations.
+func (s *subreddit) forget(n int) {
+	for _, name := range s.names[:n] {
+		delete(s.obs, name)
+	}
+	s.names = s.names[n:]
+}
+
+func median(xs []int) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+
+	sort.Ints(xs)
+	mid := len(xs) / 2
+	if len(xs)%2 == 1 {
+		return float64(xs[mid])
+	}
+	return float64(xs[mid-1]+xs[mid]) / 2
+}
@@ -0,0 +1,73 @@
+package stats
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+)
+
+func TestAggregator(t *testing.T) {
+	sim := clock.NewSimulation(time.Unix(0, 0))
+	a := New(Config{Window: 2 * time.Hour, Clock: sim})
+
+	a.Post(&reddit.Post{Name: "t3_a", Subreddit: "golang", Author: "x", Score: 1})
+	a.Comment(&reddit.Comment{Name: "t1_a", Subreddit: "golang", Author: "y"})
+
+	sim.AdvanceTo(time.Unix(3600, 0))
+	a.Post(&reddit.Post{Name: "t3_b", Subreddit: "Golang", Author: "X", Score: 5})
+	a.Post(&reddit.Post{Name: "t3_c", Subreddit: "golang", Author: "z", Score: 2})
+	a.Comment(&reddit.Comment{Name: "t1_b", Subreddit: "golang", Author: "x"})
+	// Observing a post again updates its score.
+	a.Post(&reddit.Post{Name: "t3_a", Subreddit: "golang", Author: "x", Score: 9})
+
+	expected := Stats{
+		Posts:           3,
+		Comments:        2,
+		PostsPerHour:    3,
+		CommentsPerPost: 2.0 / 3,
+		UniqueAuthors:   3,
+		MedianScore:     5,
+	}
+	if st := a.Stats("golang"); st != expected {
+		t.Errorf("got %+v; wanted %+v", st, expected)
+	}
+
+	// Observations leave the window.
+	sim.AdvanceTo(time.Unix(2*3600+1, 0))
+	expected = Stats{
+		Posts:           2,
+		Comments:        1,
+		PostsPerHour:    1,
+		CommentsPerPost: 0.5,
+		UniqueAuthors:   2,
+		MedianScore:     3.5,
+	}
+	if st := a.Stats("golang"); st != expected {
+		t.Errorf("got %+v; wanted %+v", st, expected)
+	}
+
+	sim.AdvanceTo(time.Unix(5*3600, 0))
+	if all := a.All(); len(all) != 0 {
+		t.Errorf("got %v; wanted no subreddits once the window passed", all)
+	}
+}
+
+func TestJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	err := JSONLines(&buf).Publish(time.Unix(60, 0), map[string]Stats{
+		"golang": {Posts: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"at":60,"subreddits":{"golang":{"posts":1,"comments":0,` +
+		`"posts_per_hour":0,"comments_per_post":0,"unique_authors":0,` +
+		`"median_score":0}}}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("got %s; wanted %s", buf.String(), expected)
+	}
+}
@@ -0,0 +1,139 @@
+package store
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+var (
+	// noKeyErr is returned when a Keyring hands out an empty key.
+	noKeyErr = fmt.Errorf("the encryption key is empty")
+	// sealedErr is returned when a value can't be decrypted, because it was
+	// encrypted with another key, under another label, or was tampered with.
+	sealedErr = fmt.Errorf("the value could not be decrypted with this key")
+)
+
+// Keyring supplies the key state is encrypted with, e.g. from a file outside
+// the state directory, an environment variable, or the operating system's
+// keyring.
+type Keyring interface {
+	// Key returns the secret key. Any length is accepted; it is hashed into
+	// an AES-256 key.
+	Key() ([]byte, error)
+}
+
+type staticKey []byte
+
+// StaticKey returns a Keyring which always supplies key.
+func StaticKey(key []byte) Keyring {
+	return staticKey(key)
+}
+
+func (s staticKey) Key() ([]byte, error) {
+	return s, nil
+}
+
+// Seal encrypts and authenticates plaintext with the key in k. The label, such
+// as the key the value is stored under, is authenticated too, so a sealed value
+// can't be swapped for another sealed under a different label.
+func Seal(k Keyring, label string, plaintext []byte) ([]byte, error) {
+	aead, err := newAEAD(k)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, []byte(label)), nil
+}
+
+// Open decrypts a value sealed by Seal with the same key and label.
+func Open(k Keyring, label string, sealed []byte) ([]byte, error) {
+	aead, err := newAEAD(k)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, sealedErr
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(label))
+	if err != nil {
+		return nil, sealedErr
+	}
+
+	return plaintext, nil
+}
+
+func newAEAD(k Keyring) (cipher.AEAD, error) {
+	secret, err := k.Key()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(secret) == 0 {
+		return nil, noKeyErr
+	}
+
+	key := sha256.Sum256(secret)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+type encryptedStore struct {
+	s Store
+	k Keyring
+}
+
+// NewEncryptedStore returns a Store which encrypts every value with the key in
+// k before saving it in s, for bots running on shared or less trusted hosts.
+// Keys are not encrypted. State exported from the store stays encrypted.
+func NewEncryptedStore(s Store, k Keyring) Store {
+	return &encryptedStore{s: s, k: k}
+}
+
+func (e *encryptedStore) Load(key string, v interface{}) error {
+	var sealed []byte
+	if err := e.s.Load(key, &sealed); err != nil {
+		return err
+	}
+
+	blob, err := Open(e.k, key, sealed)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(blob, v)
+}
+
+func (e *encryptedStore) Save(key string, v interface{}) error {
+	blob, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := Seal(e.k, key, blob)
+	if err != nil {
+		return err
+	}
+
+	return e.s.Save(key, sealed)
+}
+
+func (e *encryptedStore) Keys() ([]string, error) {
+	return e.s.Keys()
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// exportVersion is the version of the format ExportState writes.
+const exportVersion = 1
+
+var versionErr = fmt.Errorf("the export is of an unknown format version")
+
+// export is the format of an exported store.
+type export struct {
+	Version int                        `json:"version"`
+	State   map[string]json.RawMessage `json:"state"`
+}
+
+// ExportState writes all the state saved in s, such as deferred events, seen
+// results, learned baselines, and the idempotency ledger, to w, so a bot can be
+// backed up or moved to another host and restored there with ImportState.
+func ExportState(s Store, w io.Writer) error {
+	keys, err := s.Keys()
+	if err != nil {
+		return err
+	}
+
+	e := export{Version: exportVersion, State: make(map[string]json.RawMessage)}
+	for _, key := range keys {
+		var value json.RawMessage
+		if err := s.Load(key, &value); err != nil {
+			return err
+		}
+		e.State[key] = value
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(e)
+}
+
+// ImportState saves the state exported by ExportState in r into s, replacing
+// the values saved under the same keys. Keys which are not in the export are
+// left alone. Import before starting the bot, since streams only load their
+// state when they start.
+func ImportState(s Store, r io.Reader) error {
+	var e export
+	if err := json.NewDecoder(r).Decode(&e); err != nil {
+		return err
+	}
+
+	if e.Version != exportVersion {
+		return versionErr
+	}
+
+	for key, value := range e.State {
+		if err := s.Save(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
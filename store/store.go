@@ -0,0 +1,176 @@
+// Package store persists the state graw's streams keep, such as learned
+// baselines, so that it survives restarts.
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	// NotFoundErr is returned when loading a key that was never saved.
+	NotFoundErr = fmt.Errorf("no state is stored under that key")
+	// badKeyErr is returned for keys which can't be used as file names.
+	badKeyErr = fmt.Errorf("keys may only hold letters, digits, '-', '_' and '.'")
+)
+
+// validKey matches the keys a Store accepts, which are safe as file names.
+var validKey = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// Store saves and loads values as JSON under string keys.
+type Store interface {
+	// Load decodes the value saved under key into v. It returns
+	// NotFoundErr if nothing was saved under the key.
+	Load(key string, v interface{}) error
+	// Save replaces the value saved under key with v.
+	Save(key string, v interface{}) error
+	// Keys returns every key a value is saved under, sorted.
+	Keys() ([]string, error)
+}
+
+type fileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileStore returns a Store which keeps each key in its own JSON file in
+// dir. Saves are atomic; a crash mid-save leaves the previous value intact.
+func NewFileStore(dir string) (Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	return &fileStore{dir: dir}, nil
+}
+
+func (f *fileStore) path(key string) (string, error) {
+	if !validKey.MatchString(key) || key == "." || key == ".." {
+		return "", badKeyErr
+	}
+
+	return filepath.Join(f.dir, key+".json"), nil
+}
+
+func (f *fileStore) Load(key string, v interface{}) error {
+	path, err := f.path(key)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	blob, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return NotFoundErr
+	} else if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(blob, v)
+}
+
+func (f *fileStore) Save(key string, v interface{}) error {
+	path, err := f.path(key)
+	if err != nil {
+		return err
+	}
+
+	blob, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tmp, err := ioutil.TempFile(f.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(blob); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (f *fileStore) Keys() ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	files, err := ioutil.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, file := range files {
+		key := strings.TrimSuffix(file.Name(), ".json")
+		if file.Mode().IsRegular() && key != file.Name() && validKey.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+type memoryStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+// NewMemoryStore returns a Store which keeps values in memory, for tests and
+// bots which don't need their state to outlive them.
+func NewMemoryStore() Store {
+	return &memoryStore{values: make(map[string][]byte)}
+}
+
+func (m *memoryStore) Load(key string, v interface{}) error {
+	m.mu.Lock()
+	blob, ok := m.values[key]
+	m.mu.Unlock()
+	if !ok {
+		return NotFoundErr
+	}
+
+	return json.Unmarshal(blob, v)
+}
+
+func (m *memoryStore) Save(key string, v interface{}) error {
+	blob, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = blob
+	return nil
+}
+
+func (m *memoryStore) Keys() ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]string, 0, len(m.values))
+	for key := range m.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
@@ -0,0 +1,148 @@
+package store
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+type state struct {
+	Counts map[string]int
+	Name   string
+}
+
+func testStore(s Store, t *testing.T) {
+	var got state
+	if err := s.Load("missing", &got); err != NotFoundErr {
+		t.Errorf("wanted NotFoundErr loading missing key; got %v", err)
+	}
+
+	want := state{Counts: map[string]int{"golang": 3}, Name: "baseline"}
+	if err := s.Save("volume", want); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	if err := s.Load("volume", &got); err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v; wanted %+v", got, want)
+	}
+
+	if err := s.Save("deferred-events", []string{"t3_a"}); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	keys, err := s.Keys()
+	if err != nil {
+		t.Fatalf("failed to list keys: %v", err)
+	}
+	if wantKeys := []string{"deferred-events", "volume"}; !reflect.DeepEqual(keys, wantKeys) {
+		t.Errorf("got keys %v; wanted %v", keys, wantKeys)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatalf("failed to make temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("failed to make store: %v", err)
+	}
+	testStore(s, t)
+
+	// A new store over the same directory sees the saved state.
+	s, _ = NewFileStore(dir)
+	var got state
+	if err := s.Load("volume", &got); err != nil || got.Name != "baseline" {
+		t.Errorf("state did not survive; got %+v, %v", got, err)
+	}
+
+	for _, key := range []string{"", "..", "../escape", "a/b"} {
+		if err := s.Save(key, got); err != badKeyErr {
+			t.Errorf("wanted badKeyErr saving %q; got %v", key, err)
+		}
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(NewMemoryStore(), t)
+}
+
+func TestEncryptedStore(t *testing.T) {
+	plain := NewMemoryStore()
+	s := NewEncryptedStore(plain, StaticKey([]byte("hunter2")))
+	testStore(s, t)
+
+	var raw []byte
+	if err := plain.Load("volume", &raw); err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if bytes.Contains(raw, []byte("baseline")) {
+		t.Errorf("the value was saved in the clear: %s", raw)
+	}
+
+	var got state
+	wrong := NewEncryptedStore(plain, StaticKey([]byte("wrong")))
+	if err := wrong.Load("volume", &got); err != sealedErr {
+		t.Errorf("wanted sealedErr loading with the wrong key; got %v", err)
+	}
+
+	// A value can't be passed off as another key's.
+	if err := plain.Save("deferred-events", raw); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	if err := s.Load("deferred-events", &got); err != sealedErr {
+		t.Errorf("wanted sealedErr loading a moved value; got %v", err)
+	}
+
+	empty := NewEncryptedStore(plain, StaticKey(nil))
+	if err := empty.Save("volume", got); err != noKeyErr {
+		t.Errorf("wanted noKeyErr saving with an empty key; got %v", err)
+	}
+}
+
+func TestExportState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatalf("failed to make temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	from, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("failed to make store: %v", err)
+	}
+	testStore(from, t)
+
+	var buf bytes.Buffer
+	if err := ExportState(from, &buf); err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+
+	to := NewMemoryStore()
+	if err := ImportState(to, &buf); err != nil {
+		t.Fatalf("failed to import: %v", err)
+	}
+
+	var got state
+	if err := to.Load("volume", &got); err != nil || got.Name != "baseline" {
+		t.Errorf("state was not imported; got %+v, %v", got, err)
+	}
+	var events []string
+	if err := to.Load("deferred-events", &events); err != nil || len(events) != 1 {
+		t.Errorf("state was not imported; got %v, %v", events, err)
+	}
+
+	bad := bytes.NewBufferString(`{"version": 2, "state": {}}`)
+	if err := ImportState(to, bad); err != versionErr {
+		t.Errorf("wanted versionErr importing a newer export; got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package streams
+
+import "github.com/aldarisbm/graw/reddit"
+
+// CiteConfig configures how the comments of a stream are cited.
+type CiteConfig struct {
+	// NonParticipation, when true, makes citation links point at
+	// np.reddit.com, as many subreddits require of links into other
+	// communities.
+	NonParticipation bool
+	// Context is how many of each comment's parents its link displays,
+	// and are fetched with it, e.g. 3. If zero, the comment is cited
+	// alone and nothing is fetched.
+	Context int
+}
+
+// Citation is a comment of a stream with what a bot needs to cite it.
+type Citation struct {
+	Comment *reddit.Comment
+	// Link is the link to the comment, following the CiteConfig.
+	Link string
+	// Thread is the comment's post, with a comment tree holding only the
+	// comment and up to Context of its parents. It is nil if Context is
+	// zero, or if fetching it failed; the error is sent to errs.
+	Thread *reddit.Post
+}
+
+// Cite returns citations of the comments of a stream, until the stream closes
+// or kill is closed. Each citation consumes an interval of the handle if the
+// config's Context is set, and none otherwise.
+func Cite(
+	lurker reddit.Lurker,
+	kill <-chan bool,
+	errs chan<- error,
+	cfg CiteConfig,
+	comments <-chan *reddit.Comment,
+) <-chan *Citation {
+	citations := make(chan *Citation)
+	go func() {
+		defer close(citations)
+		for {
+			select {
+			case <-kill:
+				return
+			case c, ok := <-comments:
+				if !ok {
+					return
+				}
+
+				citation := &Citation{
+					Comment: c,
+					Link:    c.ContextLink(cfg.NonParticipation, cfg.Context),
+				}
+				if cfg.Context > 0 {
+					thread, err := lurker.Context(c.Permalink, cfg.Context)
+					if err != nil {
+						select {
+						case errs <- err:
+						case <-kill:
+							return
+						}
+					}
+					citation.Thread = thread
+				}
+
+				select {
+				case citations <- citation:
+				case <-kill:
+					return
+				}
+			}
+		}
+	}()
+
+	return citations
+}
@@ -0,0 +1,63 @@
+package streams
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+type mockContextLurker struct {
+	reddit.Lurker
+	err     error
+	fetched []string
+}
+
+func (m *mockContextLurker) Context(permalink string, context int) (*reddit.Post, error) {
+	m.fetched = append(m.fetched, fmt.Sprintf("%s?context=%d", permalink, context))
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &reddit.Post{Name: "t3_post"}, nil
+}
+
+func TestCite(t *testing.T) {
+	for _, test := range []struct {
+		cfg     CiteConfig
+		err     error
+		link    string
+		thread  bool
+		fetched int
+	}{
+		{CiteConfig{}, nil, "https://www.reddit.com/r/a/comments/b/c/d/", false, 0},
+		{
+			CiteConfig{NonParticipation: true, Context: 3}, nil,
+			"https://np.reddit.com/r/a/comments/b/c/d/?context=3", true, 1,
+		},
+		{
+			CiteConfig{Context: 3}, reddit.BusyErr,
+			"https://www.reddit.com/r/a/comments/b/c/d/?context=3", false, 1,
+		},
+	} {
+		lurker := &mockContextLurker{err: test.err}
+		kill := make(chan bool)
+		errs := make(chan error, 1)
+		comments := make(chan *reddit.Comment, 1)
+		comments <- &reddit.Comment{Name: "t1_d", Permalink: "/r/a/comments/b/c/d/"}
+		close(comments)
+
+		c := <-Cite(lurker, kill, errs, test.cfg, comments)
+		if c.Link != test.link {
+			t.Errorf("%+v: got link %s; wanted %s", test.cfg, c.Link, test.link)
+		}
+		if (c.Thread != nil) != test.thread || len(lurker.fetched) != test.fetched {
+			t.Errorf("%+v: got thread %v after fetching %v", test.cfg, c.Thread, lurker.fetched)
+		}
+		if test.err != nil {
+			if err := <-errs; err != test.err {
+				t.Errorf("%+v: got error %v; wanted %v", test.cfg, err, test.err)
+			}
+		}
+		close(kill)
+	}
+}
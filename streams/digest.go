@@ -0,0 +1,94 @@
+package streams
+
+import (
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+)
+
+// DigestConfig configures a digest stream.
+type DigestConfig struct {
+	// Interval is how often a digest of the results gathered since the
+	// last one is delivered, e.g. hourly. Intervals without results
+	// deliver nothing.
+	Interval time.Duration
+	// Max, if set, delivers a digest early once it holds this many
+	// results, so a burst doesn't make one enormous digest.
+	Max int
+	// Clock times the digests. If nil, the wall clock is used.
+	Clock clock.Clock
+}
+
+// SearchDigest is a batch of saved search results.
+type SearchDigest struct {
+	// Results are in the order they were found.
+	Results []*SearchResult
+	// Start and End bound the span of time the results were found in.
+	Start time.Time
+	End   time.Time
+}
+
+// SearchDigests returns a stream of digests of the given saved search results,
+// delivered every cfg.Interval instead of one event per result. The results
+// gathered when results closes or kill is closed are dropped.
+func SearchDigests(
+	kill <-chan bool,
+	errs chan<- error,
+	results <-chan *SearchResult,
+	cfg DigestConfig,
+) (
+	<-chan *SearchDigest,
+	error,
+) {
+	if cfg.Interval <= 0 {
+		return nil, intervalErr
+	}
+
+	if cfg.Clock == nil {
+		cfg.Clock = clock.Real()
+	}
+
+	digests := make(chan *SearchDigest)
+	go func() {
+		defer close(digests)
+		d := &SearchDigest{Start: cfg.Clock.Now()}
+		due := cfg.Clock.After(cfg.Interval)
+		deliver := func(now time.Time) bool {
+			if len(d.Results) > 0 {
+				d.End = now
+				select {
+				case digests <- d:
+				case <-kill:
+					return false
+				}
+			}
+			d = &SearchDigest{Start: now}
+			return true
+		}
+
+		for {
+			select {
+			case <-kill:
+				return
+			case r, ok := <-results:
+				if !ok {
+					return
+				}
+
+				d.Results = append(d.Results, r)
+				if cfg.Max > 0 && len(d.Results) >= cfg.Max {
+					if !deliver(cfg.Clock.Now()) {
+						return
+					}
+				}
+			case now := <-due:
+				due = cfg.Clock.After(cfg.Interval)
+				if !deliver(now) {
+					return
+				}
+			}
+		}
+	}()
+
+	return digests, nil
+}
@@ -0,0 +1,55 @@
+package streams
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+)
+
+func TestSearchDigests(t *testing.T) {
+	sim := clock.NewSimulation(time.Unix(0, 0))
+	kill := make(chan bool)
+	defer close(kill)
+	results := make(chan *SearchResult)
+
+	digests, err := SearchDigests(kill, nil, results, DigestConfig{
+		Interval: time.Hour,
+		Max:      3,
+		Clock:    sim,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := func(name string) *SearchResult {
+		return &SearchResult{Post: &reddit.Post{Name: name}}
+	}
+
+	results <- result("t3_a")
+	results <- result("t3_b")
+	sim.AdvanceTo(time.Unix(3600, 0))
+
+	d := <-digests
+	if len(d.Results) != 2 || d.Results[0].Post.Name != "t3_a" {
+		t.Errorf("got digest %+v; wanted t3_a and t3_b", d)
+	}
+	if !d.Start.Equal(time.Unix(0, 0)) || !d.End.Equal(time.Unix(3600, 0)) {
+		t.Errorf("got digest of %v to %v; wanted the first hour", d.Start, d.End)
+	}
+
+	// A full digest is delivered early.
+	for _, name := range []string{"t3_c", "t3_d", "t3_e"} {
+		results <- result(name)
+	}
+	if d := <-digests; len(d.Results) != 3 {
+		t.Errorf("got %d results; wanted a full digest of 3", len(d.Results))
+	}
+}
+
+func TestSearchDigestsInterval(t *testing.T) {
+	if _, err := SearchDigests(nil, nil, nil, DigestConfig{}); err != intervalErr {
+		t.Errorf("got %v; wanted %v", err, intervalErr)
+	}
+}
@@ -0,0 +1,193 @@
+package streams
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// maxParents is the most comment authors a comment filter remembers to find
+// the authors of replies' parents by.
+const maxParents = 10000
+
+// CommentFilter chooses the comments of a stream by where they are in their
+// threads, who wrote them, and whom they reply to. The zero value passes every
+// comment.
+type CommentFilter struct {
+	// Threads, if set, passes only comments on the posts named, by
+	// fullname, e.g. for a bot following the updates of a few threads.
+	Threads []string
+	// TopLevel passes only comments on the post itself, not replies to
+	// other comments.
+	TopLevel bool
+	// Submitter passes only comments by the post's author.
+	Submitter bool
+	// RepliesToOP passes only comments replying to the post's author:
+	// top level comments, and replies to the author's comments.
+	RepliesToOP bool
+	// RepliesTo, if set, passes only comments replying to the posts and
+	// comments of the named users, e.g. the bot's own name.
+	RepliesTo []string
+	// Distinguished passes only comments a moderator or admin
+	// distinguished, such as moderators' announcements.
+	Distinguished bool
+}
+
+// empty reports whether the filter passes every comment.
+func (f CommentFilter) empty() bool {
+	return len(f.Threads) == 0 && !f.TopLevel && !f.Submitter &&
+		!f.RepliesToOP && len(f.RepliesTo) == 0 && !f.Distinguished
+}
+
+// PostFilter chooses the posts of a stream. The zero value passes every post.
+type PostFilter struct {
+	// Distinguished passes only posts a moderator or admin distinguished,
+	// such as moderators' announcements.
+	Distinguished bool
+}
+
+// MatchPosts returns a function reporting whether a post passes the filter.
+func MatchPosts(f PostFilter) func(p *reddit.Post) bool {
+	return func(p *reddit.Post) bool {
+		return !f.Distinguished || p.Distinguished != ""
+	}
+}
+
+// FilterComments returns the comments of a stream which pass the filter, until
+// the stream closes or kill is closed. The authors replied to are known from
+// the post's author and from the comments the stream carried before, without
+// requests; replies to comments the stream did not carry do not pass a filter
+// on the authors replied to.
+func FilterComments(
+	comments <-chan *reddit.Comment,
+	kill <-chan bool,
+	f CommentFilter,
+) <-chan *reddit.Comment {
+	if f.empty() {
+		return comments
+	}
+
+	passes := MatchComments(f)
+	filtered := make(chan *reddit.Comment)
+	go func() {
+		defer close(filtered)
+		for {
+			select {
+			case <-kill:
+				return
+			case c, ok := <-comments:
+				if !ok {
+					return
+				}
+				if !passes(c) {
+					continue
+				}
+
+				select {
+				case filtered <- c:
+				case <-kill:
+					return
+				}
+			}
+		}
+	}()
+
+	return filtered
+}
+
+// MatchComments returns a function reporting whether each comment of a stream
+// passes the filter, for streams read some other way than by FilterComments.
+// It must be shown every comment of the stream, in order, to know the authors
+// replied to.
+func MatchComments(f CommentFilter) func(c *reddit.Comment) bool {
+	return newCommentFilter(f).passes
+}
+
+// commentFilter applies a CommentFilter, remembering the authors of the
+// comments it is shown.
+type commentFilter struct {
+	f CommentFilter
+
+	mu sync.Mutex
+	// authors are the authors of recent comments, by fullname.
+	authors map[string]string
+	order   []string
+}
+
+func newCommentFilter(f CommentFilter) *commentFilter {
+	return &commentFilter{f: f, authors: make(map[string]string)}
+}
+
+// passes reports whether the comment passes the filter.
+func (cf *commentFilter) passes(c *reddit.Comment) bool {
+	cf.mu.Lock()
+	defer cf.mu.Unlock()
+	cf.remember(c)
+
+	if len(cf.f.Threads) > 0 && !namedIn(c.LinkID, cf.f.Threads) {
+		return false
+	}
+
+	if cf.f.Distinguished && c.Distinguished == "" {
+		return false
+	}
+
+	parent, reply := c.ParentComment()
+	if cf.f.TopLevel && reply {
+		return false
+	}
+
+	if cf.f.Submitter && !c.IsSubmitter && !strings.EqualFold(c.Author, c.LinkAuthor) {
+		return false
+	}
+
+	if !cf.f.RepliesToOP && len(cf.f.RepliesTo) == 0 {
+		return true
+	}
+
+	// The parent's author is the post's author for top level comments.
+	parentAuthor := c.LinkAuthor
+	if reply {
+		author, ok := cf.authors[parent]
+		if !ok {
+			return false
+		}
+		parentAuthor = author
+	}
+
+	if cf.f.RepliesToOP && (parentAuthor == "" || !strings.EqualFold(parentAuthor, c.LinkAuthor)) {
+		return false
+	}
+	if len(cf.f.RepliesTo) > 0 && !namedIn(parentAuthor, cf.f.RepliesTo) {
+		return false
+	}
+	return true
+}
+
+// remember records the comment's author. The caller must hold the lock.
+func (cf *commentFilter) remember(c *reddit.Comment) {
+	if c.Name == "" {
+		return
+	}
+	if _, ok := cf.authors[c.Name]; ok {
+		return
+	}
+
+	cf.authors[c.Name] = c.Author
+	cf.order = append(cf.order, c.Name)
+	if len(cf.order) > maxParents {
+		delete(cf.authors, cf.order[0])
+		cf.order = cf.order[1:]
+	}
+}
+
+// namedIn reports whether the name is one of the names, regardless of case.
+func namedIn(name string, names []string) bool {
+	for _, n := range names {
+		if strings.EqualFold(name, n) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package streams
+
+import (
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+func TestCommentFilter(t *testing.T) {
+	thread := []*reddit.Comment{
+		{Name: "t1_a", ParentID: "t3_p", Author: "op", LinkAuthor: "op", IsSubmitter: true, Distinguished: "moderator"},
+		{Name: "t1_b", ParentID: "t3_p", Author: "x", LinkAuthor: "op"},
+		{Name: "t1_c", ParentID: "t1_a", Author: "y", LinkAuthor: "op"},
+		{Name: "t1_d", ParentID: "t1_b", Author: "OP", LinkAuthor: "op"},
+		{Name: "t1_e", ParentID: "t1_d", Author: "bot", LinkAuthor: "op"},
+		{Name: "t1_f", ParentID: "t1_e", Author: "x", LinkAuthor: "op"},
+		// Its parent was never seen.
+		{Name: "t1_g", ParentID: "t1_z", Author: "x", LinkAuthor: "op"},
+		{Name: "t1_h", LinkID: "t3_q", ParentID: "t3_q", Author: "op", LinkAuthor: "op", IsSubmitter: true},
+	}
+
+	for _, test := range []struct {
+		name     string
+		f        CommentFilter
+		expected string
+	}{
+		{"top level", CommentFilter{TopLevel: true}, "abh"},
+		{"submitter", CommentFilter{Submitter: true}, "adh"},
+		{"replies to op", CommentFilter{RepliesToOP: true}, "abceh"},
+		{"replies to bot", CommentFilter{RepliesTo: []string{"Bot"}}, "f"},
+		{"op's replies to op", CommentFilter{Submitter: true, RepliesToOP: true}, "ah"},
+		{"op in thread", CommentFilter{Threads: []string{"t3_q"}, Submitter: true}, "h"},
+		{"distinguished", CommentFilter{Distinguished: true}, "a"},
+	} {
+		cf := newCommentFilter(test.f)
+		got := ""
+		for _, c := range thread {
+			if cf.passes(c) {
+				got += c.Name[3:]
+			}
+		}
+		if got != test.expected {
+			t.Errorf("%s: passed %q; wanted %q", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestPostFilter(t *testing.T) {
+	passes := MatchPosts(PostFilter{Distinguished: true})
+	if passes(&reddit.Post{}) {
+		t.Errorf("passed a post nobody distinguished")
+	}
+	if !passes(&reddit.Post{Distinguished: "moderator"}) {
+		t.Errorf("did not pass a moderator's post")
+	}
+	if !MatchPosts(PostFilter{})(&reddit.Post{}) {
+		t.Errorf("the zero filter did not pass a post")
+	}
+}
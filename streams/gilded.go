@@ -0,0 +1,125 @@
+package streams
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// GildedConfig configures a gilded stream.
+type GildedConfig struct {
+	// Interval is how often the gilded listing is read.
+	Interval time.Duration
+}
+
+// Awarded is a post or comment which appeared in a gilded listing. One of
+// Post and Comment is set.
+type Awarded struct {
+	Post    *reddit.Post
+	Comment *reddit.Comment
+	// Awards are the awards the post or comment had received when it
+	// appeared, and Total how many there were in all.
+	Awards []reddit.Award
+	Total  int32
+}
+
+// Gilded returns a stream of the posts and comments of the given subreddits
+// as they first appear in their gilded listing, with the awards they received.
+// Each is dispatched once, however many more awards it receives later. The
+// elements of each poll are dispatched most awarded first, posts before
+// comments when they tie. The elements gilded when the stream starts are not
+// dispatched.
+//
+// Each poll consumes one interval of the handle.
+func Gilded(
+	scanner reddit.Scanner,
+	kill <-chan bool,
+	errs chan<- error,
+	cfg GildedConfig,
+	subreddits ...string,
+) (
+	<-chan *Awarded,
+	error,
+) {
+	if cfg.Interval <= 0 {
+		return nil, intervalErr
+	}
+
+	path := "/r/" + strings.Join(subreddits, "+") + "/gilded"
+	params := map[string]string{"limit": "100", "raw_json": "1"}
+	h, err := scanner.ListingWithParams(path, params)
+	if err != nil {
+		return nil, err
+	}
+
+	r := newRisen()
+	observeGilded(r, h)
+
+	gilded := make(chan *Awarded)
+	go func() {
+		defer close(gilded)
+		ticker := newPoller(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-kill:
+				return
+			case <-ticker.C:
+				h, err := scanner.ListingWithParams(path, params)
+				if err != nil {
+					select {
+					case errs <- err:
+					case <-kill:
+						return
+					}
+					continue
+				}
+
+				for _, a := range observeGilded(r, h) {
+					select {
+					case gilded <- a:
+					case <-kill:
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return gilded, nil
+}
+
+// observeGilded returns the posts and comments in a read of a gilded listing
+// which were not seen in it before, most awarded first.
+func observeGilded(r *risen, h reddit.Harvest) []*Awarded {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var awarded []*Awarded
+	for _, p := range h.Posts {
+		if r.add(p.Name) {
+			awarded = append(awarded, &Awarded{
+				Post:   p,
+				Awards: p.Awards,
+				Total:  p.TotalAwards,
+			})
+		}
+	}
+	for _, c := range h.Comments {
+		if r.add(c.Name) {
+			awarded = append(awarded, &Awarded{
+				Comment: c,
+				Awards:  c.Awards,
+				Total:   c.TotalAwards,
+			})
+		}
+	}
+	r.trim()
+
+	sort.SliceStable(awarded, func(i, j int) bool {
+		return awarded[i].Total > awarded[j].Total
+	})
+	return awarded
+}
@@ -0,0 +1,49 @@
+package streams
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+func TestObserveGilded(t *testing.T) {
+	r := newRisen()
+
+	gold := []reddit.Award{{Name: "Gold", Count: 1}}
+	for i, test := range []struct {
+		h        reddit.Harvest
+		expected string
+	}{
+		{
+			reddit.Harvest{
+				Posts:    []*reddit.Post{{Name: "t3_a", Awards: gold, TotalAwards: 1}},
+				Comments: []*reddit.Comment{{Name: "t1_b", TotalAwards: 3}},
+			},
+			"[t1_b@3 t3_a@1]",
+		},
+		// Elements gilded again are not dispatched again.
+		{
+			reddit.Harvest{
+				Posts:    []*reddit.Post{{Name: "t3_a", TotalAwards: 2}},
+				Comments: []*reddit.Comment{{Name: "t1_c", TotalAwards: 2}},
+			},
+			"[t1_c@2]",
+		},
+	} {
+		var got []string
+		for _, a := range observeGilded(r, test.h) {
+			name := ""
+			if a.Post != nil {
+				name = a.Post.Name
+			} else {
+				name = a.Comment.Name
+			}
+			got = append(got, fmt.Sprintf("%s@%d", name, a.Total))
+		}
+
+		if fmt.Sprint(got) != test.expected {
+			t.Errorf("%d: got %v; wanted %s", i, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package monitor
+
+import (
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// note records the creation time of the youngest element in a harvest the
+// monitor has seen.
+func (m *monitor) note(h reddit.Harvest) {
+	for _, born := range birthdays(h) {
+		if born > m.newest {
+			m.newest = born
+		}
+	}
+}
+
+// reanchor finds the monitor's place in the listing again when every tip it
+// knows is dead, which would otherwise leave it reading empty pages forever.
+// It reads the listing from its current tip back to the youngest element the
+// monitor saw, anchors on the current tip, and returns the elements it missed
+// in between.
+func (m *monitor) reanchor() (reddit.Harvest, error) {
+	names, h, err := m.harvest("", maxLimit)
+	if err != nil {
+		return reddit.Harvest{}, err
+	}
+
+	all := h
+	for page := 1; page < maxResumePages && len(names) > 0; page++ {
+		if oldest(all) <= m.newest {
+			break
+		}
+
+		read := m.sorter.Sort(all)
+		older, err := m.older(read[len(read)-1])
+		if err != nil {
+			return reddit.Harvest{}, err
+		}
+		if len(m.sorter.Sort(older)) == 0 {
+			break
+		}
+		all = merge(all, older)
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range m.tip {
+		seen[name] = true
+	}
+	missed := since(all, m.newest, seen)
+
+	if len(names) > 0 {
+		m.tip = names
+		if len(m.tip) > maxTipSize {
+			m.tip = m.tip[:maxTipSize]
+		}
+	} else {
+		m.tip = defaultTip
+	}
+	m.blanks = 0
+	m.note(all)
+
+	return missed, nil
+}
+
+// oldest returns the creation time of the oldest element in a harvest.
+func oldest(h reddit.Harvest) uint64 {
+	var min uint64
+	first := true
+	for _, born := range birthdays(h) {
+		if first || born < min {
+			min, first = born, false
+		}
+	}
+	return min
+}
+
+func merge(a, b reddit.Harvest) reddit.Harvest {
+	return reddit.Harvest{
+		Posts:    append(a.Posts, b.Posts...),
+		Comments: append(a.Comments, b.Comments...),
+		Messages: append(a.Messages, b.Messages...),
+	}
+}
+
+// since returns the elements of a harvest created at or after the cutoff which
+// are not in seen.
+func since(h reddit.Harvest, cutoff uint64, seen map[string]bool) reddit.Harvest {
+	var fresh reddit.Harvest
+	for _, p := range h.Posts {
+		if p.CreatedUTC >= cutoff && !seen[p.Name] {
+			fresh.Posts = append(fresh.Posts, p)
+		}
+	}
+	for _, c := range h.Comments {
+		if c.CreatedUTC >= cutoff && !seen[c.Name] {
+			fresh.Comments = append(fresh.Comments, c)
+		}
+	}
+	for _, msg := range h.Messages {
+		if msg.CreatedUTC >= cutoff && !seen[msg.Name] {
+			fresh.Messages = append(fresh.Messages, msg)
+		}
+	}
+	return fresh
+}
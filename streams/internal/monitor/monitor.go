@@ -2,9 +2,12 @@
 package monitor
 
 import (
-	"github.com/turnage/graw/reddit"
+	"strconv"
+	"time"
 
-	"github.com/turnage/graw/streams/internal/rsort"
+	"github.com/aldarisbm/graw/reddit"
+
+	"github.com/aldarisbm/graw/streams/internal/rsort"
 )
 
 const (
@@ -16,6 +19,13 @@ const (
 	// maxTipSize is the maximum size of the tip log (number of backup tips
 	// + the current tip).
 	maxTipSize = 20
+	// checkSize is how many elements are fetched after the oldest backup
+	// tip to check the others' health. Backup tips were consecutive in the
+	// listing when they were seen, so this leaves room for elements which
+	// turn up between them later, such as posts approved out of the spam
+	// filter, without fetching a full page every time a quiet listing
+	// goes blank.
+	checkSize = 2 * maxTipSize
 )
 
 // defaultTip is the blank reference point in a Reddit listing, which asks for
@@ -39,6 +49,19 @@ type Config struct {
 
 	// Sorter sorts the monitor's new listing elements.
 	Sorter rsort.Sorter
+
+	// From, if set, is the fullname of an element in the listing which the
+	// monitor resumes from instead of the current tip of the listing. If
+	// the element is not in the listing, the monitor starts from the tip.
+	From string
+
+	// Since, if set, resumes the monitor from the youngest element in the
+	// listing created before this time.
+	Since time.Time
+
+	// Limit, if set, is how many elements each update asks for, up to
+	// 100. If zero, the monitor sizes its pages to the volume it sees.
+	Limit int
 }
 
 type monitor struct {
@@ -55,6 +78,12 @@ type monitor struct {
 
 	scanner reddit.Scanner
 	sorter  rsort.Sorter
+	pager   pager
+	// newest is the creation time of the youngest element the monitor has
+	// seen, or zero if it has seen none. It lets the monitor tell which
+	// elements it missed when it must find its place in the listing
+	// again.
+	newest uint64
 }
 
 // New provides a monitor for the listing endpoint.
@@ -65,8 +94,18 @@ func New(c Config) (Monitor, error) {
 		scanner:        c.Scanner,
 		sorter:         c.Sorter,
 	}
-
-	if err := m.sync(); err != nil {
+	m.pager.fixed = c.Limit
+
+	var err error
+	switch {
+	case c.From != "":
+		err = m.resumeFrom(c.From)
+	case !c.Since.IsZero():
+		err = m.resumeSince(c.Since)
+	default:
+		err = m.sync()
+	}
+	if err != nil {
 		return nil, err
 	}
 
@@ -77,26 +116,39 @@ func New(c Config) (Monitor, error) {
 // new content to the bot for processing.
 func (m *monitor) Update() (reddit.Harvest, error) {
 	if m.blanks > blankThreshold {
-		return reddit.Harvest{}, m.fixTip()
+		return m.fixTip()
 	}
 
-	names, harvest, err := m.harvest(m.tip[0])
+	names, harvest, err := m.harvest(m.tip[0], m.pager.limit())
+	if err == nil {
+		m.pager.observe(len(names))
+	}
+	m.note(harvest)
 	m.updateTip(names)
 	return harvest, err
 }
 
-// harvest fetches from the listing any posts after the given reference post,
-// and returns those posts and a reverse chronologically sorted list of their
-// names.
-func (m *monitor) harvest(ref string) ([]string, reddit.Harvest, error) {
-	h, err := m.scanner.Listing(m.path, ref)
+// harvest fetches from the listing up to limit posts after the given reference
+// post, and returns those posts and a reverse chronologically sorted list of
+// their names. Reddit answers with the posts nearest the reference, so a
+// small limit costs nothing but the posts a caller has no use for.
+func (m *monitor) harvest(ref string, limit int) (
+	[]string,
+	reddit.Harvest,
+	error,
+) {
+	h, err := m.scanner.ListingWithParams(m.path, map[string]string{
+		"before": ref,
+		"limit":  strconv.Itoa(limit),
+	})
 	return m.sorter.Sort(h), h, err
 }
 
 // sync fetches the current tip of a listing endpoint, so that grawbots crawling
 // forward in time don't treat it as a new post, or reprocess it when restarted.
 func (m *monitor) sync() error {
-	names, _, err := m.harvest("")
+	names, h, err := m.harvest("", maxTipSize)
+	m.note(h)
 	if len(names) > 0 {
 		m.tip = names
 	} else {
@@ -123,21 +175,28 @@ func (m *monitor) updateTip(names []string) {
 // fixTip checks all of the stored backup tips for health. If the post at the
 // front has been deleted or caught in a spam filter, the feed will die and we
 // will stop getting posts. This will adjust backward if a tip is dead and
-// remove any other dead tips in the list. Returns whether the tip was broken.
-func (m *monitor) fixTip() error {
-	names, _, err := m.harvest(m.tip[len(m.tip)-1])
+// remove any other dead tips in the list. If every tip is dead, it reanchors
+// the monitor, returning the elements which were missed.
+func (m *monitor) fixTip() (reddit.Harvest, error) {
+	names, _, err := m.harvest(m.tip[len(m.tip)-1], checkSize)
 	if err != nil {
-		return err
+		return reddit.Harvest{}, err
 	}
 
 	// If none of our backup tips were returned, most likely the last backup
-	// tip is dead and this check was meaningless.
+	// tip is dead and this check was meaningless. If we know how far we
+	// got, we can find our place again now, rather than shaving one tip
+	// per check.
 	if len(names) == 0 {
+		if m.newest > 0 {
+			return m.reanchor()
+		}
+
 		m.tip = m.tip[:len(m.tip)-1]
 		if len(m.tip) == 0 {
 			m.tip = defaultTip
 		}
-		return nil
+		return reddit.Harvest{}, nil
 	}
 
 	// n^2 because your cycles don't matter to me & n <= maxTipSize
@@ -154,5 +213,5 @@ func (m *monitor) fixTip() error {
 	}
 
 	m.blanks = 0
-	return nil
+	return reddit.Harvest{}, nil
 }
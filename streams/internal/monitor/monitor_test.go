@@ -1,10 +1,12 @@
 package monitor
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 
-	"github.com/turnage/graw/reddit"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/streams/internal/rsort"
 )
 
 type mockScanner struct{}
@@ -18,6 +20,14 @@ func (m *mockScanner) ListingWithParams(_ string, _ map[string]string) (reddit.H
 	return reddit.Harvest{}, nil
 }
 
+func (m *mockScanner) ListingPage(_ string, _ map[string]string) (reddit.Harvest, reddit.ListingInfo, error) {
+	return reddit.Harvest{}, reddit.ListingInfo{}, nil
+}
+
+func (m *mockScanner) Search(_, _ string, _ reddit.SearchOptions) (reddit.Harvest, error) {
+	return reddit.Harvest{}, nil
+}
+
 type mockSorter struct {
 	names []string
 }
@@ -157,3 +167,120 @@ func TestTipStaysNonNil(t *testing.T) {
 		t.Errorf("error in second update: %v", err)
 	}
 }
+
+func TestPager(t *testing.T) {
+	p := &pager{}
+	for i, test := range []struct {
+		n     int
+		limit int
+	}{
+		{-1, 100},
+		{3, 25},
+		{20, 40},
+		{30, 60},
+		// A full page jumps to the largest page.
+		{60, 100},
+		{5, 100},
+	} {
+		if test.n >= 0 {
+			p.observe(test.n)
+		}
+		if l := p.limit(); l != test.limit {
+			t.Errorf("%d: got limit %d; wanted %d", i, l, test.limit)
+		}
+	}
+
+	for i := 0; i < pagerWindow; i++ {
+		p.observe(1)
+	}
+	if l := p.limit(); l != minLimit {
+		t.Errorf("got limit %d for a quiet listing; wanted %d", l, minLimit)
+	}
+
+	fixed := &pager{fixed: 50}
+	fixed.observe(50)
+	if l := fixed.limit(); l != 50 {
+		t.Errorf("got limit %d; wanted the fixed limit", l)
+	}
+}
+
+func TestDeletedTipInQuietListing(t *testing.T) {
+	sc := &pagedScanner{}
+	for i := 30; i > 0; i-- {
+		sc.posts = append(sc.posts, &reddit.Post{
+			Name:       fmt.Sprintf("t3_%d", i),
+			CreatedUTC: uint64(i),
+		})
+	}
+
+	m, err := New(Config{Scanner: sc, Sorter: rsort.New()})
+	if err != nil {
+		t.Fatalf("error creating monitor: %v", err)
+	}
+
+	// The tip is deleted, so the listing looks quiet until the monitor
+	// checks its backup tips.
+	sc.posts = sc.posts[1:]
+	for i := 0; i <= blankThreshold+1; i++ {
+		if h, err := m.Update(); err != nil || len(h.Posts) > 0 {
+			t.Fatalf("%d: got %v, %v; wanted a blank update", i, h.Posts, err)
+		}
+	}
+
+	sc.posts = append([]*reddit.Post{{Name: "t3_31", CreatedUTC: 31}}, sc.posts...)
+	h, err := m.Update()
+	if err != nil {
+		t.Fatalf("error in update: %v", err)
+	}
+
+	if len(h.Posts) != 1 || h.Posts[0].Name != "t3_31" {
+		t.Errorf("got %v; wanted only the new post", h.Posts)
+	}
+}
+
+func TestReanchor(t *testing.T) {
+	sc := &pagedScanner{}
+	for i := 30; i > 0; i-- {
+		sc.posts = append(sc.posts, &reddit.Post{
+			Name:       fmt.Sprintf("t3_%d", i),
+			CreatedUTC: uint64(i),
+		})
+	}
+
+	m, err := New(Config{Scanner: sc, Sorter: rsort.New()})
+	if err != nil {
+		t.Fatalf("error creating monitor: %v", err)
+	}
+
+	// Every tip is deleted, and posts arrive after them, which before
+	// polling on a dead tip can't see.
+	sc.posts = append([]*reddit.Post{
+		{Name: "t3_33", CreatedUTC: 33},
+		{Name: "t3_32", CreatedUTC: 32},
+		{Name: "t3_31", CreatedUTC: 31},
+	}, sc.posts[maxTipSize:]...)
+
+	var missed []string
+	for i := 0; i <= blankThreshold+1; i++ {
+		h, err := m.Update()
+		if err != nil {
+			t.Fatalf("%d: error in update: %v", i, err)
+		}
+		for _, p := range h.Posts {
+			missed = append(missed, p.Name)
+		}
+	}
+
+	if fmt.Sprint(missed) != "[t3_33 t3_32 t3_31]" {
+		t.Errorf("got missed posts %v; wanted t3_31 to t3_33", missed)
+	}
+
+	sc.posts = append([]*reddit.Post{{Name: "t3_34", CreatedUTC: 34}}, sc.posts...)
+	h, err := m.Update()
+	if err != nil {
+		t.Fatalf("error in update: %v", err)
+	}
+	if len(h.Posts) != 1 || h.Posts[0].Name != "t3_34" {
+		t.Errorf("got %v after reanchoring; wanted only t3_34", h.Posts)
+	}
+}
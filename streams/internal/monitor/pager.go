@@ -0,0 +1,72 @@
+package monitor
+
+const (
+	// minLimit and maxLimit bound how many elements an adaptive monitor
+	// asks for per update. Reddit serves at most maxLimit.
+	minLimit = 25
+	maxLimit = 100
+	// pagerWindow is how many recent updates an adaptive monitor sizes its
+	// pages for.
+	pagerWindow = 10
+)
+
+// pager picks how many elements each update asks the listing for. Small pages
+// are cheaper for Reddit to serve and for us to parse, but a page which comes
+// back full means the listing is busier than the page, and the monitor falls
+// behind until it catches up.
+type pager struct {
+	// fixed, if positive, is used as the limit of every update.
+	fixed int
+	// size is the adaptive limit; zero until the first update.
+	size int
+	// counts holds how many elements the last updates returned.
+	counts []int
+}
+
+// limit returns the limit for the next update.
+func (p *pager) limit() int {
+	switch {
+	case p.fixed > maxLimit:
+		return maxLimit
+	case p.fixed > 0:
+		return p.fixed
+	case p.size == 0:
+		return maxLimit
+	}
+	return p.size
+}
+
+// observe adapts the limit to the number of elements an update returned. A
+// full page jumps straight to the largest limit; otherwise the limit is twice
+// the busiest recent update, for headroom.
+func (p *pager) observe(n int) {
+	if p.fixed > 0 {
+		return
+	}
+
+	full := n >= p.limit()
+	p.counts = append(p.counts, n)
+	if len(p.counts) > pagerWindow {
+		p.counts = p.counts[1:]
+	}
+
+	if full {
+		p.size = maxLimit
+		return
+	}
+
+	peak := 0
+	for _, c := range p.counts {
+		if c > peak {
+			peak = c
+		}
+	}
+
+	p.size = 2 * peak
+	if p.size < minLimit {
+		p.size = minLimit
+	}
+	if p.size > maxLimit {
+		p.size = maxLimit
+	}
+}
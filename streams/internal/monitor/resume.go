@@ -0,0 +1,97 @@
+package monitor
+
+import (
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// maxResumePages is the number of listing pages the monitor will search back
+// through for a starting point. Reddit does not keep listings much longer
+// than this.
+const maxResumePages = 10
+
+// resumeFrom sets the tip to the named element if it is in the listing, and
+// syncs to the current tip of the listing otherwise.
+func (m *monitor) resumeFrom(name string) error {
+	// Reddit answers with an empty listing for references that are not in
+	// the listing, so the element must have either younger or older
+	// neighbors for us to know it is there.
+	newer, _, err := m.harvest(name, 1)
+	if err != nil {
+		return err
+	}
+
+	if len(newer) == 0 {
+		older, err := m.older(name)
+		if err != nil {
+			return err
+		}
+
+		if len(older.Posts)+len(older.Comments)+len(older.Messages) == 0 {
+			return m.sync()
+		}
+	}
+
+	m.tip = []string{name}
+	return nil
+}
+
+// resumeSince sets the tip to the youngest element in the listing created
+// before the given time, searching back through the listing as far as Reddit
+// allows. If every element found is younger, the oldest one is used.
+func (m *monitor) resumeSince(since time.Time) error {
+	cutoff := uint64(since.Unix())
+	ref := ""
+	for i := 0; i < maxResumePages; i++ {
+		h, err := m.older(ref)
+		if err != nil {
+			return err
+		}
+
+		names := m.sorter.Sort(h)
+		if len(names) == 0 {
+			break
+		}
+
+		births := birthdays(h)
+		for _, name := range names {
+			if births[name] <= cutoff {
+				m.tip = []string{name}
+				m.newest = births[name]
+				return nil
+			}
+		}
+
+		ref = names[len(names)-1]
+	}
+
+	if ref == "" {
+		m.tip = defaultTip
+	} else {
+		m.tip = []string{ref}
+	}
+	return nil
+}
+
+// older fetches the page of the listing older than the given reference
+// element.
+func (m *monitor) older(ref string) (reddit.Harvest, error) {
+	return m.scanner.ListingWithParams(m.path, map[string]string{"after": ref})
+}
+
+// birthdays maps the names of the elements in a harvest to their creation
+// times.
+func birthdays(h reddit.Harvest) map[string]uint64 {
+	births := make(map[string]uint64)
+	for _, p := range h.Posts {
+		births[p.Name] = p.CreatedUTC
+	}
+	for _, c := range h.Comments {
+		births[c.Name] = c.CreatedUTC
+	}
+	for _, msg := range h.Messages {
+		births[msg.Name] = msg.CreatedUTC
+	}
+	return births
+}
@@ -0,0 +1,146 @@
+package monitor
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/streams/internal/rsort"
+)
+
+// pagedScanner serves a listing of posts, youngest first.
+type pagedScanner struct {
+	posts []*reddit.Post
+}
+
+func (p *pagedScanner) index(name string) int {
+	for i, post := range p.posts {
+		if post.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (p *pagedScanner) Listing(_, before string) (reddit.Harvest, error) {
+	if before == "" {
+		return reddit.Harvest{Posts: p.posts}, nil
+	}
+
+	i := p.index(before)
+	if i < 0 {
+		return reddit.Harvest{}, nil
+	}
+	return reddit.Harvest{Posts: p.posts[:i]}, nil
+}
+
+func (p *pagedScanner) ListingWithParams(_ string, params map[string]string) (reddit.Harvest, error) {
+	if before, ok := params["before"]; ok {
+		return p.newer(before, params["limit"])
+	}
+
+	if params["after"] == "" && len(p.posts) > 0 {
+		return reddit.Harvest{Posts: p.posts[:1]}, nil
+	}
+
+	i := p.index(params["after"])
+	if i < 0 || i+1 >= len(p.posts) {
+		return reddit.Harvest{}, nil
+	}
+	return reddit.Harvest{Posts: p.posts[i+1 : i+2]}, nil
+}
+
+func (p *pagedScanner) ListingPage(path string, params map[string]string) (reddit.Harvest, reddit.ListingInfo, error) {
+	h, err := p.ListingWithParams(path, params)
+	return h, reddit.ListingInfo{}, err
+}
+
+func (p *pagedScanner) Search(_, _ string, _ reddit.SearchOptions) (reddit.Harvest, error) {
+	return reddit.Harvest{}, nil
+}
+
+// newer serves the posts younger than before which are nearest to it, as
+// Reddit does.
+func (p *pagedScanner) newer(before, limit string) (reddit.Harvest, error) {
+	n, err := strconv.Atoi(limit)
+	if err != nil {
+		return reddit.Harvest{}, err
+	}
+
+	if before == "" {
+		if n > len(p.posts) {
+			n = len(p.posts)
+		}
+		return reddit.Harvest{Posts: p.posts[:n]}, nil
+	}
+
+	i := p.index(before)
+	if i < 0 {
+		return reddit.Harvest{}, nil
+	}
+
+	from := i - n
+	if from < 0 {
+		from = 0
+	}
+	return reddit.Harvest{Posts: p.posts[from:i]}, nil
+}
+
+func listing() *pagedScanner {
+	return &pagedScanner{
+		posts: []*reddit.Post{
+			{Name: "t3_c", CreatedUTC: 30},
+			{Name: "t3_b", CreatedUTC: 20},
+			{Name: "t3_a", CreatedUTC: 10},
+		},
+	}
+}
+
+func TestResume(t *testing.T) {
+	for i, test := range []struct {
+		from  string
+		since time.Time
+		tip   []string
+	}{
+		{tip: []string{"t3_c", "t3_b", "t3_a"}},
+		{from: "t3_b", tip: []string{"t3_b"}},
+		{from: "t3_c", tip: []string{"t3_c"}},
+		{from: "t3_z", tip: []string{"t3_c", "t3_b", "t3_a"}},
+		{since: time.Unix(25, 0), tip: []string{"t3_b"}},
+		{since: time.Unix(10, 0), tip: []string{"t3_a"}},
+		{since: time.Unix(5, 0), tip: []string{"t3_a"}},
+		{since: time.Unix(35, 0), tip: []string{"t3_c"}},
+	} {
+		m, err := New(Config{
+			Scanner: listing(),
+			Sorter:  rsort.New(),
+			From:    test.from,
+			Since:   test.since,
+		})
+		if err != nil {
+			t.Errorf("%d: error creating monitor: %v", i, err)
+			continue
+		}
+
+		if tip := m.(*monitor).tip; !reflect.DeepEqual(tip, test.tip) {
+			t.Errorf("%d: got tip %v; wanted %v", i, tip, test.tip)
+		}
+	}
+}
+
+func TestResumeSinceEmptyListing(t *testing.T) {
+	m, err := New(Config{
+		Scanner: &pagedScanner{},
+		Sorter:  rsort.New(),
+		Since:   time.Unix(25, 0),
+	})
+	if err != nil {
+		t.Fatalf("error creating monitor: %v", err)
+	}
+
+	if tip := m.(*monitor).tip; !reflect.DeepEqual(tip, defaultTip) {
+		t.Errorf("got tip %v; wanted %v", tip, defaultTip)
+	}
+}
@@ -0,0 +1,251 @@
+package streams
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+const (
+	// liveRetry is how long a live thread stream waits to reconnect after
+	// connecting fails, if it does not poll meanwhile.
+	liveRetry = 30 * time.Second
+	// liveBackfill is how many of a live thread's newest updates are read
+	// after reconnecting, and on each poll, to catch those posted while
+	// disconnected.
+	liveBackfill = 25
+	// maxLiveUpdates is how many updates a live thread stream remembers
+	// dispatching.
+	maxLiveUpdates = 1000
+)
+
+// LiveConfig configures a live thread stream.
+type LiveConfig struct {
+	// Fallback, if set, is how often the thread's updates are polled
+	// while its websocket can't be reached, so they are still delivered,
+	// if late, until it can be again. If zero, the stream waits for the
+	// websocket, retrying every 30 seconds and sending the errors it
+	// meets to errs.
+	Fallback time.Duration
+}
+
+// LiveThread returns a stream of the events of a live thread, pushed from its
+// websocket as they happen, such as the updates posted to it. Websockets drop,
+// so the stream reconnects when its connection fails, and dispatches the
+// updates posted while it was disconnected, up to 25 of them. The stream
+// closes after the thread's LiveCompleteEvent, or once the thread is found
+// complete while reconnecting.
+//
+// The stream consumes an interval of the handle each time it connects, and
+// two each time it polls.
+func LiveThread(
+	live reddit.Live,
+	kill <-chan bool,
+	errs chan<- error,
+	cfg LiveConfig,
+	thread string,
+) (
+	<-chan *reddit.LiveEvent,
+	error,
+) {
+	stream, err := live.ListenLive(thread)
+	if err != nil && (cfg.Fallback <= 0 || err == reddit.LiveCompleteErr) {
+		return nil, err
+	}
+
+	f := &liveFollow{
+		live:   live,
+		kill:   kill,
+		errs:   errs,
+		cfg:    cfg,
+		thread: thread,
+		events: make(chan *reddit.LiveEvent),
+		seen:   newLiveSeen(),
+	}
+	go f.run(stream)
+	return f.events, nil
+}
+
+// liveFollow follows a live thread for a stream.
+type liveFollow struct {
+	live   reddit.Live
+	kill   <-chan bool
+	errs   chan<- error
+	cfg    LiveConfig
+	thread string
+	events chan *reddit.LiveEvent
+	seen   *liveSeen
+}
+
+// run dispatches the thread's events, beginning with those of the stream,
+// which is nil if it could not connect.
+func (f *liveFollow) run(stream reddit.LiveStream) {
+	defer close(f.events)
+
+	// The current stream is closed on kill to unblock its read.
+	var mu sync.Mutex
+	current := stream
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-f.kill:
+			mu.Lock()
+			if current != nil {
+				current.Close()
+			}
+			mu.Unlock()
+		case <-done:
+		}
+	}()
+
+	for {
+		if stream == nil {
+			if stream = f.reconnect(); stream == nil {
+				return
+			}
+
+			mu.Lock()
+			current = stream
+			mu.Unlock()
+			select {
+			case <-f.kill:
+				stream.Close()
+				return
+			default:
+			}
+		}
+
+		e, err := stream.Next()
+		if err != nil {
+			stream.Close()
+			stream = nil
+			continue
+		}
+
+		if e.Update != nil && !f.seen.observe(e.Update.Name) {
+			continue
+		}
+		if !f.dispatch(e) {
+			stream.Close()
+			return
+		}
+
+		if e.Type == reddit.LiveCompleteEvent {
+			stream.Close()
+			return
+		}
+	}
+}
+
+// reconnect connects to the thread's websocket again, polling its updates
+// until it can if the stream falls back to polling. It returns nil if the
+// stream should end, because kill was closed or the thread is complete.
+func (f *liveFollow) reconnect() reddit.LiveStream {
+	for {
+		select {
+		case <-f.kill:
+			return nil
+		default:
+		}
+
+		stream, err := f.live.ListenLive(f.thread)
+		if err == nil {
+			if !f.poll() {
+				stream.Close()
+				return nil
+			}
+			return stream
+		}
+		if err == reddit.LiveCompleteErr {
+			f.poll()
+			return nil
+		}
+
+		wait := liveRetry
+		if f.cfg.Fallback > 0 {
+			if !f.poll() {
+				return nil
+			}
+			wait = f.cfg.Fallback
+		} else if !f.send(err) {
+			return nil
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-f.kill:
+			return nil
+		}
+	}
+}
+
+// poll dispatches the thread's newest updates which were not seen, oldest
+// first. It reports whether the stream should go on.
+func (f *liveFollow) poll() bool {
+	updates, err := f.live.LiveUpdates(f.thread, liveBackfill)
+	if err != nil {
+		return f.send(err)
+	}
+
+	for i := len(updates) - 1; i >= 0; i-- {
+		if !f.seen.observe(updates[i].Name) {
+			continue
+		}
+		if !f.dispatch(&reddit.LiveEvent{
+			Thread: f.thread,
+			Type:   reddit.LiveUpdateEvent,
+			Update: updates[i],
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// dispatch sends an event down the stream, reporting false if kill was closed
+// first.
+func (f *liveFollow) dispatch(e *reddit.LiveEvent) bool {
+	select {
+	case f.events <- e:
+		return true
+	case <-f.kill:
+		return false
+	}
+}
+
+// send sends an error to errs, reporting false if kill was closed first.
+func (f *liveFollow) send(err error) bool {
+	select {
+	case f.errs <- err:
+		return true
+	case <-f.kill:
+		return false
+	}
+}
+
+// liveSeen remembers the updates a live thread stream dispatched.
+type liveSeen struct {
+	names map[string]bool
+	order []string
+}
+
+func newLiveSeen() *liveSeen {
+	return &liveSeen{names: make(map[string]bool)}
+}
+
+// observe records an update, and reports whether it was not seen before.
+func (s *liveSeen) observe(name string) bool {
+	if s.names[name] {
+		return false
+	}
+
+	s.names[name] = true
+	s.order = append(s.order, name)
+	if len(s.order) > maxLiveUpdates {
+		delete(s.names, s.order[0])
+		s.order = s.order[1:]
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package streams
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// liveConn is a connection to a live thread which announces its events, then
+// drops.
+type liveConn struct {
+	events chan *reddit.LiveEvent
+}
+
+func (c *liveConn) Next() (*reddit.LiveEvent, error) {
+	if e, ok := <-c.events; ok {
+		return e, nil
+	}
+	return nil, fmt.Errorf("connection dropped")
+}
+
+func (c *liveConn) Close() error {
+	return nil
+}
+
+// liveThread is a live thread which hands out its connections in order, nil
+// ones failing, and lists its updates for backfills.
+type liveThread struct {
+	reddit.Live
+	conns   chan *liveConn
+	updates []*reddit.LiveUpdate
+}
+
+func (l *liveThread) ListenLive(thread string) (reddit.LiveStream, error) {
+	c, ok := <-l.conns
+	if !ok {
+		return nil, reddit.LiveCompleteErr
+	}
+	if c == nil {
+		return nil, fmt.Errorf("websocket unreachable")
+	}
+	return c, nil
+}
+
+func (l *liveThread) LiveUpdates(thread string, limit int) ([]*reddit.LiveUpdate, error) {
+	return l.updates, nil
+}
+
+func update(name string) *reddit.LiveEvent {
+	return &reddit.LiveEvent{
+		Type:   reddit.LiveUpdateEvent,
+		Update: &reddit.LiveUpdate{Name: name},
+	}
+}
+
+func TestLiveThread(t *testing.T) {
+	first := &liveConn{events: make(chan *reddit.LiveEvent, 2)}
+	first.events <- update("LiveUpdate_a")
+	first.events <- &reddit.LiveEvent{Type: reddit.LiveActivityEvent, Viewers: 10}
+	close(first.events)
+
+	second := &liveConn{events: make(chan *reddit.LiveEvent, 2)}
+	second.events <- update("LiveUpdate_c")
+	second.events <- &reddit.LiveEvent{Type: reddit.LiveCompleteEvent}
+
+	conns := make(chan *liveConn, 2)
+	conns <- first
+	conns <- second
+	live := &liveThread{
+		conns: conns,
+		// Newest first; a was seen, b was missed while reconnecting.
+		updates: []*reddit.LiveUpdate{{Name: "LiveUpdate_b"}, {Name: "LiveUpdate_a"}},
+	}
+
+	kill := make(chan bool)
+	defer close(kill)
+	events, err := LiveThread(live, kill, make(chan error), LiveConfig{}, "thread")
+	if err != nil {
+		t.Fatalf("error starting stream: %v", err)
+	}
+
+	var got []string
+	for e := range events {
+		if e.Update != nil {
+			got = append(got, e.Update.Name)
+		} else {
+			got = append(got, e.Type)
+		}
+	}
+
+	expected := []string{"LiveUpdate_a", "activity", "LiveUpdate_b", "LiveUpdate_c", "complete"}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("got events %v; wanted %v", got, expected)
+	}
+}
+
+func TestLiveThreadFallback(t *testing.T) {
+	conn := &liveConn{events: make(chan *reddit.LiveEvent, 2)}
+	conn.events <- update("LiveUpdate_c")
+	conn.events <- &reddit.LiveEvent{Type: reddit.LiveCompleteEvent}
+
+	conns := make(chan *liveConn, 2)
+	conns <- nil
+	conns <- conn
+	live := &liveThread{
+		conns:   conns,
+		updates: []*reddit.LiveUpdate{{Name: "LiveUpdate_b"}, {Name: "LiveUpdate_a"}},
+	}
+
+	kill := make(chan bool)
+	defer close(kill)
+	errs := make(chan error, 1)
+	events, err := LiveThread(live, kill, errs, LiveConfig{Fallback: time.Millisecond}, "thread")
+	if err != nil {
+		t.Fatalf("error starting stream: %v", err)
+	}
+
+	var got []string
+	for e := range events {
+		if e.Update != nil {
+			got = append(got, e.Update.Name)
+		} else {
+			got = append(got, e.Type)
+		}
+	}
+
+	// The updates are polled until the websocket is reached.
+	expected := []string{"LiveUpdate_a", "LiveUpdate_b", "LiveUpdate_c", "complete"}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("got events %v; wanted %v", got, expected)
+	}
+	select {
+	case err := <-errs:
+		t.Errorf("unreachable websocket reported while polling: %v", err)
+	default:
+	}
+}
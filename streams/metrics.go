@@ -0,0 +1,21 @@
+package streams
+
+import "github.com/aldarisbm/graw/reddit"
+
+// metered is implemented by the handles which report to a MetricsCollector,
+// such as Bots and Scripts.
+type metered interface {
+	Metrics() reddit.MetricsCollector
+}
+
+// dropped reports n events a stream dropped to the metrics of the handle it
+// reads through, if it has any.
+func dropped(handle interface{}, stream string, n int) {
+	m, ok := handle.(metered)
+	if !ok {
+		return
+	}
+	if c := m.Metrics(); c != nil {
+		c.CountDropped(stream, n)
+	}
+}
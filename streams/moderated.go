@@ -0,0 +1,32 @@
+package streams
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Moderated names every subreddit the scanner's account moderates, for mod
+// bots covering many subreddits with one stream instead of one per
+// subreddit. It can be given to the subreddit, comment, moderation queue, and
+// report streams, alone; the elements of its streams are routed by their
+// Subreddit fields.
+const Moderated = "mod"
+
+// moderatedErr is returned for streams given Moderated among other
+// subreddits, which Reddit can't combine it with.
+var moderatedErr = fmt.Errorf(
+	"the %q multireddit names every moderated subreddit and can't be combined with others",
+	Moderated,
+)
+
+// multireddit returns the path of the multireddit of the subreddits.
+func multireddit(subreddits []string) (string, error) {
+	if len(subreddits) > 1 {
+		for _, s := range subreddits {
+			if strings.EqualFold(s, Moderated) {
+				return "", moderatedErr
+			}
+		}
+	}
+	return "/r/" + strings.Join(subreddits, "+"), nil
+}
@@ -0,0 +1,124 @@
+package streams
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// modmailPage is how many of the most recently active conversations each poll
+// of a modmail stream reads.
+const modmailPage = 100
+
+// ModmailConfig configures a modmail stream.
+type ModmailConfig struct {
+	// Interval is how often the modmail is read.
+	Interval time.Duration
+}
+
+// Modmail returns a stream of the modmail conversations of the given
+// subreddits, or of every subreddit the handle moderates if none are given,
+// in which the participant wrote after the stream started. A conversation is
+// dispatched again each time the participant writes in it again, holding
+// their newest message; moderators' replies do not dispatch it.
+//
+// Each poll consumes one interval of the handle.
+func Modmail(
+	mm reddit.Modmail,
+	kill <-chan bool,
+	errs chan<- error,
+	cfg ModmailConfig,
+	subreddits ...string,
+) (
+	<-chan *reddit.ModmailConversation,
+	error,
+) {
+	if cfg.Interval <= 0 {
+		return nil, intervalErr
+	}
+
+	q := reddit.ModmailQuery{
+		Subreddits: subreddits,
+		Sort:       "recent",
+		Limit:      modmailPage,
+	}
+	w := &modmailWatch{}
+	conversations := make(chan *reddit.ModmailConversation)
+	go func() {
+		defer close(conversations)
+		ticker := newPoller(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-kill:
+				return
+			case <-ticker.C:
+				page, err := mm.Conversations(q)
+				if err != nil {
+					select {
+					case errs <- err:
+					case <-kill:
+						return
+					}
+					continue
+				}
+
+				for _, c := range w.observe(page.Conversations) {
+					select {
+					case conversations <- c:
+					case <-kill:
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return conversations, nil
+}
+
+// modmailWatch remembers when the participants of conversations last wrote.
+type modmailWatch struct {
+	mu      sync.Mutex
+	started bool
+	// since is the newest participant message seen on the first read;
+	// conversations first seen later are dispatched if they are newer.
+	since time.Time
+	// seen holds when the participant last wrote in each conversation of
+	// the last read.
+	seen map[string]time.Time
+}
+
+// observe returns the conversations in a read of the modmail which the
+// participant wrote in since the last read.
+func (w *modmailWatch) observe(
+	conversations []*reddit.ModmailConversation,
+) []*reddit.ModmailConversation {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	seen := make(map[string]time.Time, len(conversations))
+	var updated []*reddit.ModmailConversation
+	for _, c := range conversations {
+		seen[c.ID] = c.LastUserUpdate
+		if !w.started {
+			if c.LastUserUpdate.After(w.since) {
+				w.since = c.LastUserUpdate
+			}
+			continue
+		}
+
+		last, ok := w.seen[c.ID]
+		if !ok {
+			last = w.since
+		}
+		if c.LastUserUpdate.After(last) {
+			updated = append(updated, c)
+		}
+	}
+
+	w.started = true
+	w.seen = seen
+	return updated
+}
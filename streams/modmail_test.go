@@ -0,0 +1,46 @@
+package streams
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+func TestModmailWatch(t *testing.T) {
+	at := func(s int64) time.Time { return time.Unix(s, 0) }
+	conv := func(id string, s int64) *reddit.ModmailConversation {
+		c := &reddit.ModmailConversation{ID: id}
+		if s > 0 {
+			c.LastUserUpdate = at(s)
+		}
+		return c
+	}
+
+	w := &modmailWatch{}
+	for i, test := range []struct {
+		read     []*reddit.ModmailConversation
+		expected []string
+	}{
+		// What is waiting when the stream starts is not dispatched.
+		{[]*reddit.ModmailConversation{conv("a", 10), conv("mods", 0)}, nil},
+		{[]*reddit.ModmailConversation{conv("a", 10)}, nil},
+		{[]*reddit.ModmailConversation{conv("a", 20), conv("b", 15), conv("old", 5)}, []string{"a", "b"}},
+		{[]*reddit.ModmailConversation{conv("b", 15), conv("a", 20)}, nil},
+		{[]*reddit.ModmailConversation{conv("b", 30)}, []string{"b"}},
+	} {
+		var ids []string
+		for _, c := range w.observe(test.read) {
+			ids = append(ids, c.ID)
+		}
+		if len(ids) != len(test.expected) {
+			t.Errorf("%d: got %v; wanted %v", i, ids, test.expected)
+			continue
+		}
+		for j := range ids {
+			if ids[j] != test.expected[j] {
+				t.Errorf("%d: got %v; wanted %v", i, ids, test.expected)
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package streams
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// pollJitter is the most a poll is moved off its interval, as a fraction of
+// it.
+const pollJitter = 0.1
+
+// goldenRatio spreads the start offsets of pollers evenly over their interval
+// however many there are: the fractional parts of its multiples never bunch.
+const goldenRatio = 0.6180339887498949
+
+// pollers numbers the pollers started and jitters their polls.
+var pollers = struct {
+	sync.Mutex
+	n    int
+	rand *rand.Rand
+}{rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// poller ticks about once per interval, as a time.Ticker does, but starts at
+// an offset into its interval staggered from the other pollers, and moves each
+// poll by up to a tenth of the interval at random, so streams polling on the
+// same interval don't spend the rate limit in bursts.
+type poller struct {
+	C    <-chan time.Time
+	stop chan struct{}
+}
+
+func newPoller(interval time.Duration) *poller {
+	pollers.Lock()
+	offset := staggered(pollers.n, interval)
+	pollers.n++
+	pollers.Unlock()
+
+	ticks := make(chan time.Time, 1)
+	p := &poller{C: ticks, stop: make(chan struct{})}
+	go p.run(ticks, offset, interval)
+	return p
+}
+
+func (p *poller) run(ticks chan<- time.Time, offset, interval time.Duration) {
+	timer := time.NewTimer(offset)
+	defer timer.Stop()
+	for {
+		select {
+		case t := <-timer.C:
+			// Like a ticker's, ticks are dropped for slow readers.
+			select {
+			case ticks <- t:
+			default:
+			}
+			timer.Reset(jittered(interval))
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// Stop stops the poller.
+func (p *poller) Stop() {
+	close(p.stop)
+}
+
+// staggered returns when the nth poller first polls, within its first
+// interval. The first poller polls after a full interval, as a ticker would.
+func staggered(n int, interval time.Duration) time.Duration {
+	frac := float64(n) * goldenRatio
+	frac -= float64(int(frac))
+	return time.Duration((1 - frac) * float64(interval))
+}
+
+// jittered returns the interval moved by up to pollJitter of it at random.
+func jittered(interval time.Duration) time.Duration {
+	pollers.Lock()
+	r := pollers.rand.Float64()
+	pollers.Unlock()
+	return interval + time.Duration((2*r-1)*pollJitter*float64(interval))
+}
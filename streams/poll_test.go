@@ -0,0 +1,51 @@
+package streams
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestStaggered(t *testing.T) {
+	interval := time.Minute
+	var offsets []time.Duration
+	for n := 0; n < 10; n++ {
+		offset := staggered(n, interval)
+		if offset <= 0 || offset > interval {
+			t.Errorf("poller %d starts at %v; wanted within %v", n, offset, interval)
+		}
+		offsets = append(offsets, offset)
+	}
+	if offsets[0] != interval {
+		t.Errorf("first poller starts at %v; wanted %v", offsets[0], interval)
+	}
+
+	// Ten pollers leave no gap over twice an even share of the interval.
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	for i := 1; i < len(offsets); i++ {
+		if gap := offsets[i] - offsets[i-1]; gap > interval/5 {
+			t.Errorf("gap of %v between pollers", gap)
+		}
+	}
+}
+
+func TestJittered(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		if d := jittered(time.Minute); d < 54*time.Second || d > 66*time.Second {
+			t.Fatalf("jittered a minute to %v", d)
+		}
+	}
+}
+
+func TestPoller(t *testing.T) {
+	p := newPoller(time.Millisecond)
+	defer p.Stop()
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-p.C:
+		case <-time.After(time.Second):
+			t.Fatalf("poller did not tick")
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package streams
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// Moderation queues a Queue stream can read. Reports have a stream of their
+// own, which counts them; see Reports.
+const (
+	// ModQueue holds the reported posts and comments and those the spam
+	// filter caught, awaiting review.
+	ModQueue = "modqueue"
+	// SpamQueue holds the posts and comments removed as spam.
+	SpamQueue = "spam"
+	// EditedQueue holds the posts and comments edited recently.
+	EditedQueue = "edited"
+)
+
+// QueueConfig configures a moderation queue stream.
+type QueueConfig struct {
+	// Interval is how often the queue is read.
+	Interval time.Duration
+}
+
+// Queued is an element of a moderation queue. Exactly one of Post and
+// Comment is set.
+type Queued struct {
+	// Queue is the queue the element is in, e.g. ModQueue.
+	Queue string
+	// Subreddit is the subreddit the element is in, e.g. to route the
+	// elements of a Moderated queue.
+	Subreddit string
+	Post      *reddit.Post
+	Comment   *reddit.Comment
+}
+
+// Queue returns a stream of the elements entering a moderation queue of the
+// given subreddits, which the scanner must be a moderator of. Each element is
+// dispatched once while it stays in the queue, and again if it leaves and
+// returns. Elements of the EditedQueue are dispatched again on each edit.
+// Elements in the queue when the stream starts are dispatched on its first
+// poll.
+//
+// Each poll consumes one interval of the handle per hundred elements in the
+// queue, up to ten.
+func Queue(
+	scanner reddit.Scanner,
+	kill <-chan bool,
+	errs chan<- error,
+	cfg QueueConfig,
+	queue string,
+	subreddits ...string,
+) (
+	<-chan *Queued,
+	error,
+) {
+	if cfg.Interval <= 0 {
+		return nil, intervalErr
+	}
+
+	multi, err := multireddit(subreddits)
+	if err != nil {
+		return nil, err
+	}
+	path := multi + "/about/" + queue
+	q := newModQueue(queue)
+	queued := make(chan *Queued)
+	go func() {
+		defer close(queued)
+		ticker := newPoller(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-kill:
+				return
+			case <-ticker.C:
+				h, complete, err := readQueue(scanner, path)
+				if err != nil {
+					select {
+					case errs <- err:
+					case <-kill:
+						return
+					}
+					continue
+				}
+
+				for _, e := range q.observe(h, complete) {
+					select {
+					case queued <- e:
+					case <-kill:
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return queued, nil
+}
+
+// modQueue remembers which elements of a moderation queue were dispatched.
+type modQueue struct {
+	mu    sync.Mutex
+	queue string
+	// dispatched holds the keys of elements dispatched while they have
+	// been in the queue.
+	dispatched map[string]bool
+}
+
+func newModQueue(queue string) *modQueue {
+	return &modQueue{queue: queue, dispatched: make(map[string]bool)}
+}
+
+// key identifies an element's stay in the queue. In the edited queue, each
+// edit is a stay of its own.
+func (q *modQueue) key(name string, edited uint64) string {
+	if q.queue == EditedQueue {
+		return name + "@" + strconv.FormatUint(edited, 10)
+	}
+	return name
+}
+
+// observe returns the elements in a read of the queue which should be
+// dispatched. If the read was complete, elements no longer in the queue are
+// forgotten.
+func (q *modQueue) observe(h reddit.Harvest, complete bool) []*Queued {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	seen := make(map[string]bool)
+	var queued []*Queued
+	check := func(key string, e *Queued) {
+		seen[key] = true
+		if !q.dispatched[key] {
+			q.dispatched[key] = true
+			queued = append(queued, e)
+		}
+	}
+
+	for _, p := range h.Posts {
+		check(q.key(p.Name, p.EditedUTC), &Queued{Queue: q.queue, Subreddit: p.Subreddit, Post: p})
+	}
+	for _, c := range h.Comments {
+		check(q.key(c.Name, c.EditedUTC), &Queued{Queue: q.queue, Subreddit: c.Subreddit, Comment: c})
+	}
+
+	if complete {
+		for key := range q.dispatched {
+			if !seen[key] {
+				delete(q.dispatched, key)
+			}
+		}
+	}
+
+	return queued
+}
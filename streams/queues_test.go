@@ -0,0 +1,65 @@
+package streams
+
+import (
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+func TestModQueue(t *testing.T) {
+	for _, test := range []struct {
+		queue    string
+		reads    []reddit.Harvest
+		expected []int
+	}{
+		{
+			queue: ModQueue,
+			reads: []reddit.Harvest{
+				{Posts: []*reddit.Post{{Name: "t3_a"}}},
+				// Staying in the queue, even edited, does not
+				// dispatch it again.
+				{Posts: []*reddit.Post{{Name: "t3_a", EditedUTC: 5}}},
+				{},
+				// Returning to the queue does.
+				{Posts: []*reddit.Post{{Name: "t3_a"}}, Comments: []*reddit.Comment{{Name: "t1_b"}}},
+			},
+			expected: []int{1, 0, 0, 2},
+		},
+		{
+			queue: EditedQueue,
+			reads: []reddit.Harvest{
+				{Comments: []*reddit.Comment{{Name: "t1_b", EditedUTC: 5}}},
+				{Comments: []*reddit.Comment{{Name: "t1_b", EditedUTC: 5}}},
+				{Comments: []*reddit.Comment{{Name: "t1_b", EditedUTC: 9}}},
+			},
+			expected: []int{1, 0, 1},
+		},
+	} {
+		q := newModQueue(test.queue)
+		for i, h := range test.reads {
+			queued := q.observe(h, true)
+			if len(queued) != test.expected[i] {
+				t.Errorf("%s %d: dispatched %d; wanted %d", test.queue, i, len(queued), test.expected[i])
+			}
+			for _, e := range queued {
+				if e.Queue != test.queue {
+					t.Errorf("%s %d: dispatched from queue %q", test.queue, i, e.Queue)
+				}
+			}
+		}
+	}
+}
+
+func TestModeratedQueue(t *testing.T) {
+	if _, err := Queue(nil, nil, nil, QueueConfig{Interval: 1}, ModQueue, Moderated, "golang"); err != moderatedErr {
+		t.Errorf("got %v; wanted %v", err, moderatedErr)
+	}
+
+	queued := newModQueue(ModQueue).observe(reddit.Harvest{
+		Posts:    []*reddit.Post{{Name: "t3_a", Subreddit: "golang"}},
+		Comments: []*reddit.Comment{{Name: "t1_b", Subreddit: "rust"}},
+	}, true)
+	if len(queued) != 2 || queued[0].Subreddit != "golang" || queued[1].Subreddit != "rust" {
+		t.Errorf("elements not tagged with their subreddits: %+v", queued)
+	}
+}
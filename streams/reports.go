@@ -0,0 +1,207 @@
+package streams
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+const (
+	// reportPage is the most elements requested per page of a report queue.
+	reportPage = 100
+	// maxReportPages is the most pages of a report queue read per poll.
+	maxReportPages = 10
+)
+
+// ReportConfig configures a reports stream.
+type ReportConfig struct {
+	// Interval is how often the report queue is read.
+	Interval time.Duration
+	// MinReports is how many reports, summed over every reason, an element
+	// must gather before it is dispatched. If zero, elements are
+	// dispatched on their first report.
+	MinReports int
+}
+
+// Reported is an element in a report queue which gathered enough reports to
+// be dispatched. Exactly one of Post and Comment is set.
+type Reported struct {
+	// Subreddit is the subreddit the element is in, e.g. to route the
+	// elements of a Moderated report queue.
+	Subreddit string
+	Post      *reddit.Post
+	Comment   *reddit.Comment
+	// Reports holds one entry per reason the element was reported for.
+	Reports []reddit.Report
+	// Total is the sum of the reports' counts.
+	Total int
+}
+
+// Reports returns a stream of elements reported in the given subreddits,
+// which the scanner must be a moderator of. Each element is dispatched once,
+// with every report it has, when its reports first reach cfg.MinReports,
+// however often it is reported after that and however many polls it stays
+// in the queue. An element which leaves the queue, e.g. because it was
+// approved, is dispatched again if it is reported back into it.
+//
+// Each poll consumes one interval of the handle per hundred elements in the
+// queue, up to ten.
+func Reports(
+	scanner reddit.Scanner,
+	kill <-chan bool,
+	errs chan<- error,
+	cfg ReportConfig,
+	subreddits ...string,
+) (
+	<-chan *Reported,
+	error,
+) {
+	if cfg.Interval <= 0 {
+		return nil, intervalErr
+	}
+
+	multi, err := multireddit(subreddits)
+	if err != nil {
+		return nil, err
+	}
+	path := multi + "/about/reports"
+	q := newReportQueue(cfg.MinReports)
+	reported := make(chan *Reported)
+	go func() {
+		defer close(reported)
+		ticker := newPoller(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-kill:
+				return
+			case <-ticker.C:
+				h, complete, err := readQueue(scanner, path)
+				if err != nil {
+					select {
+					case errs <- err:
+					case <-kill:
+						return
+					}
+					continue
+				}
+
+				for _, r := range q.observe(h, complete) {
+					select {
+					case reported <- r:
+					case <-kill:
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return reported, nil
+}
+
+// readQueue reads the pages of a moderation queue, and reports whether it
+// read every page.
+func readQueue(scanner reddit.Scanner, path string) (
+	reddit.Harvest,
+	bool,
+	error,
+) {
+	var all reddit.Harvest
+	after := ""
+	for page := 0; page < maxReportPages; page++ {
+		params := map[string]string{
+			"limit":    strconv.Itoa(reportPage),
+			"raw_json": "1",
+		}
+		if after != "" {
+			params["after"] = after
+		}
+
+		h, info, err := scanner.ListingPage(path, params)
+		if err != nil {
+			return all, false, err
+		}
+
+		all.Posts = append(all.Posts, h.Posts...)
+		all.Comments = append(all.Comments, h.Comments...)
+		if info.After == "" {
+			return all, true, nil
+		}
+
+		after = info.After
+	}
+	return all, false, nil
+}
+
+// reportQueue remembers which elements of a report queue were dispatched.
+type reportQueue struct {
+	mu         sync.Mutex
+	minReports int
+	// dispatched holds the fullnames of elements dispatched while they
+	// have been in the queue.
+	dispatched map[string]bool
+}
+
+func newReportQueue(minReports int) *reportQueue {
+	if minReports < 1 {
+		minReports = 1
+	}
+
+	return &reportQueue{
+		minReports: minReports,
+		dispatched: make(map[string]bool),
+	}
+}
+
+// observe returns the elements in a read of the queue which should be
+// dispatched. If the read was complete, elements no longer in the queue are
+// forgotten.
+func (q *reportQueue) observe(h reddit.Harvest, complete bool) []*Reported {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	seen := make(map[string]bool)
+	var reported []*Reported
+	check := func(name string, r *Reported) {
+		seen[name] = true
+		if q.dispatched[name] {
+			return
+		}
+
+		for _, report := range r.Reports {
+			r.Total += report.Count
+		}
+		if r.Total >= q.minReports {
+			q.dispatched[name] = true
+			reported = append(reported, r)
+		}
+	}
+
+	for _, p := range h.Posts {
+		check(p.Name, &Reported{
+			Subreddit: p.Subreddit,
+			Post:      p,
+			Reports:   reddit.ParseReports(p.UserReports, p.ModReports),
+		})
+	}
+	for _, c := range h.Comments {
+		check(c.Name, &Reported{
+			Subreddit: c.Subreddit,
+			Comment:   c,
+			Reports:   reddit.ParseReports(c.UserReports, c.ModReports),
+		})
+	}
+
+	if complete {
+		for name := range q.dispatched {
+			if !seen[name] {
+				delete(q.dispatched, name)
+			}
+		}
+	}
+
+	return reported
+}
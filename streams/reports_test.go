@@ -0,0 +1,98 @@
+package streams
+
+import (
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+func reportedPost(name string, count int) *reddit.Post {
+	return &reddit.Post{
+		Name:        name,
+		UserReports: [][]interface{}{{"spam", float64(count)}},
+	}
+}
+
+func TestReportQueue(t *testing.T) {
+	q := newReportQueue(3)
+
+	for i, test := range []struct {
+		h        reddit.Harvest
+		complete bool
+		expected []string
+	}{
+		{reddit.Harvest{Posts: []*reddit.Post{reportedPost("t3_a", 2)}}, true, nil},
+		{reddit.Harvest{Posts: []*reddit.Post{reportedPost("t3_a", 3)}}, true, []string{"t3_a"}},
+		// Further reports and reads do not dispatch it again.
+		{reddit.Harvest{Posts: []*reddit.Post{reportedPost("t3_a", 9)}}, true, nil},
+		// An incomplete read does not forget it.
+		{reddit.Harvest{}, false, nil},
+		{reddit.Harvest{Posts: []*reddit.Post{reportedPost("t3_a", 9)}}, true, nil},
+		// Once it leaves the queue, it is dispatched on its return.
+		{reddit.Harvest{}, true, nil},
+		{reddit.Harvest{Posts: []*reddit.Post{reportedPost("t3_a", 4)}}, true, []string{"t3_a"}},
+		{
+			reddit.Harvest{Comments: []*reddit.Comment{{
+				Name:       "t1_b",
+				ModReports: [][]interface{}{{"rule 1", "mod"}},
+				UserReports: [][]interface{}{
+					{"rule 1", float64(1)},
+					{"rule 2", float64(1)},
+				},
+			}}},
+			true,
+			[]string{"t1_b"},
+		},
+	} {
+		var names []string
+		for _, r := range q.observe(test.h, test.complete) {
+			if r.Post != nil {
+				names = append(names, r.Post.Name)
+			} else {
+				names = append(names, r.Comment.Name)
+			}
+		}
+
+		if len(names) != len(test.expected) ||
+			(len(names) > 0 && names[0] != test.expected[0]) {
+			t.Errorf("%d: got %v; wanted %v", i, names, test.expected)
+		}
+	}
+}
+
+// queueScanner serves a moderation queue in pages, keyed by the after token
+// they are requested with.
+type queueScanner struct {
+	reddit.Scanner
+	pages map[string]reddit.Harvest
+	after map[string]string
+}
+
+func (q *queueScanner) ListingPage(_ string, params map[string]string) (
+	reddit.Harvest,
+	reddit.ListingInfo,
+	error,
+) {
+	after := params["after"]
+	return q.pages[after], reddit.ListingInfo{After: q.after[after]}, nil
+}
+
+func TestReadQueue(t *testing.T) {
+	sc := &queueScanner{
+		pages: map[string]reddit.Harvest{
+			"":     {Posts: []*reddit.Post{{Name: "t3_a"}}},
+			"t3_x": {Comments: []*reddit.Comment{{Name: "t1_b"}}},
+		},
+		// The token need not be the name of the page's last element.
+		after: map[string]string{"": "t3_x"},
+	}
+
+	h, complete, err := readQueue(sc, "/r/sub/about/reports")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !complete || len(h.Posts) != 1 || len(h.Comments) != 1 {
+		t.Errorf("got %v, %v, complete %v; wanted both pages", h.Posts, h.Comments, complete)
+	}
+}
@@ -0,0 +1,144 @@
+package streams
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// maxRisen is how many posts a rising stream remembers having seen.
+const maxRisen = 10000
+
+// RisingConfig configures a rising stream.
+type RisingConfig struct {
+	// Interval is how often the rising listing is read.
+	Interval time.Duration
+}
+
+// RisingPost is a post which appeared in a rising listing.
+type RisingPost struct {
+	Post *reddit.Post
+	// Rank is the post's position in the listing when it appeared, from
+	// zero.
+	Rank int
+	// Handled is set by graw when the post was already forwarded to the
+	// bot's PostHandler by a stream of new posts.
+	Handled bool
+}
+
+// Rising returns a stream of posts as they first appear in the rising listing
+// of the given subreddits. Each post is dispatched once, however many polls it
+// stays in the listing. The posts rising when the stream starts are not
+// dispatched.
+//
+// Each poll consumes one interval of the handle.
+func Rising(
+	scanner reddit.Scanner,
+	kill <-chan bool,
+	errs chan<- error,
+	cfg RisingConfig,
+	subreddits ...string,
+) (
+	<-chan *RisingPost,
+	error,
+) {
+	if cfg.Interval <= 0 {
+		return nil, intervalErr
+	}
+
+	path := "/r/" + strings.Join(subreddits, "+") + "/rising"
+	params := map[string]string{"limit": "100", "raw_json": "1"}
+	h, err := scanner.ListingWithParams(path, params)
+	if err != nil {
+		return nil, err
+	}
+
+	r := newRisen()
+	r.observe(h)
+
+	rising := make(chan *RisingPost)
+	go func() {
+		defer close(rising)
+		ticker := newPoller(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-kill:
+				return
+			case <-ticker.C:
+				h, err := scanner.ListingWithParams(path, params)
+				if err != nil {
+					select {
+					case errs <- err:
+					case <-kill:
+						return
+					}
+					continue
+				}
+
+				for _, p := range r.observe(h) {
+					select {
+					case rising <- p:
+					case <-kill:
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return rising, nil
+}
+
+// risen remembers which posts were seen in a rising listing.
+type risen struct {
+	mu    sync.Mutex
+	names map[string]bool
+	order []string
+}
+
+func newRisen() *risen {
+	return &risen{names: make(map[string]bool)}
+}
+
+// observe returns the posts in a read of the listing which were not seen in
+// it before.
+func (r *risen) observe(h reddit.Harvest) []*RisingPost {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var rising []*RisingPost
+	for i, p := range h.Posts {
+		if r.add(p.Name) {
+			rising = append(rising, &RisingPost{Post: p, Rank: i})
+		}
+	}
+
+	r.trim()
+	return rising
+}
+
+// add records a fullname as seen, returning false if it was seen before. The
+// caller must hold the lock.
+func (r *risen) add(name string) bool {
+	if r.names[name] {
+		return false
+	}
+
+	r.names[name] = true
+	r.order = append(r.order, name)
+	return true
+}
+
+// trim forgets the oldest fullnames beyond maxRisen. The caller must hold the
+// lock.
+func (r *risen) trim() {
+	if over := len(r.order) - maxRisen; over > 0 {
+		for _, name := range r.order[:over] {
+			delete(r.names, name)
+		}
+		r.order = r.order[over:]
+	}
+}
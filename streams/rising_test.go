@@ -0,0 +1,41 @@
+package streams
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+func risingHarvest(names ...string) reddit.Harvest {
+	var h reddit.Harvest
+	for _, name := range names {
+		h.Posts = append(h.Posts, &reddit.Post{Name: name})
+	}
+	return h
+}
+
+func TestRisen(t *testing.T) {
+	r := newRisen()
+
+	for i, test := range []struct {
+		h        reddit.Harvest
+		expected string
+	}{
+		{risingHarvest("t3_a", "t3_b"), "[t3_a@0 t3_b@1]"},
+		{risingHarvest("t3_c", "t3_a", "t3_b"), "[t3_c@0]"},
+		// Posts which leave the listing and come back are not
+		// dispatched again.
+		{risingHarvest("t3_d"), "[t3_d@0]"},
+		{risingHarvest("t3_d", "t3_a"), "[]"},
+	} {
+		var got []string
+		for _, p := range r.observe(test.h) {
+			got = append(got, fmt.Sprintf("%s@%d", p.Post.Name, p.Rank))
+		}
+
+		if fmt.Sprint(got) != test.expected {
+			t.Errorf("%d: got %v; wanted %s", i, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package streams
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/store"
+)
+
+const (
+	// searchKey is the key the results seen by saved searches are stored
+	// under.
+	searchKey = "saved-searches"
+	// maxSearchSeen is how many results of each search are remembered.
+	maxSearchSeen = 1000
+)
+
+var noSearchesErr = fmt.Errorf("a search stream needs at least one search")
+
+// Search is a saved search, run over and over by a search stream.
+type Search struct {
+	// Query is the search query, in Reddit's search syntax.
+	Query string `json:"query"`
+	// Subreddit restricts the search to a subreddit, or a combination of
+	// them such as "golang+rust". If empty, all of Reddit is searched.
+	Subreddit string `json:"subreddit"`
+	// Sort is the order Reddit sorts results in, e.g. "relevance", "top",
+	// or "comments". If empty, the newest results come first, which suits
+	// a stream best.
+	Sort string `json:"sort"`
+}
+
+func (s Search) path() string {
+	if s.Subreddit == "" {
+		return "/search"
+	}
+	return "/r/" + s.Subreddit + "/search"
+}
+
+func (s Search) params() map[string]string {
+	params := map[string]string{
+		"q":        s.Query,
+		"sort":     s.Sort,
+		"type":     "link",
+		"limit":    "100",
+		"raw_json": "1",
+	}
+	if s.Sort == "" {
+		params["sort"] = "new"
+	}
+	if s.Subreddit != "" {
+		params["restrict_sr"] = "on"
+	}
+	return params
+}
+
+// id identifies the search in the stored state.
+func (s Search) id() string {
+	return s.Subreddit + "|" + s.Sort + "|" + s.Query
+}
+
+// SearchConfig configures a search stream.
+type SearchConfig struct {
+	// Searches are the saved searches to run.
+	Searches []Search
+	// Interval is how often every search is run.
+	Interval time.Duration
+	// Store, if set, persists the results each search has seen, so they
+	// are not dispatched again after a restart.
+	Store store.Store
+}
+
+// SearchResult is a post newly matching a saved search.
+type SearchResult struct {
+	Search Search
+	Post   *reddit.Post
+}
+
+// Searches returns a stream of posts as they first match the configured saved
+// searches. Each post is dispatched once per search it matches. The results of
+// a search when it is first run are not dispatched, so adding a search to a
+// stream does not flood it with old matches.
+//
+// Each poll consumes one interval of the handle per search.
+func Searches(
+	scanner reddit.Scanner,
+	kill <-chan bool,
+	errs chan<- error,
+	cfg SearchConfig,
+) (
+	<-chan *SearchResult,
+	error,
+) {
+	if cfg.Interval <= 0 {
+		return nil, intervalErr
+	}
+
+	if len(cfg.Searches) == 0 {
+		return nil, noSearchesErr
+	}
+
+	seen := make(map[string][]string)
+	if cfg.Store != nil {
+		err := cfg.Store.Load(searchKey, &seen)
+		if err != nil && err != store.NotFoundErr {
+			return nil, err
+		}
+	}
+	results := newSearchResults(seen)
+
+	found := make(chan *SearchResult)
+	go func() {
+		defer close(found)
+		ticker := newPoller(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			for _, s := range cfg.Searches {
+				h, err := scanner.ListingWithParams(s.path(), s.params())
+				if err != nil {
+					select {
+					case errs <- err:
+					case <-kill:
+						return
+					}
+					continue
+				}
+
+				for _, r := range results.observe(s, h) {
+					select {
+					case found <- r:
+					case <-kill:
+						return
+					}
+				}
+			}
+
+			if cfg.Store != nil {
+				if err := cfg.Store.Save(searchKey, results.seen); err != nil {
+					errs <- err
+				}
+			}
+
+			select {
+			case <-kill:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return found, nil
+}
+
+// searchResults remembers which results each saved search has seen.
+type searchResults struct {
+	// seen holds the fullnames of the results of each search, by the
+	// search's id, oldest first.
+	seen map[string][]string
+	sets map[string]map[string]bool
+}
+
+func newSearchResults(seen map[string][]string) *searchResults {
+	r := &searchResults{seen: seen, sets: make(map[string]map[string]bool)}
+	for id, names := range seen {
+		r.sets[id] = make(map[string]bool)
+		for _, name := range names {
+			r.sets[id][name] = true
+		}
+	}
+	return r
+}
+
+// observe returns the results of a run of the search which it has not seen
+// before. A search which never ran before dispatches nothing.
+func (r *searchResults) observe(s Search, h reddit.Harvest) []*SearchResult {
+	id := s.id()
+	set, ran := r.sets[id]
+	if !ran {
+		set = make(map[string]bool)
+		r.sets[id] = set
+	}
+
+	var found []*SearchResult
+	// Results come newest first; remember them oldest first.
+	for i := len(h.Posts) - 1; i >= 0; i-- {
+		p := h.Posts[i]
+		if set[p.Name] {
+			continue
+		}
+
+		set[p.Name] = true
+		r.seen[id] = append(r.seen[id], p.Name)
+		if ran {
+			found = append(found, &SearchResult{Search: s, Post: p})
+		}
+	}
+
+	if over := len(r.seen[id]) - maxSearchSeen; over > 0 {
+		for _, name := range r.seen[id][:over] {
+			delete(set, name)
+		}
+		r.seen[id] = r.seen[id][over:]
+	}
+
+	return found
+}
@@ -0,0 +1,66 @@
+package streams
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+func searchHarvest(names ...string) reddit.Harvest {
+	var h reddit.Harvest
+	for _, name := range names {
+		h.Posts = append(h.Posts, &reddit.Post{Name: name})
+	}
+	return h
+}
+
+func TestSearchResults(t *testing.T) {
+	golang := Search{Query: "generics", Subreddit: "golang"}
+	rust := Search{Query: "generics", Subreddit: "rust"}
+	r := newSearchResults(map[string][]string{})
+
+	for i, test := range []struct {
+		s        Search
+		h        reddit.Harvest
+		expected string
+	}{
+		// The first run of a search only seeds it.
+		{golang, searchHarvest("t3_b", "t3_a"), "[]"},
+		{golang, searchHarvest("t3_d", "t3_c", "t3_b", "t3_a"), "[t3_c t3_d]"},
+		{golang, searchHarvest("t3_d", "t3_c"), "[]"},
+		// Searches are deduplicated separately.
+		{rust, searchHarvest("t3_d"), "[]"},
+		{rust, searchHarvest("t3_e", "t3_d"), "[t3_e]"},
+	} {
+		var got []string
+		for _, result := range r.observe(test.s, test.h) {
+			if result.Search != test.s {
+				t.Errorf("%d: got result of %+v; wanted %+v", i, result.Search, test.s)
+			}
+			got = append(got, result.Post.Name)
+		}
+
+		if fmt.Sprint(got) != test.expected {
+			t.Errorf("%d: got %v; wanted %s", i, got, test.expected)
+		}
+	}
+
+	// Results seen before a restart are not dispatched again.
+	restarted := newSearchResults(r.seen)
+	found := restarted.observe(golang, searchHarvest("t3_f", "t3_d"))
+	if len(found) != 1 || found[0].Post.Name != "t3_f" {
+		t.Errorf("got %v after restarting; wanted only t3_f", found)
+	}
+}
+
+func TestSearchParams(t *testing.T) {
+	params := Search{Query: "graw", Subreddit: "golang"}.params()
+	if params["sort"] != "new" || params["restrict_sr"] != "on" || params["q"] != "graw" {
+		t.Errorf("got params %v", params)
+	}
+
+	if path := (Search{Query: "graw"}).path(); path != "/search" {
+		t.Errorf("got path %s; wanted /search", path)
+	}
+}
@@ -0,0 +1,101 @@
+package streams
+
+import (
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// AccountSnapshot is a record of how the bot's account stood on Reddit at
+// one moment.
+type AccountSnapshot struct {
+	// Time is when the snapshot was taken.
+	Time time.Time
+	// Account is the bot's account as Reddit reported it.
+	Account *reddit.Redditor
+	// Followers is the number of accounts following the bot's profile.
+	Followers int32
+	// Recent is the number of the account's most recent comments that
+	// were inspected to compute RemovalRate.
+	Recent int
+	// RemovalRate is the fraction of the recently inspected comments
+	// which were removed by moderators, AutoModerator, or Reddit, rather
+	// than deleted by the bot.
+	RemovalRate float64
+}
+
+// AccountSnapshots returns a stream of snapshots of the bot's account, taken
+// once every interval. Each snapshot consumes two intervals of the handle.
+func AccountSnapshots(
+	bot reddit.Bot,
+	kill <-chan bool,
+	errs chan<- error,
+	interval time.Duration,
+) (
+	<-chan *AccountSnapshot,
+	error,
+) {
+	if _, err := snapshot(bot); err != nil {
+		return nil, err
+	}
+
+	snapshots := make(chan *AccountSnapshot)
+	go func() {
+		defer close(snapshots)
+		ticker := newPoller(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-kill:
+				return
+			case <-ticker.C:
+				if s, err := snapshot(bot); err != nil {
+					select {
+					case errs <- err:
+					case <-kill:
+						return
+					}
+				} else {
+					select {
+					case snapshots <- s:
+					case <-kill:
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return snapshots, nil
+}
+
+func snapshot(bot reddit.Bot) (*AccountSnapshot, error) {
+	me, err := bot.Me()
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := bot.Listing("/user/"+me.Name+"/comments", "")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &AccountSnapshot{
+		Time:      time.Now(),
+		Account:   me,
+		Followers: me.Subreddit.Subscribers,
+		Recent:    len(h.Comments),
+	}
+
+	removed := 0
+	for _, c := range h.Comments {
+		if r, ok := c.Removal(); ok && r.Source != reddit.RemovedByAuthor {
+			removed++
+		}
+	}
+	if s.Recent > 0 {
+		s.RemovalRate = float64(removed) / float64(s.Recent)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,58 @@
+package streams
+
+import (
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+type mockSnapshotBot struct {
+	reddit.Bot
+	me   *reddit.Redditor
+	h    reddit.Harvest
+	path string
+}
+
+func (m *mockSnapshotBot) Me() (*reddit.Redditor, error) {
+	return m.me, nil
+}
+
+func (m *mockSnapshotBot) Listing(path, _ string) (reddit.Harvest, error) {
+	m.path = path
+	return m.h, nil
+}
+
+func TestSnapshot(t *testing.T) {
+	me := &reddit.Redditor{Name: "bot", CommentKarma: 10}
+	me.Subreddit.Subscribers = 7
+	bot := &mockSnapshotBot{
+		me: me,
+		h: reddit.Harvest{
+			Comments: []*reddit.Comment{
+				&reddit.Comment{Body: "hello"},
+				// Reddit shows a removed comment's body to its
+				// author.
+				&reddit.Comment{Body: "a reply", Removed: true},
+				&reddit.Comment{Body: "[deleted]"},
+				&reddit.Comment{Body: "a link", BannedBy: "AutoModerator"},
+			},
+		},
+	}
+
+	s, err := snapshot(bot)
+	if err != nil {
+		t.Fatalf("error taking snapshot: %v", err)
+	}
+
+	if bot.path != "/user/bot/comments" {
+		t.Errorf("wrong history path: %s", bot.path)
+	}
+
+	if s.Account != me || s.Followers != 7 || s.Recent != 4 {
+		t.Errorf("wrong snapshot: %+v", s)
+	}
+
+	if s.RemovalRate != 0.5 {
+		t.Errorf("wanted removal rate 0.5; got %f", s.RemovalRate)
+	}
+}
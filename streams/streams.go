@@ -32,17 +32,43 @@
 // E.g. if you create two user streams which depend on a handle with a rate
 // limit of 5 seconds, each of them will be unblocked once every 10 seconds
 // (ish), since they each consume one interval, and the interval is 5 seconds.
+//
+// Streams which poll on an interval of their own, such as the moderation
+// queues, stagger their first polls over the interval and move each poll by up
+// to a tenth of it at random, so streams sharing an interval don't align their
+// requests into bursts.
 package streams
 
 import (
 	"strings"
+	"time"
 
-	"github.com/turnage/graw/reddit"
+	"github.com/aldarisbm/graw/reddit"
 
-	"github.com/turnage/graw/streams/internal/monitor"
-	"github.com/turnage/graw/streams/internal/rsort"
+	"github.com/aldarisbm/graw/streams/internal/monitor"
+	"github.com/aldarisbm/graw/streams/internal/rsort"
 )
 
+// Start is a point in a listing a stream can start from instead of its current
+// tip, so that elements created while a bot was down are not missed.
+type Start struct {
+	// From is the fullname of an element in the listing, e.g. t3_abc123.
+	// The stream begins with the elements created after it. If the element
+	// is not in the listing, the stream starts from the current tip.
+	From string
+	// Since, if From is not set, starts the stream from the youngest
+	// element created before this time. Reddit only keeps the last
+	// thousand or so elements of a listing, so a stream can't go further
+	// back than that.
+	Since time.Time
+	// Limit, if set, is how many elements the stream asks for each time it
+	// polls, up to Reddit's maximum of 100. Smaller pages are cheaper, but
+	// a stream whose pages come back full falls behind the listing until
+	// it catches up. If zero, the stream starts at 100 and sizes its pages
+	// to twice the busiest of its recent polls, down to 25.
+	Limit int
+}
+
 // Subreddits returns a stream of new posts from the requested subreddits. This
 // stream monitors the combination listing of all subreddits using Reddit's "+"
 // feature e.g. /r/golang+rust. This will consume one interval of the handle per
@@ -60,8 +86,25 @@ func Subreddits(
 	<-chan *reddit.Post,
 	error,
 ) {
-	path := "/r/" + strings.Join(subreddits, "+") + "/new"
-	posts, _, _, err := streamFromPath(scanner, kill, errs, path)
+	return SubredditsFrom(scanner, kill, errs, Start{}, subreddits...)
+}
+
+// SubredditsFrom is Subreddits, beginning at the given start.
+func SubredditsFrom(
+	scanner reddit.Scanner,
+	kill <-chan bool,
+	errs chan<- error,
+	start Start,
+	subreddits ...string,
+) (
+	<-chan *reddit.Post,
+	error,
+) {
+	multi, err := multireddit(subreddits)
+	if err != nil {
+		return nil, err
+	}
+	posts, _, _, err := streamFromPath(scanner, kill, errs, multi+"/new", start)
 	return posts, err
 }
 
@@ -78,9 +121,24 @@ func CustomFeeds(
 ) (
 	<-chan *reddit.Post,
 	error,
+) {
+	return CustomFeedsFrom(scanner, kill, errs, Start{}, user, feeds...)
+}
+
+// CustomFeedsFrom is CustomFeeds, beginning at the given start.
+func CustomFeedsFrom(
+	scanner reddit.Scanner,
+	kill <-chan bool,
+	errs chan<- error,
+	start Start,
+	user string,
+	feeds ...string,
+) (
+	<-chan *reddit.Post,
+	error,
 ) {
 	path := "/user/" + user + "/m/" + strings.Join(feeds, "+") + "/new"
-	posts, _, _, err := streamFromPath(scanner, kill, errs, path)
+	posts, _, _, err := streamFromPath(scanner, kill, errs, path, start)
 	return posts, err
 }
 
@@ -102,8 +160,25 @@ func SubredditComments(
 	<-chan *reddit.Comment,
 	error,
 ) {
-	path := "/r/" + strings.Join(subreddits, "+") + "/comments"
-	_, comments, _, err := streamFromPath(scanner, kill, errs, path)
+	return SubredditCommentsFrom(scanner, kill, errs, Start{}, subreddits...)
+}
+
+// SubredditCommentsFrom is SubredditComments, beginning at the given start.
+func SubredditCommentsFrom(
+	scanner reddit.Scanner,
+	kill <-chan bool,
+	errs chan<- error,
+	start Start,
+	subreddits ...string,
+) (
+	<-chan *reddit.Comment,
+	error,
+) {
+	multi, err := multireddit(subreddits)
+	if err != nil {
+		return nil, err
+	}
+	_, comments, _, err := streamFromPath(scanner, kill, errs, multi+"/comments", start)
 	return comments, err
 }
 
@@ -118,9 +193,24 @@ func User(
 	<-chan *reddit.Post,
 	<-chan *reddit.Comment,
 	error,
+) {
+	return UserFrom(scanner, kill, errs, Start{}, user)
+}
+
+// UserFrom is User, beginning at the given start.
+func UserFrom(
+	scanner reddit.Scanner,
+	kill <-chan bool,
+	errs chan<- error,
+	start Start,
+	user string,
+) (
+	<-chan *reddit.Post,
+	<-chan *reddit.Comment,
+	error,
 ) {
 	path := "/u/" + user
-	posts, comments, _, err := streamFromPath(scanner, kill, errs, path)
+	posts, comments, _, err := streamFromPath(scanner, kill, errs, path, start)
 	return posts, comments, err
 }
 
@@ -134,7 +224,20 @@ func PostReplies(
 	<-chan *reddit.Message,
 	error,
 ) {
-	return inboxStream(bot, kill, errs, "selfreply")
+	return PostRepliesFrom(bot, kill, errs, Start{})
+}
+
+// PostRepliesFrom is PostReplies, beginning at the given start.
+func PostRepliesFrom(
+	bot reddit.Bot,
+	kill <-chan bool,
+	errs chan<- error,
+	start Start,
+) (
+	<-chan *reddit.Message,
+	error,
+) {
+	return inboxStream(bot, kill, errs, "selfreply", start)
 }
 
 // CommentReplies returns a stream of replies to comments made by the bot's
@@ -147,7 +250,20 @@ func CommentReplies(
 	<-chan *reddit.Message,
 	error,
 ) {
-	return inboxStream(bot, kill, errs, "comments")
+	return CommentRepliesFrom(bot, kill, errs, Start{})
+}
+
+// CommentRepliesFrom is CommentReplies, beginning at the given start.
+func CommentRepliesFrom(
+	bot reddit.Bot,
+	kill <-chan bool,
+	errs chan<- error,
+	start Start,
+) (
+	<-chan *reddit.Message,
+	error,
+) {
+	return inboxStream(bot, kill, errs, "comments", start)
 }
 
 // Mentions returns a stream of mentions of the bot's username anywhere on
@@ -162,7 +278,20 @@ func Mentions(
 	<-chan *reddit.Message,
 	error,
 ) {
-	return inboxStream(bot, kill, errs, "mentions")
+	return MentionsFrom(bot, kill, errs, Start{})
+}
+
+// MentionsFrom is Mentions, beginning at the given start.
+func MentionsFrom(
+	bot reddit.Bot,
+	kill <-chan bool,
+	errs chan<- error,
+	start Start,
+) (
+	<-chan *reddit.Message,
+	error,
+) {
+	return inboxStream(bot, kill, errs, "mentions", start)
 }
 
 // Messages returns a stream of messages sent to the bot's inbox. It consumes
@@ -174,10 +303,23 @@ func Messages(
 ) (
 	<-chan *reddit.Message,
 	error,
+) {
+	return MessagesFrom(bot, kill, errs, Start{})
+}
+
+// MessagesFrom is Messages, beginning at the given start.
+func MessagesFrom(
+	bot reddit.Bot,
+	kill <-chan bool,
+	errs chan<- error,
+	start Start,
+) (
+	<-chan *reddit.Message,
+	error,
 ) {
 	onlyMessages := make(chan *reddit.Message)
 
-	messages, err := inboxStream(bot, kill, errs, "inbox")
+	messages, err := inboxStream(bot, kill, errs, "inbox", start)
 	go func() {
 		for m := range messages {
 			if !m.WasComment {
@@ -194,12 +336,13 @@ func inboxStream(
 	kill <-chan bool,
 	errs chan<- error,
 	subpath string,
+	start Start,
 ) (
 	<-chan *reddit.Message,
 	error,
 ) {
 	path := "/message/" + subpath
-	_, _, messages, err := streamFromPath(scanner, kill, errs, path)
+	_, _, messages, err := streamFromPath(scanner, kill, errs, path, start)
 	return messages, err
 }
 
@@ -208,13 +351,14 @@ func streamFromPath(
 	kill <-chan bool,
 	errs chan<- error,
 	path string,
+	start Start,
 ) (
 	<-chan *reddit.Post,
 	<-chan *reddit.Comment,
 	<-chan *reddit.Message,
 	error,
 ) {
-	mon, err := monitorFromPath(path, scanner)
+	mon, err := monitorFromPath(path, scanner, start)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -223,12 +367,19 @@ func streamFromPath(
 	return posts, comments, messages, nil
 }
 
-func monitorFromPath(path string, sc reddit.Scanner) (monitor.Monitor, error) {
+func monitorFromPath(
+	path string,
+	sc reddit.Scanner,
+	start Start,
+) (monitor.Monitor, error) {
 	return monitor.New(
 		monitor.Config{
 			Path:    path,
 			Scanner: sc,
 			Sorter:  rsort.New(),
+			From:    start.From,
+			Since:   start.Since,
+			Limit:   start.Limit,
 		},
 	)
 }
@@ -270,7 +421,12 @@ func flow(
 			return
 		default:
 			if h, err := mon.Update(); err != nil {
-				errs <- err
+				// Requests fail when a run stops and cancels
+				// them, after no one listens for errors.
+				select {
+				case errs <- err:
+				case <-kill:
+				}
 			} else {
 				// lol no generics
 				for _, p := range h.Posts {
@@ -0,0 +1,293 @@
+package streams
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+const (
+	// infoPath is the Reddit endpoint which returns elements by fullname.
+	infoPath = "/api/info"
+	// infoBatch is the most fullnames infoPath accepts in one request.
+	infoBatch = 100
+	// maxTracked is the most elements a threshold stream tracks at once.
+	// Once it is reached, the longest tracked elements are dropped, and
+	// counted in the metrics of the stream's handle.
+	maxTracked = 1000
+)
+
+// intervalErr is returned for polling streams configured without a positive
+// interval.
+var intervalErr = fmt.Errorf("stream interval must be positive")
+
+// Threshold is a level of score or comment count that a tracked post or
+// comment can cross.
+type Threshold struct {
+	// Comments makes this a threshold on a post's comment count instead of
+	// its score. Comments have no comment count and never cross it.
+	Comments bool
+	// Level is crossed when the score or comment count rises to it.
+	Level int32
+	// Hysteresis is how far the score or comment count must fall back
+	// below Level before the threshold can be crossed again, so elements
+	// wavering around Level do not fire repeatedly.
+	Hysteresis int32
+}
+
+// Crossing is a tracked post or comment crossing a threshold. Exactly one of
+// Post and Comment is set.
+type Crossing struct {
+	Threshold Threshold
+	// Value is the score or comment count which crossed the threshold.
+	Value   int32
+	Post    *reddit.Post
+	Comment *reddit.Comment
+}
+
+// Thresholds returns a stream of tracked posts and comments crossing the given
+// thresholds. Elements are tracked by sending their fullnames over track, and
+// are fetched again once every interval, which consumes one interval of the
+// handle per hundred elements tracked.
+//
+// Elements already past a threshold when they are first fetched do not cross
+// it until they have fallen back below it. Hidden scores, e.g. of new comments
+// or of comments in contest mode threads, are not checked against thresholds
+// until they are revealed. Elements Reddit no longer returns are no longer
+// tracked.
+func Thresholds(
+	scanner reddit.Scanner,
+	kill <-chan bool,
+	errs chan<- error,
+	track <-chan string,
+	interval time.Duration,
+	thresholds ...Threshold,
+) (
+	<-chan *Crossing,
+	error,
+) {
+	if interval <= 0 {
+		return nil, intervalErr
+	}
+
+	t := newTracker(thresholds)
+	go func() {
+		for {
+			select {
+			case <-kill:
+				return
+			case name, ok := <-track:
+				if !ok {
+					return
+				}
+				if t.track(name) {
+					dropped(scanner, "thresholds", 1)
+				}
+			}
+		}
+	}()
+
+	crossings := make(chan *Crossing)
+	go func() {
+		defer close(crossings)
+		ticker := newPoller(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-kill:
+				return
+			case <-ticker.C:
+				for _, batch := range t.batches() {
+					h, err := scanner.ListingWithParams(
+						infoPath,
+						map[string]string{"id": strings.Join(batch, ",")},
+					)
+					if err != nil {
+						select {
+						case errs <- err:
+						case <-kill:
+							return
+						}
+						continue
+					}
+
+					for _, c := range t.observe(batch, h) {
+						select {
+						case crossings <- c:
+						case <-kill:
+							return
+						}
+					}
+				}
+			}
+		}
+	}()
+
+	return crossings, nil
+}
+
+// armState is the state of a threshold for a tracked element.
+type armState int8
+
+const (
+	// unobserved thresholds have not seen a value yet, e.g. because the
+	// element's score is hidden.
+	unobserved armState = iota
+	// armed thresholds can be crossed.
+	armed
+	// disarmed thresholds must be fallen back below before they can be
+	// crossed again.
+	disarmed
+)
+
+// tracker holds the state of the elements a threshold stream tracks.
+type tracker struct {
+	mu         sync.Mutex
+	thresholds []Threshold
+	// order is the tracked fullnames in the order they were tracked.
+	order []string
+	// armed holds, per tracked fullname, the state of each threshold. It
+	// is nil until the element is first observed.
+	armed map[string][]armState
+}
+
+func newTracker(thresholds []Threshold) *tracker {
+	return &tracker{
+		thresholds: thresholds,
+		armed:      make(map[string][]armState),
+	}
+}
+
+// track tracks an element, and reports whether the longest tracked element was
+// dropped to make room for it.
+func (t *tracker) track(name string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.armed[name]; ok {
+		return false
+	}
+
+	t.order = append(t.order, name)
+	t.armed[name] = nil
+	if len(t.order) > maxTracked {
+		delete(t.armed, t.order[0])
+		t.order = t.order[1:]
+		return true
+	}
+	return false
+}
+
+func (t *tracker) untrack(name string) {
+	delete(t.armed, name)
+	for i, n := range t.order {
+		if n == name {
+			t.order = append(t.order[:i], t.order[i+1:]...)
+			return
+		}
+	}
+}
+
+// batches returns the tracked fullnames in groups small enough to fetch in
+// one request.
+func (t *tracker) batches() [][]string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var batches [][]string
+	for i := 0; i < len(t.order); i += infoBatch {
+		end := i + infoBatch
+		if end > len(t.order) {
+			end = len(t.order)
+		}
+		batches = append(batches, append([]string(nil), t.order[i:end]...))
+	}
+	return batches
+}
+
+// observe updates the tracked state of a fetched batch of elements and
+// returns the thresholds they crossed.
+func (t *tracker) observe(batch []string, h reddit.Harvest) []*Crossing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	seen := make(map[string]bool)
+	var crossings []*Crossing
+	for _, p := range h.Posts {
+		seen[p.Name] = true
+		_, visible := p.VisibleScore()
+		for _, c := range t.check(p.Name, p.Score, visible, p.NumComments, true) {
+			c.Post = p
+			crossings = append(crossings, c)
+		}
+	}
+	for _, cm := range h.Comments {
+		seen[cm.Name] = true
+		_, visible := cm.VisibleScore()
+		for _, c := range t.check(cm.Name, cm.Score, visible, 0, false) {
+			c.Comment = cm
+			crossings = append(crossings, c)
+		}
+	}
+
+	for _, name := range batch {
+		if !seen[name] {
+			t.untrack(name)
+		}
+	}
+
+	return crossings
+}
+
+// check updates the state of an element's thresholds with its latest score
+// and comment count. Score thresholds are left as they are while the score is
+// hidden, and are checked once it is visible.
+func (t *tracker) check(
+	name string,
+	score int32,
+	scoreVisible bool,
+	comments int32,
+	isPost bool,
+) []*Crossing {
+	states, ok := t.armed[name]
+	if !ok {
+		return nil
+	}
+
+	if states == nil {
+		states = make([]armState, len(t.thresholds))
+		t.armed[name] = states
+	}
+
+	var crossings []*Crossing
+	for i, th := range t.thresholds {
+		value := score
+		if th.Comments {
+			if !isPost {
+				continue
+			}
+			value = comments
+		} else if !scoreVisible {
+			continue
+		}
+
+		switch {
+		case states[i] == unobserved && value < th.Level:
+			states[i] = armed
+		case states[i] == unobserved:
+			states[i] = disarmed
+		case value < th.Level-th.Hysteresis:
+			states[i] = armed
+		case value >= th.Level && states[i] == armed:
+			states[i] = disarmed
+			crossings = append(crossings, &Crossing{
+				Threshold: th,
+				Value:     value,
+			})
+		}
+	}
+	return crossings
+}
@@ -0,0 +1,146 @@
+package streams
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+func TestTrackerHysteresis(t *testing.T) {
+	tr := newTracker([]Threshold{{Level: 100, Hysteresis: 10}})
+	tr.track("t3_a")
+
+	var fired []int32
+	for _, score := range []int32{5, 99, 100, 120, 95, 100, 89, 101} {
+		h := reddit.Harvest{Posts: []*reddit.Post{{Name: "t3_a", Score: score}}}
+		for _, c := range tr.observe([]string{"t3_a"}, h) {
+			if c.Post.Name != "t3_a" {
+				t.Errorf("crossing for wrong post: %+v", c.Post)
+			}
+			fired = append(fired, c.Value)
+		}
+	}
+
+	if len(fired) != 2 || fired[0] != 100 || fired[1] != 101 {
+		t.Errorf("got crossings at %v; wanted [100 101]", fired)
+	}
+}
+
+func TestTrackerFirstObservation(t *testing.T) {
+	tr := newTracker([]Threshold{{Level: 100}, {Comments: true, Level: 10}})
+	tr.track("t3_a")
+	tr.track("t1_b")
+
+	batch := []string{"t3_a", "t1_b"}
+	h := reddit.Harvest{
+		Posts:    []*reddit.Post{{Name: "t3_a", Score: 150, NumComments: 1}},
+		Comments: []*reddit.Comment{{Name: "t1_b", Score: 1}},
+	}
+	if c := tr.observe(batch, h); len(c) != 0 {
+		t.Errorf("wanted no crossings on first observation; got %d", len(c))
+	}
+
+	h.Posts[0].NumComments = 20
+	h.Comments[0].Score = 200
+	crossings := tr.observe(batch, h)
+	if len(crossings) != 2 {
+		t.Fatalf("wanted 2 crossings; got %d", len(crossings))
+	}
+
+	if !crossings[0].Threshold.Comments || crossings[0].Post == nil {
+		t.Errorf("wanted post comment count crossing; got %+v", crossings[0])
+	}
+
+	if crossings[1].Threshold.Comments || crossings[1].Comment == nil {
+		t.Errorf("wanted comment score crossing; got %+v", crossings[1])
+	}
+}
+
+func TestTrackerDropsVanished(t *testing.T) {
+	tr := newTracker([]Threshold{{Level: 1}})
+	tr.track("t3_a")
+	tr.track("t3_b")
+
+	tr.observe(
+		[]string{"t3_a", "t3_b"},
+		reddit.Harvest{Posts: []*reddit.Post{{Name: "t3_b"}}},
+	)
+
+	batches := tr.batches()
+	if len(batches) != 1 || len(batches[0]) != 1 || batches[0][0] != "t3_b" {
+		t.Errorf("wanted only t3_b tracked; got %v", batches)
+	}
+}
+
+func TestTrackerDropsLongestTracked(t *testing.T) {
+	tr := newTracker([]Threshold{{Level: 1}})
+	for i := 0; i < maxTracked; i++ {
+		if tr.track(fmt.Sprintf("t3_%d", i)) {
+			t.Fatalf("dropped an element tracking %d", i+1)
+		}
+	}
+	if tr.track("t3_0") {
+		t.Errorf("dropped an element tracking one already tracked")
+	}
+
+	if !tr.track("t3_new") {
+		t.Errorf("wanted the longest tracked element dropped")
+	}
+	if _, ok := tr.armed["t3_0"]; ok {
+		t.Errorf("t3_0 still tracked")
+	}
+}
+
+func TestTrackerBatches(t *testing.T) {
+	tr := newTracker(nil)
+	for i := 0; i < maxTracked+5; i++ {
+		tr.track(fmt.Sprintf("t3_%d", i))
+	}
+
+	batches := tr.batches()
+	if len(batches) != maxTracked/infoBatch {
+		t.Errorf("got %d batches; wanted %d", len(batches), maxTracked/infoBatch)
+	}
+
+	for _, b := range batches {
+		if len(b) > infoBatch {
+			t.Errorf("batch of %d exceeds %d", len(b), infoBatch)
+		}
+	}
+}
+
+func TestTrackerHiddenScore(t *testing.T) {
+	tr := newTracker([]Threshold{{Level: 10}})
+	tr.track("t1_a")
+
+	var fired []int32
+	for _, c := range []*reddit.Comment{
+		{Name: "t1_a", Score: 1, ScoreHidden: true},
+		{Name: "t1_a", Score: 1, ScoreHidden: true},
+		{Name: "t1_a", Score: 4},
+		{Name: "t1_a", Score: 1, ScoreHidden: true},
+		{Name: "t1_a", Score: 12},
+	} {
+		h := reddit.Harvest{Comments: []*reddit.Comment{c}}
+		for _, cr := range tr.observe([]string{"t1_a"}, h) {
+			fired = append(fired, cr.Value)
+		}
+	}
+
+	if len(fired) != 1 || fired[0] != 12 {
+		t.Errorf("got crossings at %v; wanted [12]", fired)
+	}
+
+	// A score first seen above the level once revealed does not cross it.
+	tr.track("t1_b")
+	for _, c := range []*reddit.Comment{
+		{Name: "t1_b", Score: 1, ScoreHidden: true},
+		{Name: "t1_b", Score: 50},
+	} {
+		h := reddit.Harvest{Comments: []*reddit.Comment{c}}
+		if cr := tr.observe([]string{"t1_b"}, h); len(cr) != 0 {
+			t.Errorf("revealed score crossed a threshold: %+v", cr[0])
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package streams
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/aldarisbm/graw/clock"
+)
+
+// windowErr is returned for trend streams configured without a window.
+var windowErr = fmt.Errorf("trend window must be positive")
+
+// minTermLength is the length below which words are not counted as terms.
+const minTermLength = 3
+
+// stopWords are common words which are never trends.
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true,
+	"not": true, "you": true, "all": true, "any": true, "can": true,
+	"had": true, "her": true, "was": true, "one": true, "our": true,
+	"out": true, "has": true, "have": true, "this": true, "that": true,
+	"with": true, "they": true, "from": true, "what": true, "your": true,
+	"just": true, "like": true, "about": true, "there": true, "their": true,
+	"would": true, "which": true, "been": true, "were": true, "when": true,
+	"will": true, "more": true, "some": true, "than": true, "them": true,
+	"then": true, "also": true, "into": true, "only": true, "its": true,
+	"it's": true, "don't": true, "i'm": true, "how": true, "why": true,
+	"who": true, "get": true, "got": true, "his": true, "she": true,
+}
+
+// TrendConfig configures a trend stream.
+type TrendConfig struct {
+	// Window is the span of time term frequencies are counted over. Each
+	// window's counts are compared against the windows before it.
+	Window time.Duration
+	// History is how many past windows make up the baseline a window is
+	// compared against.
+	History int
+	// ZScore is how many standard deviations above its baseline mean a
+	// term's count must be to trend.
+	ZScore float64
+	// MinCount is the fewest texts a term must appear in during a window
+	// to trend, so rare terms don't trend on noise.
+	MinCount int
+	// Clock times the windows. If nil, the wall clock is used.
+	Clock clock.Clock
+}
+
+// Trend is a term appearing far more often than usual.
+type Trend struct {
+	// Term is the trending word, in lower case.
+	Term string
+	// Count is how many texts the term appeared in during the window.
+	Count int
+	// Mean and StdDev describe the term's counts over the baseline.
+	Mean   float64
+	StdDev float64
+	// ZScore is how many standard deviations Count is above Mean.
+	ZScore float64
+	// Time is when the window closed.
+	Time time.Time
+}
+
+// Trends returns a stream of terms trending in the texts it is fed, e.g. post
+// titles or comment bodies from other streams. Term frequencies are counted
+// per window; when a window closes, each term whose count spikes beyond the
+// configured z-score over the previous windows is emitted. A term is counted
+// once per text, so one text repeating a word can't make it trend.
+func Trends(
+	kill <-chan bool,
+	texts <-chan string,
+	cfg TrendConfig,
+) (
+	<-chan *Trend,
+	error,
+) {
+	if cfg.Window <= 0 {
+		return nil, windowErr
+	}
+
+	if cfg.Clock == nil {
+		cfg.Clock = clock.Real()
+	}
+
+	trends := make(chan *Trend)
+	go func() {
+		defer close(trends)
+		c := newCounter(cfg)
+		window := cfg.Clock.After(cfg.Window)
+		for {
+			select {
+			case <-kill:
+				return
+			case text, ok := <-texts:
+				if !ok {
+					return
+				}
+				c.count(text)
+			case now := <-window:
+				window = cfg.Clock.After(cfg.Window)
+				for _, t := range c.roll(now) {
+					select {
+					case trends <- t:
+					case <-kill:
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return trends, nil
+}
+
+// counter keeps rolling term frequencies.
+type counter struct {
+	cfg     TrendConfig
+	current map[string]int
+	// history holds the counts of past windows, oldest first.
+	history []map[string]int
+}
+
+func newCounter(cfg TrendConfig) *counter {
+	return &counter{cfg: cfg, current: make(map[string]int)}
+}
+
+func (c *counter) count(text string) {
+	for term := range terms(text) {
+		c.current[term]++
+	}
+}
+
+// roll closes the current window and returns the terms trending in it.
+func (c *counter) roll(now time.Time) []*Trend {
+	var trends []*Trend
+	if len(c.history) == c.cfg.History {
+		for term, count := range c.current {
+			if count < c.cfg.MinCount {
+				continue
+			}
+
+			mean, stdDev := c.baseline(term)
+			z := (float64(count) - mean) / math.Max(stdDev, 1)
+			if z >= c.cfg.ZScore {
+				trends = append(trends, &Trend{
+					Term:   term,
+					Count:  count,
+					Mean:   mean,
+					StdDev: stdDev,
+					ZScore: z,
+					Time:   now,
+				})
+			}
+		}
+	}
+
+	c.history = append(c.history, c.current)
+	if len(c.history) > c.cfg.History {
+		c.history = c.history[1:]
+	}
+	c.current = make(map[string]int)
+	return trends
+}
+
+// baseline returns the mean and standard deviation of a term's counts over
+// the past windows.
+func (c *counter) baseline(term string) (float64, float64) {
+	if len(c.history) == 0 {
+		return 0, 0
+	}
+
+	sum := 0.0
+	for _, h := range c.history {
+		sum += float64(h[term])
+	}
+	mean := sum / float64(len(c.history))
+
+	variance := 0.0
+	for _, h := range c.history {
+		d := float64(h[term]) - mean
+		variance += d * d
+	}
+	variance /= float64(len(c.history))
+
+	return mean, math.Sqrt(variance)
+}
+
+// terms returns the set of terms in a text.
+func terms(text string) map[string]bool {
+	set := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+	for _, w := range words {
+		w = strings.Trim(w, "'")
+		if len(w) >= minTermLength && !stopWords[w] {
+			set[w] = true
+		}
+	}
+	return set
+}
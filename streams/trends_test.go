@@ -0,0 +1,87 @@
+package streams
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+)
+
+func TestTerms(t *testing.T) {
+	got := terms("The Go compiler, the GO runtime... and 'generics'!")
+	want := map[string]bool{"compiler": true, "runtime": true, "generics": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got terms %v; wanted %v", got, want)
+	}
+}
+
+func TestCounterRoll(t *testing.T) {
+	c := newCounter(TrendConfig{History: 3, ZScore: 3, MinCount: 2})
+
+	for _, window := range [][]string{
+		{"golang release", "rust"},
+		{"golang", "rust release"},
+		{"golang", "rust"},
+	} {
+		for _, text := range window {
+			c.count(text)
+		}
+		if trends := c.roll(time.Time{}); len(trends) != 0 {
+			t.Errorf("wanted no trends before history is full; got %v", trends)
+		}
+	}
+
+	for _, text := range []string{
+		"golang", "release candidate", "release notes", "release day",
+		"release", "rust",
+	} {
+		c.count(text)
+	}
+
+	trends := c.roll(time.Time{})
+	if len(trends) != 1 || trends[0].Term != "release" || trends[0].Count != 4 {
+		t.Fatalf("wanted release to trend; got %+v", trends)
+	}
+
+	if len(c.history) != 3 {
+		t.Errorf("history grew to %d windows; wanted 3", len(c.history))
+	}
+}
+
+func TestTrends(t *testing.T) {
+	kill := make(chan bool)
+	defer close(kill)
+
+	sim := clock.NewSimulation(time.Unix(0, 0))
+	texts := make(chan string)
+	trends, err := Trends(kill, texts, TrendConfig{
+		Window:   time.Minute,
+		ZScore:   2,
+		MinCount: 3,
+		Clock:    sim,
+	})
+	if err != nil {
+		t.Fatalf("failed to start trend stream: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		texts <- "outage reported"
+	}
+	texts <- "quiet"
+	sim.Sleep(time.Minute)
+
+	seen := map[string]bool{}
+	for len(seen) < 2 {
+		select {
+		case tr := <-trends:
+			seen[tr.Term] = true
+		case <-time.After(time.Second):
+			t.Fatalf("got trends %v; wanted outage and reported", seen)
+		}
+	}
+
+	if !seen["outage"] || !seen["reported"] {
+		t.Errorf("got trends %v; wanted outage and reported", seen)
+	}
+}
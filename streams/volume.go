@@ -0,0 +1,183 @@
+package streams
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/store"
+)
+
+// volumeKey is the key post volume baselines are stored under.
+const volumeKey = "post-volume"
+
+// VolumeConfig configures a post volume stream.
+type VolumeConfig struct {
+	// Window is the span of time posts are counted over, e.g. a minute.
+	Window time.Duration
+	// Smoothing is the weight, between 0 and 1, each window's count is
+	// given in a subreddit's baseline; smaller values make for a longer
+	// memory. If zero, 0.05 is used.
+	Smoothing float64
+	// Warmup is how many windows of a subreddit must be seen before its
+	// volume is judged against its baseline.
+	Warmup int
+	// ZScore is how many standard deviations from its baseline a
+	// subreddit's volume must be to be anomalous, in either direction.
+	ZScore float64
+	// Store, if set, persists the baselines so they survive restarts.
+	Store store.Store
+	// Clock times the windows. If nil, the wall clock is used.
+	Clock clock.Clock
+}
+
+// VolumeAnomaly is a subreddit receiving far more, or far fewer, posts than
+// usual, as in a brigade or a spam wave.
+type VolumeAnomaly struct {
+	Subreddit string
+	// Count is the number of posts in the window.
+	Count int
+	// Mean and StdDev describe the subreddit's baseline count per window.
+	Mean   float64
+	StdDev float64
+	// ZScore is how many standard deviations Count is from Mean; it is
+	// negative when volume drops.
+	ZScore float64
+	// Time is when the window closed.
+	Time time.Time
+}
+
+// baseline is an exponentially weighted mean and variance of a subreddit's
+// post count per window.
+type baseline struct {
+	Mean     float64 `json:"mean"`
+	Variance float64 `json:"variance"`
+	Windows  int     `json:"windows"`
+}
+
+// PostVolume returns a stream of anomalies in the number of posts per window
+// each subreddit in the given posts receives. Baselines are loaded from the
+// configured store when the stream starts and saved to it after every window.
+func PostVolume(
+	kill <-chan bool,
+	errs chan<- error,
+	posts <-chan *reddit.Post,
+	cfg VolumeConfig,
+) (
+	<-chan *VolumeAnomaly,
+	error,
+) {
+	if cfg.Window <= 0 {
+		return nil, windowErr
+	}
+
+	if cfg.Smoothing == 0 {
+		cfg.Smoothing = 0.05
+	}
+
+	if cfg.Clock == nil {
+		cfg.Clock = clock.Real()
+	}
+
+	v := newVolume(cfg)
+	if cfg.Store != nil {
+		err := cfg.Store.Load(volumeKey, &v.baselines)
+		if err != nil && err != store.NotFoundErr {
+			return nil, err
+		}
+	}
+
+	anomalies := make(chan *VolumeAnomaly)
+	go func() {
+		defer close(anomalies)
+		window := cfg.Clock.After(cfg.Window)
+		for {
+			select {
+			case <-kill:
+				return
+			case p, ok := <-posts:
+				if !ok {
+					return
+				}
+				v.counts[strings.ToLower(p.Subreddit)]++
+			case now := <-window:
+				window = cfg.Clock.After(cfg.Window)
+				found := v.roll(now)
+				if cfg.Store != nil {
+					if err := cfg.Store.Save(volumeKey, v.baselines); err != nil {
+						errs <- err
+					}
+				}
+				for _, a := range found {
+					select {
+					case anomalies <- a:
+					case <-kill:
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return anomalies, nil
+}
+
+type volume struct {
+	cfg       VolumeConfig
+	counts    map[string]int
+	baselines map[string]*baseline
+}
+
+func newVolume(cfg VolumeConfig) *volume {
+	return &volume{
+		cfg:       cfg,
+		counts:    make(map[string]int),
+		baselines: make(map[string]*baseline),
+	}
+}
+
+// roll closes the current window, returning the anomalies in it and folding
+// its counts into the baselines. Subreddits with a baseline and no posts in
+// the window count zero posts.
+func (v *volume) roll(now time.Time) []*VolumeAnomaly {
+	for sub := range v.baselines {
+		if _, ok := v.counts[sub]; !ok {
+			v.counts[sub] = 0
+		}
+	}
+
+	var anomalies []*VolumeAnomaly
+	for sub, count := range v.counts {
+		b, ok := v.baselines[sub]
+		if !ok {
+			b = &baseline{Mean: float64(count)}
+			v.baselines[sub] = b
+		}
+
+		stdDev := math.Sqrt(b.Variance)
+		if b.Windows >= v.cfg.Warmup {
+			z := (float64(count) - b.Mean) / math.Max(stdDev, 1)
+			if math.Abs(z) >= v.cfg.ZScore {
+				anomalies = append(anomalies, &VolumeAnomaly{
+					Subreddit: sub,
+					Count:     count,
+					Mean:      b.Mean,
+					StdDev:    stdDev,
+					ZScore:    z,
+					Time:      now,
+				})
+			}
+		}
+
+		// Incremental exponentially weighted mean and variance.
+		d := float64(count) - b.Mean
+		b.Mean += v.cfg.Smoothing * d
+		b.Variance = (1 - v.cfg.Smoothing) * (b.Variance + v.cfg.Smoothing*d*d)
+		b.Windows++
+	}
+
+	v.counts = make(map[string]int)
+	return anomalies
+}
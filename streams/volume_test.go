@@ -0,0 +1,99 @@
+package streams
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/store"
+)
+
+func TestVolumeRoll(t *testing.T) {
+	v := newVolume(VolumeConfig{Smoothing: 0.2, Warmup: 10, ZScore: 4})
+
+	for i := 0; i < 20; i++ {
+		v.counts["golang"] = 10 + i%3
+		if a := v.roll(time.Time{}); len(a) != 0 {
+			t.Fatalf("window %d: wanted steady volume; got %+v", i, a[0])
+		}
+	}
+
+	v.counts["golang"] = 60
+	a := v.roll(time.Time{})
+	if len(a) != 1 || a[0].Subreddit != "golang" || a[0].ZScore <= 0 {
+		t.Fatalf("wanted a spike in golang; got %v", a)
+	}
+
+}
+
+func TestVolumeDrop(t *testing.T) {
+	v := newVolume(VolumeConfig{Smoothing: 0.2, Warmup: 10, ZScore: 4})
+	for i := 0; i < 20; i++ {
+		v.counts["golang"] = 30 + i%3
+		v.roll(time.Time{})
+	}
+
+	// Quiet subreddits count zero posts, which is a drop.
+	a := v.roll(time.Time{})
+	if len(a) != 1 || a[0].Count != 0 || a[0].ZScore >= 0 {
+		t.Errorf("wanted a drop in golang; got %v", a)
+	}
+}
+
+func TestVolumeWarmup(t *testing.T) {
+	v := newVolume(VolumeConfig{Smoothing: 0.2, Warmup: 3, ZScore: 1})
+	for _, count := range []int{1, 50, 1} {
+		v.counts["golang"] = count
+		if a := v.roll(time.Time{}); len(a) != 0 {
+			t.Errorf("wanted no anomalies during warmup; got %+v", a[0])
+		}
+	}
+}
+
+func TestPostVolumePersists(t *testing.T) {
+	s := store.NewMemoryStore()
+	sim := clock.NewSimulation(time.Unix(0, 0))
+	kill := make(chan bool)
+	errs := make(chan error)
+	posts := make(chan *reddit.Post)
+
+	anomalies, err := PostVolume(kill, errs, posts, VolumeConfig{
+		Window: time.Minute,
+		ZScore: 100,
+		Store:  s,
+		Clock:  sim,
+	})
+	if err != nil {
+		t.Fatalf("failed to start stream: %v", err)
+	}
+
+	posts <- &reddit.Post{Subreddit: "GoLang"}
+	posts <- &reddit.Post{Subreddit: "golang"}
+	sim.Sleep(time.Minute)
+
+	// Wait for the window to be saved.
+	deadline := time.Now().Add(time.Second)
+	var saved map[string]*baseline
+	for time.Now().Before(deadline) {
+		if err := s.Load(volumeKey, &saved); err == nil {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(kill)
+	for range anomalies {
+	}
+
+	if b := saved["golang"]; b == nil || b.Mean != 2 || b.Windows != 1 {
+		t.Fatalf("wanted golang baseline saved; got %+v", saved)
+	}
+
+	v := newVolume(VolumeConfig{})
+	if err := s.Load(volumeKey, &v.baselines); err != nil {
+		t.Fatalf("failed to load baselines: %v", err)
+	}
+	if v.baselines["golang"].Windows != 1 {
+		t.Errorf("baseline did not survive restart: %+v", v.baselines["golang"])
+	}
+}
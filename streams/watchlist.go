@@ -0,0 +1,98 @@
+package streams
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// Watch is an entry in a watchlist.
+type Watch struct {
+	// Username is the watched user.
+	Username string
+	// Note is why the user is watched, for the mods acting on alerts.
+	Note string
+	// AddedBy is the mod who added the entry.
+	AddedBy string
+	// Added is when the entry was added.
+	Added time.Time
+	// Anywhere asks for the user's activity anywhere on Reddit, not just
+	// in the monitored subreddits. graw opens a user stream for each such
+	// entry present when the run starts.
+	Anywhere bool
+}
+
+// WatchedActivity is a post or comment made by a watched user. Exactly one of
+// Post and Comment is set.
+type WatchedActivity struct {
+	// Watch is the watchlist entry the author matched.
+	Watch   Watch
+	Post    *reddit.Post
+	Comment *reddit.Comment
+}
+
+// Watchlist is a set of watched users. It is safe to change while a bot is
+// running.
+type Watchlist interface {
+	// Add adds an entry to the watchlist, replacing any entry for the same
+	// user.
+	Add(w Watch)
+	// Remove removes the user from the watchlist.
+	Remove(username string)
+	// Match returns the entry for the author, if they are watched.
+	Match(author string) (Watch, bool)
+	// Watches returns every entry in the watchlist, ordered by username.
+	Watches() []Watch
+}
+
+type watchlist struct {
+	mu      sync.RWMutex
+	watches map[string]Watch
+}
+
+// NewWatchlist returns a watchlist holding the given entries. Usernames are
+// matched without regard to case, as Reddit does.
+func NewWatchlist(watches ...Watch) Watchlist {
+	w := &watchlist{watches: make(map[string]Watch)}
+	for _, watch := range watches {
+		w.Add(watch)
+	}
+	return w
+}
+
+func (w *watchlist) Add(watch Watch) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.watches[strings.ToLower(watch.Username)] = watch
+}
+
+func (w *watchlist) Remove(username string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.watches, strings.ToLower(username))
+}
+
+func (w *watchlist) Match(author string) (Watch, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	watch, ok := w.watches[strings.ToLower(author)]
+	return watch, ok
+}
+
+func (w *watchlist) Watches() []Watch {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	watches := make([]Watch, 0, len(w.watches))
+	for _, watch := range w.watches {
+		watches = append(watches, watch)
+	}
+	sort.Slice(watches, func(i, j int) bool {
+		return strings.ToLower(watches[i].Username) <
+			strings.ToLower(watches[j].Username)
+	})
+	return watches
+}
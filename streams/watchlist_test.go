@@ -0,0 +1,30 @@
+package streams
+
+import (
+	"testing"
+)
+
+func TestWatchlist(t *testing.T) {
+	w := NewWatchlist(
+		Watch{Username: "Spammer", Note: "link farm"},
+		Watch{Username: "alt_account"},
+	)
+
+	if watch, ok := w.Match("spammer"); !ok || watch.Note != "link farm" {
+		t.Errorf("wanted spammer matched regardless of case; got %+v, %v", watch, ok)
+	}
+
+	w.Add(Watch{Username: "SPAMMER", Note: "ban evasion"})
+	if watch, _ := w.Match("Spammer"); watch.Note != "ban evasion" {
+		t.Errorf("wanted entry replaced; got %+v", watch)
+	}
+
+	w.Remove("Alt_Account")
+	if _, ok := w.Match("alt_account"); ok {
+		t.Errorf("wanted alt_account removed")
+	}
+
+	if watches := w.Watches(); len(watches) != 1 || watches[0].Username != "SPAMMER" {
+		t.Errorf("got watches %+v", watches)
+	}
+}
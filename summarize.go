@@ -0,0 +1,45 @@
+package graw
+
+import (
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/streams"
+	"github.com/aldarisbm/graw/summarize"
+)
+
+// connectSummarizer answers summons from the bot's mentions and messages
+// with the config's summarizer, if it has one.
+func connectSummarizer(
+	bot reddit.Bot,
+	c Config,
+	from streams.Start,
+	kill <-chan bool,
+	errs chan<- error,
+) error {
+	if c.Summarizer == nil {
+		return nil
+	}
+
+	if c.Ledger != nil {
+		bot = c.Ledger.Bot(bot)
+	}
+
+	mentions, err := streams.MentionsFrom(bot, kill, errs, from)
+	if err != nil {
+		return err
+	}
+
+	messages, err := streams.MessagesFrom(bot, kill, errs, from)
+	if err != nil {
+		return err
+	}
+
+	summon := func(ms <-chan *reddit.Message) {
+		for m := range ms {
+			errs <- summarize.Summon(bot, c.Summarizer, c.Summarize, m)
+		}
+	}
+	go summon(mentions)
+	go summon(messages)
+
+	return nil
+}
@@ -0,0 +1,183 @@
+// Package summarize lets bots reply to summons with a summary of a thread.
+// The bot supplies only the Summarizer; fetching and expanding the thread,
+// splitting long summaries, and posting them are handled here.
+package summarize
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+const (
+	// maxCommentLength is the longest comment Reddit accepts.
+	maxCommentLength = 10000
+	// DefaultCommand is the private message command which summons a
+	// summary, followed by a link to the thread.
+	DefaultCommand = "summarize"
+)
+
+var (
+	noLinkErr     = fmt.Errorf("summons has no link to a thread")
+	badLinkErr    = fmt.Errorf("link is not to a Reddit thread")
+	noSummaryErr  = fmt.Errorf("summarizer returned no summary")
+	notSummonsErr = fmt.Errorf("message is not a summons")
+)
+
+// Summarizer summarizes threads.
+type Summarizer interface {
+	// Summarize returns a summary of the thread, given its post with the
+	// fully expanded comment tree in its Replies. The summary is posted as
+	// markdown.
+	Summarize(thread *reddit.Post) (string, error)
+}
+
+// Config configures how summons are answered.
+type Config struct {
+	// Command is the private message command which summons a summary. If
+	// empty, DefaultCommand is used. Mentions always summon a summary of
+	// the thread they are in.
+	Command string
+	// MaxExpansions caps the requests made to expand a thread's comment
+	// tree. If zero, 20 are allowed.
+	MaxExpansions int
+	// Thread chooses the sort and limits of the comment tree fetched before
+	// it is expanded.
+	Thread reddit.ThreadOptions
+}
+
+// Summon answers a summons, which is either a mention of the bot in a thread
+// or a private message holding the command and a link to a thread. The
+// summary is posted in reply to the summons, split over several comments if
+// it is too long for one. Messages which are not summons are ignored.
+func Summon(bot reddit.Bot, s Summarizer, c Config, m *reddit.Message) error {
+	permalink, err := summoned(c, m)
+	if err == notSummonsErr {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	thread, err := bot.ThreadWithOptions(permalink, c.Thread)
+	if err != nil {
+		return err
+	}
+
+	if err := bot.ExpandThread(thread, maxExpansions(c)); err != nil {
+		return err
+	}
+
+	summary, err := s.Summarize(thread)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(summary) == "" {
+		return noSummaryErr
+	}
+
+	return post(bot, m.Name, Split(summary, maxCommentLength))
+}
+
+func maxExpansions(c Config) int {
+	if c.MaxExpansions == 0 {
+		return 20
+	}
+	return c.MaxExpansions
+}
+
+// summoned returns the permalink of the thread a message summons a summary
+// of, or notSummonsErr.
+func summoned(c Config, m *reddit.Message) (string, error) {
+	if m.WasComment {
+		return threadPermalink(m.Context)
+	}
+
+	command := c.Command
+	if command == "" {
+		command = DefaultCommand
+	}
+
+	fields := strings.Fields(m.Body)
+	if len(fields) == 0 || !strings.EqualFold(fields[0], command) {
+		return "", notSummonsErr
+	}
+
+	if len(fields) < 2 {
+		return "", noLinkErr
+	}
+
+	return threadPermalink(fields[1])
+}
+
+// threadPermalink returns the permalink of the thread a Reddit link points
+// into, e.g. /r/golang/comments/abc123/ for a link to a comment in it.
+func threadPermalink(link string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", badLinkErr
+	}
+
+	if u.Host != "" && !strings.HasSuffix(u.Host, "reddit.com") {
+		return "", badLinkErr
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 4 || parts[0] != "r" || parts[2] != "comments" {
+		return "", badLinkErr
+	}
+
+	return "/r/" + parts[1] + "/comments/" + parts[3] + "/", nil
+}
+
+// post posts the parts of a summary as a chain of replies to the parent.
+func post(bot reddit.Bot, parent string, parts []string) error {
+	for _, part := range parts {
+		s, err := bot.GetReply(parent, part)
+		if err != nil {
+			return err
+		}
+		parent = s.Name
+	}
+	return nil
+}
+
+// Split splits text into parts no longer than max bytes, breaking between
+// paragraphs, then lines, then words where it can.
+func Split(text string, max int) []string {
+	var parts []string
+	text = strings.TrimSpace(text)
+	for len(text) > max {
+		cut := lastBreak(text[:max+1])
+		parts = append(parts, strings.TrimSpace(text[:cut]))
+		text = strings.TrimSpace(text[cut:])
+	}
+
+	if text != "" {
+		parts = append(parts, text)
+	}
+	return parts
+}
+
+// lastBreak returns where to cut a part from the front of text, which is one
+// byte longer than a part may be.
+func lastBreak(text string) int {
+	max := len(text) - 1
+	for _, sep := range []string{"\n\n", "\n", " "} {
+		if i := strings.LastIndex(text, sep); i > 0 {
+			return i
+		}
+	}
+
+	// No break; cut at the last rune boundary that fits.
+	for max > 0 && !runeStart(text[max]) {
+		max--
+	}
+	return max
+}
+
+func runeStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
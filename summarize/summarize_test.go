@@ -0,0 +1,197 @@
+package summarize
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+func TestSplit(t *testing.T) {
+	for i, test := range []struct {
+		text  string
+		max   int
+		parts []string
+	}{
+		{"short", 10, []string{"short"}},
+		{"one two three four", 9, []string{"one two", "three", "four"}},
+		{"para one\n\npara two", 15, []string{"para one", "para two"}},
+		{"line one\nline two", 12, []string{"line one", "line two"}},
+		{"abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"ééé", 3, []string{"é", "é", "é"}},
+		{"  ", 5, nil},
+	} {
+		if parts := Split(test.text, test.max); !reflect.DeepEqual(parts, test.parts) {
+			t.Errorf("%d: got %q; wanted %q", i, parts, test.parts)
+		}
+	}
+}
+
+func TestSummoned(t *testing.T) {
+	for i, test := range []struct {
+		m         reddit.Message
+		permalink string
+		err       error
+	}{
+		{
+			reddit.Message{
+				WasComment: true,
+				Context:    "/r/golang/comments/abc123/title/def456/?context=3",
+			},
+			"/r/golang/comments/abc123/", nil,
+		},
+		{
+			reddit.Message{Body: "Summarize https://www.reddit.com/r/golang/comments/abc123/"},
+			"/r/golang/comments/abc123/", nil,
+		},
+		{reddit.Message{Body: "summarize"}, "", noLinkErr},
+		{reddit.Message{Body: "summarize https://example.com/r/a/comments/b"}, "", badLinkErr},
+		{reddit.Message{Body: "summarize /r/golang/wiki/index"}, "", badLinkErr},
+		{reddit.Message{Body: "hello there"}, "", notSummonsErr},
+		{reddit.Message{}, "", notSummonsErr},
+	} {
+		permalink, err := summoned(Config{}, &test.m)
+		if permalink != test.permalink || err != test.err {
+			t.Errorf("%d: got %q, %v; wanted %q, %v", i, permalink, err, test.permalink, test.err)
+		}
+	}
+}
+
+type mockBot struct {
+	reddit.Bot
+	thread  *reddit.Post
+	more    map[string]reddit.Harvest
+	replies []string
+	parents []string
+	// maxRequests is the cap the thread was expanded with.
+	maxRequests int
+}
+
+func (m *mockBot) ThreadWithOptions(
+	permalink string,
+	opts reddit.ThreadOptions,
+) (*reddit.Post, error) {
+	return m.thread, nil
+}
+
+// ExpandThread grafts the comments of the mock's more harvests into the thread
+// in place of the stubs whose first child they are keyed by.
+func (m *mockBot) ExpandThread(thread *reddit.Post, maxRequests int) error {
+	nodes := make(map[string]*reddit.Comment)
+	var stubs []*reddit.More
+	var walk func([]*reddit.Comment)
+	walk = func(comments []*reddit.Comment) {
+		for _, c := range comments {
+			nodes[c.Name] = c
+			if c.More != nil {
+				stubs = append(stubs, c.More)
+				c.More = nil
+			}
+			walk(c.Replies)
+		}
+	}
+	walk(thread.Replies)
+	if thread.More != nil {
+		stubs = append(stubs, thread.More)
+		thread.More = nil
+	}
+
+	m.maxRequests = maxRequests
+	for _, more := range stubs {
+		for _, c := range m.more[more.Children[0]].Comments {
+			nodes[c.Name] = c
+			if parent, ok := nodes[c.ParentID]; ok {
+				parent.Replies = append(parent.Replies, c)
+			} else {
+				thread.Replies = append(thread.Replies, c)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *mockBot) GetReply(parent, text string) (reddit.Submission, error) {
+	m.parents = append(m.parents, parent)
+	m.replies = append(m.replies, text)
+	return reddit.Submission{Name: fmt.Sprintf("t1_reply%d", len(m.replies))}, nil
+}
+
+type countingSummarizer struct{}
+
+// Summarize summarizes a thread by counting its comments, at great length.
+func (countingSummarizer) Summarize(thread *reddit.Post) (string, error) {
+	count := 0
+	var walk func([]*reddit.Comment)
+	walk = func(comments []*reddit.Comment) {
+		for _, c := range comments {
+			count++
+			walk(c.Replies)
+		}
+	}
+	walk(thread.Replies)
+	return fmt.Sprintf("%d comments\n\n%s", count, strings.Repeat("x", maxCommentLength)), nil
+}
+
+func TestSummon(t *testing.T) {
+	top := &reddit.Comment{
+		Name: "t1_a",
+		More: &reddit.More{Children: []string{"c"}},
+	}
+	bot := &mockBot{
+		thread: &reddit.Post{
+			Name:    "t3_p",
+			Replies: []*reddit.Comment{top},
+			More:    &reddit.More{Children: []string{"b"}},
+		},
+		more: map[string]reddit.Harvest{
+			"b": {
+				Comments: []*reddit.Comment{{Name: "t1_b", ParentID: "t3_p"}},
+			},
+			"c": {
+				Comments: []*reddit.Comment{
+					{Name: "t1_c", ParentID: "t1_a"},
+					{Name: "t1_d", ParentID: "t1_c"},
+				},
+				Mores: []*reddit.More{{ParentID: "t1_d"}},
+			},
+		},
+	}
+
+	mention := &reddit.Message{
+		Name:       "t1_mention",
+		WasComment: true,
+		Context:    "/r/golang/comments/p/title/mention/?context=3",
+	}
+	if err := Summon(bot, countingSummarizer{}, Config{}, mention); err != nil {
+		t.Fatalf("summons failed: %v", err)
+	}
+
+	if len(bot.replies) != 2 || bot.replies[0] != "4 comments" {
+		t.Fatalf("wanted summary of 4 comments in 2 parts; got %d parts", len(bot.replies))
+	}
+
+	if !reflect.DeepEqual(bot.parents, []string{"t1_mention", "t1_reply1"}) {
+		t.Errorf("wanted parts chained as replies; got parents %v", bot.parents)
+	}
+
+	if len(top.Replies) != 1 || len(top.Replies[0].Replies) != 1 {
+		t.Errorf("expanded comments were not grafted into the tree")
+	}
+
+	if bot.maxRequests != 20 {
+		t.Errorf("expanded the thread with up to %d requests; wanted the default of 20", bot.maxRequests)
+	}
+}
+
+func TestSummonIgnoresChatter(t *testing.T) {
+	bot := &mockBot{}
+	if err := Summon(bot, countingSummarizer{}, Config{}, &reddit.Message{Body: "hi"}); err != nil {
+		t.Errorf("wanted chatter ignored; got %v", err)
+	}
+
+	if len(bot.replies) != 0 {
+		t.Errorf("replied to chatter")
+	}
+}
@@ -0,0 +1,127 @@
+package graw
+
+import (
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/reputation"
+	"github.com/aldarisbm/graw/stats"
+	"github.com/aldarisbm/graw/streams"
+)
+
+// taps feed the posts and comments dispatched from scan streams to the
+// aggregate streams which watch them. Unused taps are nil.
+type taps struct {
+	// track takes the fullnames of elements to check against thresholds.
+	track chan<- string
+	// texts takes the text of elements to find trends in.
+	texts chan<- string
+	// posts takes posts to count the volume of.
+	posts chan<- *reddit.Post
+	// watched takes the activity of users on the watchlist.
+	watched   chan<- *streams.WatchedActivity
+	watchlist streams.Watchlist
+	seen      *recent
+	// links takes link posts with their domain's reputation.
+	links  chan<- *reputation.LinkPost
+	ledger reputation.Ledger
+	// stats counts posts and comments toward their subreddit's statistics.
+	stats stats.Aggregator
+	// authors counts posts and comments toward their author's activity.
+	authors stats.Authors
+	// overlap records posts' and comments' authors in the overlap graph.
+	overlap stats.Overlap
+	// handled takes the fullnames of posts forwarded to the PostHandler,
+	// which rising posts are checked against.
+	handled *recent
+	kill    <-chan bool
+}
+
+func (t taps) post(p *reddit.Post) {
+	t.send(t.track, p.Name)
+	t.send(t.texts, p.Title+"\n"+p.SelfText)
+	if t.posts != nil {
+		select {
+		case t.posts <- p:
+		case <-t.kill:
+		}
+	}
+	t.watchPost(p)
+	if t.stats != nil {
+		t.stats.Post(p)
+	}
+	if t.authors != nil {
+		t.authors.Post(p)
+	}
+	if t.overlap != nil {
+		t.overlap.Post(p)
+	}
+	if t.links != nil {
+		if lp := reputation.Enrich(t.ledger, p); lp != nil {
+			select {
+			case t.links <- lp:
+			case <-t.kill:
+			}
+		}
+	}
+}
+
+// handle records that the post was forwarded to the PostHandler.
+func (t taps) handle(p *reddit.Post) {
+	if t.handled != nil {
+		t.handled.add(p.Name)
+	}
+}
+
+func (t taps) comment(c *reddit.Comment) {
+	t.send(t.track, c.Name)
+	t.send(t.texts, c.Body)
+	if t.stats != nil {
+		t.stats.Comment(c)
+	}
+	if t.authors != nil {
+		t.authors.Comment(c)
+	}
+	if t.overlap != nil {
+		t.overlap.Comment(c)
+	}
+	t.watchComment(c)
+}
+
+// watchPost dispatches the post if its author is watched.
+func (t taps) watchPost(p *reddit.Post) {
+	if t.watched == nil {
+		return
+	}
+
+	if w, ok := t.watchlist.Match(p.Author); ok && t.seen.add(p.Name) {
+		t.dispatch(&streams.WatchedActivity{Watch: w, Post: p})
+	}
+}
+
+// watchComment dispatches the comment if its author is watched.
+func (t taps) watchComment(c *reddit.Comment) {
+	if t.watched == nil {
+		return
+	}
+
+	if w, ok := t.watchlist.Match(c.Author); ok && t.seen.add(c.Name) {
+		t.dispatch(&streams.WatchedActivity{Watch: w, Comment: c})
+	}
+}
+
+func (t taps) dispatch(a *streams.WatchedActivity) {
+	select {
+	case t.watched <- a:
+	case <-t.kill:
+	}
+}
+
+func (t taps) send(tap chan<- string, s string) {
+	if tap == nil {
+		return
+	}
+
+	select {
+	case tap <- s:
+	case <-t.kill:
+	}
+}
@@ -0,0 +1,64 @@
+package graw
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aldarisbm/graw/botfaces"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/streams"
+)
+
+// defaultThresholdInterval is how often tracked elements are checked against
+// the Config's Thresholds if no interval is given.
+const defaultThresholdInterval = 5 * time.Minute
+
+var crossingHandlerErr = fmt.Errorf(
+	"You must implement CrossingHandler to take threshold crossings.",
+)
+
+// connectThresholds connects a threshold stream to the handler if the config
+// asks for one, and returns the channel to track elements with. The channel
+// is nil if there are no thresholds.
+func connectThresholds(
+	handler interface{},
+	sc reddit.Scanner,
+	c Config,
+	kill <-chan bool,
+	errs chan<- error,
+) (chan<- string, error) {
+	if len(c.Thresholds) == 0 {
+		return nil, nil
+	}
+
+	ch, ok := handler.(botfaces.CrossingHandler)
+	if !ok {
+		return nil, crossingHandlerErr
+	}
+
+	interval := c.ThresholdInterval
+	if interval == 0 {
+		interval = defaultThresholdInterval
+	}
+
+	track := make(chan string)
+	crossings, err := streams.Thresholds(
+		sc,
+		kill,
+		errs,
+		track,
+		interval,
+		c.Thresholds...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		for cr := range crossings {
+			errs <- ch.Crossing(cr)
+		}
+	}()
+
+	return track, nil
+}
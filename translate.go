@@ -0,0 +1,54 @@
+package graw
+
+import (
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/translate"
+)
+
+// translation translates events into the bot's language before they are
+// handled, if the config has a translation pipeline.
+type translation struct {
+	p translate.Pipeline
+}
+
+func (t translation) post(p *reddit.Post, handle func(*reddit.Post) error) error {
+	if t.p == nil {
+		return handle(p)
+	}
+
+	p, err := t.p.Post(p)
+	if err != nil {
+		return err
+	}
+	return handle(p)
+}
+
+func (t translation) comment(
+	c *reddit.Comment,
+	handle func(*reddit.Comment) error,
+) error {
+	if t.p == nil {
+		return handle(c)
+	}
+
+	c, err := t.p.Comment(c)
+	if err != nil {
+		return err
+	}
+	return handle(c)
+}
+
+func (t translation) message(
+	m *reddit.Message,
+	handle func(*reddit.Message) error,
+) error {
+	if t.p == nil {
+		return handle(m)
+	}
+
+	m, err := t.p.Message(m)
+	if err != nil {
+		return err
+	}
+	return handle(m)
+}
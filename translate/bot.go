@@ -0,0 +1,131 @@
+package translate
+
+import (
+	"context"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// translatedBot is a Bot whose replies and posts are translated into the
+// language of the subreddit they are made in.
+type translatedBot struct {
+	reddit.Bot
+	p *pipeline
+}
+
+func (t *translatedBot) ReadOnly() reddit.Bot {
+	return &translatedBot{Bot: t.Bot.ReadOnly(), p: t.p}
+}
+
+func (t *translatedBot) Restrict(p reddit.Permission) reddit.Bot {
+	return &translatedBot{Bot: t.Bot.Restrict(p), p: t.p}
+}
+
+func (t *translatedBot) WithContext(ctx context.Context) reddit.Bot {
+	return &translatedBot{Bot: t.Bot.WithContext(ctx), p: t.p}
+}
+
+func (t *translatedBot) Reply(parentName, text string) error {
+	text, err := t.p.out(text, t.p.subreddit(parentName))
+	if err != nil {
+		return err
+	}
+	return t.Bot.Reply(parentName, text)
+}
+
+func (t *translatedBot) GetReply(parentName, text string) (reddit.Submission, error) {
+	text, err := t.p.out(text, t.p.subreddit(parentName))
+	if err != nil {
+		return reddit.Submission{}, err
+	}
+	return t.Bot.GetReply(parentName, text)
+}
+
+func (t *translatedBot) EditText(name, text string) error {
+	text, err := t.p.out(text, t.p.subreddit(name))
+	if err != nil {
+		return err
+	}
+	return t.Bot.EditText(name, text)
+}
+
+func (t *translatedBot) PostSelf(subreddit, title, text string) error {
+	title, text, err := t.post(subreddit, title, text)
+	if err != nil {
+		return err
+	}
+	return t.Bot.PostSelf(subreddit, title, text)
+}
+
+func (t *translatedBot) GetPostSelf(subreddit, title, text string) (reddit.Submission, error) {
+	title, text, err := t.post(subreddit, title, text)
+	if err != nil {
+		return reddit.Submission{}, err
+	}
+	return t.Bot.GetPostSelf(subreddit, title, text)
+}
+
+func (t *translatedBot) GetPostSelfWithFlair(
+	subreddit, title, text string,
+	flair reddit.PostFlair,
+) (reddit.Submission, error) {
+	title, text, err := t.post(subreddit, title, text)
+	if err != nil {
+		return reddit.Submission{}, err
+	}
+	return t.Bot.GetPostSelfWithFlair(subreddit, title, text, flair)
+}
+
+func (t *translatedBot) PostLink(subreddit, title, url string) error {
+	title, err := t.p.out(title, subreddit)
+	if err != nil {
+		return err
+	}
+	return t.Bot.PostLink(subreddit, title, url)
+}
+
+func (t *translatedBot) GetPostLink(subreddit, title, url string) (reddit.Submission, error) {
+	title, err := t.p.out(title, subreddit)
+	if err != nil {
+		return reddit.Submission{}, err
+	}
+	return t.Bot.GetPostLink(subreddit, title, url)
+}
+
+func (t *translatedBot) SubmitGallery(
+	subreddit, title string,
+	images []reddit.GalleryImage,
+) (reddit.Submission, error) {
+	title, err := t.p.out(title, subreddit)
+	if err != nil {
+		return reddit.Submission{}, err
+	}
+	return t.Bot.SubmitGallery(subreddit, title, images)
+}
+
+func (t *translatedBot) SubmitPoll(subreddit, title string, poll reddit.Poll) (reddit.Submission, error) {
+	title, text, err := t.post(subreddit, title, poll.Text)
+	if err != nil {
+		return reddit.Submission{}, err
+	}
+	poll.Text = text
+	return t.Bot.SubmitPoll(subreddit, title, poll)
+}
+
+func (t *translatedBot) SubmitVideo(subreddit, title string, video reddit.Video) (reddit.Submission, error) {
+	title, err := t.p.out(title, subreddit)
+	if err != nil {
+		return reddit.Submission{}, err
+	}
+	return t.Bot.SubmitVideo(subreddit, title, video)
+}
+
+func (t *translatedBot) post(subreddit, title, text string) (string, string, error) {
+	title, err := t.p.out(title, subreddit)
+	if err != nil {
+		return "", "", err
+	}
+
+	text, err = t.p.out(text, subreddit)
+	return title, text, err
+}
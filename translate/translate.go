@@ -0,0 +1,209 @@
+// Package translate plugs a translation function into a bot, so that one bot
+// can serve, or mirror posts between, communities in different languages.
+//
+// A Pipeline translates incoming posts, comments, and messages from their
+// subreddit's language into the bot's, and wraps the bot so its replies and
+// posts are translated back into the language of the subreddit they are
+// made in:
+//
+//	p := translate.New(translate.Config{
+//		Translator: myTranslator,
+//		Language:   "en",
+//		Subreddits: map[string]string{"de": "de", "france": "fr"},
+//	})
+//	bot = p.Bot(bot)
+//	cfg := graw.Config{Subreddits: []string{"de", "france"}, Translation: p}
+package translate
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// maxRemembered is how many fullnames of translated elements the pipeline
+// remembers the subreddits of, so replies to them are translated back.
+const maxRemembered = 10000
+
+// Translator translates text between languages. Languages are named by
+// whatever codes the translator understands, e.g. "en" or "pt-BR".
+type Translator interface {
+	Translate(text, from, to string) (string, error)
+}
+
+// Config configures a Pipeline.
+type Config struct {
+	Translator Translator
+	// Language is the language the bot reads and writes in.
+	Language string
+	// Subreddits maps subreddit names to the language they are written in.
+	// Content in subreddits which are not named here, or which are written
+	// in Language, is not translated.
+	Subreddits map[string]string
+	// IncomingOnly, when true, translates what the bot reads but never
+	// what it writes.
+	IncomingOnly bool
+	// OutgoingOnly, when true, translates what the bot writes but never
+	// what it reads.
+	OutgoingOnly bool
+}
+
+// Pipeline translates the content a bot reads and writes.
+type Pipeline interface {
+	// Post returns a copy of the post with its title and text translated
+	// into the bot's language.
+	Post(p *reddit.Post) (*reddit.Post, error)
+	// Comment returns a copy of the comment with its body translated into
+	// the bot's language. Its replies are not translated.
+	Comment(c *reddit.Comment) (*reddit.Comment, error)
+	// Message returns a copy of the message with its subject and body
+	// translated into the bot's language. Only messages sent from a
+	// subreddit, such as comment replies and mentions, are translated.
+	Message(m *reddit.Message) (*reddit.Message, error)
+	// Bot wraps the bot so that its replies and posts are translated into
+	// the language of the subreddit they are made in. The subreddit of a
+	// reply is known only if the pipeline translated its parent, or the
+	// parent's subreddit is written in the bot's language; replies to
+	// anything else are made as they are.
+	Bot(b reddit.Bot) reddit.Bot
+}
+
+type pipeline struct {
+	cfg       Config
+	languages map[string]string
+
+	mu sync.Mutex
+	// subreddits maps the fullnames of elements the pipeline has read to
+	// the subreddit they were in.
+	subreddits map[string]string
+	order      []string
+}
+
+// New returns a Pipeline which translates with the config's translator.
+func New(c Config) Pipeline {
+	languages := make(map[string]string, len(c.Subreddits))
+	for sub, lang := range c.Subreddits {
+		languages[strings.ToLower(sub)] = lang
+	}
+
+	return &pipeline{
+		cfg:        c,
+		languages:  languages,
+		subreddits: make(map[string]string),
+	}
+}
+
+func (p *pipeline) Post(post *reddit.Post) (*reddit.Post, error) {
+	p.remember(post.Name, post.Subreddit)
+	lang, ok := p.foreign(post.Subreddit, p.cfg.OutgoingOnly)
+	if !ok {
+		return post, nil
+	}
+
+	translated := *post
+	var err error
+	if translated.Title, err = p.in(post.Title, lang); err != nil {
+		return nil, err
+	}
+	if translated.SelfText, err = p.in(post.SelfText, lang); err != nil {
+		return nil, err
+	}
+	return &translated, nil
+}
+
+func (p *pipeline) Comment(c *reddit.Comment) (*reddit.Comment, error) {
+	p.remember(c.Name, c.Subreddit)
+	lang, ok := p.foreign(c.Subreddit, p.cfg.OutgoingOnly)
+	if !ok {
+		return c, nil
+	}
+
+	translated := *c
+	var err error
+	if translated.Body, err = p.in(c.Body, lang); err != nil {
+		return nil, err
+	}
+	return &translated, nil
+}
+
+func (p *pipeline) Message(m *reddit.Message) (*reddit.Message, error) {
+	if m.Subreddit == "" {
+		return m, nil
+	}
+
+	p.remember(m.Name, m.Subreddit)
+	lang, ok := p.foreign(m.Subreddit, p.cfg.OutgoingOnly)
+	if !ok {
+		return m, nil
+	}
+
+	translated := *m
+	var err error
+	if translated.Subject, err = p.in(m.Subject, lang); err != nil {
+		return nil, err
+	}
+	if translated.Body, err = p.in(m.Body, lang); err != nil {
+		return nil, err
+	}
+	return &translated, nil
+}
+
+func (p *pipeline) Bot(b reddit.Bot) reddit.Bot {
+	return &translatedBot{Bot: b, p: p}
+}
+
+// foreign returns the language of the subreddit and whether content in it
+// should be translated.
+func (p *pipeline) foreign(subreddit string, disabled bool) (string, bool) {
+	if disabled || p.cfg.Translator == nil {
+		return "", false
+	}
+
+	lang, ok := p.languages[strings.ToLower(subreddit)]
+	return lang, ok && lang != p.cfg.Language
+}
+
+// in translates text from lang into the bot's language.
+func (p *pipeline) in(text, lang string) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	return p.cfg.Translator.Translate(text, lang, p.cfg.Language)
+}
+
+// out translates text from the bot's language into the language of the
+// subreddit, if it is foreign.
+func (p *pipeline) out(text, subreddit string) (string, error) {
+	lang, ok := p.foreign(subreddit, p.cfg.IncomingOnly)
+	if !ok || text == "" {
+		return text, nil
+	}
+	return p.cfg.Translator.Translate(text, p.cfg.Language, lang)
+}
+
+func (p *pipeline) remember(name, subreddit string) {
+	if name == "" || subreddit == "" {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.subreddits[name]; ok {
+		return
+	}
+
+	if len(p.order) >= maxRemembered {
+		delete(p.subreddits, p.order[0])
+		p.order = p.order[1:]
+	}
+	p.subreddits[name] = subreddit
+	p.order = append(p.order, name)
+}
+
+func (p *pipeline) subreddit(name string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.subreddits[name]
+}
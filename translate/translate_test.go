@@ -0,0 +1,127 @@
+package translate
+
+import (
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// tagTranslator "translates" text by tagging it with its languages.
+type tagTranslator struct{}
+
+func (tagTranslator) Translate(text, from, to string) (string, error) {
+	return from + ">" + to + ":" + text, nil
+}
+
+type replyBot struct {
+	reddit.Bot
+	replies []string
+	titles  []string
+}
+
+func (r *replyBot) Reply(parentName, text string) error {
+	r.replies = append(r.replies, text)
+	return nil
+}
+
+func (r *replyBot) PostLink(subreddit, title, url string) error {
+	r.titles = append(r.titles, title)
+	return nil
+}
+
+func testPipeline(c Config) Pipeline {
+	c.Translator = tagTranslator{}
+	c.Language = "en"
+	c.Subreddits = map[string]string{"France": "fr", "english": "en"}
+	return New(c)
+}
+
+func TestIncoming(t *testing.T) {
+	p := testPipeline(Config{})
+
+	original := &reddit.Post{
+		Name:      "t3_a",
+		Subreddit: "france",
+		Title:     "bonjour",
+	}
+	post, err := p.Post(original)
+	if err != nil {
+		t.Fatalf("failed to translate post: %v", err)
+	}
+
+	if post.Title != "fr>en:bonjour" || post.SelfText != "" {
+		t.Errorf("got title %q, text %q", post.Title, post.SelfText)
+	}
+
+	if original.Title != "bonjour" {
+		t.Errorf("translation modified the original post")
+	}
+
+	comment, err := p.Comment(&reddit.Comment{Subreddit: "english", Body: "hi"})
+	if err != nil {
+		t.Fatalf("failed to translate comment: %v", err)
+	}
+
+	if comment.Body != "hi" {
+		t.Errorf("translated comment written in the bot's language: %q", comment.Body)
+	}
+
+	message, err := p.Message(&reddit.Message{Subject: "salut", Body: "salut"})
+	if err != nil {
+		t.Fatalf("failed to translate message: %v", err)
+	}
+
+	if message.Body != "salut" {
+		t.Errorf("translated private message: %q", message.Body)
+	}
+}
+
+func TestOutgoing(t *testing.T) {
+	p := testPipeline(Config{})
+	inner := &replyBot{}
+	bot := p.Bot(inner)
+
+	if _, err := p.Comment(&reddit.Comment{Name: "t1_a", Subreddit: "France"}); err != nil {
+		t.Fatalf("failed to translate comment: %v", err)
+	}
+
+	for _, parent := range []string{"t1_a", "t1_unknown"} {
+		if err := bot.Reply(parent, "hello"); err != nil {
+			t.Fatalf("failed to reply: %v", err)
+		}
+	}
+
+	if err := bot.PostLink("france", "news", "https://example.com"); err != nil {
+		t.Fatalf("failed to post: %v", err)
+	}
+
+	if inner.replies[0] != "en>fr:hello" || inner.replies[1] != "hello" {
+		t.Errorf("got replies %q", inner.replies)
+	}
+
+	if inner.titles[0] != "en>fr:news" {
+		t.Errorf("got title %q", inner.titles[0])
+	}
+}
+
+func TestOneWay(t *testing.T) {
+	p := testPipeline(Config{IncomingOnly: true})
+	inner := &replyBot{}
+	if err := p.Bot(inner).PostLink("france", "news", ""); err != nil {
+		t.Fatalf("failed to post: %v", err)
+	}
+
+	if inner.titles[0] != "news" {
+		t.Errorf("translated outgoing title %q", inner.titles[0])
+	}
+
+	p = testPipeline(Config{OutgoingOnly: true})
+	post, err := p.Post(&reddit.Post{Subreddit: "france", Title: "bonjour"})
+	if err != nil {
+		t.Fatalf("failed to translate post: %v", err)
+	}
+
+	if post.Title != "bonjour" {
+		t.Errorf("translated incoming title %q", post.Title)
+	}
+}
@@ -0,0 +1,45 @@
+package graw
+
+import (
+	"fmt"
+
+	"github.com/aldarisbm/graw/botfaces"
+	"github.com/aldarisbm/graw/streams"
+)
+
+var trendHandlerErr = fmt.Errorf(
+	"You must implement TrendHandler to take trends.",
+)
+
+// connectTrends connects a trend stream to the handler if the config asks for
+// one, and returns the channel to feed it texts with. The channel is nil if
+// no trends were asked for.
+func connectTrends(
+	handler interface{},
+	c Config,
+	kill <-chan bool,
+	errs chan<- error,
+) (chan<- string, error) {
+	if c.Trends == nil {
+		return nil, nil
+	}
+
+	th, ok := handler.(botfaces.TrendHandler)
+	if !ok {
+		return nil, trendHandlerErr
+	}
+
+	texts := make(chan string)
+	trends, err := streams.Trends(kill, texts, *c.Trends)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		for t := range trends {
+			errs <- th.Trend(t)
+		}
+	}()
+
+	return texts, nil
+}
@@ -0,0 +1,42 @@
+package usernotes
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	commentLink = regexp.MustCompile(`/comments/(\w+)/[^/]*/(\w+)`)
+	postLink    = regexp.MustCompile(`/comments/(\w+)`)
+	messageLink = regexp.MustCompile(`/message/messages/(\w+)`)
+)
+
+// compress shortens a permalink the way Toolbox stores it: "l,post" for a
+// post, "l,post,comment" for a comment, and "m,message" for a message. Links
+// to anything else are stored as they are.
+func compress(link string) string {
+	if m := commentLink.FindStringSubmatch(link); m != nil {
+		return "l," + m[1] + "," + m[2]
+	}
+	if m := postLink.FindStringSubmatch(link); m != nil {
+		return "l," + m[1]
+	}
+	if m := messageLink.FindStringSubmatch(link); m != nil {
+		return "m," + m[1]
+	}
+	return link
+}
+
+// expand turns a link stored by Toolbox back into a permalink.
+func expand(subreddit, link string) string {
+	parts := strings.Split(link, ",")
+	switch {
+	case len(parts) == 2 && parts[0] == "l":
+		return "/r/" + subreddit + "/comments/" + parts[1] + "/"
+	case len(parts) == 3 && parts[0] == "l":
+		return "/r/" + subreddit + "/comments/" + parts[1] + "/-/" + parts[2] + "/"
+	case len(parts) == 2 && parts[0] == "m":
+		return "/message/messages/" + parts[1]
+	}
+	return link
+}
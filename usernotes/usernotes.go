@@ -0,0 +1,317 @@
+// Package usernotes reads and writes the user notes that the Moderator
+// Toolbox browser extension keeps in a subreddit's "usernotes" wiki page, so
+// bots and moderators using Toolbox share one set of notes.
+//
+// The page holds a JSON document whose notes are compressed into a blob,
+// with moderator names and note types stored once and referred to by index.
+// Notes hides all of that:
+//
+//	err := usernotes.Update(bot, "mysub", "note spammer", func(n *usernotes.Notes) error {
+//		n.Add("spammer", usernotes.Note{
+//			Text:      "link farm",
+//			Moderator: "mybot",
+//			Type:      usernotes.SpamWatch,
+//			Link:      post.Permalink,
+//		})
+//		return nil
+//	})
+package usernotes
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+// Page is the wiki page Toolbox keeps user notes in.
+const Page = "usernotes"
+
+// version is the version of the usernotes format this package reads and
+// writes.
+const version = 6
+
+// The note types Toolbox offers by default. Subreddits may configure others.
+const (
+	GoodUser  = "gooduser"
+	SpamWatch = "spamwatch"
+	SpamWarn  = "spamwarn"
+	AbuseWarn = "abusewarn"
+	Ban       = "ban"
+	PermBan   = "permban"
+	BotBan    = "botban"
+)
+
+var versionErr = fmt.Errorf(
+	"usernotes page is not in version %d of the format", version,
+)
+
+// Note is a note about a user.
+type Note struct {
+	Text string
+	Time time.Time
+	// Moderator is the name of the moderator who left the note.
+	Moderator string
+	// Type is the kind of note, e.g. Ban. It may be empty.
+	Type string
+	// Link is the permalink of what the note is about, e.g. a post or
+	// comment, or a message such as "/message/messages/abc". It may be
+	// empty.
+	Link string
+}
+
+// Notes are the user notes of a subreddit.
+type Notes struct {
+	subreddit string
+	// users maps lowercased usernames to the username and notes of the
+	// user.
+	users map[string]*user
+	// mods and types keep the order of the page's constants, so indices
+	// in notes this package does not touch stay stable.
+	mods  []string
+	types []string
+}
+
+type user struct {
+	name  string
+	notes []Note
+}
+
+// New returns an empty set of notes for the subreddit.
+func New(subreddit string) *Notes {
+	return &Notes{subreddit: subreddit, users: make(map[string]*user)}
+}
+
+// Of returns the notes about a user, newest first. Usernames are not case
+// sensitive.
+func (n *Notes) Of(username string) []Note {
+	if u, ok := n.users[strings.ToLower(username)]; ok {
+		return append([]Note(nil), u.notes...)
+	}
+	return nil
+}
+
+// Users returns the names of all users with notes, in no particular order.
+func (n *Notes) Users() []string {
+	names := make([]string, 0, len(n.users))
+	for _, u := range n.users {
+		names = append(names, u.name)
+	}
+	return names
+}
+
+// Add adds a note about a user. If the note has no time, it is timed now.
+func (n *Notes) Add(username string, note Note) {
+	if note.Time.IsZero() {
+		note.Time = time.Now()
+	}
+
+	key := strings.ToLower(username)
+	u, ok := n.users[key]
+	if !ok {
+		u = &user{name: username}
+		n.users[key] = u
+	}
+	u.notes = append([]Note{note}, u.notes...)
+}
+
+// Set replaces the notes about a user. Setting none removes the user.
+func (n *Notes) Set(username string, notes []Note) {
+	key := strings.ToLower(username)
+	if len(notes) == 0 {
+		delete(n.users, key)
+		return
+	}
+
+	name := username
+	if u, ok := n.users[key]; ok {
+		name = u.name
+	}
+	n.users[key] = &user{name: name, notes: append([]Note(nil), notes...)}
+}
+
+// page is the JSON document on the usernotes wiki page.
+type page struct {
+	Version   int       `json:"ver"`
+	Constants constants `json:"constants"`
+	Blob      string    `json:"blob"`
+}
+
+type constants struct {
+	Users    []interface{} `json:"users"`
+	Warnings []interface{} `json:"warnings"`
+}
+
+// rawNotes are the notes about a user in the compressed blob.
+type rawNotes struct {
+	Notes []rawNote `json:"ns"`
+}
+
+type rawNote struct {
+	Text string `json:"n"`
+	Time int64  `json:"t"`
+	Mod  int    `json:"m"`
+	Link string `json:"l"`
+	Type *int   `json:"w"`
+}
+
+// Parse parses the content of a subreddit's usernotes wiki page.
+func Parse(subreddit, content string) (*Notes, error) {
+	var p page
+	if err := json.Unmarshal([]byte(content), &p); err != nil {
+		return nil, err
+	}
+
+	if p.Version != version {
+		return nil, versionErr
+	}
+
+	compressed, err := base64.StdEncoding.DecodeString(p.Blob)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := zlib.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	blob, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make(map[string]rawNotes)
+	if err := json.Unmarshal(blob, &raw); err != nil {
+		return nil, err
+	}
+
+	n := New(subreddit)
+	n.mods = strs(p.Constants.Users)
+	n.types = strs(p.Constants.Warnings)
+	for name, rn := range raw {
+		u := &user{name: name}
+		for _, r := range rn.Notes {
+			note := Note{
+				Text:      r.Text,
+				Time:      time.Unix(r.Time, 0),
+				Moderator: at(n.mods, r.Mod),
+				Link:      expand(subreddit, r.Link),
+			}
+			if r.Type != nil {
+				note.Type = at(n.types, *r.Type)
+			}
+			u.notes = append(u.notes, note)
+		}
+		n.users[strings.ToLower(name)] = u
+	}
+
+	return n, nil
+}
+
+// Marshal returns the notes as the content of a usernotes wiki page.
+func (n *Notes) Marshal() (string, error) {
+	mods := newIndex(n.mods)
+	types := newIndex(n.types)
+
+	raw := make(map[string]rawNotes, len(n.users))
+	for _, u := range n.users {
+		var rn rawNotes
+		for _, note := range u.notes {
+			r := rawNote{
+				Text: note.Text,
+				Time: note.Time.Unix(),
+				Mod:  mods.of(note.Moderator),
+				Link: compress(note.Link),
+			}
+			if note.Type != "" {
+				t := types.of(note.Type)
+				r.Type = &t
+			}
+			rn.Notes = append(rn.Notes, r)
+		}
+		raw[u.name] = rn
+	}
+
+	blob, err := json.Marshal(raw)
+	if err != nil {
+		return "", err
+	}
+
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	if _, err := w.Write(blob); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	content, err := json.Marshal(page{
+		Version: version,
+		Constants: constants{
+			Users:    ifaces(mods.values),
+			Warnings: ifaces(types.values),
+		},
+		Blob: base64.StdEncoding.EncodeToString(compressed.Bytes()),
+	})
+	return string(content), err
+}
+
+// index assigns indices to the values of a constants list.
+type index struct {
+	values    []string
+	positions map[string]int
+}
+
+func newIndex(values []string) *index {
+	i := &index{
+		values:    append([]string(nil), values...),
+		positions: make(map[string]int),
+	}
+	for j, v := range values {
+		if _, ok := i.positions[v]; !ok {
+			i.positions[v] = j
+		}
+	}
+	return i
+}
+
+func (i *index) of(value string) int {
+	if j, ok := i.positions[value]; ok {
+		return j
+	}
+
+	i.positions[value] = len(i.values)
+	i.values = append(i.values, value)
+	return len(i.values) - 1
+}
+
+// strs converts a constants list, whose entries may be null, to strings.
+func strs(values []interface{}) []string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i], _ = v.(string)
+	}
+	return s
+}
+
+func ifaces(values []string) []interface{} {
+	v := make([]interface{}, len(values))
+	for i, s := range values {
+		v[i] = s
+	}
+	return v
+}
+
+func at(values []string, i int) string {
+	if i < 0 || i >= len(values) {
+		return ""
+	}
+	return values[i]
+}
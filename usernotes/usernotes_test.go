@@ -0,0 +1,163 @@
+package usernotes
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/kylelemons/godebug/pretty"
+)
+
+// toolboxPage returns a usernotes page as Toolbox would write it.
+func toolboxPage(t *testing.T) string {
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	w.Write([]byte(`{"Spammer":{"ns":[` +
+		`{"n":"link farm","t":1500000000,"m":1,"l":"l,abc,def","w":0},` +
+		`{"n":"first warning","t":1400000000,"m":0,"l":"m,xyz","w":null}` +
+		`]}}`))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return fmt.Sprintf(
+		`{"ver":6,"constants":{"users":["alice","bob"],"warnings":["spamwatch"]},"blob":%q}`,
+		base64.StdEncoding.EncodeToString(compressed.Bytes()),
+	)
+}
+
+func TestParse(t *testing.T) {
+	n, err := Parse("sub", toolboxPage(t))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expected := []Note{
+		{
+			Text:      "link farm",
+			Time:      time.Unix(1500000000, 0),
+			Moderator: "bob",
+			Type:      SpamWatch,
+			Link:      "/r/sub/comments/abc/-/def/",
+		},
+		{
+			Text:      "first warning",
+			Time:      time.Unix(1400000000, 0),
+			Moderator: "alice",
+			Link:      "/message/messages/xyz",
+		},
+	}
+	if diff := pretty.Compare(n.Of("spammer"), expected); diff != "" {
+		t.Errorf("notes incorrect; diff: %s", diff)
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	if _, err := Parse("sub", `{"ver":5}`); err != versionErr {
+		t.Errorf("got %v; wanted versionErr", err)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	n, err := Parse("sub", toolboxPage(t))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	n.Add("Spammer", Note{
+		Text:      "banned",
+		Time:      time.Unix(1600000000, 0),
+		Moderator: "bot",
+		Type:      Ban,
+		Link:      "/r/sub/comments/ghi/",
+	})
+
+	content, err := n.Marshal()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	m, err := Parse("sub", content)
+	if err != nil {
+		t.Fatalf("failed to parse marshalled notes: %v", err)
+	}
+
+	if diff := pretty.Compare(m.Of("spammer"), n.Of("spammer")); diff != "" {
+		t.Errorf("notes changed in round trip; diff: %s", diff)
+	}
+
+	if len(m.mods) != 3 || m.mods[0] != "alice" || m.mods[2] != "bot" {
+		t.Errorf("moderator indices not preserved: %v", m.mods)
+	}
+}
+
+// conflictWiki holds a usernotes page, and fails the first edits as if
+// another moderator had saved it first.
+type conflictWiki struct {
+	reddit.Wiki
+	content   string
+	revision  int
+	conflicts int
+}
+
+func (c *conflictWiki) WikiPage(subreddit, page string) (*reddit.WikiPage, error) {
+	return &reddit.WikiPage{
+		Content:    c.content,
+		RevisionID: fmt.Sprint(c.revision),
+	}, nil
+}
+
+func (c *conflictWiki) EditWikiPage(
+	subreddit, page string,
+	edit reddit.WikiEdit,
+) error {
+	if c.conflicts > 0 {
+		c.conflicts--
+		c.revision++
+		return reddit.ConflictErr
+	}
+
+	if edit.Previous != fmt.Sprint(c.revision) {
+		return fmt.Errorf("edit based on stale revision %s", edit.Previous)
+	}
+
+	c.content = edit.Content
+	c.revision++
+	return nil
+}
+
+func TestUpdate(t *testing.T) {
+	w := &conflictWiki{content: toolboxPage(t), conflicts: 2}
+	calls := 0
+	if err := Update(w, "sub", "note", func(n *Notes) error {
+		calls++
+		n.Set("spammer", nil)
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+
+	if calls != 3 {
+		t.Errorf("got %d calls; wanted one per attempt", calls)
+	}
+
+	n, err := Read(w, "sub")
+	if err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+
+	if len(n.Users()) != 0 {
+		t.Errorf("update was not saved: %v", n.Users())
+	}
+
+	w.conflicts = maxAttempts
+	if err := Update(w, "sub", "note", func(*Notes) error {
+		return nil
+	}); err != reddit.ConflictErr {
+		t.Errorf("got %v; wanted ConflictErr after all attempts", err)
+	}
+}
@@ -0,0 +1,69 @@
+package usernotes
+
+import (
+	"github.com/aldarisbm/graw/reddit"
+)
+
+// maxAttempts is how many times Update tries to save its change before giving
+// up on a page other moderators keep changing.
+const maxAttempts = 3
+
+// Read reads the user notes of a subreddit from its wiki.
+func Read(w reddit.Wiki, subreddit string) (*Notes, error) {
+	p, err := w.WikiPage(subreddit, Page)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(subreddit, p.Content)
+}
+
+// Update reads the user notes of a subreddit, applies f to them, and saves
+// them with the given reason for the edit. If someone else saved the notes
+// in the meantime, e.g. a moderator using Toolbox, the notes are read again
+// and f is applied to the new revision, so no one's notes are lost. f may be
+// called several times, and should only change the notes it is given. If f
+// returns an error, nothing is saved.
+func Update(
+	w reddit.Wiki,
+	subreddit, reason string,
+	f func(*Notes) error,
+) error {
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if err = update(w, subreddit, reason, f); err != reddit.ConflictErr {
+			return err
+		}
+	}
+	return err
+}
+
+func update(
+	w reddit.Wiki,
+	subreddit, reason string,
+	f func(*Notes) error,
+) error {
+	p, err := w.WikiPage(subreddit, Page)
+	if err != nil {
+		return err
+	}
+
+	notes, err := Parse(subreddit, p.Content)
+	if err != nil {
+		return err
+	}
+
+	if err := f(notes); err != nil {
+		return err
+	}
+
+	content, err := notes.Marshal()
+	if err != nil {
+		return err
+	}
+
+	return w.EditWikiPage(subreddit, Page, reddit.WikiEdit{
+		Content:  content,
+		Reason:   reason,
+		Previous: p.RevisionID,
+	})
+}
@@ -0,0 +1,46 @@
+package graw
+
+import (
+	"fmt"
+
+	"github.com/aldarisbm/graw/botfaces"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/streams"
+)
+
+var volumeHandlerErr = fmt.Errorf(
+	"You must implement VolumeHandler to take post volume anomalies.",
+)
+
+// connectPostVolume connects a post volume stream to the handler if the config
+// asks for one, and returns the channel to feed it posts with. The channel is
+// nil if no post volume stream was asked for.
+func connectPostVolume(
+	handler interface{},
+	c Config,
+	kill <-chan bool,
+	errs chan<- error,
+) (chan<- *reddit.Post, error) {
+	if c.PostVolume == nil {
+		return nil, nil
+	}
+
+	vh, ok := handler.(botfaces.VolumeHandler)
+	if !ok {
+		return nil, volumeHandlerErr
+	}
+
+	posts := make(chan *reddit.Post)
+	anomalies, err := streams.PostVolume(kill, errs, posts, *c.PostVolume)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		for a := range anomalies {
+			errs <- vh.VolumeAnomaly(a)
+		}
+	}()
+
+	return posts, nil
+}
@@ -0,0 +1,133 @@
+package graw
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aldarisbm/graw/botfaces"
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/streams"
+)
+
+// maxRecent is how many fullnames of watched activity are remembered, so
+// activity seen in two streams is dispatched once.
+const maxRecent = 1000
+
+var watchedUserHandlerErr = fmt.Errorf(
+	"You must implement WatchedUserHandler to take watchlist activity.",
+)
+
+// connectWatchlist connects the handler to the activity of watched users if
+// the config has a watchlist, and returns the channel to dispatch activity
+// over. The channel is nil if there is no watchlist.
+func connectWatchlist(
+	handler interface{},
+	c Config,
+	kill <-chan bool,
+	errs chan<- error,
+) (chan<- *streams.WatchedActivity, error) {
+	if c.Watchlist == nil {
+		return nil, nil
+	}
+
+	wh, ok := handler.(botfaces.WatchedUserHandler)
+	if !ok {
+		return nil, watchedUserHandlerErr
+	}
+
+	activity := make(chan *streams.WatchedActivity)
+	go func() {
+		for a := range activity {
+			errs <- wh.WatchedUser(a)
+		}
+	}()
+
+	return activity, nil
+}
+
+// watchAnywhere opens user streams for the users on the watchlist who are
+// watched anywhere and aren't already streamed as one of the config's Users.
+func watchAnywhere(
+	tap taps,
+	sc reddit.Scanner,
+	c Config,
+	from streams.Start,
+	kill <-chan bool,
+	errs chan<- error,
+) error {
+	if c.Watchlist == nil {
+		return nil
+	}
+
+	streamed := make(map[string]bool)
+	for _, user := range c.Users {
+		streamed[strings.ToLower(user)] = true
+	}
+
+	for _, w := range c.Watchlist.Watches() {
+		if !w.Anywhere || streamed[strings.ToLower(w.Username)] {
+			continue
+		}
+
+		posts, comments, err := streams.UserFrom(
+			sc,
+			kill,
+			errs,
+			from,
+			w.Username,
+		)
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			for p := range posts {
+				tap.watchPost(p)
+			}
+		}()
+		go func() {
+			for c := range comments {
+				tap.watchComment(c)
+			}
+		}()
+	}
+
+	return nil
+}
+
+// recent is a bounded set of recently seen fullnames.
+type recent struct {
+	mu    sync.Mutex
+	names map[string]bool
+	order []string
+}
+
+func newRecent() *recent {
+	return &recent{names: make(map[string]bool)}
+}
+
+// has reports whether the name is in the set.
+func (r *recent) has(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.names[name]
+}
+
+// add adds the name to the set, and reports whether it was new.
+func (r *recent) add(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.names[name] {
+		return false
+	}
+
+	r.names[name] = true
+	r.order = append(r.order, name)
+	if len(r.order) > maxRecent {
+		delete(r.names, r.order[0])
+		r.order = r.order[1:]
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+package graw
+
+import (
+	"testing"
+
+	"github.com/aldarisbm/graw/reddit"
+	"github.com/aldarisbm/graw/streams"
+)
+
+func TestTapWatched(t *testing.T) {
+	kill := make(chan bool)
+	watched := make(chan *streams.WatchedActivity, 10)
+	tap := taps{
+		watched:   watched,
+		watchlist: streams.NewWatchlist(streams.Watch{Username: "Spammer"}),
+		seen:      newRecent(),
+		kill:      kill,
+	}
+
+	tap.post(&reddit.Post{Name: "t3_a", Author: "spammer"})
+	tap.watchPost(&reddit.Post{Name: "t3_a", Author: "spammer"})
+	tap.comment(&reddit.Comment{Name: "t1_b", Author: "bystander"})
+	tap.watchComment(&reddit.Comment{Name: "t1_c", Author: "SPAMMER"})
+	close(watched)
+
+	var got []*streams.WatchedActivity
+	for a := range watched {
+		got = append(got, a)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("wanted 2 watched activities; got %d", len(got))
+	}
+
+	if got[0].Post == nil || got[0].Post.Name != "t3_a" ||
+		got[0].Watch.Username != "Spammer" {
+		t.Errorf("wanted watched post t3_a; got %+v", got[0])
+	}
+
+	if got[1].Comment == nil || got[1].Comment.Name != "t1_c" {
+		t.Errorf("wanted watched comment t1_c; got %+v", got[1])
+	}
+}
@@ -0,0 +1,325 @@
+// Package wikiconfig reads a bot's per-subreddit configuration from a page in
+// each subreddit's wiki, so moderators can configure the bot without access to
+// its server.
+//
+// The page is YAML, decoded into a struct of the bot's choosing. Pages which
+// do not decode, have unknown fields, or fail the struct's Validate method
+// are reported to the subreddit's modmail, and the last valid config stays in
+// use:
+//
+//	type Settings struct {
+//		Greeting string `yaml:"greeting"`
+//	}
+//
+//	configs := wikiconfig.New(bot, wikiconfig.Config{
+//		Page:   "mybot",
+//		Schema: func() interface{} { return &Settings{Greeting: "hi"} },
+//	})
+//	c, err := configs.Get("mysub")
+//	greeting := c.(*Settings).Greeting
+package wikiconfig
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultCommand reloads a subreddit's config when a message begins
+	// with it, followed by the subreddit, e.g. "reload r/mysub".
+	DefaultCommand = "reload"
+	// reloadCooldown is the least time between reloads of a subreddit's
+	// config by command, so commands can't be used to flood the wiki.
+	reloadCooldown = time.Minute
+)
+
+var (
+	noPageErr   = fmt.Errorf("wikiconfig needs the name of a wiki page")
+	noSchemaErr = fmt.Errorf("wikiconfig needs a schema")
+)
+
+// Validator is implemented by config structs which check their own values.
+type Validator interface {
+	// Validate returns an error describing what is wrong with the config,
+	// or nil if it can be used.
+	Validate() error
+}
+
+// Config configures Configs.
+type Config struct {
+	// Page is the name of the wiki page holding the config.
+	Page string
+	// Schema returns a pointer to a new config struct, holding the
+	// defaults for fields the page omits. Implement Validator on it to
+	// check the page's values.
+	Schema func() interface{}
+	// Subreddits are read as soon as the configs are watched. Other
+	// subreddits are read the first time they are asked for.
+	Subreddits []string
+	// Interval is how often Watch reads the configs again. If zero,
+	// configs are only read again by command or by Reload.
+	Interval time.Duration
+	// Command is the command which reloads a subreddit's config. If empty,
+	// DefaultCommand is used.
+	Command string
+	// Clock times reload cooldowns. If nil, the wall clock is used.
+	Clock clock.Clock
+}
+
+// InvalidError describes a config page which could not be used.
+type InvalidError struct {
+	Subreddit string
+	Page      string
+	// Revision is the revision ID of the invalid page.
+	Revision string
+	Err      error
+}
+
+func (e *InvalidError) Error() string {
+	return fmt.Sprintf(
+		"invalid config in /r/%s/wiki/%s: %v", e.Subreddit, e.Page, e.Err,
+	)
+}
+
+// Configs holds the configs of many subreddits. It is safe to use from
+// multiple goroutines.
+type Configs interface {
+	// Get returns the subreddit's config, a value returned by the Schema,
+	// reading it if it was never read.
+	Get(subreddit string) (interface{}, error)
+	// Reload reads the subreddit's config again. If the page changed and
+	// is invalid, the moderators are told, the previous config is kept,
+	// and an *InvalidError is returned.
+	Reload(subreddit string) error
+	// Command reloads the config of the subreddit named in a message
+	// holding the reload command, and reports whether the message was a
+	// command. Anyone may ask for a reload; only the moderators, who can
+	// edit the page, change what it reads.
+	Command(m *reddit.Message) (bool, error)
+	// Watch reads every known subreddit's config once every interval until
+	// kill is closed, sending errors to errs. It blocks.
+	Watch(kill <-chan bool, errs chan<- error)
+}
+
+type entry struct {
+	value interface{}
+	// revision is the revision ID of the page the value was read from.
+	revision string
+	// reported is the revision ID of the last invalid page reported.
+	reported string
+	// reloaded is when the config was last reloaded by command.
+	reloaded time.Time
+}
+
+type configs struct {
+	bot reddit.Bot
+	cfg Config
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// New returns Configs which read their pages with the bot.
+func New(bot reddit.Bot, c Config) Configs {
+	if c.Command == "" {
+		c.Command = DefaultCommand
+	}
+	if c.Clock == nil {
+		c.Clock = clock.Real()
+	}
+
+	return &configs{bot: bot, cfg: c, entries: make(map[string]*entry)}
+}
+
+func (c *configs) Get(subreddit string) (interface{}, error) {
+	c.mu.Lock()
+	e, ok := c.entries[key(subreddit)]
+	c.mu.Unlock()
+	if ok && e.value != nil {
+		return e.value, nil
+	}
+
+	if err := c.Reload(subreddit); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries[key(subreddit)].value, nil
+}
+
+func (c *configs) Reload(subreddit string) error {
+	if c.cfg.Page == "" {
+		return noPageErr
+	}
+	if c.cfg.Schema == nil {
+		return noSchemaErr
+	}
+
+	page, err := c.bot.WikiPage(subreddit, c.cfg.Page)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	e, ok := c.entries[key(subreddit)]
+	if !ok {
+		e = &entry{}
+		c.entries[key(subreddit)] = e
+	}
+	unchanged := e.value != nil && e.revision == page.RevisionID
+	reported := e.reported == page.RevisionID
+	c.mu.Unlock()
+
+	if unchanged {
+		return nil
+	}
+
+	value, err := c.decode(page.Content)
+	if err != nil {
+		invalid := &InvalidError{
+			Subreddit: subreddit,
+			Page:      c.cfg.Page,
+			Revision:  page.RevisionID,
+			Err:       err,
+		}
+		if !reported {
+			if err := c.report(invalid); err != nil {
+				return err
+			}
+		}
+		return invalid
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e.value = value
+	e.revision = page.RevisionID
+	return nil
+}
+
+func (c *configs) Command(m *reddit.Message) (bool, error) {
+	fields := strings.Fields(m.Body)
+	if len(fields) != 2 || !strings.EqualFold(fields[0], c.cfg.Command) {
+		return false, nil
+	}
+
+	subreddit := strings.TrimPrefix(strings.TrimPrefix(fields[1], "/"), "r/")
+	now := c.cfg.Clock.Now()
+
+	c.mu.Lock()
+	e, ok := c.entries[key(subreddit)]
+	if !ok {
+		e = &entry{}
+		c.entries[key(subreddit)] = e
+	}
+	cooling := now.Sub(e.reloaded) < reloadCooldown
+	if !cooling {
+		e.reloaded = now
+	}
+	c.mu.Unlock()
+
+	if cooling {
+		return true, nil
+	}
+	return true, c.Reload(subreddit)
+}
+
+func (c *configs) Watch(kill <-chan bool, errs chan<- error) {
+	c.reload(c.cfg.Subreddits, kill, errs)
+
+	if c.cfg.Interval <= 0 {
+		<-kill
+		return
+	}
+
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-kill:
+			return
+		case <-ticker.C:
+			c.reload(c.known(), kill, errs)
+		}
+	}
+}
+
+func (c *configs) reload(
+	subreddits []string,
+	kill <-chan bool,
+	errs chan<- error,
+) {
+	for _, subreddit := range subreddits {
+		if err := c.Reload(subreddit); err != nil {
+			select {
+			case errs <- err:
+			case <-kill:
+				return
+			}
+		}
+	}
+}
+
+// known returns every subreddit whose config was asked for.
+func (c *configs) known() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	subreddits := make([]string, 0, len(c.entries))
+	for sub := range c.entries {
+		subreddits = append(subreddits, sub)
+	}
+	return subreddits
+}
+
+// decode decodes a page into a new config and validates it.
+func (c *configs) decode(content string) (interface{}, error) {
+	value := c.cfg.Schema()
+	dec := yaml.NewDecoder(bytes.NewBufferString(content))
+	dec.KnownFields(true)
+	if err := dec.Decode(value); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if v, ok := value.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return value, nil
+}
+
+// report tells the subreddit's moderators their config is invalid, once per
+// revision.
+func (c *configs) report(invalid *InvalidError) error {
+	if err := c.bot.SendMessage(
+		"/r/"+invalid.Subreddit,
+		"Invalid bot config",
+		fmt.Sprintf(
+			"The config in [%s](/r/%s/wiki/%s) could not be used, so the "+
+				"previous config is still in use:\n\n    %v",
+			invalid.Page, invalid.Subreddit, invalid.Page, invalid.Err,
+		),
+	); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key(invalid.Subreddit)].reported = invalid.Revision
+	return nil
+}
+
+func key(subreddit string) string {
+	return strings.ToLower(subreddit)
+}
@@ -0,0 +1,134 @@
+package wikiconfig
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aldarisbm/graw/clock"
+	"github.com/aldarisbm/graw/reddit"
+)
+
+type settings struct {
+	Greeting string `yaml:"greeting"`
+	Limit    int    `yaml:"limit"`
+}
+
+func (s *settings) Validate() error {
+	if s.Limit < 0 {
+		return fmt.Errorf("limit must not be negative")
+	}
+	return nil
+}
+
+// wikiBot serves one wiki page and records the modmail it is sent.
+type wikiBot struct {
+	reddit.Bot
+	content  string
+	revision int
+	reads    int
+	modmail  []string
+}
+
+func (w *wikiBot) edit(content string) {
+	w.content = content
+	w.revision++
+}
+
+func (w *wikiBot) WikiPage(subreddit, page string) (*reddit.WikiPage, error) {
+	w.reads++
+	return &reddit.WikiPage{
+		Content:    w.content,
+		RevisionID: fmt.Sprint(w.revision),
+	}, nil
+}
+
+func (w *wikiBot) SendMessage(user, subject, text string) error {
+	w.modmail = append(w.modmail, user)
+	return nil
+}
+
+func testConfigs(bot *wikiBot, sim clock.Clock) Configs {
+	return New(bot, Config{
+		Page:   "bot",
+		Schema: func() interface{} { return &settings{Greeting: "hi"} },
+		Clock:  sim,
+	})
+}
+
+func TestGet(t *testing.T) {
+	bot := &wikiBot{content: "limit: 3"}
+	c := testConfigs(bot, nil)
+
+	for i := 0; i < 2; i++ {
+		v, err := c.Get("Sub")
+		if err != nil {
+			t.Fatalf("failed to get config: %v", err)
+		}
+
+		if s := v.(*settings); s.Greeting != "hi" || s.Limit != 3 {
+			t.Errorf("got config %+v", s)
+		}
+	}
+
+	if bot.reads != 1 {
+		t.Errorf("got %d reads; wanted the config cached", bot.reads)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	bot := &wikiBot{content: "greeting: hello"}
+	c := testConfigs(bot, nil)
+	if _, err := c.Get("sub"); err != nil {
+		t.Fatalf("failed to get config: %v", err)
+	}
+
+	for _, content := range []string{"limit: -1", "limt: 3", "greeting: ["} {
+		bot.edit(content)
+		for i := 0; i < 2; i++ {
+			if _, ok := c.Reload("sub").(*InvalidError); !ok {
+				t.Errorf("%q: wanted InvalidError", content)
+			}
+		}
+
+		v, _ := c.Get("sub")
+		if s := v.(*settings); s.Greeting != "hello" {
+			t.Errorf("%q: previous config was not kept; got %+v", content, s)
+		}
+	}
+
+	if len(bot.modmail) != 3 || bot.modmail[0] != "/r/sub" {
+		t.Errorf("wanted each invalid revision reported once; got %v", bot.modmail)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	sim := clock.NewSimulation(time.Unix(0, 0))
+	bot := &wikiBot{content: "limit: 1"}
+	c := testConfigs(bot, sim)
+
+	if ok, err := c.Command(&reddit.Message{Body: "hello there"}); ok || err != nil {
+		t.Errorf("chatter was taken as a command")
+	}
+
+	for i := 0; i < 2; i++ {
+		if ok, err := c.Command(&reddit.Message{Body: "Reload /r/sub"}); !ok || err != nil {
+			t.Fatalf("reload command failed: %v, %v", ok, err)
+		}
+	}
+
+	if bot.reads != 1 {
+		t.Errorf("got %d reads; wanted repeated commands to cool down", bot.reads)
+	}
+
+	bot.edit("limit: 2")
+	sim.AdvanceTo(time.Unix(0, 0).Add(reloadCooldown))
+	if _, err := c.Command(&reddit.Message{Body: "reload r/sub"}); err != nil {
+		t.Fatalf("reload command failed: %v", err)
+	}
+
+	v, _ := c.Get("sub")
+	if s := v.(*settings); s.Limit != 2 {
+		t.Errorf("config was not reloaded; got %+v", s)
+	}
+}